@@ -47,9 +47,34 @@ func (this *AbiMgr) GetNativeAbi(address string) *NativeContractAbi {
 	return nil
 }
 
+//GetNativeAbiByName return the abi by contract name, or by contract address in hex
+func (this *AbiMgr) GetNativeAbiByName(name string) *NativeContractAbi {
+	for _, abi := range this.nativeAbis {
+		if abi.Name == name {
+			return abi
+		}
+	}
+	return this.GetNativeAbi(strings.TrimPrefix(name, "0x"))
+}
+
 func (this *AbiMgr) Init(path string) {
 	this.Path = path
-	this.loadNativeAbi()
+	this.loadBuiltinNativeAbi()
+	if path != "" {
+		this.loadNativeAbi()
+	}
+}
+
+//loadBuiltinNativeAbi load the abi generated from native contract params, abi files in path override them
+func (this *AbiMgr) loadBuiltinNativeAbi() {
+	nativeAbis, err := GenerateNativeAbis()
+	if err != nil {
+		log.Errorf("AbiMgr loadBuiltinNativeAbi error:%s", err)
+		return
+	}
+	for _, nativeAbi := range nativeAbis {
+		this.nativeAbis[nativeAbi.Address] = nativeAbi
+	}
 }
 
 func (this *AbiMgr) loadNativeAbi() {
@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//abigen generate the abi files of poly native contracts
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/polynetwork/poly/cmd/abi"
+)
+
+func main() {
+	out := flag.String("out", "./native_abi_script", "directory to write the abi files")
+	flag.Parse()
+
+	abis, err := abi.GenerateNativeAbis()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+	for _, nativeAbi := range abis {
+		data, err := abi.MarshalNativeAbi(nativeAbi)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "marshal abi of %s error:%s\n", nativeAbi.Name, err)
+			os.Exit(1)
+		}
+		fileName := filepath.Join(*out, nativeAbi.Name+".json")
+		if err = ioutil.WriteFile(fileName, data, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "write %s error:%s\n", fileName, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Generate %s\n", fileName)
+	}
+}
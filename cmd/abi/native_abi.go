@@ -21,18 +21,25 @@ package abi
 import "strings"
 
 const (
-	NATIVE_PARAM_TYPE_BOOL      = "bool"
-	NATIVE_PARAM_TYPE_BYTE      = "byte"
-	NATIVE_PARAM_TYPE_INTEGER   = "int"
-	NATIVE_PARAM_TYPE_STRING    = "string"
-	NATIVE_PARAM_TYPE_BYTEARRAY = "bytearray"
-	NATIVE_PARAM_TYPE_ARRAY     = "array"
-	NATIVE_PARAM_TYPE_ADDRESS   = "address"
-	NATIVE_PARAM_TYPE_UINT256   = "uint256"
-	NATIVE_PARAM_TYPE_STRUCT    = "struct"
+	NATIVE_PARAM_TYPE_BOOL       = "bool"
+	NATIVE_PARAM_TYPE_BYTE       = "byte"
+	NATIVE_PARAM_TYPE_INTEGER    = "int" // var uint encoded
+	NATIVE_PARAM_TYPE_UINT32     = "uint32"
+	NATIVE_PARAM_TYPE_UINT64     = "uint64"
+	NATIVE_PARAM_TYPE_STRING     = "string"
+	NATIVE_PARAM_TYPE_BYTEARRAY  = "bytearray"
+	NATIVE_PARAM_TYPE_ARRAY      = "array"   // var uint length prefix
+	NATIVE_PARAM_TYPE_ARRAY64    = "array64" // uint64 length prefix
+	NATIVE_PARAM_TYPE_ADDRESS    = "address" // var bytes encoded
+	NATIVE_PARAM_TYPE_RAWADDRESS = "rawaddress"
+	NATIVE_PARAM_TYPE_UINT256    = "uint256"
+	NATIVE_PARAM_TYPE_BIGINT     = "bigint"
+	NATIVE_PARAM_TYPE_MAP        = "map" // entries sorted by key in descending order
+	NATIVE_PARAM_TYPE_STRUCT     = "struct"
 )
 
 type NativeContractAbi struct {
+	Name      string                       `json:"name,omitempty"`
 	Address   string                       `json:"hash"`
 	Functions []*NativeContractFunctionAbi `json:"functions"`
 	Events    []*NativeContractEventAbi    `json:"events"`
@@ -47,7 +54,7 @@ type NativeContractFunctionAbi struct {
 type NativeContractParamAbi struct {
 	Name    string                    `json:"name"`
 	Type    string                    `json:"type"`
-	SubType []*NativeContractParamAbi `json:"subType"`
+	SubType []*NativeContractParamAbi `json:"subType,omitempty"`
 }
 
 type NativeContractEventAbi struct {
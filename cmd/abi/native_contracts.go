@@ -0,0 +1,121 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package abi
+
+import (
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager" // register event schemas of chain handlers
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/cross_chain_manager/ripple"
+	"github.com/polynetwork/poly/native/service/governance/fork_manager"
+	"github.com/polynetwork/poly/native/service/governance/neo3_state_manager"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/relayer_manager"
+	"github.com/polynetwork/poly/native/service/governance/replenish"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/governance/signature_manager"
+	_ "github.com/polynetwork/poly/native/service/header_sync" // register event schemas of chain handlers
+	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+var nativeContracts = []*nativeContract{
+	{"header_sync", utils.HeaderSyncContractAddress, []*nativeMethod{
+		{hscommon.SYNC_GENESIS_HEADER, &hscommon.SyncGenesisHeaderParam{},
+			map[string]string{"ChainID": NATIVE_PARAM_TYPE_UINT64}, ""},
+		{hscommon.SYNC_BLOCK_HEADER, &hscommon.SyncBlockHeaderParam{},
+			map[string]string{"ChainID": NATIVE_PARAM_TYPE_UINT64, "Address": NATIVE_PARAM_TYPE_RAWADDRESS,
+				"Headers": NATIVE_PARAM_TYPE_ARRAY64}, ""},
+		{hscommon.SYNC_CROSS_CHAIN_MSG, &hscommon.SyncCrossChainMsgParam{},
+			map[string]string{"ChainID": NATIVE_PARAM_TYPE_UINT64, "Address": NATIVE_PARAM_TYPE_RAWADDRESS,
+				"CrossChainMsgs": NATIVE_PARAM_TYPE_ARRAY64}, ""},
+	}},
+	{"cross_chain_manager", utils.CrossChainManagerContractAddress, []*nativeMethod{
+		{scom.IMPORT_OUTER_TRANSFER_NAME, &scom.EntranceParam{},
+			map[string]string{"SourceChainID": NATIVE_PARAM_TYPE_UINT64}, ""},
+		{scom.MULTI_SIGN, &scom.MultiSignParam{},
+			map[string]string{"ChainID": NATIVE_PARAM_TYPE_UINT64, "Signs": NATIVE_PARAM_TYPE_ARRAY64}, ""},
+		{scom.MULTI_SIGN_RIPPLE, &ripple.MultiSignParam{}, nil, ""},
+		{scom.RECONSTRUCT_RIPPLE_TX, &ripple.ReconstructTxParam{}, nil, ""},
+		{scom.BLACK_CHAIN, &scom.BlackChainParam{}, nil, ""},
+		{scom.WHITE_CHAIN, &scom.BlackChainParam{}, nil, ""},
+		{scom.SET_RATE_LIMIT, &scom.RateLimitParam{},
+			map[string]string{"Period": NATIVE_PARAM_TYPE_INTEGER}, ""},
+		{scom.RELEASE_RATE_LIMITED_TX, &scom.ReleaseRateLimitedTxParam{}, nil, ""},
+		{scom.SET_CONTRACT_POLICY, &scom.ContractPolicyParam{}, nil, ""},
+		{scom.GET_CONTRACT_POLICY, &scom.GetContractPolicyParam{}, nil, NATIVE_PARAM_TYPE_BYTEARRAY},
+		{scom.GET_CONTRACT_POLICY_LIST, &scom.GetContractPolicyListParam{}, nil, NATIVE_PARAM_TYPE_BYTEARRAY},
+		{scom.SET_EXECUTION_DELAY, &scom.ExecutionDelayParam{},
+			map[string]string{"Delay": NATIVE_PARAM_TYPE_INTEGER}, ""},
+		{scom.RELEASE_DELAYED_TX, &scom.DelayedTxParam{}, nil, ""},
+		{scom.VETO_DELAYED_TX, &scom.DelayedTxParam{}, nil, ""},
+	}},
+	{"side_chain_manager", utils.SideChainManagerContractAddress, []*nativeMethod{
+		{side_chain_manager.REGISTER_SIDE_CHAIN, &side_chain_manager.RegisterSideChainParam{}, nil, ""},
+		{side_chain_manager.APPROVE_REGISTER_SIDE_CHAIN, &side_chain_manager.ChainidParam{}, nil, ""},
+		{side_chain_manager.UPDATE_SIDE_CHAIN, &side_chain_manager.RegisterSideChainParam{}, nil, ""},
+		{side_chain_manager.APPROVE_UPDATE_SIDE_CHAIN, &side_chain_manager.ChainidParam{}, nil, ""},
+		{side_chain_manager.QUIT_SIDE_CHAIN, &side_chain_manager.ChainidParam{}, nil, ""},
+		{side_chain_manager.APPROVE_QUIT_SIDE_CHAIN, &side_chain_manager.ChainidParam{}, nil, ""},
+		{side_chain_manager.REGISTER_ASSET, &side_chain_manager.RegisterAssetParam{},
+			map[string]string{"OperatorAddress": NATIVE_PARAM_TYPE_RAWADDRESS}, ""},
+		{side_chain_manager.UPDATE_FEE, &side_chain_manager.UpdateFeeParam{},
+			map[string]string{"Address": NATIVE_PARAM_TYPE_RAWADDRESS, "ChainId": NATIVE_PARAM_TYPE_UINT64,
+				"View": NATIVE_PARAM_TYPE_UINT64}, ""},
+		{side_chain_manager.REGISTER_REDEEM, &side_chain_manager.RegisterRedeemParam{}, nil, ""},
+		{side_chain_manager.SET_BTC_TX_PARAM, &side_chain_manager.BtcTxParam{}, nil, ""},
+	}},
+	{"node_manager", utils.NodeManagerContractAddress, []*nativeMethod{
+		{node_manager.REGISTER_CANDIDATE, &node_manager.RegisterPeerParam{}, nil, ""},
+		{node_manager.UNREGISTER_CANDIDATE, &node_manager.PeerParam{}, nil, ""},
+		{node_manager.APPROVE_CANDIDATE, &node_manager.PeerParam{}, nil, ""},
+		{node_manager.BLACK_NODE, &node_manager.PeerListParam{}, nil, ""},
+		{node_manager.WHITE_NODE, &node_manager.PeerParam{}, nil, ""},
+		{node_manager.QUIT_NODE, &node_manager.PeerParam{}, nil, ""},
+		{node_manager.UPDATE_CONFIG, &node_manager.UpdateConfigParam{}, nil, ""},
+		{node_manager.COMMIT_DPOS, nil, nil, ""},
+	}},
+	{"relayer_manager", utils.RelayerManagerContractAddress, []*nativeMethod{
+		{relayer_manager.REGISTER_RELAYER, &relayer_manager.RelayerListParam{}, nil, ""},
+		{relayer_manager.APPROVE_REGISTER_RELAYER, &relayer_manager.ApproveRelayerParam{}, nil, ""},
+		{relayer_manager.REMOVE_RELAYER, &relayer_manager.RelayerListParam{}, nil, ""},
+		{relayer_manager.APPROVE_REMOVE_RELAYER, &relayer_manager.ApproveRelayerParam{}, nil, ""},
+		{relayer_manager.GET_RELAYER_WORK, &relayer_manager.GetRelayerWorkParam{}, nil, NATIVE_PARAM_TYPE_BYTEARRAY},
+		{relayer_manager.GET_FEE_SCHEDULE, &relayer_manager.GetFeeScheduleParam{}, nil, NATIVE_PARAM_TYPE_BYTEARRAY},
+		{relayer_manager.SET_RELAYER_BOND, &relayer_manager.RelayerBondParam{}, nil, ""},
+		{relayer_manager.GET_RELAYER_STATUS, &relayer_manager.GetRelayerStatusParam{}, nil, NATIVE_PARAM_TYPE_BYTEARRAY},
+	}},
+	{"neo3_state_manager", utils.Neo3StateManagerContractAddress, []*nativeMethod{
+		{neo3_state_manager.GET_CURRENT_STATE_VALIDATOR, nil, nil, NATIVE_PARAM_TYPE_BYTEARRAY},
+		{neo3_state_manager.REGISTER_STATE_VALIDATOR, &neo3_state_manager.StateValidatorListParam{}, nil, ""},
+		{neo3_state_manager.APPROVE_REGISTER_STATE_VALIDATOR, &neo3_state_manager.ApproveStateValidatorParam{}, nil, ""},
+		{neo3_state_manager.REMOVE_STATE_VALIDATOR, &neo3_state_manager.StateValidatorListParam{}, nil, ""},
+		{neo3_state_manager.APPROVE_REMOVE_STATE_VALIDATOR, &neo3_state_manager.ApproveStateValidatorParam{}, nil, ""},
+	}},
+	{"signature_manager", utils.SignatureManagerContractAddress, []*nativeMethod{
+		{signature_manager.ADD_SIGNATURE, &signature_manager.AddSignatureParam{},
+			map[string]string{"SideChainID": NATIVE_PARAM_TYPE_UINT64}, ""},
+	}},
+	{"replenish", utils.ReplenishContractAddress, []*nativeMethod{
+		{replenish.REPLENISH_TX, &replenish.ReplenishTxParam{}, nil, ""},
+	}},
+	{"fork_manager", utils.ForkManagerContractAddress, []*nativeMethod{
+		{fork_manager.SCHEDULE_FORK, &fork_manager.ScheduleForkParam{},
+			map[string]string{"Height": NATIVE_PARAM_TYPE_UINT64}, ""},
+	}},
+}
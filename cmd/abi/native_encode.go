@@ -0,0 +1,257 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package abi
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/polynetwork/poly/common"
+)
+
+//EncodeArgs encode the json args of function into the input of native contract invoke.
+//Args could be a json object with parameter names as keys, or a json array in parameter order.
+func (this *NativeContractFunctionAbi) EncodeArgs(args []byte) ([]byte, error) {
+	var value interface{}
+	if len(bytes.TrimSpace(args)) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(args))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			return nil, fmt.Errorf("invalid json args:%s", err)
+		}
+	}
+	if value == nil && len(this.Parameters) == 0 {
+		return []byte{}, nil
+	}
+	sink := common.NewZeroCopySink(nil)
+	if err := encodeStruct(sink, this.Parameters, value); err != nil {
+		return nil, fmt.Errorf("function %s %s", this.Name, err)
+	}
+	return sink.Bytes(), nil
+}
+
+func encodeStruct(sink *common.ZeroCopySink, fields []*NativeContractParamAbi, value interface{}) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, field := range fields {
+			fieldValue, ok := v[field.Name]
+			if !ok {
+				return fmt.Errorf("missing param %s", field.Name)
+			}
+			if err := encodeParam(sink, field, fieldValue); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		if len(v) != len(fields) {
+			return fmt.Errorf("params count %d unmatch, expect %d", len(v), len(fields))
+		}
+		for i, field := range fields {
+			if err := encodeParam(sink, field, v[i]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("struct params should be json object or array")
+	}
+	return nil
+}
+
+func encodeParam(sink *common.ZeroCopySink, param *NativeContractParamAbi, value interface{}) error {
+	var err error
+	switch strings.ToLower(param.Type) {
+	case NATIVE_PARAM_TYPE_BOOL:
+		b, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("param %s should be bool", param.Name)
+		}
+		sink.WriteBool(b)
+	case NATIVE_PARAM_TYPE_BYTE:
+		var n uint64
+		if n, err = parseUint(value, 8); err == nil {
+			sink.WriteUint8(uint8(n))
+		}
+	case NATIVE_PARAM_TYPE_INTEGER:
+		var n uint64
+		if n, err = parseUint(value, 64); err == nil {
+			sink.WriteVarUint(n)
+		}
+	case NATIVE_PARAM_TYPE_UINT32:
+		var n uint64
+		if n, err = parseUint(value, 32); err == nil {
+			sink.WriteUint32(uint32(n))
+		}
+	case NATIVE_PARAM_TYPE_UINT64:
+		var n uint64
+		if n, err = parseUint(value, 64); err == nil {
+			sink.WriteUint64(n)
+		}
+	case NATIVE_PARAM_TYPE_STRING:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("param %s should be string", param.Name)
+		}
+		sink.WriteString(s)
+	case NATIVE_PARAM_TYPE_BYTEARRAY:
+		var data []byte
+		if data, err = parseHex(value); err == nil {
+			sink.WriteVarBytes(data)
+		}
+	case NATIVE_PARAM_TYPE_ADDRESS, NATIVE_PARAM_TYPE_RAWADDRESS:
+		var addr common.Address
+		if addr, err = parseAddress(value); err != nil {
+			break
+		}
+		if strings.ToLower(param.Type) == NATIVE_PARAM_TYPE_ADDRESS {
+			sink.WriteVarBytes(addr[:])
+		} else {
+			sink.WriteAddress(addr)
+		}
+	case NATIVE_PARAM_TYPE_UINT256:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("param %s should be hex string", param.Name)
+		}
+		var hash common.Uint256
+		if hash, err = common.Uint256FromHexString(strings.TrimPrefix(s, "0x")); err == nil {
+			sink.WriteHash(hash)
+		}
+	case NATIVE_PARAM_TYPE_BIGINT:
+		n, ok := new(big.Int).SetString(fmt.Sprint(value), 10)
+		if !ok || n.Sign() < 0 {
+			return fmt.Errorf("param %s should be non-negative integer", param.Name)
+		}
+		sink.WriteVarBytes(n.Bytes())
+	case NATIVE_PARAM_TYPE_ARRAY, NATIVE_PARAM_TYPE_ARRAY64:
+		err = encodeArray(sink, param, value)
+	case NATIVE_PARAM_TYPE_MAP:
+		err = encodeMap(sink, param, value)
+	case NATIVE_PARAM_TYPE_STRUCT:
+		err = encodeStruct(sink, param.SubType, value)
+	default:
+		return fmt.Errorf("param %s has unsupported type %s", param.Name, param.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("param %s %s", param.Name, err)
+	}
+	return nil
+}
+
+func encodeArray(sink *common.ZeroCopySink, param *NativeContractParamAbi, value interface{}) error {
+	if len(param.SubType) != 1 {
+		return fmt.Errorf("array should have one sub type")
+	}
+	items, ok := value.([]interface{})
+	if !ok && value != nil {
+		return fmt.Errorf("should be json array")
+	}
+	if strings.ToLower(param.Type) == NATIVE_PARAM_TYPE_ARRAY64 {
+		sink.WriteUint64(uint64(len(items)))
+	} else {
+		sink.WriteVarUint(uint64(len(items)))
+	}
+	for _, item := range items {
+		if err := encodeParam(sink, param.SubType[0], item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeMap(sink *common.ZeroCopySink, param *NativeContractParamAbi, value interface{}) error {
+	if len(param.SubType) != 2 || strings.ToLower(param.SubType[0].Type) != NATIVE_PARAM_TYPE_INTEGER {
+		return fmt.Errorf("map should have int key and one value sub type")
+	}
+	entries, ok := value.(map[string]interface{})
+	if !ok && value != nil {
+		return fmt.Errorf("should be json object")
+	}
+	keys := make([]uint64, 0, len(entries))
+	values := make(map[uint64]interface{}, len(entries))
+	for k, v := range entries {
+		key, err := strconv.ParseUint(k, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid map key %s", k)
+		}
+		keys = append(keys, key)
+		values[key] = v
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] > keys[j]
+	})
+	sink.WriteVarUint(uint64(len(keys)))
+	for _, key := range keys {
+		sink.WriteVarUint(key)
+		if err := encodeParam(sink, param.SubType[1], values[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseUint(value interface{}, bitSize int) (uint64, error) {
+	var s string
+	switch v := value.(type) {
+	case json.Number:
+		s = v.String()
+	case string:
+		s = v
+	default:
+		return 0, fmt.Errorf("should be integer")
+	}
+	n, err := strconv.ParseUint(s, 10, bitSize)
+	if err != nil {
+		return 0, fmt.Errorf("invalid uint%d:%s", bitSize, s)
+	}
+	return n, nil
+}
+
+func parseHex(value interface{}) ([]byte, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("should be hex string")
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex string:%s", s)
+	}
+	return data, nil
+}
+
+//parseAddress accept base58 address and hex address
+func parseAddress(value interface{}) (common.Address, error) {
+	s, ok := value.(string)
+	if !ok {
+		return common.ADDRESS_EMPTY, fmt.Errorf("should be address string")
+	}
+	if addr, err := common.AddressFromBase58(s); err == nil {
+		return addr, nil
+	}
+	addr, err := common.AddressFromHexString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return common.ADDRESS_EMPTY, fmt.Errorf("invalid address:%s", s)
+	}
+	return addr, nil
+}
@@ -0,0 +1,213 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package abi
+
+//go:generate go run ./abigen -out ./native_abi_script
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native/event"
+)
+
+var (
+	addressType = reflect.TypeOf(common.Address{})
+	uint256Type = reflect.TypeOf(common.Uint256{})
+	bigIntType  = reflect.TypeOf(big.Int{})
+	bytesType   = reflect.TypeOf([]byte{})
+)
+
+//nativeMethod bind a native contract method to the param struct it deserializes its input into
+type nativeMethod struct {
+	name  string
+	param interface{} // nil if method has no input
+	//abi type of fields whose serialization differs from the default of its go type, keyed by field path
+	types      map[string]string
+	returnType string
+}
+
+type nativeContract struct {
+	name    string
+	address common.Address
+	methods []*nativeMethod
+}
+
+//GenerateNativeAbis generate the abi of all poly native contracts from their param structs and event schemas
+func GenerateNativeAbis() ([]*NativeContractAbi, error) {
+	abis := make([]*NativeContractAbi, 0, len(nativeContracts))
+	for _, contract := range nativeContracts {
+		abi, err := generateNativeAbi(contract)
+		if err != nil {
+			return nil, fmt.Errorf("generate abi of %s error:%s", contract.name, err)
+		}
+		abis = append(abis, abi)
+	}
+	return abis, nil
+}
+
+//MarshalNativeAbi marshal the abi in the format of abi files
+func MarshalNativeAbi(abi *NativeContractAbi) ([]byte, error) {
+	data, err := json.MarshalIndent(abi, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func generateNativeAbi(contract *nativeContract) (*NativeContractAbi, error) {
+	abi := &NativeContractAbi{
+		Name:      contract.name,
+		Address:   contract.address.ToHexString(),
+		Functions: make([]*NativeContractFunctionAbi, 0, len(contract.methods)),
+		Events:    make([]*NativeContractEventAbi, 0),
+	}
+	for _, method := range contract.methods {
+		funcAbi := &NativeContractFunctionAbi{
+			Name:       method.name,
+			Parameters: make([]*NativeContractParamAbi, 0),
+			ReturnType: method.returnType,
+		}
+		if funcAbi.ReturnType == "" {
+			funcAbi.ReturnType = NATIVE_PARAM_TYPE_BOOL
+		}
+		if method.param != nil {
+			params, err := generateParams(reflect.TypeOf(method.param), "", method.types)
+			if err != nil {
+				return nil, fmt.Errorf("method %s %s", method.name, err)
+			}
+			funcAbi.Parameters = params
+		}
+		abi.Functions = append(abi.Functions, funcAbi)
+	}
+	for _, schema := range event.GetEventSchemas() {
+		if schema.Contract != contract.address {
+			continue
+		}
+		evtAbi := &NativeContractEventAbi{
+			Name:       schema.Name,
+			Parameters: make([]*NativeContractParamAbi, 0, len(schema.Fields)),
+		}
+		for _, field := range schema.Fields {
+			evtAbi.Parameters = append(evtAbi.Parameters, &NativeContractParamAbi{Name: field.Name, Type: string(field.Type)})
+		}
+		abi.Events = append(abi.Events, evtAbi)
+	}
+	sort.SliceStable(abi.Events, func(i, j int) bool {
+		return abi.Events[i].Name < abi.Events[j].Name
+	})
+	return abi, nil
+}
+
+//generateParams generate the abi of struct fields in declaration order, embedded structs are flattened
+func generateParams(t reflect.Type, path string, types map[string]string) ([]*NativeContractParamAbi, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s is not struct", t)
+	}
+	params := make([]*NativeContractParamAbi, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			embedded, err := generateParams(field.Type, path, types)
+			if err != nil {
+				return nil, err
+			}
+			params = append(params, embedded...)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+		param, err := generateParam(field.Name, field.Type, fieldPath, types)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+func generateParam(name string, t reflect.Type, path string, types map[string]string) (*NativeContractParamAbi, error) {
+	param := &NativeContractParamAbi{Name: name}
+	if t.Kind() == reflect.Ptr && t.Elem() != bigIntType {
+		t = t.Elem()
+	}
+	switch {
+	case t == addressType:
+		param.Type = NATIVE_PARAM_TYPE_ADDRESS
+	case t == uint256Type:
+		param.Type = NATIVE_PARAM_TYPE_UINT256
+	case t == bytesType:
+		param.Type = NATIVE_PARAM_TYPE_BYTEARRAY
+	case t.Kind() == reflect.Ptr:
+		param.Type = NATIVE_PARAM_TYPE_BIGINT
+	case t.Kind() == reflect.Bool:
+		param.Type = NATIVE_PARAM_TYPE_BOOL
+	case t.Kind() == reflect.Uint8:
+		param.Type = NATIVE_PARAM_TYPE_BYTE
+	case t.Kind() == reflect.Uint32:
+		param.Type = NATIVE_PARAM_TYPE_UINT32
+	case t.Kind() == reflect.Uint64:
+		param.Type = NATIVE_PARAM_TYPE_INTEGER
+	case t.Kind() == reflect.String:
+		param.Type = NATIVE_PARAM_TYPE_STRING
+	case t.Kind() == reflect.Slice:
+		item, err := generateParam("", t.Elem(), path+"[]", types)
+		if err != nil {
+			return nil, err
+		}
+		param.Type = NATIVE_PARAM_TYPE_ARRAY
+		param.SubType = []*NativeContractParamAbi{item}
+	case t.Kind() == reflect.Map:
+		key, err := generateParam("key", t.Key(), path+".key", types)
+		if err != nil {
+			return nil, err
+		}
+		value, err := generateParam("value", t.Elem(), path+".value", types)
+		if err != nil {
+			return nil, err
+		}
+		param.Type = NATIVE_PARAM_TYPE_MAP
+		param.SubType = []*NativeContractParamAbi{key, value}
+	case t.Kind() == reflect.Struct:
+		fields, err := generateParams(t, path, types)
+		if err != nil {
+			return nil, err
+		}
+		param.Type = NATIVE_PARAM_TYPE_STRUCT
+		param.SubType = fields
+	default:
+		return nil, fmt.Errorf("unsupported type %s of field %s", t, path)
+	}
+	if typ, ok := types[path]; ok {
+		param.Type = typ
+	}
+	return param, nil
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package abi
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type sampleFiller struct {
+	seed uint64
+}
+
+func (this *sampleFiller) next() uint64 {
+	this.seed++
+	return this.seed
+}
+
+//fill set v to sample value according to param abi, and return the json representation of it
+func (this *sampleFiller) fill(v reflect.Value, param *NativeContractParamAbi) interface{} {
+	if v.Kind() == reflect.Ptr && v.Type().Elem() != bigIntType {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+	n := this.next()
+	switch param.Type {
+	case NATIVE_PARAM_TYPE_BOOL:
+		v.SetBool(true)
+		return true
+	case NATIVE_PARAM_TYPE_BYTE, NATIVE_PARAM_TYPE_UINT32:
+		v.SetUint(n)
+		return json.Number(fmt.Sprint(n))
+	case NATIVE_PARAM_TYPE_INTEGER, NATIVE_PARAM_TYPE_UINT64:
+		// large enough to take multi bytes in var uint
+		v.SetUint(n << 20)
+		return json.Number(fmt.Sprint(n << 20))
+	case NATIVE_PARAM_TYPE_STRING:
+		s := fmt.Sprintf("str%d", n)
+		v.SetString(s)
+		return s
+	case NATIVE_PARAM_TYPE_BYTEARRAY:
+		data := []byte(fmt.Sprintf("bytes%d", n))
+		v.SetBytes(data)
+		return hex.EncodeToString(data)
+	case NATIVE_PARAM_TYPE_ADDRESS, NATIVE_PARAM_TYPE_RAWADDRESS:
+		addr := common.Address{byte(n), 1, 2, 3}
+		v.Set(reflect.ValueOf(addr))
+		return addr.ToBase58()
+	case NATIVE_PARAM_TYPE_UINT256:
+		hash := common.Uint256{byte(n), 1, 2, 3}
+		v.Set(reflect.ValueOf(hash))
+		return hash.ToHexString()
+	case NATIVE_PARAM_TYPE_BIGINT:
+		value := new(big.Int).SetUint64(n << 40)
+		v.Set(reflect.ValueOf(value))
+		return value.String()
+	case NATIVE_PARAM_TYPE_ARRAY, NATIVE_PARAM_TYPE_ARRAY64:
+		items := reflect.MakeSlice(v.Type(), 2, 2)
+		values := make([]interface{}, 0, 2)
+		for i := 0; i < items.Len(); i++ {
+			values = append(values, this.fill(items.Index(i), param.SubType[0]))
+		}
+		v.Set(items)
+		return values
+	case NATIVE_PARAM_TYPE_MAP:
+		entries := reflect.MakeMap(v.Type())
+		values := make(map[string]interface{})
+		for i := 0; i < 3; i++ {
+			key := reflect.New(v.Type().Key()).Elem()
+			keyJson := this.fill(key, param.SubType[0])
+			value := reflect.New(v.Type().Elem()).Elem()
+			values[fmt.Sprint(keyJson)] = this.fill(value, param.SubType[1])
+			entries.SetMapIndex(key, value)
+		}
+		v.Set(entries)
+		return values
+	case NATIVE_PARAM_TYPE_STRUCT:
+		return this.fillStruct(v, param.SubType)
+	}
+	panic(fmt.Sprintf("unsupported type %s", param.Type))
+}
+
+func (this *sampleFiller) fillStruct(v reflect.Value, fields []*NativeContractParamAbi) map[string]interface{} {
+	values := make(map[string]interface{})
+	for _, field := range fields {
+		values[field.Name] = this.fill(v.FieldByName(field.Name), field)
+	}
+	return values
+}
+
+func serializeParam(t *testing.T, param interface{}) []byte {
+	sink := common.NewZeroCopySink(nil)
+	switch p := param.(type) {
+	case interface {
+		Serialization(*common.ZeroCopySink)
+	}:
+		p.Serialization(sink)
+	case interface {
+		Serialization(*common.ZeroCopySink) error
+	}:
+		assert.NoError(t, p.Serialization(sink))
+	default:
+		t.Fatalf("%T has no Serialization", param)
+	}
+	return sink.Bytes()
+}
+
+func TestNativeAbiEncodeArgs(t *testing.T) {
+	abis, err := GenerateNativeAbis()
+	assert.NoError(t, err)
+	for i, contract := range nativeContracts {
+		for j, method := range contract.methods {
+			funcAbi := abis[i].Functions[j]
+			if method.param == nil {
+				data, err := funcAbi.EncodeArgs(nil)
+				assert.NoError(t, err)
+				assert.Empty(t, data)
+				continue
+			}
+			param := reflect.New(reflect.TypeOf(method.param).Elem())
+			filler := new(sampleFiller)
+			values := filler.fillStruct(param.Elem(), funcAbi.Parameters)
+			args, err := json.Marshal(values)
+			assert.NoError(t, err)
+
+			data, err := funcAbi.EncodeArgs(args)
+			assert.NoError(t, err, "%s.%s", contract.name, method.name)
+			assert.Equal(t, serializeParam(t, param.Interface()), data, "%s.%s", contract.name, method.name)
+
+			// positional args
+			list := make([]interface{}, 0, len(funcAbi.Parameters))
+			for _, p := range funcAbi.Parameters {
+				list = append(list, values[p.Name])
+			}
+			args, err = json.Marshal(list)
+			assert.NoError(t, err)
+			positional, err := funcAbi.EncodeArgs(args)
+			assert.NoError(t, err)
+			assert.Equal(t, data, positional, "%s.%s", contract.name, method.name)
+		}
+	}
+}
+
+func TestNativeAbiEncodeArgsError(t *testing.T) {
+	funcAbi := &NativeContractFunctionAbi{
+		Name: "test",
+		Parameters: []*NativeContractParamAbi{
+			{Name: "ChainID", Type: NATIVE_PARAM_TYPE_INTEGER},
+			{Name: "Address", Type: NATIVE_PARAM_TYPE_ADDRESS},
+		},
+	}
+	_, err := funcAbi.EncodeArgs([]byte(`{"ChainID":1}`))
+	assert.Error(t, err)
+	_, err = funcAbi.EncodeArgs([]byte(`[1]`))
+	assert.Error(t, err)
+	_, err = funcAbi.EncodeArgs([]byte(`{"ChainID":-1,"Address":"0300000000000000000000000000000000000000"}`))
+	assert.Error(t, err)
+	_, err = funcAbi.EncodeArgs([]byte(`{"ChainID":1,"Address":"abc"}`))
+	assert.Error(t, err)
+	data, err := funcAbi.EncodeArgs([]byte(`{"ChainID":"2","Address":"0300000000000000000000000000000000000000"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, byte(2), data[0])
+}
+
+func TestNativeAbiFiles(t *testing.T) {
+	abis, err := GenerateNativeAbis()
+	assert.NoError(t, err)
+	files, err := filepath.Glob("native_abi_script/*.json")
+	assert.NoError(t, err)
+	assert.Equal(t, len(abis), len(files))
+	for _, nativeAbi := range abis {
+		expect, err := MarshalNativeAbi(nativeAbi)
+		assert.NoError(t, err)
+		data, err := ioutil.ReadFile(filepath.Join("native_abi_script", nativeAbi.Name+".json"))
+		assert.NoError(t, err)
+		assert.Equal(t, string(expect), string(data), "abi file of %s is out of date, run go generate", nativeAbi.Name)
+	}
+}
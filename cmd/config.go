@@ -33,12 +33,16 @@ func SetOntologyConfig(ctx *cli.Context) (*config.OntologyConfig, error) {
 	if err != nil {
 		return nil, fmt.Errorf("setGenesis error:%s", err)
 	}
-	setCommonConfig(ctx, cfg.Common)
+	err = setCommonConfig(ctx, cfg.Common)
+	if err != nil {
+		return nil, fmt.Errorf("setCommonConfig error:%s", err)
+	}
 	setConsensusConfig(ctx, cfg.Consensus)
 	setP2PNodeConfig(ctx, cfg.P2PNode)
 	setRpcConfig(ctx, cfg.Rpc)
 	setRestfulConfig(ctx, cfg.Restful)
 	setWebSocketConfig(ctx, cfg.Ws)
+	setMetricsConfig(ctx, cfg.Metrics)
 	if cfg.Genesis.ConsensusType == config.CONSENSUS_TYPE_SOLO {
 		cfg.Ws.EnableHttpWs = true
 		cfg.Restful.EnableHttpRestful = true
@@ -117,10 +121,19 @@ func setGenesis(ctx *cli.Context, cfg *config.OntologyConfig) error {
 	return nil
 }
 
-func setCommonConfig(ctx *cli.Context, cfg *config.CommonConfig) {
+func setCommonConfig(ctx *cli.Context, cfg *config.CommonConfig) error {
 	cfg.LogLevel = ctx.Uint(utils.GetFlagName(utils.LogLevelFlag))
 	cfg.EnableEventLog = !ctx.Bool(utils.GetFlagName(utils.DisableEventLogFlag))
 	cfg.DataDir = ctx.String(utils.GetFlagName(utils.DataDirFlag))
+	cfg.PruneBlocks = uint32(ctx.Uint(utils.GetFlagName(utils.PruneFlag)))
+	cfg.ArchiveMode = ctx.Bool(utils.GetFlagName(utils.ArchiveFlag))
+	if cfg.ArchiveMode && cfg.PruneBlocks != 0 {
+		return fmt.Errorf("--%s and --%s can not be used together", utils.GetFlagName(utils.PruneFlag), utils.GetFlagName(utils.ArchiveFlag))
+	}
+	if cfg.PruneBlocks != 0 && cfg.PruneBlocks < config.MIN_PRUNE_BLOCKS {
+		return fmt.Errorf("--%s should keep at least %d blocks", utils.GetFlagName(utils.PruneFlag), config.MIN_PRUNE_BLOCKS)
+	}
+	return nil
 }
 
 func setConsensusConfig(ctx *cli.Context, cfg *config.ConsensusConfig) {
@@ -179,6 +192,11 @@ func setWebSocketConfig(ctx *cli.Context, cfg *config.WebSocketConfig) {
 	cfg.HttpWsPort = ctx.Uint(utils.GetFlagName(utils.WsPortFlag))
 }
 
+func setMetricsConfig(ctx *cli.Context, cfg *config.MetricsConfig) {
+	cfg.EnableHttpMetrics = ctx.Bool(utils.GetFlagName(utils.MetricsEnableFlag))
+	cfg.HttpMetricsPort = ctx.Uint(utils.GetFlagName(utils.MetricsPortFlag))
+}
+
 func SetRpcPort(ctx *cli.Context) {
 	if ctx.IsSet(utils.GetFlagName(utils.RPCPortFlag)) {
 		config.DefConfig.Rpc.HttpJsonPort = ctx.Uint(utils.GetFlagName(utils.RPCPortFlag))
@@ -0,0 +1,614 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/account"
+	cmdcom "github.com/polynetwork/poly/cmd/common"
+	"github.com/polynetwork/poly/cmd/utils"
+	"github.com/polynetwork/poly/common"
+	httpcom "github.com/polynetwork/poly/http/base/common"
+	"github.com/polynetwork/poly/native/event"
+	ccom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/relayer_manager"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	nutils "github.com/polynetwork/poly/native/service/utils"
+	"github.com/urfave/cli"
+)
+
+//govMethod describe a governance method of native contract and how to build its params
+type govMethod struct {
+	name      string
+	usage     string
+	contract  common.Address
+	method    string
+	flags     []cli.Flag
+	buildArgs func(params *govParams, signer common.Address) ([]byte, error)
+}
+
+var govSignFlags = []cli.Flag{
+	utils.RPCPortFlag,
+	utils.WalletFileFlag,
+	utils.AccountAddressFlag,
+	utils.AccountPassFlag,
+	utils.AccountMultiMFlag,
+	utils.AccountMultiPubKeyFlag,
+	utils.GovSigsvrFlag,
+	utils.GovParamsFlag,
+	utils.GovWaitFlag,
+	utils.PrepareExecTransactionFlag,
+}
+
+var GovCommand = cli.Command{
+	Name:  "gov",
+	Usage: "Build, sign and send governance transactions",
+	Subcommands: []cli.Command{
+		{
+			Name:  "sidechain",
+			Usage: "Manage side chains",
+			Subcommands: govCommands([]*govMethod{
+				{"register", "Apply to register side chain", nutils.SideChainManagerContractAddress,
+					side_chain_manager.REGISTER_SIDE_CHAIN, govSideChainFlags, buildRegisterSideChainArgs},
+				{"approve-register", "Approve the side chain register apply", nutils.SideChainManagerContractAddress,
+					side_chain_manager.APPROVE_REGISTER_SIDE_CHAIN, []cli.Flag{utils.GovChainIDFlag}, buildChainIDArgs},
+				{"update", "Apply to update side chain", nutils.SideChainManagerContractAddress,
+					side_chain_manager.UPDATE_SIDE_CHAIN, govSideChainFlags, buildRegisterSideChainArgs},
+				{"approve-update", "Approve the side chain update apply", nutils.SideChainManagerContractAddress,
+					side_chain_manager.APPROVE_UPDATE_SIDE_CHAIN, []cli.Flag{utils.GovChainIDFlag}, buildChainIDArgs},
+				{"quit", "Apply to quit side chain", nutils.SideChainManagerContractAddress,
+					side_chain_manager.QUIT_SIDE_CHAIN, []cli.Flag{utils.GovChainIDFlag}, buildChainIDArgs},
+				{"approve-quit", "Approve the side chain quit apply", nutils.SideChainManagerContractAddress,
+					side_chain_manager.APPROVE_QUIT_SIDE_CHAIN, []cli.Flag{utils.GovChainIDFlag}, buildChainIDArgs},
+			}),
+		},
+		{
+			Name:  "relayer",
+			Usage: "Manage relayers",
+			Subcommands: govCommands([]*govMethod{
+				{"register", "Apply to register relayers", nutils.RelayerManagerContractAddress,
+					relayer_manager.REGISTER_RELAYER, []cli.Flag{utils.GovRelayersFlag}, buildRelayerListArgs},
+				{"approve-register", "Approve the relayers register apply", nutils.RelayerManagerContractAddress,
+					relayer_manager.APPROVE_REGISTER_RELAYER, []cli.Flag{utils.GovApplyIDFlag}, buildApproveRelayerArgs},
+				{"remove", "Apply to remove relayers", nutils.RelayerManagerContractAddress,
+					relayer_manager.REMOVE_RELAYER, []cli.Flag{utils.GovRelayersFlag}, buildRelayerListArgs},
+				{"approve-remove", "Approve the relayers remove apply", nutils.RelayerManagerContractAddress,
+					relayer_manager.APPROVE_REMOVE_RELAYER, []cli.Flag{utils.GovApplyIDFlag}, buildApproveRelayerArgs},
+			}),
+		},
+		{
+			Name:  "node",
+			Usage: "Manage consensus nodes",
+			Subcommands: govCommands([]*govMethod{
+				{"register-candidate", "Register as consensus candidate", nutils.NodeManagerContractAddress,
+					node_manager.REGISTER_CANDIDATE, []cli.Flag{utils.GovPeerPubkeyFlag}, buildRegisterPeerArgs},
+				{"unregister-candidate", "Cancel the candidate register", nutils.NodeManagerContractAddress,
+					node_manager.UNREGISTER_CANDIDATE, []cli.Flag{utils.GovPeerPubkeyFlag}, buildPeerArgs},
+				{"approve-candidate", "Approve the candidate", nutils.NodeManagerContractAddress,
+					node_manager.APPROVE_CANDIDATE, []cli.Flag{utils.GovPeerPubkeyFlag}, buildPeerArgs},
+				{"black", "Put consensus nodes into black list", nutils.NodeManagerContractAddress,
+					node_manager.BLACK_NODE, []cli.Flag{utils.GovPeerPubkeyFlag}, buildPeerListArgs},
+				{"white", "Remove consensus node from black list", nutils.NodeManagerContractAddress,
+					node_manager.WHITE_NODE, []cli.Flag{utils.GovPeerPubkeyFlag}, buildPeerArgs},
+				{"quit", "Quit consensus node", nutils.NodeManagerContractAddress,
+					node_manager.QUIT_NODE, []cli.Flag{utils.GovPeerPubkeyFlag}, buildPeerArgs},
+				{"update-config", "Update vbft config", nutils.NodeManagerContractAddress,
+					node_manager.UPDATE_CONFIG, govConfigFlags, buildUpdateConfigArgs},
+			}),
+		},
+		{
+			Name:  "chain",
+			Usage: "Manage cross chain black list",
+			Subcommands: govCommands([]*govMethod{
+				{"black", "Put side chain into black list", nutils.CrossChainManagerContractAddress,
+					ccom.BLACK_CHAIN, []cli.Flag{utils.GovChainIDFlag}, buildBlackChainArgs},
+				{"white", "Remove side chain from black list", nutils.CrossChainManagerContractAddress,
+					ccom.WHITE_CHAIN, []cli.Flag{utils.GovChainIDFlag}, buildBlackChainArgs},
+			}),
+		},
+	},
+	Description: "Governance transactions are signed by local wallet account or the account unlocked in sigsvr, " +
+		"and the operator transactions are signed by one of the multi-signature accounts.",
+}
+
+var govSideChainFlags = []cli.Flag{
+	utils.GovChainIDFlag,
+	utils.GovRouterFlag,
+	utils.GovChainNameFlag,
+	utils.GovBlocksToWaitFlag,
+	utils.GovCCMCAddressFlag,
+	utils.GovExtraInfoFlag,
+}
+
+var govConfigFlags = []cli.Flag{
+	utils.GovBlockMsgDelayFlag,
+	utils.GovHashMsgDelayFlag,
+	utils.GovPeerHandshakeTimeoutFlag,
+	utils.GovMaxBlockChangeViewFlag,
+	utils.GovMakeProposalTimeoutFlag,
+	utils.GovEndorseBlockTimeoutFlag,
+	utils.GovCommitBlockTimeoutFlag,
+	utils.GovZeroTxBlockTimeoutFlag,
+	utils.GovTxPoolTimeoutFlag,
+	utils.GovMaxTimeoutBackoffFlag,
+}
+
+func govCommands(methods []*govMethod) []cli.Command {
+	cmds := make([]cli.Command, 0, len(methods))
+	for _, m := range methods {
+		method := m
+		cmds = append(cmds, cli.Command{
+			Name:  method.name,
+			Usage: method.usage,
+			Action: func(ctx *cli.Context) error {
+				return sendGovTx(ctx, method)
+			},
+			Flags:       append(append([]cli.Flag{}, method.flags...), govSignFlags...),
+			Description: fmt.Sprintf("%s, invoke method %s of contract %s", method.usage, method.method, method.contract.ToHexString()),
+		})
+	}
+	return cmds
+}
+
+//govParams read the params of governance method from command line flags and the json of params flag
+type govParams struct {
+	ctx    *cli.Context
+	values map[string]json.RawMessage
+}
+
+func newGovParams(ctx *cli.Context) (*govParams, error) {
+	params := &govParams{ctx: ctx, values: make(map[string]json.RawMessage)}
+	data, err := readJsonFlag(ctx, utils.GovParamsFlag)
+	if err != nil || data == nil {
+		return params, err
+	}
+	if err := json.Unmarshal(data, &params.values); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal params error:%s", err)
+	}
+	return params, nil
+}
+
+//readJsonFlag return the json of flag, which is either inline json or the file contains it
+func readJsonFlag(ctx *cli.Context, flag cli.Flag) ([]byte, error) {
+	raw := strings.TrimSpace(ctx.String(utils.GetFlagName(flag)))
+	if raw == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(raw, "{") || strings.HasPrefix(raw, "[") {
+		return []byte(raw), nil
+	}
+	data, err := ioutil.ReadFile(raw)
+	if err != nil {
+		return nil, fmt.Errorf("read %s file:%s error:%s", utils.GetFlagName(flag), raw, err)
+	}
+	return data, nil
+}
+
+func (this *govParams) get(flag cli.Flag, value interface{}) (bool, error) {
+	name := utils.GetFlagName(flag)
+	if this.ctx.IsSet(name) {
+		switch v := value.(type) {
+		case *string:
+			*v = this.ctx.String(name)
+		case *uint64:
+			*v = this.ctx.Uint64(name)
+		case *uint32:
+			*v = uint32(this.ctx.Uint(name))
+		default:
+			return false, fmt.Errorf("unsupported type %T of flag %s", value, name)
+		}
+		return true, nil
+	}
+	raw, ok := this.values[name]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, value); err != nil {
+		return false, fmt.Errorf("invalid %s in params:%s", name, err)
+	}
+	return true, nil
+}
+
+func (this *govParams) require(flag cli.Flag, value interface{}) error {
+	ok, err := this.get(flag, value)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("missing %s argument", utils.GetFlagName(flag))
+	}
+	return nil
+}
+
+func (this *govParams) hex(flag cli.Flag) ([]byte, error) {
+	var value string
+	if _, err := this.get(flag, &value); err != nil {
+		return nil, err
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex %s:%s", utils.GetFlagName(flag), err)
+	}
+	return data, nil
+}
+
+func (this *govParams) list(flag cli.Flag) ([]string, error) {
+	var value string
+	if err := this.require(flag, &value); err != nil {
+		return nil, err
+	}
+	list := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("empty %s argument", utils.GetFlagName(flag))
+	}
+	return list, nil
+}
+
+func buildRegisterSideChainArgs(params *govParams, signer common.Address) ([]byte, error) {
+	param := &side_chain_manager.RegisterSideChainParam{Address: signer}
+	var err error
+	if err = params.require(utils.GovChainIDFlag, &param.ChainId); err != nil {
+		return nil, err
+	}
+	if err = params.require(utils.GovRouterFlag, &param.Router); err != nil {
+		return nil, err
+	}
+	if err = params.require(utils.GovChainNameFlag, &param.Name); err != nil {
+		return nil, err
+	}
+	if err = params.require(utils.GovBlocksToWaitFlag, &param.BlocksToWait); err != nil {
+		return nil, err
+	}
+	if param.CCMCAddress, err = params.hex(utils.GovCCMCAddressFlag); err != nil {
+		return nil, err
+	}
+	if param.ExtraInfo, err = params.hex(utils.GovExtraInfoFlag); err != nil {
+		return nil, err
+	}
+	sink := common.NewZeroCopySink(nil)
+	if err = param.Serialization(sink); err != nil {
+		return nil, err
+	}
+	return sink.Bytes(), nil
+}
+
+func buildChainIDArgs(params *govParams, signer common.Address) ([]byte, error) {
+	param := &side_chain_manager.ChainidParam{Address: signer}
+	if err := params.require(utils.GovChainIDFlag, &param.Chainid); err != nil {
+		return nil, err
+	}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+func buildRelayerListArgs(params *govParams, signer common.Address) ([]byte, error) {
+	relayers, err := params.list(utils.GovRelayersFlag)
+	if err != nil {
+		return nil, err
+	}
+	param := &relayer_manager.RelayerListParam{Address: signer}
+	for _, relayer := range relayers {
+		address, err := common.AddressFromBase58(relayer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid relayer address:%s", relayer)
+		}
+		param.AddressList = append(param.AddressList, address)
+	}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+func buildApproveRelayerArgs(params *govParams, signer common.Address) ([]byte, error) {
+	param := &relayer_manager.ApproveRelayerParam{Address: signer}
+	if err := params.require(utils.GovApplyIDFlag, &param.ID); err != nil {
+		return nil, err
+	}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+func buildRegisterPeerArgs(params *govParams, signer common.Address) ([]byte, error) {
+	param := &node_manager.RegisterPeerParam{Address: signer}
+	if err := params.require(utils.GovPeerPubkeyFlag, &param.PeerPubkey); err != nil {
+		return nil, err
+	}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+func buildPeerArgs(params *govParams, signer common.Address) ([]byte, error) {
+	param := &node_manager.PeerParam{Address: signer}
+	if err := params.require(utils.GovPeerPubkeyFlag, &param.PeerPubkey); err != nil {
+		return nil, err
+	}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+func buildPeerListArgs(params *govParams, signer common.Address) ([]byte, error) {
+	peers, err := params.list(utils.GovPeerPubkeyFlag)
+	if err != nil {
+		return nil, err
+	}
+	param := &node_manager.PeerListParam{PeerPubkeyList: peers, Address: signer}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+func buildUpdateConfigArgs(params *govParams, signer common.Address) ([]byte, error) {
+	config := new(node_manager.Configuration)
+	if err := params.require(utils.GovBlockMsgDelayFlag, &config.BlockMsgDelay); err != nil {
+		return nil, err
+	}
+	if err := params.require(utils.GovHashMsgDelayFlag, &config.HashMsgDelay); err != nil {
+		return nil, err
+	}
+	if err := params.require(utils.GovPeerHandshakeTimeoutFlag, &config.PeerHandshakeTimeout); err != nil {
+		return nil, err
+	}
+	if err := params.require(utils.GovMaxBlockChangeViewFlag, &config.MaxBlockChangeView); err != nil {
+		return nil, err
+	}
+	//the optional timeouts left unset are derived from the msg delays
+	if _, err := params.get(utils.GovMakeProposalTimeoutFlag, &config.MakeProposalTimeout); err != nil {
+		return nil, err
+	}
+	if _, err := params.get(utils.GovEndorseBlockTimeoutFlag, &config.EndorseBlockTimeout); err != nil {
+		return nil, err
+	}
+	if _, err := params.get(utils.GovCommitBlockTimeoutFlag, &config.CommitBlockTimeout); err != nil {
+		return nil, err
+	}
+	if _, err := params.get(utils.GovZeroTxBlockTimeoutFlag, &config.ZeroTxBlockTimeout); err != nil {
+		return nil, err
+	}
+	if _, err := params.get(utils.GovTxPoolTimeoutFlag, &config.TxPoolTimeout); err != nil {
+		return nil, err
+	}
+	if _, err := params.get(utils.GovMaxTimeoutBackoffFlag, &config.MaxTimeoutBackoff); err != nil {
+		return nil, err
+	}
+	param := &node_manager.UpdateConfigParam{Configuration: config}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+func buildBlackChainArgs(params *govParams, signer common.Address) ([]byte, error) {
+	param := new(ccom.BlackChainParam)
+	if err := params.require(utils.GovChainIDFlag, &param.ChainID); err != nil {
+		return nil, err
+	}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+func sendGovTx(ctx *cli.Context, method *govMethod) error {
+	params, err := newGovParams(ctx)
+	if err != nil {
+		return err
+	}
+	return sendNativeInvokeTx(ctx, method.contract, method.method, func(signer common.Address) ([]byte, error) {
+		return method.buildArgs(params, signer)
+	})
+}
+
+//sendNativeInvokeTx sign the native invoke transaction by local wallet, sigsvr or multi-signature accounts,
+//then send it and wait for the events
+func sendNativeInvokeTx(ctx *cli.Context, contract common.Address, method string,
+	buildArgs func(signer common.Address) ([]byte, error)) error {
+	SetRpcPort(ctx)
+	var err error
+	sigsvr := ctx.String(utils.GetFlagName(utils.GovSigsvrFlag))
+	multiPubKeys := strings.TrimSpace(strings.Trim(ctx.String(utils.GetFlagName(utils.AccountMultiPubKeyFlag)), ","))
+	if sigsvr != "" && multiPubKeys != "" {
+		return fmt.Errorf("%s cannot be used with %s", utils.GovSigsvrFlag.Name, utils.AccountMultiPubKeyFlag.Name)
+	}
+
+	var signer common.Address
+	var acc *account.Account
+	if sigsvr != "" {
+		accAddr := ctx.String(utils.GetFlagName(utils.AccountAddressFlag))
+		if signer, err = common.AddressFromBase58(accAddr); err != nil {
+			return fmt.Errorf("invalid %s:%s, base58 address is required by sigsvr", utils.AccountAddressFlag.Name, accAddr)
+		}
+	} else {
+		if acc, err = cmdcom.GetAccount(ctx); err != nil {
+			return fmt.Errorf("GetAccount error:%s", err)
+		}
+		signer = acc.Address
+	}
+
+	args, err := buildArgs(signer)
+	if err != nil {
+		return err
+	}
+	tx, err := utils.NewNativeInvokeTransaction(contract, method, args)
+	if err != nil {
+		return fmt.Errorf("NewNativeInvokeTransaction error:%s", err)
+	}
+
+	var rawTx string
+	multiSigPending := false
+	switch {
+	case sigsvr != "":
+		passwd, err := cmdcom.GetPasswd(ctx)
+		if err != nil {
+			return err
+		}
+		rawTx, err = utils.SigTxBySigsvr(sigsvr, signer.ToBase58(), string(passwd), hex.EncodeToString(tx.Raw))
+		cmdcom.ClearPasswd(passwd)
+		if err != nil {
+			return fmt.Errorf("SigTxBySigsvr error:%s", err)
+		}
+	case multiPubKeys != "":
+		pubKeys, err := parseGovPubKeys(multiPubKeys)
+		if err != nil {
+			return err
+		}
+		m := uint16(ctx.Uint(utils.GetFlagName(utils.AccountMultiMFlag)))
+		if err = utils.MultiSigTransaction(tx, m, pubKeys, acc); err != nil {
+			return fmt.Errorf("MultiSigTransaction error:%s", err)
+		}
+		multiSigPending = m > 1
+	default:
+		if err = utils.SignTransaction(acc, tx); err != nil {
+			return fmt.Errorf("SignTransaction error:%s", err)
+		}
+	}
+	if rawTx == "" {
+		sink := common.NewZeroCopySink(nil)
+		if err = tx.Serialization(sink); err != nil {
+			return fmt.Errorf("tx serialization error:%s", err)
+		}
+		rawTx = hex.EncodeToString(sink.Bytes())
+	}
+
+	if multiSigPending {
+		PrintInfoMsg("RawTx signed by %s:", signer.ToBase58())
+		PrintInfoMsg(rawTx)
+		PrintInfoMsg("\nTip:")
+		PrintInfoMsg("  Using './poly multisigtx --send <rawtx>' to collect the other signatures and send.")
+		return nil
+	}
+
+	if ctx.IsSet(utils.GetFlagName(utils.PrepareExecTransactionFlag)) {
+		preResult, err := utils.PrepareSendRawTransaction(rawTx)
+		if err != nil {
+			return err
+		}
+		if preResult.State == 0 {
+			return fmt.Errorf("prepare execute transaction failed. %v", preResult)
+		}
+		PrintInfoMsg("Prepare execute transaction success.")
+		PrintInfoMsg("Result:%v", preResult.Result)
+		printGovNotify(httpcom.ConvertPreExecuteResult(preResult).Notify)
+		return nil
+	}
+
+	txHash, err := utils.SendRawTransactionData(rawTx)
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("Send transaction success.")
+	PrintInfoMsg("  TxHash:%s", txHash)
+
+	wait := ctx.Uint(utils.GetFlagName(utils.GovWaitFlag))
+	if wait == 0 {
+		PrintInfoMsg("\nTip:")
+		PrintInfoMsg("  Using './poly info status %s' to query transaction status.", txHash)
+		return nil
+	}
+	notify, err := waitGovTx(txHash, time.Duration(wait)*time.Second)
+	if err != nil {
+		return err
+	}
+	if notify.State == event.CONTRACT_STATE_FAIL {
+		return fmt.Errorf("transaction %s execute failed", txHash)
+	}
+	PrintInfoMsg("Transaction executed, gas consumed:%d", notify.GasConsumed)
+	printGovNotify(notify.Notify)
+	return nil
+}
+
+func parseGovPubKeys(pkstr string) ([]keypair.PublicKey, error) {
+	pubKeys := make([]keypair.PublicKey, 0)
+	for _, pk := range strings.Split(pkstr, ",") {
+		pk = strings.TrimSpace(pk)
+		if pk == "" {
+			continue
+		}
+		data, err := hex.DecodeString(pk)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pub key:%s", pk)
+		}
+		pubKey, err := keypair.DeserializePublicKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pub key:%s", pk)
+		}
+		pubKeys = append(pubKeys, pubKey)
+	}
+	return pubKeys, nil
+}
+
+func waitGovTx(txHash string, timeout time.Duration) (*httpcom.ExecuteNotify, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		data, err := utils.GetSmartContractEventInfo(txHash)
+		if err != nil {
+			return nil, fmt.Errorf("GetSmartContractEventInfo error:%s", err)
+		}
+		if len(data) > 0 && string(data) != "null" {
+			notify := new(httpcom.ExecuteNotify)
+			if err = json.Unmarshal(data, notify); err != nil {
+				return nil, fmt.Errorf("json.Unmarshal ExecuteNotify error:%s", err)
+			}
+			return notify, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("transaction %s is not executed in %s", txHash, timeout)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+//printGovNotify print the events with field names described by the event schemas
+func printGovNotify(notifies []httpcom.NotifyEventInfo) {
+	for _, notify := range notifies {
+		states, ok := notify.States.([]interface{})
+		contract, err := common.AddressFromHexString(notify.ContractAddress)
+		var schema *event.EventSchema
+		if err == nil {
+			schema = event.GetEventSchema(contract, notify.EventName)
+		}
+		if !ok || schema == nil {
+			PrintInfoMsg("Event of contract %s: %v", notify.ContractAddress, notify.States)
+			continue
+		}
+		if !schema.Anonymous && len(states) > 0 {
+			states = states[1:]
+		}
+		PrintInfoMsg("Event %s of contract %s:", schema.Name, notify.ContractAddress)
+		for i, field := range schema.Fields {
+			if i < len(states) {
+				PrintInfoMsg("  %s: %v", field.Name, states[i])
+			}
+		}
+	}
+}
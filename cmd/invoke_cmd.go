@@ -0,0 +1,107 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/polynetwork/poly/cmd/abi"
+	"github.com/polynetwork/poly/cmd/utils"
+	"github.com/polynetwork/poly/common"
+	"github.com/urfave/cli"
+)
+
+var InvokeCommand = cli.Command{
+	Name:      "invoke",
+	Usage:     "Invoke native contract method with args encoded by abi",
+	ArgsUsage: "<contract> [<method>]",
+	Action:    invokeNativeContract,
+	Flags: append([]cli.Flag{
+		utils.InvokeArgsFlag,
+		utils.CliABIPathFlag,
+	}, govSignFlags...),
+	Description: "Contract could be the name of native contract abi, such as side_chain_manager, or contract address in hex. " +
+		"Methods of the contract and their params are listed if method is omitted. " +
+		"Abi files in abi path override the builtin abi generated from native contract params.",
+}
+
+func invokeNativeContract(ctx *cli.Context) error {
+	if ctx.NArg() < 1 {
+		PrintErrorMsg("Missing contract argument.")
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	abiMgr := abi.NewAbiMgr()
+	abiPath := ""
+	if ctx.IsSet(utils.GetFlagName(utils.CliABIPathFlag)) {
+		abiPath = ctx.String(utils.GetFlagName(utils.CliABIPathFlag))
+	}
+	abiMgr.Init(abiPath)
+	contractAbi := abiMgr.GetNativeAbiByName(ctx.Args().First())
+	if contractAbi == nil {
+		return fmt.Errorf("abi of contract %s not found", ctx.Args().First())
+	}
+	if ctx.NArg() < 2 {
+		printNativeAbi(contractAbi)
+		return nil
+	}
+	funcAbi := contractAbi.GetFunc(ctx.Args().Get(1))
+	if funcAbi == nil {
+		return fmt.Errorf("method %s not found in abi of contract %s", ctx.Args().Get(1), ctx.Args().First())
+	}
+	contract, err := common.AddressFromHexString(contractAbi.Address)
+	if err != nil {
+		return fmt.Errorf("invalid contract address %s in abi", contractAbi.Address)
+	}
+	args, err := readJsonFlag(ctx, utils.InvokeArgsFlag)
+	if err != nil {
+		return err
+	}
+	input, err := funcAbi.EncodeArgs(args)
+	if err != nil {
+		return err
+	}
+	return sendNativeInvokeTx(ctx, contract, funcAbi.Name, func(signer common.Address) ([]byte, error) {
+		return input, nil
+	})
+}
+
+func printNativeAbi(contractAbi *abi.NativeContractAbi) {
+	PrintInfoMsg("Contract %s address:%s", contractAbi.Name, contractAbi.Address)
+	PrintInfoMsg("Methods:")
+	for _, funcAbi := range contractAbi.Functions {
+		PrintInfoMsg("  %s(%s)", funcAbi.Name, formatAbiParams(funcAbi.Parameters))
+	}
+}
+
+func formatAbiParams(params []*abi.NativeContractParamAbi) string {
+	items := make([]string, 0, len(params))
+	for _, param := range params {
+		typ := param.Type
+		if len(param.SubType) > 0 {
+			typ = fmt.Sprintf("%s<%s>", typ, formatAbiParams(param.SubType))
+		}
+		if param.Name != "" {
+			typ = param.Name + " " + typ
+		}
+		items = append(items, typ)
+	}
+	return strings.Join(items, ", ")
+}
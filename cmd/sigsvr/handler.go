@@ -24,4 +24,6 @@ func init() {
 	DefCliRpcSvr.RegHandler("createaccount", handlers.CreateAccount)
 	DefCliRpcSvr.RegHandler("exportaccount", handlers.ExportAccount)
 	DefCliRpcSvr.RegHandler("sigdata", handlers.SigData)
+	DefCliRpcSvr.RegHandler("sigrawtx", handlers.SigRawTransaction)
+	DefCliRpcSvr.RegHandler("signativeinvoketx", handlers.SigNativeInvokeTx)
 }
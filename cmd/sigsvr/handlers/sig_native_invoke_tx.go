@@ -0,0 +1,98 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"github.com/polynetwork/poly/cmd/abi"
+	clisvrcom "github.com/polynetwork/poly/cmd/sigsvr/common"
+	cliutil "github.com/polynetwork/poly/cmd/utils"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/log"
+)
+
+type SigNativeInvokeTxReq struct {
+	Contract string          `json:"contract"` // contract name or address in hex
+	Method   string          `json:"method"`
+	Args     json.RawMessage `json:"args"`
+}
+
+type SigNativeInvokeTxRsp struct {
+	SignedTx string `json:"signed_tx"`
+}
+
+func SigNativeInvokeTx(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
+	rawReq := &SigNativeInvokeTxReq{}
+	err := json.Unmarshal(req.Params, rawReq)
+	if err != nil {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	contractAbi := abi.DefAbiMgr.GetNativeAbiByName(rawReq.Contract)
+	if contractAbi == nil {
+		resp.ErrorCode = clisvrcom.CLIERR_ABI_NOT_FOUND
+		return
+	}
+	funcAbi := contractAbi.GetFunc(rawReq.Method)
+	if funcAbi == nil {
+		resp.ErrorCode = clisvrcom.CLIERR_ABI_NOT_FOUND
+		return
+	}
+	input, err := funcAbi.EncodeArgs(rawReq.Args)
+	if err != nil {
+		log.Infof("Cli Qid:%s SigNativeInvokeTx EncodeArgs error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_ABI_UNMATCH
+		resp.ErrorInfo = err.Error()
+		return
+	}
+	contract, err := common.AddressFromHexString(contractAbi.Address)
+	if err != nil {
+		log.Infof("Cli Qid:%s SigNativeInvokeTx invalid contract address:%s", req.Qid, contractAbi.Address)
+		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+		return
+	}
+	tx, err := cliutil.NewNativeInvokeTransaction(contract, funcAbi.Name, input)
+	if err != nil {
+		log.Infof("Cli Qid:%s SigNativeInvokeTx NewNativeInvokeTransaction error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+		return
+	}
+	signer, err := req.GetAccount()
+	if err != nil {
+		log.Infof("Cli Qid:%s SigNativeInvokeTx GetAccount:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_ACCOUNT_UNLOCK
+		return
+	}
+	err = cliutil.SignTransaction(signer, tx)
+	if err != nil {
+		log.Infof("Cli Qid:%s SigNativeInvokeTx SignTransaction error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+		return
+	}
+	sink := common.NewZeroCopySink(nil)
+	err = tx.Serialization(sink)
+	if err != nil {
+		log.Infof("Cli Qid:%s SigNativeInvokeTx tx Serialization error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_TX
+		return
+	}
+	resp.Result = &SigNativeInvokeTxRsp{
+		SignedTx: hex.EncodeToString(sink.Bytes()),
+	}
+}
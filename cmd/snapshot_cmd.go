@@ -0,0 +1,168 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"compress/zlib"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/polynetwork/poly/cmd/utils"
+	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/core/genesis"
+	"github.com/polynetwork/poly/core/ledger"
+	"github.com/urfave/cli"
+)
+
+var ExportSnapshotCommand = cli.Command{
+	Name:      "export-snapshot",
+	Usage:     "Export state snapshot at current block height to a file",
+	ArgsUsage: "",
+	Action:    exportSnapshot,
+	Flags: []cli.Flag{
+		utils.SnapshotFileFlag,
+		utils.DataDirFlag,
+		utils.ConfigFlag,
+		utils.NetworkIdFlag,
+	},
+	Description: "Note that node should be stopped before exporting snapshot. The printed digest should be published along with the snapshot file",
+}
+
+var ImportSnapshotCommand = cli.Command{
+	Name:      "import-snapshot",
+	Usage:     "Bootstrap an empty DB from a state snapshot file",
+	ArgsUsage: "",
+	Action:    importSnapshot,
+	Flags: []cli.Flag{
+		utils.SnapshotFileFlag,
+		utils.SnapshotDigestFlag,
+		utils.DataDirFlag,
+		utils.ConfigFlag,
+		utils.NetworkIdFlag,
+		utils.ArchiveFlag,
+	},
+	Description: "Headers are verified from genesis, but state is not committed to by any signed header and is only trusted by the snapshot digest, " +
+		"which must be obtained from a trusted source instead of along with the snapshot file. The DB is removed if importing fails",
+}
+
+func initSnapshotLedger(ctx *cli.Context) (string, error) {
+	log.InitLog(log.InfoLog)
+	cfg, err := SetOntologyConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	dbDir := utils.GetStoreDirPath(cfg.Common.DataDir, cfg.P2PNode.NetworkName)
+	ledger.DefLedger, err = ledger.NewLedger(dbDir)
+	if err != nil {
+		return "", fmt.Errorf("NewLedger error:%s", err)
+	}
+	bookKeepers, err := cfg.GetBookkeepers()
+	if err != nil {
+		return "", fmt.Errorf("GetBookkeepers error:%s", err)
+	}
+	genesisBlock, err := genesis.BuildGenesisBlock(bookKeepers, cfg.Genesis)
+	if err != nil {
+		return "", fmt.Errorf("BuildGenesisBlock error %s", err)
+	}
+	err = ledger.DefLedger.Init(bookKeepers, genesisBlock)
+	if err != nil {
+		return "", fmt.Errorf("init ledger error:%s", err)
+	}
+	return dbDir, nil
+}
+
+func exportSnapshot(ctx *cli.Context) error {
+	snapshotFile := ctx.String(utils.GetFlagName(utils.SnapshotFileFlag))
+	if snapshotFile == "" {
+		PrintErrorMsg("Missing %s argument.", utils.SnapshotFileFlag.Name)
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	if _, err := initSnapshotLedger(ctx); err != nil {
+		return err
+	}
+	defer ledger.DefLedger.Close()
+
+	sf, err := os.OpenFile(snapshotFile, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0664)
+	if err != nil {
+		return fmt.Errorf("open file:%s error:%s", snapshotFile, err)
+	}
+	defer sf.Close()
+	fWriter := bufio.NewWriter(sf)
+	zWriter := zlib.NewWriter(fWriter)
+
+	PrintInfoMsg("Start export snapshot.")
+	height, digest, err := ledger.DefLedger.ExportSnapshot(zWriter)
+	if err != nil {
+		return fmt.Errorf("ExportSnapshot error:%s", err)
+	}
+	if err = zWriter.Close(); err != nil {
+		return fmt.Errorf("compress snapshot error:%s", err)
+	}
+	if err = fWriter.Flush(); err != nil {
+		return fmt.Errorf("write snapshot file error:%s", err)
+	}
+	PrintInfoMsg("Export snapshot at height:%d to file:%s completed, digest:%s.", height, snapshotFile, hex.EncodeToString(digest))
+	return nil
+}
+
+func importSnapshot(ctx *cli.Context) error {
+	snapshotFile := ctx.String(utils.GetFlagName(utils.SnapshotFileFlag))
+	if snapshotFile == "" {
+		PrintErrorMsg("Missing %s argument.", utils.SnapshotFileFlag.Name)
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	digest, err := hex.DecodeString(ctx.String(utils.GetFlagName(utils.SnapshotDigestFlag)))
+	if err != nil || len(digest) != sha256.Size {
+		PrintErrorMsg("Missing or invalid %s argument.", utils.SnapshotDigestFlag.Name)
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+
+	sf, err := os.OpenFile(snapshotFile, os.O_RDONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("OpenFile error:%s", err)
+	}
+	defer sf.Close()
+	zReader, err := zlib.NewReader(bufio.NewReader(sf))
+	if err != nil {
+		return fmt.Errorf("snapshot file decompress error:%s", err)
+	}
+	defer zReader.Close()
+
+	dbDir, err := initSnapshotLedger(ctx)
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("Start import snapshot.")
+	height, err := ledger.DefLedger.ImportSnapshot(zReader, digest)
+	ledger.DefLedger.Close()
+	if err != nil {
+		//the state is written before it is verified, so the untrusted DB must not be kept
+		if e := os.RemoveAll(dbDir); e != nil {
+			return fmt.Errorf("ImportSnapshot error:%s, remove DB:%s error:%s", err, dbDir, e)
+		}
+		return fmt.Errorf("ImportSnapshot error:%s, DB:%s is removed", err, dbDir)
+	}
+	PrintInfoMsg("Import snapshot completed, current block height:%d.", height)
+	return nil
+}
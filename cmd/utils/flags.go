@@ -27,9 +27,12 @@ import (
 
 const (
 	DEFAULT_EXPORT_FILE   = "./OntBlocks.dat"
+	DEFAULT_SNAPSHOT_FILE = "./PolySnapshot.dat"
 	DEFAULT_ABI_PATH      = "./abi"
 	DEFAULT_EXPORT_HEIGHT = 0
 	DEFAULT_WALLET_PATH   = "./wallet_data"
+
+	DEFAULT_GOV_WAIT_SECONDS = 60
 )
 
 var (
@@ -47,6 +50,14 @@ var (
 		Name:  "disable-event-log",
 		Usage: "Discard event log output by smart contract execution",
 	}
+	PruneFlag = cli.UintFlag{
+		Name:  "prune",
+		Usage: "Keep transactions and events of the latest `<number>` blocks only. Headers and cross states roots are always kept. 0 keeps all",
+	}
+	ArchiveFlag = cli.BoolFlag{
+		Name:  "archive",
+		Usage: "Run as archive node, which keeps all blocks, events and historical states",
+	}
 	WalletFileFlag = cli.StringFlag{
 		Name:  "wallet,w",
 		Value: config.DEFAULT_WALLET_FILE_NAME,
@@ -62,6 +73,15 @@ var (
 		Usage: "Stop import block `<height>` of the import.",
 		Value: DEFAULT_EXPORT_HEIGHT,
 	}
+	SnapshotFileFlag = cli.StringFlag{
+		Name:  "snapshot-file",
+		Usage: "Path of state snapshot `<file>`",
+		Value: DEFAULT_SNAPSHOT_FILE,
+	}
+	SnapshotDigestFlag = cli.StringFlag{
+		Name:  "snapshot-digest",
+		Usage: "Trusted hex `<digest>` of the state snapshot, which is printed by export-snapshot",
+	}
 	DataDirFlag = cli.StringFlag{
 		Name:  "data-dir",
 		Usage: "Block data storage `<path>`",
@@ -170,6 +190,17 @@ var (
 		Value: config.DEFAULT_WS_PORT,
 	}
 
+	//Metrics setting
+	MetricsEnableFlag = cli.BoolFlag{
+		Name:  "metrics",
+		Usage: "Enable prometheus metrics server",
+	}
+	MetricsPortFlag = cli.UintFlag{
+		Name:  "metricsport",
+		Usage: "Metrics server listening port `<number>`",
+		Value: config.DEFAULT_METRICS_PORT,
+	}
+
 	//Restful setting
 	RestfulEnableFlag = cli.BoolFlag{
 		Name:  "rest",
@@ -434,6 +465,101 @@ var (
 		Value: "m",
 	}
 
+	//Governance setting
+	GovSigsvrFlag = cli.StringFlag{
+		Name:  "sigsvr",
+		Usage: "Sign transaction by the sigsvr at `<url>` instead of local wallet, e.g. http://127.0.0.1:20000/cli",
+	}
+	GovParamsFlag = cli.StringFlag{
+		Name:  "params",
+		Usage: "Params in json `<string|file>`, whose keys are the flag names of command. Flags set in command line take precedence",
+	}
+	InvokeArgsFlag = cli.StringFlag{
+		Name:  "args",
+		Usage: "Method args in json `<string|file>`, either object keyed by param names or array in param order",
+	}
+	GovWaitFlag = cli.UintFlag{
+		Name:  "wait",
+		Usage: "Wait at most `<seconds>` for the transaction to be executed and print the events, 0 means not to wait",
+		Value: DEFAULT_GOV_WAIT_SECONDS,
+	}
+	GovChainIDFlag = cli.Uint64Flag{
+		Name:  "chain-id",
+		Usage: "Side chain `<id>`",
+	}
+	GovRouterFlag = cli.Uint64Flag{
+		Name:  "router",
+		Usage: "Router `<number>` of side chain",
+	}
+	GovChainNameFlag = cli.StringFlag{
+		Name:  "chain-name",
+		Usage: "Side chain `<name>`",
+	}
+	GovBlocksToWaitFlag = cli.Uint64Flag{
+		Name:  "blocks-to-wait",
+		Usage: "Confirmation `<blocks>` of side chain",
+	}
+	GovCCMCAddressFlag = cli.StringFlag{
+		Name:  "ccmc-address",
+		Usage: "Cross chain manager contract `<address>` on side chain in hex",
+	}
+	GovExtraInfoFlag = cli.StringFlag{
+		Name:  "extra-info",
+		Usage: "Extra `<info>` of side chain in hex",
+	}
+	GovApplyIDFlag = cli.Uint64Flag{
+		Name:  "id",
+		Usage: "Apply `<id>` to approve",
+	}
+	GovRelayersFlag = cli.StringFlag{
+		Name:  "relayers",
+		Usage: "Relayer `<addresses>` in base58, separated by ','",
+	}
+	GovPeerPubkeyFlag = cli.StringFlag{
+		Name:  "peer-pubkey",
+		Usage: "Consensus peer `<pubkeys>` in hex, separated by ',' if more than one is accepted",
+	}
+	GovBlockMsgDelayFlag = cli.UintFlag{
+		Name:  "block-msg-delay",
+		Usage: "Vbft block msg delay in `<milliseconds>`",
+	}
+	GovHashMsgDelayFlag = cli.UintFlag{
+		Name:  "hash-msg-delay",
+		Usage: "Vbft hash msg delay in `<milliseconds>`",
+	}
+	GovPeerHandshakeTimeoutFlag = cli.UintFlag{
+		Name:  "peer-handshake-timeout",
+		Usage: "Vbft peer handshake timeout in `<seconds>`",
+	}
+	GovMaxBlockChangeViewFlag = cli.UintFlag{
+		Name:  "max-block-change-view",
+		Usage: "Vbft max block change `<view>`",
+	}
+	GovMakeProposalTimeoutFlag = cli.UintFlag{
+		Name:  "make-proposal-timeout",
+		Usage: "Vbft make proposal timeout in `<milliseconds>`, derived from block msg delay if not set",
+	}
+	GovEndorseBlockTimeoutFlag = cli.UintFlag{
+		Name:  "endorse-block-timeout",
+		Usage: "Vbft endorse block timeout in `<milliseconds>`, derived from hash msg delay if not set",
+	}
+	GovCommitBlockTimeoutFlag = cli.UintFlag{
+		Name:  "commit-block-timeout",
+		Usage: "Vbft commit block timeout in `<milliseconds>`, derived from hash msg delay if not set",
+	}
+	GovZeroTxBlockTimeoutFlag = cli.UintFlag{
+		Name:  "zero-tx-block-timeout",
+		Usage: "Vbft interval of empty blocks in `<milliseconds>`, derived from block msg delay if not set",
+	}
+	GovTxPoolTimeoutFlag = cli.UintFlag{
+		Name:  "tx-pool-timeout",
+		Usage: "Vbft interval of checking tx pool for new block in `<milliseconds>`, 1000 if not set",
+	}
+	GovMaxTimeoutBackoffFlag = cli.UintFlag{
+		Name:  "max-timeout-backoff",
+		Usage: "Vbft max `<factor>` the timeouts are scaled by on slow rounds and timeouts, disabled if not set",
+	}
+
 	//PreExecute switcher
 	TxpoolPreExecDisableFlag = cli.BoolFlag{
 		Name:  "disable-tx-pool-pre-exec",
@@ -26,13 +26,18 @@ import (
 	"github.com/polynetwork/poly/common/constants"
 	"github.com/polynetwork/poly/core/signature"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/ontio/ontology-crypto/keypair"
 	sig "github.com/ontio/ontology-crypto/signature"
 	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/core/payload"
 	"github.com/polynetwork/poly/core/types"
 	"github.com/polynetwork/poly/native/states"
 )
@@ -278,6 +283,77 @@ func GetSmartContractEventInfo(txHash string) ([]byte, error) {
 	return nil, ontErr.Error
 }
 
+//NewNativeInvokeTransaction return the transaction invoking method of native contract, the chain id
+//is decided by the network id of the node connected
+func NewNativeInvokeTransaction(contract common.Address, method string, args []byte) (*types.Transaction, error) {
+	networkId, err := GetNetworkId()
+	if err != nil {
+		return nil, fmt.Errorf("GetNetworkId error:%s", err)
+	}
+	invokeParam := &states.ContractInvokeParam{
+		Address: contract,
+		Method:  method,
+		Args:    args,
+	}
+	sink := common.NewZeroCopySink(nil)
+	invokeParam.Serialization(sink)
+	tx := &types.Transaction{
+		Version: types.CURR_TX_VERSION,
+		TxType:  types.Invoke,
+		Payload: &payload.InvokeCode{Code: sink.Bytes()},
+		Nonce:   uint32(time.Now().Unix()),
+		ChainID: config.GetChainIdByNetId(networkId),
+	}
+	sink = common.NewZeroCopySink(nil)
+	err = tx.Serialization(sink)
+	if err != nil {
+		return nil, fmt.Errorf("tx serialization error:%s", err)
+	}
+	return types.TransactionFromRawBytes(sink.Bytes())
+}
+
+//SigTxBySigsvr sign the raw transaction by the account unlocked in sigsvr at sigsvrAddr
+func SigTxBySigsvr(sigsvrAddr, accAddr, passwd, rawTx string) (string, error) {
+	params, err := json.Marshal(map[string]string{"raw_tx": rawTx})
+	if err != nil {
+		return "", fmt.Errorf("json.Marshal params error:%s", err)
+	}
+	req, err := json.Marshal(map[string]interface{}{
+		"qid":     "cli",
+		"method":  "sigrawtx",
+		"account": accAddr,
+		"pwd":     passwd,
+		"params":  json.RawMessage(params),
+	})
+	if err != nil {
+		return "", fmt.Errorf("json.Marshal request error:%s", err)
+	}
+	resp, err := http.Post(sigsvrAddr, "application/json", bytes.NewReader(req))
+	if err != nil {
+		return "", fmt.Errorf("post sigsvr error:%s", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("read sigsvr response body error:%s", err)
+	}
+	rsp := &struct {
+		ErrorCode int    `json:"error_code"`
+		ErrorInfo string `json:"error_info"`
+		Result    struct {
+			SignedTx string `json:"signed_tx"`
+		} `json:"result"`
+	}{}
+	err = json.Unmarshal(body, rsp)
+	if err != nil {
+		return "", fmt.Errorf("json.Unmarshal sigsvr response:%s error:%s", body, err)
+	}
+	if rsp.ErrorCode != 0 {
+		return "", fmt.Errorf("sigsvr error code:%d info:%s", rsp.ErrorCode, rsp.ErrorInfo)
+	}
+	return rsp.Result.SignedTx, nil
+}
+
 func hasAlreadySig(data []byte, pk keypair.PublicKey, sigDatas [][]byte) bool {
 	for _, sigData := range sigDatas {
 		err := signature.Verify(pk, data, sigData)
@@ -54,6 +54,7 @@ const (
 	DEFAULT_RPC_LOCAL_PORT                  = uint(20337)
 	DEFAULT_REST_PORT                       = uint(20334)
 	DEFAULT_WS_PORT                         = uint(20335)
+	DEFAULT_METRICS_PORT                    = uint(20340)
 	DEFAULT_REST_MAX_CONN                   = uint(1024)
 	DEFAULT_MAX_CONN_IN_BOUND               = uint(1024)
 	DEFAULT_MAX_CONN_OUT_BOUND              = uint(1024)
@@ -67,6 +68,7 @@ const (
 	DEFUALT_CLI_RPC_ADDRESS                 = "127.0.0.1"
 	DEFAULT_GAS_LIMIT                       = 20000
 	DEFAULT_GAS_PRICE                       = 500
+	MIN_PRUNE_BLOCKS                        = 1000 //min count of recent blocks kept by pruned node
 
 	DEFAULT_DATA_DIR      = "./Chain"
 	DEFAULT_RESERVED_FILE = "./peers.rsv"
@@ -122,6 +124,11 @@ var POLYGON_SNAP_CHAINID = map[uint32]uint32{
 	NETWORK_ID_MAIN_NET: constants.POLYGON_SNAP_CHAINID_MAINNET,
 }
 
+var STATE_TRIE_ROOT_HEIGHT = map[uint32]uint32{
+	NETWORK_ID_MAIN_NET: constants.STATE_TRIE_ROOT_HEIGHT_MAINNET,
+	NETWORK_ID_TEST_NET: constants.STATE_TRIE_ROOT_HEIGHT_TESTNET,
+}
+
 var (
 	EXTRA_INFO_HEIGHT_FORK_CHECK bool
 )
@@ -165,6 +172,12 @@ func GetExtraInfoHeight(id uint32) uint32 {
 	return EXTRA_INFO_HEIGHT[id]
 }
 
+//GetStateTrieRootHeight return the height from which the state trie root of previous block is committed to the
+//cross states root of block
+func GetStateTrieRootHeight(id uint32) uint32 {
+	return STATE_TRIE_ROOT_HEIGHT[id]
+}
+
 func GetNetworkName(id uint32) string {
 	name, ok := NETWORK_NAME[id]
 	if ok {
@@ -434,6 +447,8 @@ type CommonConfig struct {
 	GasLimit       uint64
 	GasPrice       uint64
 	DataDir        string
+	PruneBlocks    uint32 //keep transactions and events of the latest PruneBlocks blocks only, 0 means keep all
+	ArchiveMode    bool   //keep all blocks, events and historical states
 }
 
 type ConsensusConfig struct {
@@ -487,6 +502,11 @@ type WebSocketConfig struct {
 	HttpKeyPath  string
 }
 
+type MetricsConfig struct {
+	EnableHttpMetrics bool
+	HttpMetricsPort   uint
+}
+
 type OntologyConfig struct {
 	Genesis   *GenesisConfig
 	Common    *CommonConfig
@@ -495,6 +515,7 @@ type OntologyConfig struct {
 	Rpc       *RpcConfig
 	Restful   *RestfulConfig
 	Ws        *WebSocketConfig
+	Metrics   *MetricsConfig
 }
 
 func NewOntologyConfig() *OntologyConfig {
@@ -543,6 +564,10 @@ func NewOntologyConfig() *OntologyConfig {
 			EnableHttpWs: true,
 			HttpWsPort:   DEFAULT_WS_PORT,
 		},
+		Metrics: &MetricsConfig{
+			EnableHttpMetrics: false,
+			HttpMetricsPort:   DEFAULT_METRICS_PORT,
+		},
 	}
 }
 
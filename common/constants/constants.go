@@ -19,6 +19,7 @@
 package constants
 
 import (
+	"math"
 	"time"
 )
 
@@ -56,3 +57,16 @@ const POLYGON_SNAP_CHAINID_MAINNET = 16
 
 // eth arrow glacier upgrade
 const ETH4345_HEIGHT_MAINNET = 13_773_000
+
+// harmony, hsc and bytom router start height
+const ROUTER_START_HEIGHT_MAINNET = 18823000
+
+// beacon router start height, disabled until scheduled by the consensus nodes
+const BEACON_ROUTER_START_HEIGHT_MAINNET = math.MaxUint32
+
+// state trie root committed to cross states root height, disabled until scheduled by the consensus nodes
+const STATE_TRIE_ROOT_HEIGHT_MAINNET = math.MaxUint32
+const STATE_TRIE_ROOT_HEIGHT_TESTNET = math.MaxUint32
+
+// vote router done tx check height
+const VOTE_DONE_TX_HEIGHT_TESTNET = 19954185
@@ -0,0 +1,320 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package metrics provides counters, gauges and histograms exported in the
+// prometheus text format
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	TYPE_COUNTER   = "counter"
+	TYPE_GAUGE     = "gauge"
+	TYPE_HISTOGRAM = "histogram"
+)
+
+//DefBuckets are the default latency buckets in seconds
+var DefBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+//Labels are the label name and value pairs of a metric
+type Labels map[string]string
+
+//Sample is a single exported value, suffix is appended to the metric name
+type Sample struct {
+	Suffix string
+	Labels Labels
+	Value  float64
+}
+
+//Metric is implemented by all the metric types collected by a Registry
+type Metric interface {
+	Name() string
+	Help() string
+	Type() string
+	Samples() []Sample
+}
+
+type desc struct {
+	name   string
+	help   string
+	labels Labels
+}
+
+func (self *desc) Name() string {
+	return self.name
+}
+
+func (self *desc) Help() string {
+	return self.help
+}
+
+func (self *desc) key() string {
+	return self.name + formatLabels(self.labels)
+}
+
+//Counter is a monotonically increasing value
+type Counter struct {
+	desc
+	value uint64
+}
+
+func NewCounter(name, help string, labels Labels) *Counter {
+	c := &Counter{desc: desc{name: name, help: help, labels: labels}}
+	DefRegistry.Register(c)
+	return c
+}
+
+func (self *Counter) Inc() {
+	atomic.AddUint64(&self.value, 1)
+}
+
+func (self *Counter) Add(n uint64) {
+	atomic.AddUint64(&self.value, n)
+}
+
+func (self *Counter) Value() uint64 {
+	return atomic.LoadUint64(&self.value)
+}
+
+func (self *Counter) Type() string {
+	return TYPE_COUNTER
+}
+
+func (self *Counter) Samples() []Sample {
+	return []Sample{{Labels: self.labels, Value: float64(self.Value())}}
+}
+
+//CounterFunc is a counter whose value is read from fn when collected
+type CounterFunc struct {
+	desc
+	fn func() float64
+}
+
+func NewCounterFunc(name, help string, labels Labels, fn func() float64) *CounterFunc {
+	c := &CounterFunc{desc: desc{name: name, help: help, labels: labels}, fn: fn}
+	DefRegistry.Register(c)
+	return c
+}
+
+func (self *CounterFunc) Type() string {
+	return TYPE_COUNTER
+}
+
+func (self *CounterFunc) Samples() []Sample {
+	return []Sample{{Labels: self.labels, Value: self.fn()}}
+}
+
+//CounterVec is a set of counters which differ in the values of the given label names
+type CounterVec struct {
+	desc
+	labelNames []string
+	lock       sync.RWMutex
+	counters   map[string]*Counter
+}
+
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{
+		desc:       desc{name: name, help: help},
+		labelNames: labelNames,
+		counters:   make(map[string]*Counter),
+	}
+	DefRegistry.Register(c)
+	return c
+}
+
+//WithLabelValues returns the counter of the label values, the values are in the order of the label names
+func (self *CounterVec) WithLabelValues(values ...string) *Counter {
+	key := strings.Join(values, "\xff")
+	self.lock.RLock()
+	c, ok := self.counters[key]
+	self.lock.RUnlock()
+	if ok {
+		return c
+	}
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if c, ok := self.counters[key]; ok {
+		return c
+	}
+	labels := make(Labels, len(self.labelNames))
+	for i, name := range self.labelNames {
+		if i < len(values) {
+			labels[name] = values[i]
+		} else {
+			labels[name] = ""
+		}
+	}
+	c = &Counter{desc: desc{name: self.name, help: self.help, labels: labels}}
+	self.counters[key] = c
+	return c
+}
+
+func (self *CounterVec) Type() string {
+	return TYPE_COUNTER
+}
+
+func (self *CounterVec) Samples() []Sample {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	samples := make([]Sample, 0, len(self.counters))
+	for _, c := range self.counters {
+		samples = append(samples, c.Samples()...)
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return formatLabels(samples[i].Labels) < formatLabels(samples[j].Labels)
+	})
+	return samples
+}
+
+//Gauge is a value that can go up and down
+type Gauge struct {
+	desc
+	bits uint64
+}
+
+func NewGauge(name, help string, labels Labels) *Gauge {
+	g := &Gauge{desc: desc{name: name, help: help, labels: labels}}
+	DefRegistry.Register(g)
+	return g
+}
+
+func (self *Gauge) Set(v float64) {
+	atomic.StoreUint64(&self.bits, math.Float64bits(v))
+}
+
+func (self *Gauge) Add(v float64) {
+	for {
+		old := atomic.LoadUint64(&self.bits)
+		new := math.Float64bits(math.Float64frombits(old) + v)
+		if atomic.CompareAndSwapUint64(&self.bits, old, new) {
+			return
+		}
+	}
+}
+
+func (self *Gauge) Value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&self.bits))
+}
+
+func (self *Gauge) Type() string {
+	return TYPE_GAUGE
+}
+
+func (self *Gauge) Samples() []Sample {
+	return []Sample{{Labels: self.labels, Value: self.Value()}}
+}
+
+//GaugeFunc is a gauge whose value is read from fn when collected
+type GaugeFunc struct {
+	desc
+	fn func() float64
+}
+
+func NewGaugeFunc(name, help string, labels Labels, fn func() float64) *GaugeFunc {
+	g := &GaugeFunc{desc: desc{name: name, help: help, labels: labels}, fn: fn}
+	DefRegistry.Register(g)
+	return g
+}
+
+func (self *GaugeFunc) Type() string {
+	return TYPE_GAUGE
+}
+
+func (self *GaugeFunc) Samples() []Sample {
+	return []Sample{{Labels: self.labels, Value: self.fn()}}
+}
+
+//Histogram counts observations in cumulative buckets
+type Histogram struct {
+	desc
+	lock    sync.Mutex
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+//NewHistogram creates a histogram with the ascending upper bounds of buckets, DefBuckets is used if nil
+func NewHistogram(name, help string, labels Labels, buckets []float64) *Histogram {
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+	h := &Histogram{
+		desc:    desc{name: name, help: help, labels: labels},
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	}
+	DefRegistry.Register(h)
+	return h
+}
+
+func (self *Histogram) Observe(v float64) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	for i, upper := range self.buckets {
+		if v <= upper {
+			self.counts[i]++
+		}
+	}
+	self.sum += v
+	self.count++
+}
+
+func (self *Histogram) Count() uint64 {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	return self.count
+}
+
+func (self *Histogram) Type() string {
+	return TYPE_HISTOGRAM
+}
+
+func (self *Histogram) Samples() []Sample {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	samples := make([]Sample, 0, len(self.buckets)+3)
+	for i, upper := range self.buckets {
+		samples = append(samples, Sample{
+			Suffix: "_bucket",
+			Labels: withLabel(self.labels, "le", formatValue(upper)),
+			Value:  float64(self.counts[i]),
+		})
+	}
+	samples = append(samples,
+		Sample{Suffix: "_bucket", Labels: withLabel(self.labels, "le", "+Inf"), Value: float64(self.count)},
+		Sample{Suffix: "_sum", Labels: self.labels, Value: self.sum},
+		Sample{Suffix: "_count", Labels: self.labels, Value: float64(self.count)},
+	)
+	return samples
+}
+
+func withLabel(labels Labels, name, value string) Labels {
+	ret := make(Labels, len(labels)+1)
+	for k, v := range labels {
+		ret[k] = v
+	}
+	ret[name] = value
+	return ret
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package metrics
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryWriteTo(t *testing.T) {
+	registry := NewRegistry()
+	counter := &CounterVec{
+		desc:       desc{name: "test_calls_total", help: "Calls by router"},
+		labelNames: []string{"router", "result"},
+		counters:   make(map[string]*Counter),
+	}
+	registry.Register(counter)
+	counter.WithLabelValues("2", "success").Inc()
+	counter.WithLabelValues("2", "success").Inc()
+	counter.WithLabelValues("2", "failure").Inc()
+
+	in := &GaugeFunc{desc: desc{name: "test_peers", help: "Peers", labels: Labels{"direction": "in"}}, fn: func() float64 { return 3 }}
+	out := &GaugeFunc{desc: desc{name: "test_peers", help: "Peers", labels: Labels{"direction": "out"}}, fn: func() float64 { return 5 }}
+	registry.Register(in)
+	registry.Register(out)
+
+	h := &Histogram{desc: desc{name: "test_latency_seconds", help: "Latency"}, buckets: []float64{0.1, 1}, counts: make([]uint64, 2)}
+	registry.Register(h)
+	h.Observe(0.05)
+	h.Observe(0.5)
+	h.Observe(2)
+
+	buf := new(bytes.Buffer)
+	_, err := registry.WriteTo(buf)
+	assert.NoError(t, err)
+	expect := `# HELP test_calls_total Calls by router
+# TYPE test_calls_total counter
+test_calls_total{result="failure",router="2"} 1
+test_calls_total{result="success",router="2"} 2
+# HELP test_latency_seconds Latency
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{le="0.1"} 1
+test_latency_seconds_bucket{le="1"} 2
+test_latency_seconds_bucket{le="+Inf"} 3
+test_latency_seconds_sum 2.55
+test_latency_seconds_count 3
+# HELP test_peers Peers
+# TYPE test_peers gauge
+test_peers{direction="in"} 3
+test_peers{direction="out"} 5
+`
+	assert.Equal(t, expect, buf.String())
+
+	registry.Unregister(out)
+	buf.Reset()
+	registry.WriteTo(buf)
+	assert.NotContains(t, buf.String(), `direction="out"`)
+}
+
+func TestGauge(t *testing.T) {
+	g := &Gauge{}
+	g.Set(1.5)
+	g.Add(-0.5)
+	assert.Equal(t, float64(1), g.Value())
+}
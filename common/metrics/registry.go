@@ -0,0 +1,136 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//DefRegistry collects the metrics created by the New* functions
+var DefRegistry = NewRegistry()
+
+//Registry holds the metrics exported by the node
+type Registry struct {
+	lock    sync.RWMutex
+	metrics map[string]Metric
+}
+
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]Metric)}
+}
+
+//Register adds the metric to the registry, a metric with the same name and labels is replaced
+func (self *Registry) Register(m Metric) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.metrics[metricKey(m)] = m
+}
+
+//Unregister removes the metric from the registry
+func (self *Registry) Unregister(m Metric) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if self.metrics[metricKey(m)] == m {
+		delete(self.metrics, metricKey(m))
+	}
+}
+
+//WriteTo writes all the metrics in the prometheus text format
+func (self *Registry) WriteTo(w io.Writer) (int64, error) {
+	self.lock.RLock()
+	keys := make([]string, 0, len(self.metrics))
+	for k := range self.metrics {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	metrics := make([]Metric, 0, len(keys))
+	for _, k := range keys {
+		metrics = append(metrics, self.metrics[k])
+	}
+	self.lock.RUnlock()
+
+	buf := new(bytes.Buffer)
+	lastName := ""
+	for _, m := range metrics {
+		if m.Name() != lastName {
+			fmt.Fprintf(buf, "# HELP %s %s\n", m.Name(), escapeHelp(m.Help()))
+			fmt.Fprintf(buf, "# TYPE %s %s\n", m.Name(), m.Type())
+			lastName = m.Name()
+		}
+		for _, s := range m.Samples() {
+			fmt.Fprintf(buf, "%s%s%s %s\n", m.Name(), s.Suffix, formatLabels(s.Labels), formatValue(s.Value))
+		}
+	}
+	return buf.WriteTo(w)
+}
+
+//Handler serves the metrics of the registry
+func (self *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		self.WriteTo(w)
+	})
+}
+
+func metricKey(m Metric) string {
+	if d, ok := m.(interface{ key() string }); ok {
+		return d.key()
+	}
+	return m.Name()
+}
+
+func formatLabels(labels Labels) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", name, strconv.Quote(labels[name])))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func escapeHelp(help string) string {
+	return strings.NewReplacer("\\", "\\\\", "\n", "\\n").Replace(help)
+}
@@ -40,16 +40,27 @@ type PeerConfig struct {
 }
 
 type ChainConfig struct {
-	Version              uint32        `json:"version"` // software version
-	View                 uint32        `json:"view"`    // config-updated version
-	N                    uint32        `json:"n"`       // network size
-	C                    uint32        `json:"c"`       // consensus quorum
-	BlockMsgDelay        time.Duration `json:"block_msg_delay"`
-	HashMsgDelay         time.Duration `json:"hash_msg_delay"`
-	PeerHandshakeTimeout time.Duration `json:"peer_handshake_timeout"`
-	Peers                []*PeerConfig `json:"peers"`
-	PosTable             []uint32      `json:"pos_table"`
-	MaxBlockChangeView   uint32        `json:"max_block_change_view"`
+	Version              uint32         `json:"version"` // software version
+	View                 uint32         `json:"view"`    // config-updated version
+	N                    uint32         `json:"n"`       // network size
+	C                    uint32         `json:"c"`       // consensus quorum
+	BlockMsgDelay        time.Duration  `json:"block_msg_delay"`
+	HashMsgDelay         time.Duration  `json:"hash_msg_delay"`
+	PeerHandshakeTimeout time.Duration  `json:"peer_handshake_timeout"`
+	Peers                []*PeerConfig  `json:"peers"`
+	PosTable             []uint32       `json:"pos_table"`
+	MaxBlockChangeView   uint32         `json:"max_block_change_view"`
+	Timeouts             *TimeoutConfig `json:"timeouts,omitempty"`
+}
+
+// TimeoutConfig overrides the consensus timeouts derived from the msg delays, zero values are not overridden
+type TimeoutConfig struct {
+	MakeProposal time.Duration `json:"make_proposal,omitempty"`
+	EndorseBlock time.Duration `json:"endorse_block,omitempty"`
+	CommitBlock  time.Duration `json:"commit_block,omitempty"`
+	ZeroTxBlock  time.Duration `json:"zero_tx_block,omitempty"`
+	TxPool       time.Duration `json:"tx_pool,omitempty"`
+	MaxBackoff   uint32        `json:"max_backoff,omitempty"`
 }
 
 //
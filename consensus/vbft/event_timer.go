@@ -50,7 +50,7 @@ var (
 	endorseBlockTimeout    = 100 * time.Millisecond
 	commitBlockTimeout     = 200 * time.Millisecond
 	peerHandshakeTimeout   = 10 * time.Second
-	txPooltimeout          = 1 * time.Second
+	txPooltimeout          = defaultTxPoolTimeout
 	zeroTxBlockTimeout     = 10 * time.Second
 )
 
@@ -155,17 +155,18 @@ func (self *EventTimer) CancelTimer(idx uint32) error {
 }
 
 func (self *EventTimer) getEventTimeout(evtType TimerEventType) time.Duration {
+	timeouts := &self.server.timeouts
 	switch evtType {
 	case EventProposeBlockTimeout:
-		return makeProposalTimeout
+		return timeouts.scale(evtType, makeProposalTimeout)
 	case EventPropose2ndBlockTimeout:
-		return make2ndProposalTimeout
+		return timeouts.scale(evtType, make2ndProposalTimeout)
 	case EventEndorseBlockTimeout:
-		return endorseBlockTimeout
+		return timeouts.scale(evtType, endorseBlockTimeout)
 	case EventEndorseEmptyBlockTimeout:
-		return endorseBlockTimeout
+		return timeouts.scale(evtType, endorseBlockTimeout)
 	case EventCommitBlockTimeout:
-		return commitBlockTimeout
+		return timeouts.scale(evtType, commitBlockTimeout)
 	case EventPeerHeartbeat:
 		return peerHandshakeTimeout
 	case EventProposalBackoff:
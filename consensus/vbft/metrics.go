@@ -0,0 +1,108 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"sync"
+	"time"
+
+	"github.com/polynetwork/poly/common/metrics"
+)
+
+var (
+	roundCounter   = metrics.NewCounter("poly_vbft_rounds_total", "Consensus rounds started", nil)
+	timeoutCounter = metrics.NewCounterVec("poly_vbft_timer_events_total",
+		"Consensus timer events fired by event type", "event")
+	proposalLatency = metrics.NewHistogram("poly_vbft_proposal_latency_seconds",
+		"Time from the start of a round to a verified block proposal", nil, nil)
+	endorseLatency = metrics.NewHistogram("poly_vbft_endorse_latency_seconds",
+		"Time from the start of a round to endorsing the block proposal", nil, nil)
+)
+
+var timerEventNames = map[TimerEventType]string{
+	EventProposeBlockTimeout:      "propose_block_timeout",
+	EventProposalBackoff:          "proposal_backoff",
+	EventRandomBackoff:            "random_backoff",
+	EventPropose2ndBlockTimeout:   "propose_2nd_block_timeout",
+	EventEndorseBlockTimeout:      "endorse_block_timeout",
+	EventEndorseEmptyBlockTimeout: "endorse_empty_block_timeout",
+	EventCommitBlockTimeout:       "commit_block_timeout",
+	EventPeerHeartbeat:            "peer_heartbeat",
+	EventTxPool:                   "tx_pool",
+	EventTxBlockTimeout:           "tx_block_timeout",
+	EventMax:                      "normal",
+}
+
+// roundMetrics records the latency of the consensus steps of the current round
+type roundMetrics struct {
+	lock     sync.Mutex
+	blockNum uint32
+	start    time.Time
+	proposed bool
+	endorsed bool
+}
+
+func (self *roundMetrics) startRound(blkNum uint32) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	roundCounter.Inc()
+	self.blockNum = blkNum
+	self.start = time.Now()
+	self.proposed = false
+	self.endorsed = false
+}
+
+// onProposal returns the proposal latency of the round, false if it is not the first proposal of the round
+func (self *roundMetrics) onProposal(blkNum uint32) (time.Duration, bool) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if blkNum != self.blockNum || self.proposed || self.start.IsZero() {
+		return 0, false
+	}
+	self.proposed = true
+	latency := time.Since(self.start)
+	proposalLatency.Observe(latency.Seconds())
+	return latency, true
+}
+
+// onEndorse returns the endorse latency of the round, false if the round has been endorsed
+func (self *roundMetrics) onEndorse(blkNum uint32) (time.Duration, bool) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if blkNum != self.blockNum || self.endorsed || self.start.IsZero() {
+		return 0, false
+	}
+	self.endorsed = true
+	latency := time.Since(self.start)
+	endorseLatency.Observe(latency.Seconds())
+	return latency, true
+}
+
+func (self *Server) registerMetrics() {
+	metrics.NewGaugeFunc("poly_vbft_block_num", "Block number of the current consensus round",
+		nil, func() float64 {
+			return float64(self.GetCurrentBlockNo())
+		})
+}
+
+func onTimerEvent(evtType TimerEventType) {
+	if name, present := timerEventNames[evtType]; present {
+		timeoutCounter.WithLabelValues(name).Inc()
+	}
+}
@@ -118,6 +118,8 @@ type Server struct {
 	syncer     *Syncer
 	stateMgr   *StateMgr
 	timer      *EventTimer
+	rounds     roundMetrics
+	timeouts   adaptiveTimeouts
 
 	msgRecvC   map[uint32]chan *p2pMsgPayload
 	msgC       chan ConsensusMsg
@@ -139,6 +141,7 @@ func NewVbftServer(account *account.Account, txpool, p2p *actor.PID) (*Server, e
 		incrValidator:      increment.NewIncrementValidator(20),
 	}
 	server.stateMgr = newStateMgr(server)
+	server.registerMetrics()
 
 	props := actor.FromProducer(func() actor.Actor {
 		return server
@@ -291,12 +294,7 @@ func (self *Server) LoadChainConfig(blkNum uint32) error {
 		panic("invalid view or maxblockchangeview ")
 	}
 	// update msg delays
-	makeProposalTimeout = time.Duration(self.config.BlockMsgDelay * 2)
-	make2ndProposalTimeout = time.Duration(self.config.BlockMsgDelay)
-	endorseBlockTimeout = time.Duration(self.config.HashMsgDelay * 2)
-	commitBlockTimeout = time.Duration(self.config.HashMsgDelay * 3)
-	peerHandshakeTimeout = time.Duration(self.config.PeerHandshakeTimeout)
-	zeroTxBlockTimeout = time.Duration(self.config.BlockMsgDelay * 3)
+	self.updateTimeouts()
 	// TODO: load sealed blocks from chainStore
 
 	// protected by server.metaLock
@@ -340,6 +338,8 @@ func (self *Server) updateChainConfig() error {
 	self.metaLock.RLock()
 	defer self.metaLock.RUnlock()
 
+	self.updateTimeouts()
+
 	// TODO
 	// 1. update peer pool
 	// 2. remove nonparticipation consensus node
@@ -649,6 +649,7 @@ func (self *Server) updateParticipantConfig() error {
 
 func (self *Server) startNewRound() error {
 	blkNum := self.GetCurrentBlockNo()
+	self.rounds.startRound(blkNum)
 
 	if err := self.updateParticipantConfig(); err != nil {
 		log.Errorf("startNewRound error:%s", err)
@@ -1107,6 +1108,9 @@ func (self *Server) processProposalMsg(msg *blockProposalMsg) {
 		self.msgPool.DropMsg(msg)
 		return
 	}
+	if latency, ok := self.rounds.onProposal(msgBlkNum); ok {
+		self.timeouts.observeProposal(latency)
+	}
 
 	txs := msg.Block.Block.Transactions
 	if len(txs) > 0 && self.nonSystxs(txs, msgBlkNum) {
@@ -1672,6 +1676,8 @@ func (self *Server) actionLoop() {
 }
 
 func (self *Server) processTimerEvent(evt *TimerEvent) error {
+	onTimerEvent(evt.evtType)
+	self.timeouts.onTimerEvent(evt.evtType)
 	switch evt.evtType {
 	case EventProposalBackoff:
 		// 1. if endorsed, return
@@ -1944,6 +1950,9 @@ func (self *Server) endorseBlock(proposal *blockProposalMsg, forEmpty bool) erro
 	if err := self.blockPool.setProposalEndorsed(proposal, forEmpty); err != nil {
 		return fmt.Errorf("failed to set proposal as endorsed: %s", err)
 	}
+	if latency, ok := self.rounds.onEndorse(blkNum); ok {
+		self.timeouts.observeEndorse(latency)
+	}
 
 	self.processConsensusMsg(endorseMsg)
 	// if node is endorser of current round
@@ -2095,6 +2104,7 @@ func (self *Server) sealBlock(block *Block, empty bool, sigdata bool) error {
 
 	// notify other modules that block sealed
 	self.timer.onBlockSealed(sealedBlkNum)
+	self.timeouts.onBlockSealed()
 	self.msgPool.onBlockSealed(sealedBlkNum)
 	self.blockPool.onBlockSealed(sealedBlkNum)
 
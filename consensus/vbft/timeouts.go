@@ -0,0 +1,156 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultTxPoolTimeout = 1 * time.Second
+	// timeouts adapted to the observed latencies are kept above latencyTimeoutFactor times of the latency
+	latencyTimeoutFactor = 3
+)
+
+// adaptiveTimeouts scales the timeouts of the consensus steps by the observed round latencies, and
+// backs them off after repeated timeouts, up to maxBackoff times of the configured timeouts
+type adaptiveTimeouts struct {
+	lock            sync.Mutex
+	maxBackoff      uint32
+	backoff         uint32
+	proposalLatency time.Duration // moving average from round start to block proposal
+	endorseLatency  time.Duration // moving average from round start to endorsement
+}
+
+// reset clears the observations, a maxBackoff of 0 or 1 disables the adaptation
+func (self *adaptiveTimeouts) reset(maxBackoff uint32) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if maxBackoff == 0 {
+		maxBackoff = 1
+	}
+	self.maxBackoff = maxBackoff
+	self.backoff = 1
+	self.proposalLatency = 0
+	self.endorseLatency = 0
+}
+
+// onTimerEvent doubles the backoff when a consensus step of the round timed out
+func (self *adaptiveTimeouts) onTimerEvent(evtType TimerEventType) {
+	switch evtType {
+	case EventProposeBlockTimeout, EventEndorseBlockTimeout, EventEndorseEmptyBlockTimeout, EventCommitBlockTimeout:
+	default:
+		return
+	}
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.backoff *= 2
+	if self.backoff > self.maxBackoff {
+		self.backoff = self.maxBackoff
+	}
+}
+
+// onBlockSealed halves the backoff once a round completes
+func (self *adaptiveTimeouts) onBlockSealed() {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if self.backoff > 1 {
+		self.backoff /= 2
+	}
+}
+
+func (self *adaptiveTimeouts) observeProposal(latency time.Duration) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.proposalLatency = movingAverage(self.proposalLatency, latency)
+}
+
+func (self *adaptiveTimeouts) observeEndorse(latency time.Duration) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.endorseLatency = movingAverage(self.endorseLatency, latency)
+}
+
+// scale returns the timeout of event evtType whose configured timeout is timeout
+func (self *adaptiveTimeouts) scale(evtType TimerEventType, timeout time.Duration) time.Duration {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if self.maxBackoff <= 1 {
+		return timeout
+	}
+	var latency time.Duration
+	switch evtType {
+	case EventProposeBlockTimeout, EventPropose2ndBlockTimeout:
+		latency = self.proposalLatency
+	case EventEndorseBlockTimeout, EventEndorseEmptyBlockTimeout, EventCommitBlockTimeout:
+		latency = self.endorseLatency
+	default:
+		return timeout
+	}
+	scaled := timeout * time.Duration(self.backoff)
+	if d := latency * latencyTimeoutFactor; d > scaled {
+		scaled = d
+	}
+	if max := timeout * time.Duration(self.maxBackoff); scaled > max {
+		scaled = max
+	}
+	return scaled
+}
+
+func movingAverage(avg, value time.Duration) time.Duration {
+	if avg == 0 {
+		return value
+	}
+	return (avg*7 + value) / 8
+}
+
+// updateTimeouts sets the timeouts of the consensus steps by the chain config, should be called with
+// server.metaLock held
+func (self *Server) updateTimeouts() {
+	makeProposalTimeout = time.Duration(self.config.BlockMsgDelay * 2)
+	make2ndProposalTimeout = time.Duration(self.config.BlockMsgDelay)
+	endorseBlockTimeout = time.Duration(self.config.HashMsgDelay * 2)
+	commitBlockTimeout = time.Duration(self.config.HashMsgDelay * 3)
+	peerHandshakeTimeout = time.Duration(self.config.PeerHandshakeTimeout)
+	zeroTxBlockTimeout = time.Duration(self.config.BlockMsgDelay * 3)
+	txPooltimeout = defaultTxPoolTimeout
+
+	var maxBackoff uint32
+	if timeouts := self.config.Timeouts; timeouts != nil {
+		if timeouts.MakeProposal != 0 {
+			makeProposalTimeout = timeouts.MakeProposal
+			make2ndProposalTimeout = timeouts.MakeProposal / 2
+		}
+		if timeouts.EndorseBlock != 0 {
+			endorseBlockTimeout = timeouts.EndorseBlock
+		}
+		if timeouts.CommitBlock != 0 {
+			commitBlockTimeout = timeouts.CommitBlock
+		}
+		if timeouts.ZeroTxBlock != 0 {
+			zeroTxBlockTimeout = timeouts.ZeroTxBlock
+		}
+		if timeouts.TxPool != 0 {
+			txPooltimeout = timeouts.TxPool
+		}
+		maxBackoff = timeouts.MaxBackoff
+	}
+	self.timeouts.reset(maxBackoff)
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/polynetwork/poly/core/store/overlaydb"
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
 	nutils "github.com/polynetwork/poly/native/service/utils"
+	"time"
 )
 
 func SignMsg(account *account.Account, msg ConsensusMsg) ([]byte, error) {
@@ -125,7 +126,7 @@ func verifyVrf(pk keypair.PublicKey, blkNum uint32, prevVrf, newVrf, proof []byt
 	}
 	return nil
 }
-func GetVbftConfigInfo(memdb *overlaydb.MemDB) (*config.VBFTConfig, error) {
+func getVbftConfiguration(memdb *overlaydb.MemDB) (*node_manager.Configuration, error) {
 	data, err := GetStorageValue(memdb, ledger.DefLedger, nutils.NodeManagerContractAddress, []byte(node_manager.VBFT_CONFIG))
 	if err != nil {
 		return nil, err
@@ -135,6 +136,14 @@ func GetVbftConfigInfo(memdb *overlaydb.MemDB) (*config.VBFTConfig, error) {
 	if err != nil {
 		return nil, err
 	}
+	return cfg, nil
+}
+
+func GetVbftConfigInfo(memdb *overlaydb.MemDB) (*config.VBFTConfig, error) {
+	cfg, err := getVbftConfiguration(memdb)
+	if err != nil {
+		return nil, err
+	}
 	chainconfig := &config.VBFTConfig{
 		BlockMsgDelay:        uint32(cfg.BlockMsgDelay),
 		HashMsgDelay:         uint32(cfg.HashMsgDelay),
@@ -244,5 +253,26 @@ func getChainConfig(memdb *overlaydb.MemDB, blkNum uint32) (*vconfig.ChainConfig
 		return nil, fmt.Errorf("GenesisChainConfig failed: %s", err)
 	}
 	cfg.View = goverview.View
+
+	configuration, err := getVbftConfiguration(memdb)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chainconfig from leveldb: %s", err)
+	}
+	cfg.Timeouts = getTimeoutConfig(configuration)
 	return cfg, err
 }
+
+// getTimeoutConfig returns the consensus timeouts set in the node_manager configuration, nil if none is set
+func getTimeoutConfig(cfg *node_manager.Configuration) *vconfig.TimeoutConfig {
+	if !cfg.HasTimeouts() {
+		return nil
+	}
+	return &vconfig.TimeoutConfig{
+		MakeProposal: time.Duration(cfg.MakeProposalTimeout) * time.Millisecond,
+		EndorseBlock: time.Duration(cfg.EndorseBlockTimeout) * time.Millisecond,
+		CommitBlock:  time.Duration(cfg.CommitBlockTimeout) * time.Millisecond,
+		ZeroTxBlock:  time.Duration(cfg.ZeroTxBlockTimeout) * time.Millisecond,
+		TxPool:       time.Duration(cfg.TxPoolTimeout) * time.Millisecond,
+		MaxBackoff:   cfg.MaxTimeoutBackoff,
+	}
+}
@@ -28,8 +28,11 @@ import (
 	"github.com/polynetwork/poly/core/store"
 	"github.com/polynetwork/poly/core/store/ledgerstore"
 	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/merkle"
 	"github.com/polynetwork/poly/native/event"
+	ccom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	cstate "github.com/polynetwork/poly/native/states"
+	"io"
 )
 
 var DefLedger *Ledger
@@ -165,6 +168,34 @@ func (self *Ledger) GetStorageItem(codeHash common.Address, key []byte) ([]byte,
 	return storageItem.Value, nil
 }
 
+func (self *Ledger) GetStorageItemAtHeight(codeHash common.Address, key []byte, height uint32) ([]byte, error) {
+	storageKey := &states.StorageKey{
+		ContractAddress: codeHash,
+		Key:             key,
+	}
+	storageItem, err := self.ldgStore.GetStorageItemAtHeight(storageKey, height)
+	if err != nil {
+		return nil, err
+	}
+	return storageItem.Value, nil
+}
+
+func (self *Ledger) GetStorageProof(codeHash common.Address, key []byte, height uint32) ([]byte, *merkle.SparseMerkleProof, common.Uint256, error) {
+	storageKey := &states.StorageKey{
+		ContractAddress: codeHash,
+		Key:             key,
+	}
+	return self.ldgStore.GetStorageProof(storageKey, height)
+}
+
+func (self *Ledger) GetStorageUsage(codeHash common.Address, prefix []byte) (uint64, uint64, error) {
+	storageKey := &states.StorageKey{
+		ContractAddress: codeHash,
+		Key:             prefix,
+	}
+	return self.ldgStore.GetStorageUsage(storageKey)
+}
+
 func (self *Ledger) GetMerkleProof(proofHeight, rootHeight uint32) ([]byte, error) {
 	blockHash := self.ldgStore.GetBlockHash(proofHeight)
 	if bytes.Equal(blockHash.ToArray(), common.UINT256_EMPTY.ToArray()) {
@@ -177,10 +208,18 @@ func (self *Ledger) GetCrossStatesProof(height uint32, key []byte) ([]byte, erro
 	return self.ldgStore.GetCrossStatesProof(height, key)
 }
 
+func (self *Ledger) GetStateTrieRootProof(height uint32) ([]byte, error) {
+	return self.ldgStore.GetStateTrieRootProof(height)
+}
+
 func (self *Ledger) PreExecuteContract(tx *types.Transaction) (*cstate.PreExecResult, error) {
 	return self.ldgStore.PreExecuteContract(tx)
 }
 
+func (self *Ledger) PreExecuteContracts(txs []*types.Transaction) ([]*cstate.PreExecResult, error) {
+	return self.ldgStore.PreExecuteContracts(txs)
+}
+
 func (self *Ledger) GetEventNotifyByTx(tx common.Uint256) (*event.ExecuteNotify, error) {
 	return self.ldgStore.GetEventNotifyByTx(tx)
 }
@@ -189,6 +228,26 @@ func (self *Ledger) GetEventNotifyByBlock(height uint32) ([]*event.ExecuteNotify
 	return self.ldgStore.GetEventNotifyByBlock(height)
 }
 
+func (self *Ledger) GetCrossChainTx(fromChainID uint64, crossChainID []byte) (*ccom.CrossChainTx, error) {
+	return self.ldgStore.GetCrossChainTx(fromChainID, crossChainID)
+}
+
+func (self *Ledger) ListCrossChainTxs(fromChainID uint64, offset, limit uint32) ([]*ccom.CrossChainTx, error) {
+	return self.ldgStore.ListCrossChainTxs(fromChainID, offset, limit)
+}
+
+func (self *Ledger) GetPrunedHeight() uint32 {
+	return self.ldgStore.GetPrunedHeight()
+}
+
+func (self *Ledger) ExportSnapshot(w io.Writer) (uint32, []byte, error) {
+	return self.ldgStore.ExportSnapshot(w)
+}
+
+func (self *Ledger) ImportSnapshot(r io.Reader, trustedDigest []byte) (uint32, error) {
+	return self.ldgStore.ImportSnapshot(r, trustedDigest)
+}
+
 func (self *Ledger) Close() error {
 	return self.ldgStore.Close()
 }
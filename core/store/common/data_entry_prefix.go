@@ -27,13 +27,17 @@ const (
 	DATA_HEADER                            = 0x01 //Block hash => block hash key prefix
 	DATA_TRANSACTION                       = 0x02 //Transction hash = > transaction key prefix
 	DATA_STATE_MERKLE_ROOT                 = 0x21 // block height => write set hash + state merkle root
+	DATA_STATE_TRIE_ROOT                   = 0x24 // block height => state trie root
 
 	// Transaction
 	ST_BOOKKEEPER DataEntryPrefix = 0x03 //BookKeeper state key prefix
 	ST_CONTRACT   DataEntryPrefix = 0x04 //Smart contract state key prefix
 	ST_STORAGE    DataEntryPrefix = 0x05 //Smart contract storage key prefix
+	ST_HISTORY    DataEntryPrefix = 0x06 //Storage key + reversed block height => storage value key prefix
 	ST_VALIDATOR  DataEntryPrefix = 0x07 //no use
 	ST_VOTE       DataEntryPrefix = 0x08 //Vote state key prefix
+	ST_TRIE_NODE  DataEntryPrefix = 0x25 //State trie node hash => node key prefix
+	ST_TRIE_REF   DataEntryPrefix = 0x26 //State trie node hash => reference count key prefix
 
 	IX_HEADER_HASH_LIST DataEntryPrefix = 0x09 //Block height => block hash key prefix
 
@@ -47,4 +51,12 @@ const (
 	SYS_CROSS_STATES_HASH  DataEntryPrefix = 0x23
 
 	EVENT_NOTIFY DataEntryPrefix = 0x14 //Event notify key prefix
+
+	IX_CROSS_CHAIN_TX      DataEntryPrefix = 0x15 //Source chain id + cross chain id => cross chain tx key prefix
+	IX_CROSS_CHAIN_TX_HASH DataEntryPrefix = 0x16 //Source chain id + source tx hash => cross chain id key prefix
+	IX_CROSS_CHAIN_TX_LIST DataEntryPrefix = 0x19 //Source chain id + height first indexed + cross chain id key prefix
+
+	SYS_PRUNED_HEIGHT  DataEntryPrefix = 0x17 //Pruned block height key prefix
+	SYS_HISTORY_HEIGHT DataEntryPrefix = 0x18 //Height since which storage history is kept key prefix
+	SYS_TRIE_HEIGHT    DataEntryPrefix = 0x27 //Height since which state trie roots are kept key prefix
 )
@@ -26,6 +26,8 @@ import (
 )
 
 var ErrNotFound = errors.New("not found")
+var ErrPruned = errors.New("pruned")
+var ErrHistoryUnavailable = errors.New("history unavailable")
 
 //Store iterator for iterate store
 type StoreIterator interface {
@@ -102,3 +102,11 @@ func (this *BlockCache) GetTransaction(txHash common.Uint256) (*types.Transactio
 func (this *BlockCache) ContainTransaction(txHash common.Uint256) bool {
 	return this.transactionCache.Contains(string(txHash.ToArray()))
 }
+
+//RemoveBlock remove block and its transactions from cache
+func (this *BlockCache) RemoveBlock(blockHash common.Uint256, txHashes []common.Uint256) {
+	this.blockCache.Remove(string(blockHash.ToArray()))
+	for _, txHash := range txHashes {
+		this.transactionCache.Remove(string(txHash.ToArray()))
+	}
+}
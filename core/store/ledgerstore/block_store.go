@@ -356,6 +356,9 @@ func (this *BlockStore) loadTransaction(txHash common.Uint256) (*types.Transacti
 	if eof {
 		return nil, 0, io.ErrUnexpectedEOF
 	}
+	if source.Len() == 0 {
+		return nil, height, scom.ErrPruned
+	}
 	tx = new(types.Transaction)
 	err = tx.Deserialization(source)
 	if err != nil {
@@ -383,6 +386,75 @@ func (this *BlockStore) ContainTransaction(txHash common.Uint256) (bool, error)
 	return true, nil
 }
 
+//PruneBlock drop the transactions of block. The header and the transaction hash => height index are kept,
+//so the block can still be verified and the pruned transactions can not be replayed.
+func (this *BlockStore) PruneBlock(blockHash common.Uint256) error {
+	_, txHashes, err := this.loadHeaderWithTx(blockHash)
+	if err != nil {
+		return err
+	}
+	if this.enableCache {
+		this.cache.RemoveBlock(blockHash, txHashes)
+	}
+	for _, txHash := range txHashes {
+		key := this.getTransactionKey(txHash)
+		value, err := this.store.Get(key)
+		if err != nil {
+			if err == scom.ErrNotFound {
+				continue
+			}
+			return err
+		}
+		if len(value) <= 4 {
+			continue
+		}
+		err = this.store.Put(key, value[:4])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//SavePrunedBlock persist block header and the transaction hash => height index without transactions
+func (this *BlockStore) SavePrunedBlock(header *types.Header, txHashes []common.Uint256) error {
+	sink := common.NewZeroCopySink(nil)
+	header.Serialization(sink)
+	sink.WriteUint32(uint32(len(txHashes)))
+	for _, txHash := range txHashes {
+		sink.WriteHash(txHash)
+	}
+	this.store.BatchPut(this.getHeaderKey(header.Hash()), sink.Bytes())
+	for _, txHash := range txHashes {
+		value := make([]byte, 4)
+		binary.LittleEndian.PutUint32(value, header.Height)
+		this.store.BatchPut(this.getTransactionKey(txHash), value)
+	}
+	return nil
+}
+
+//GetPrunedHeight return the height up to which blocks have been pruned
+func (this *BlockStore) GetPrunedHeight() (uint32, error) {
+	value, err := this.store.Get(this.getPrunedHeightKey())
+	if err != nil {
+		if err == scom.ErrNotFound {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if len(value) != 4 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return binary.LittleEndian.Uint32(value), nil
+}
+
+//SavePrunedHeight persist the height up to which blocks have been pruned
+func (this *BlockStore) SavePrunedHeight(height uint32) error {
+	value := make([]byte, 4)
+	binary.LittleEndian.PutUint32(value, height)
+	return this.store.Put(this.getPrunedHeightKey(), value)
+}
+
 //GetVersion return the version of store
 func (this *BlockStore) GetVersion() (byte, error) {
 	key := this.getVersionKey()
@@ -458,6 +530,10 @@ func (this *BlockStore) getVersionKey() []byte {
 	return []byte{byte(scom.SYS_VERSION)}
 }
 
+func (this *BlockStore) getPrunedHeightKey() []byte {
+	return []byte{byte(scom.SYS_PRUNED_HEIGHT)}
+}
+
 func (this *BlockStore) getHeaderIndexListKey(startHeight uint32) []byte {
 	key := bytes.NewBuffer(nil)
 	key.WriteByte(byte(scom.IX_HEADER_HASH_LIST))
@@ -29,6 +29,7 @@ import (
 	scom "github.com/polynetwork/poly/core/store/common"
 	"github.com/polynetwork/poly/core/store/leveldbstore"
 	"github.com/polynetwork/poly/native/event"
+	ccom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 )
 
 //Saving event notifies gen by smart contract execution
@@ -134,6 +135,99 @@ func (this *EventStore) GetEventNotifyByBlock(height uint32) ([]*event.ExecuteNo
 	return evtNotifies, nil
 }
 
+//PruneEventNotify delete the event notifies of all transactions in block. Cross chain tx lifecycle records are kept
+func (this *EventStore) PruneEventNotify(height uint32) error {
+	key, err := this.getEventNotifyByBlockKey(height)
+	if err != nil {
+		return err
+	}
+	data, err := this.store.Get(key)
+	if err != nil {
+		if err == scom.ErrNotFound {
+			return nil
+		}
+		return err
+	}
+	reader := bytes.NewBuffer(data)
+	size, err := serialization.ReadUint32(reader)
+	if err != nil {
+		return fmt.Errorf("ReadUint32 error %s", err)
+	}
+	for i := uint32(0); i < size; i++ {
+		var txHash common.Uint256
+		err = txHash.Deserialize(reader)
+		if err != nil {
+			return fmt.Errorf("txHash.Deserialize error %s", err)
+		}
+		err = this.store.Delete(this.getEventNotifyByTxKey(txHash))
+		if err != nil {
+			return err
+		}
+	}
+	return this.store.Delete(key)
+}
+
+//SaveCrossChainTx persist cross chain tx, and index its cross chain id by source tx hash.
+//The tx is appended to the list of source chain at its height the first time it is saved
+func (this *EventStore) SaveCrossChainTx(tx *ccom.CrossChainTx) error {
+	key := this.getCrossChainTxKey(tx.FromChainID, tx.CrossChainID)
+	_, err := this.store.Get(key)
+	if err == scom.ErrNotFound {
+		this.store.BatchPut(this.getCrossChainTxListKey(tx.FromChainID, tx.Height, tx.CrossChainID), nil)
+	} else if err != nil {
+		return err
+	}
+	sink := common.NewZeroCopySink(nil)
+	tx.Serialization(sink)
+	this.store.BatchPut(key, sink.Bytes())
+	this.store.BatchPut(this.getCrossChainTxHashKey(tx.FromChainID, tx.TxHash), tx.CrossChainID)
+	return nil
+}
+
+//GetCrossChainTx return cross chain tx by source chain id and cross chain id
+func (this *EventStore) GetCrossChainTx(fromChainID uint64, crossChainID []byte) (*ccom.CrossChainTx, error) {
+	data, err := this.store.Get(this.getCrossChainTxKey(fromChainID, crossChainID))
+	if err != nil {
+		return nil, err
+	}
+	tx := new(ccom.CrossChainTx)
+	if err := tx.Deserialization(common.NewZeroCopySource(data)); err != nil {
+		return nil, fmt.Errorf("CrossChainTx.Deserialization error %s", err)
+	}
+	return tx, nil
+}
+
+//GetCrossChainID return cross chain id by source chain id and source tx hash
+func (this *EventStore) GetCrossChainID(fromChainID uint64, txHash []byte) ([]byte, error) {
+	return this.store.Get(this.getCrossChainTxHashKey(fromChainID, txHash))
+}
+
+//ListCrossChainTxs return at most limit cross chain txs of source chain, skipping the first offset ones.
+//Txs are ordered by the height they are first indexed at, and by cross chain id within the same height
+func (this *EventStore) ListCrossChainTxs(fromChainID uint64, offset, limit uint32) ([]*ccom.CrossChainTx, error) {
+	prefix := this.getCrossChainTxListKey(fromChainID, 0, nil)[:9]
+	iter := this.store.NewIterator(prefix)
+	defer iter.Release()
+	txs := make([]*ccom.CrossChainTx, 0)
+	for index := uint32(0); uint32(len(txs)) < limit && iter.Next(); index++ {
+		if index < offset {
+			continue
+		}
+		// key of iterator is reused on next, so copy it before reading
+		crossChainID := make([]byte, len(iter.Key())-13)
+		copy(crossChainID, iter.Key()[13:])
+		tx, err := this.GetCrossChainTx(fromChainID, crossChainID)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
 //CommitTo event store batch to store
 func (this *EventStore) CommitTo() error {
 	return this.store.BatchCommit()
@@ -207,3 +301,28 @@ func (this *EventStore) getEventNotifyByTxKey(txHash common.Uint256) []byte {
 	copy(key[1:], data)
 	return key
 }
+
+func (this *EventStore) getCrossChainTxKey(fromChainID uint64, crossChainID []byte) []byte {
+	key := make([]byte, 9+len(crossChainID))
+	key[0] = byte(scom.IX_CROSS_CHAIN_TX)
+	binary.BigEndian.PutUint64(key[1:], fromChainID)
+	copy(key[9:], crossChainID)
+	return key
+}
+
+func (this *EventStore) getCrossChainTxHashKey(fromChainID uint64, txHash []byte) []byte {
+	key := make([]byte, 9+len(txHash))
+	key[0] = byte(scom.IX_CROSS_CHAIN_TX_HASH)
+	binary.BigEndian.PutUint64(key[1:], fromChainID)
+	copy(key[9:], txHash)
+	return key
+}
+
+func (this *EventStore) getCrossChainTxListKey(fromChainID uint64, height uint32, crossChainID []byte) []byte {
+	key := make([]byte, 13+len(crossChainID))
+	key[0] = byte(scom.IX_CROSS_CHAIN_TX_LIST)
+	binary.BigEndian.PutUint64(key[1:], fromChainID)
+	binary.BigEndian.PutUint32(key[9:], height)
+	copy(key[13:], crossChainID)
+	return key
+}
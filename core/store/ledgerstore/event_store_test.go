@@ -0,0 +1,114 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native/event"
+	ccom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func crossChainTxNotify(polyTxHash common.Uint256, fromChainID, toChainID uint64, txHash, crossChainID string, status uint8) *event.ExecuteNotify {
+	return &event.ExecuteNotify{
+		TxHash: polyTxHash,
+		State:  event.CONTRACT_STATE_SUCCESS,
+		Notify: []*event.NotifyEventInfo{
+			{
+				ContractAddress: utils.CrossChainManagerContractAddress,
+				States:          []interface{}{ccom.NOTIFY_CROSS_CHAIN_TX, fromChainID, toChainID, txHash, crossChainID, status},
+			},
+		},
+	}
+}
+
+func TestSaveCrossChainTxs(t *testing.T) {
+	eventStore, err := NewEventStore("test/event")
+	assert.Nil(t, err)
+	defer eventStore.Close()
+
+	// btc tx is made and signed in the same block
+	eventStore.NewBatch()
+	notifies := []*event.ExecuteNotify{
+		crossChainTxNotify(common.Uint256{1}, 2, 3, "aa01", "01", ccom.CROSS_CHAIN_TX_PROVEN),
+		crossChainTxNotify(common.Uint256{2}, 2, 1, "aa02", "02", ccom.CROSS_CHAIN_TX_PENDING_MULTISIGN),
+		crossChainTxNotify(common.Uint256{3}, 2, 1, "aa02", "", ccom.CROSS_CHAIN_TX_DONE),
+		crossChainTxNotify(common.Uint256{4}, 2, 1, "aa03", "03", ccom.CROSS_CHAIN_TX_PENDING_MULTISIGN),
+		{TxHash: common.Uint256{5}, State: event.CONTRACT_STATE_FAIL},
+	}
+	assert.Nil(t, SaveCrossChainTxs(eventStore, 10, notifies))
+	assert.Nil(t, eventStore.CommitTo())
+
+	tx, err := eventStore.GetCrossChainTx(2, []byte{2})
+	assert.Nil(t, err)
+	assert.Equal(t, &ccom.CrossChainTx{
+		FromChainID:  2,
+		CrossChainID: []byte{2},
+		TxHash:       []byte{0xaa, 0x02},
+		ToChainID:    1,
+		PolyTxHash:   common.Uint256{3},
+		Height:       10,
+		Status:       ccom.CROSS_CHAIN_TX_DONE,
+	}, tx)
+
+	// signed in a later block
+	eventStore.NewBatch()
+	notifies = []*event.ExecuteNotify{
+		crossChainTxNotify(common.Uint256{6}, 2, 1, "aa03", "", ccom.CROSS_CHAIN_TX_DONE),
+		crossChainTxNotify(common.Uint256{7}, 2, 1, "aa04", "", ccom.CROSS_CHAIN_TX_DONE),
+	}
+	assert.Nil(t, SaveCrossChainTxs(eventStore, 11, notifies))
+	assert.Nil(t, eventStore.CommitTo())
+
+	tx, err = eventStore.GetCrossChainTx(2, []byte{3})
+	assert.Nil(t, err)
+	assert.Equal(t, common.Uint256{6}, tx.PolyTxHash)
+	assert.Equal(t, uint32(11), tx.Height)
+	assert.Equal(t, ccom.CROSS_CHAIN_TX_DONE, tx.Status)
+
+	txs, err := eventStore.ListCrossChainTxs(2, 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(txs))
+	txs, err = eventStore.ListCrossChainTxs(2, 1, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(txs))
+	assert.Equal(t, []byte{2}, txs[0].CrossChainID)
+	txs, err = eventStore.ListCrossChainTxs(3, 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(txs))
+
+	// listed by indexed height rather than cross chain id, malformed notify is skipped
+	eventStore.NewBatch()
+	notifies = []*event.ExecuteNotify{
+		crossChainTxNotify(common.Uint256{8}, 2, 3, "aa05", "00", ccom.CROSS_CHAIN_TX_PROVEN),
+		crossChainTxNotify(common.Uint256{9}, 2, 3, "zz", "06", ccom.CROSS_CHAIN_TX_PROVEN),
+	}
+	assert.Nil(t, SaveCrossChainTxs(eventStore, 12, notifies))
+	assert.Nil(t, eventStore.CommitTo())
+
+	txs, err = eventStore.ListCrossChainTxs(2, 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(txs))
+	assert.Equal(t, []byte{3}, txs[2].CrossChainID)
+	assert.Equal(t, uint32(11), txs[2].Height)
+	assert.Equal(t, []byte{0}, txs[3].CrossChainID)
+}
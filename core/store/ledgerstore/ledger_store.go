@@ -44,6 +44,7 @@ import (
 	"github.com/polynetwork/poly/events/message"
 	"github.com/polynetwork/poly/merkle"
 	"github.com/polynetwork/poly/native/event"
+	ccom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	cstates "github.com/polynetwork/poly/native/states"
 	sstate "github.com/polynetwork/poly/native/states"
 	"github.com/polynetwork/poly/native/storage"
@@ -75,6 +76,11 @@ type LedgerStoreImp struct {
 	savingBlockSemaphore chan bool
 	vbftPeerInfoheader   map[string]uint32 //pubInfo save pubkey,peerindex
 	vbftPeerInfoblock    map[string]uint32 //pubInfo save pubkey,peerindex
+	pruneBlocks          uint32            //count of latest blocks whose transactions and events are kept, 0 means keep all
+	prunedHeight         uint32            //transactions and events of blocks up to this height have been pruned
+	pruneCh              chan uint32       //notify pruner of the new block height
+	pruneExit            chan bool
+	pruneWg              sync.WaitGroup
 	lock                 sync.RWMutex
 }
 
@@ -87,6 +93,9 @@ func NewLedgerStore(dataDir string) (*LedgerStoreImp, error) {
 		vbftPeerInfoblock:    make(map[string]uint32),
 		savingBlockSemaphore: make(chan bool, 1),
 	}
+	if !config.DefConfig.Common.ArchiveMode {
+		ledgerStore.pruneBlocks = config.DefConfig.Common.PruneBlocks
+	}
 
 	blockStore, err := NewBlockStore(fmt.Sprintf("%s%s%s", dataDir, string(os.PathSeparator), DBDirBlock), true)
 	if err != nil {
@@ -100,6 +109,14 @@ func NewLedgerStore(dataDir string) (*LedgerStoreImp, error) {
 	if err != nil {
 		return nil, fmt.Errorf("NewStateStore error %s", err)
 	}
+	err = stateStore.InitStorageHistory(config.DefConfig.Common.ArchiveMode)
+	if err != nil {
+		return nil, fmt.Errorf("InitStorageHistory error %s", err)
+	}
+	err = stateStore.InitStateTrie(ledgerStore.pruneBlocks)
+	if err != nil {
+		return nil, fmt.Errorf("InitStateTrie error %s", err)
+	}
 	ledgerStore.stateStore = stateStore
 
 	eventState, err := NewEventStore(fmt.Sprintf("%s%s%s", dataDir, string(os.PathSeparator), DBDirEvent))
@@ -126,6 +143,10 @@ func (this *LedgerStoreImp) InitLedgerStoreWithGenesisBlock(genesisBlock *types.
 		if err != nil {
 			return fmt.Errorf("stateStore.ClearAll error %s", err)
 		}
+		err = this.stateStore.InitStorageHistory(config.DefConfig.Common.ArchiveMode)
+		if err != nil {
+			return fmt.Errorf("stateStore.InitStorageHistory error %s", err)
+		}
 		err = this.eventStore.ClearAll()
 		if err != nil {
 			return fmt.Errorf("eventStore.ClearAll error %s", err)
@@ -154,6 +175,7 @@ func (this *LedgerStoreImp) InitLedgerStoreWithGenesisBlock(genesisBlock *types.
 		}
 		genHash := genesisBlock.Hash()
 		log.Infof("GenesisBlock init success. GenesisBlock hash:%s\n", genHash.ToHexString())
+		this.startPruner()
 	} else {
 		genesisHash := genesisBlock.Hash()
 		exist, err := this.blockStore.ContainBlock(genesisHash)
@@ -233,6 +255,14 @@ func (this *LedgerStoreImp) init() error {
 	if err != nil {
 		return fmt.Errorf("recoverStore error %s", err)
 	}
+	prunedHeight, err := this.blockStore.GetPrunedHeight()
+	if err != nil {
+		return fmt.Errorf("GetPrunedHeight error %s", err)
+	}
+	this.lock.Lock()
+	this.prunedHeight = prunedHeight
+	this.lock.Unlock()
+	this.startPruner()
 	return nil
 }
 
@@ -297,7 +327,7 @@ func (this *LedgerStoreImp) recoverStore() error {
 		if err != nil {
 			return fmt.Errorf("save to state store height:%d error:%s", i, err)
 		}
-		err = this.saveBlockToEventStore(block)
+		err = this.saveBlockToEventStore(block, result)
 		if err != nil {
 			return fmt.Errorf("save to event store height:%d error:%s", i, err)
 		}
@@ -608,6 +638,32 @@ func (this *LedgerStoreImp) GetCrossStatesProof(height uint32, key []byte) ([]by
 	return path, nil
 }
 
+//GetStateTrieRootProof return the proof of the state trie root at height in the cross states of next block, whose
+//cross states root is signed in the header at height+2. scom.ErrNotFound is returned if the root is not committed.
+func (this *LedgerStoreImp) GetStateTrieRootProof(height uint32) ([]byte, error) {
+	if !isStateTrieRootCommitted(height+1) || height+1 > this.GetCurrentBlockHeight() {
+		return nil, scom.ErrNotFound
+	}
+	root, err := this.stateStore.GetStateTrieRoot(height)
+	if err == scom.ErrNotFound {
+		return nil, scom.ErrHistoryUnavailable
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetStateTrieRoot:%s", err)
+	}
+	hashes, err := this.stateStore.GetCrossStates(height + 1)
+	if err != nil {
+		return nil, fmt.Errorf("GetCrossStates:%s", err)
+	}
+	return merkle.MerkleLeafPath(root.ToArray(), hashes)
+}
+
+//isStateTrieRootCommitted return whether the state trie root of previous block is committed to the cross states
+//of block at height
+func isStateTrieRootCommitted(height uint32) bool {
+	return height > 0 && height >= config.GetStateTrieRootHeight(config.DefConfig.P2PNode.NetworkId)
+}
+
 func (this *LedgerStoreImp) saveBlockToBlockStore(block *types.Block) error {
 	blockHash := block.Hash()
 	blockHeight := block.Header.Height
@@ -643,6 +699,15 @@ func (this *LedgerStoreImp) executeBlock(block *types.Block) (result store.Execu
 		result.Notify = append(result.Notify, notify)
 		result.CrossHashes = append(result.CrossHashes, crossHashes...)
 	}
+	if isStateTrieRootCommitted(block.Header.Height) {
+		//commit the state trie root of previous block, so it's signed in the header of next block
+		root, e := this.stateStore.GetStateTrieRoot(block.Header.Height - 1)
+		if e != nil {
+			err = fmt.Errorf("GetStateTrieRoot height:%d error %s", block.Header.Height-1, e)
+			return
+		}
+		result.CrossHashes = append(result.CrossHashes, merkle.HashLeaf(root.ToArray()))
+	}
 	if len(result.CrossHashes) != 0 {
 		result.CrossStatesRoot = merkle.TreeHasher{}.HashFullTreeWithLeafHash(result.CrossHashes)
 	} else {
@@ -695,10 +760,19 @@ func (this *LedgerStoreImp) saveBlockToStateStore(block *types.Block, result sto
 		}
 	})
 
+	err = this.stateStore.AddStorageHistory(blockHeight, result.WriteSet)
+	if err != nil {
+		return fmt.Errorf("AddStorageHistory error %s", err)
+	}
+
+	err = this.stateStore.AddStateTrieRoot(blockHeight, result.WriteSet)
+	if err != nil {
+		return fmt.Errorf("AddStateTrieRoot error %s", err)
+	}
 	return nil
 }
 
-func (this *LedgerStoreImp) saveBlockToEventStore(block *types.Block) error {
+func (this *LedgerStoreImp) saveBlockToEventStore(block *types.Block, result store.ExecuteResult) error {
 	blockHash := block.Hash()
 	blockHeight := block.Header.Height
 	txs := make([]common.Uint256, 0)
@@ -712,7 +786,11 @@ func (this *LedgerStoreImp) saveBlockToEventStore(block *types.Block) error {
 			return fmt.Errorf("SaveEventNotifyByBlock error %s", err)
 		}
 	}
-	err := this.eventStore.SaveCurrentBlock(blockHeight, blockHash)
+	err := SaveCrossChainTxs(this.eventStore, blockHeight, result.Notify)
+	if err != nil {
+		return fmt.Errorf("SaveCrossChainTxs error %s", err)
+	}
+	err = this.eventStore.SaveCurrentBlock(blockHeight, blockHash)
 	if err != nil {
 		return fmt.Errorf("SaveCurrentBlock error %s", err)
 	}
@@ -762,7 +840,7 @@ func (this *LedgerStoreImp) submitBlock(block *types.Block, result store.Execute
 	if err != nil {
 		return fmt.Errorf("save to state store height:%d error:%s", blockHeight, err)
 	}
-	err = this.saveBlockToEventStore(block)
+	err = this.saveBlockToEventStore(block, result)
 	if err != nil {
 		return fmt.Errorf("save to event store height:%d error:%s", blockHeight, err)
 	}
@@ -780,6 +858,7 @@ func (this *LedgerStoreImp) submitBlock(block *types.Block, result store.Execute
 		return fmt.Errorf("stateStore.CommitTo height:%d error %s", blockHeight, err)
 	}
 	this.setCurrentBlock(blockHeight, blockHash)
+	this.notifyPruner(blockHeight)
 
 	if events.DefActorPublisher != nil {
 		events.DefActorPublisher.Publish(
@@ -884,6 +963,42 @@ func (this *LedgerStoreImp) PreExecuteContract(tx *types.Transaction) (*cstates.
 	return &sstate.PreExecResult{State: event.CONTRACT_STATE_SUCCESS, Result: common.ToHexString(res.([]byte)), Notify: service.GetNotify()}, nil
 }
 
+//PreExecuteContracts pre-executes txs in order on the current state, a tx sees the writes of the successful txs
+//before it and the writes of a failed tx are dropped. The Result of a failed tx is its error message
+func (this *LedgerStoreImp) PreExecuteContracts(txs []*types.Transaction) ([]*cstates.PreExecResult, error) {
+	hash := this.GetCurrentBlockHash()
+	block, err := this.GetBlockByHash(hash)
+	if err != nil {
+		return nil, fmt.Errorf("get current block error")
+	}
+	overlay := this.stateStore.NewOverlayDB()
+	cache := storage.NewCacheDB(overlay)
+	timestamp := uint32(time.Now().Unix())
+
+	results := make([]*cstates.PreExecResult, 0, len(txs))
+	for _, tx := range txs {
+		invoke, ok := tx.Payload.(*payload.InvokeCode)
+		if !ok {
+			return nil, fmt.Errorf("transaction payload type error")
+		}
+		service, err := native.NewNativeService(cache, tx, timestamp, block.Header.Height,
+			hash, block.Header.ChainID, invoke.Code, true)
+		if err != nil {
+			return nil, fmt.Errorf("PreExecuteContracts Error: %+v\n", err)
+		}
+		res, err := service.Invoke()
+		if err != nil {
+			cache.Reset()
+			results = append(results, &sstate.PreExecResult{State: event.CONTRACT_STATE_FAIL, Result: err.Error()})
+			continue
+		}
+		cache.Commit()
+		results = append(results, &sstate.PreExecResult{State: event.CONTRACT_STATE_SUCCESS,
+			Result: common.ToHexString(res.([]byte)), Notify: service.GetNotify()})
+	}
+	return results, nil
+}
+
 //IsContainBlock return whether the block is in store
 func (this *LedgerStoreImp) IsContainBlock(blockHash common.Uint256) (bool, error) {
 	return this.blockStore.ContainBlock(blockHash)
@@ -968,6 +1083,30 @@ func (this *LedgerStoreImp) GetStorageItem(key *states.StorageKey) (*states.Stor
 	return this.stateStore.GetStorageState(key)
 }
 
+//GetStorageItemAtHeight return the storage value of the key in smart contract at block height, only available in archive mode.
+//Wrap function of StateStore.GetStorageStateAtHeight
+func (this *LedgerStoreImp) GetStorageItemAtHeight(key *states.StorageKey, height uint32) (*states.StorageItem, error) {
+	if height > this.GetCurrentBlockHeight() {
+		return nil, fmt.Errorf("height %d is higher than current block height %d", height, this.GetCurrentBlockHeight())
+	}
+	return this.stateStore.GetStorageStateAtHeight(key, height)
+}
+
+//GetStorageProof return the storage value of the key in smart contract at block height, the state trie root at
+//the height and the proof of the value in the trie. Wrap function of StateStore.GetStorageProof
+func (this *LedgerStoreImp) GetStorageProof(key *states.StorageKey, height uint32) ([]byte, *merkle.SparseMerkleProof, common.Uint256, error) {
+	if height > this.GetCurrentBlockHeight() {
+		return nil, nil, common.UINT256_EMPTY, fmt.Errorf("height %d is higher than current block height %d", height, this.GetCurrentBlockHeight())
+	}
+	return this.stateStore.GetStorageProof(key, height)
+}
+
+//GetStorageUsage return the count and size of the storage items under the key prefix in smart contract.
+//Wrap function of StateStore.GetStorageUsage
+func (this *LedgerStoreImp) GetStorageUsage(prefix *states.StorageKey) (uint64, uint64, error) {
+	return this.stateStore.GetStorageUsage(prefix)
+}
+
 //GetEventNotifyByTx return the events notify gen by executing of smart contract.  Wrap function of EventStore.GetEventNotifyByTx
 func (this *LedgerStoreImp) GetEventNotifyByTx(tx common.Uint256) (*event.ExecuteNotify, error) {
 	return this.eventStore.GetEventNotifyByTx(tx)
@@ -978,8 +1117,115 @@ func (this *LedgerStoreImp) GetEventNotifyByBlock(height uint32) ([]*event.Execu
 	return this.eventStore.GetEventNotifyByBlock(height)
 }
 
+//GetCrossChainTx return the lifecycle record of cross chain tx. Wrap function of EventStore.GetCrossChainTx
+func (this *LedgerStoreImp) GetCrossChainTx(fromChainID uint64, crossChainID []byte) (*ccom.CrossChainTx, error) {
+	return this.eventStore.GetCrossChainTx(fromChainID, crossChainID)
+}
+
+//ListCrossChainTxs return the lifecycle records of cross chain txs from chain in indexed height order. Wrap function of EventStore.ListCrossChainTxs
+func (this *LedgerStoreImp) ListCrossChainTxs(fromChainID uint64, offset, limit uint32) ([]*ccom.CrossChainTx, error) {
+	return this.eventStore.ListCrossChainTxs(fromChainID, offset, limit)
+}
+
+//startPruner start the background pruning of old blocks if prune mode is enabled
+func (this *LedgerStoreImp) startPruner() {
+	if this.pruneBlocks == 0 || this.pruneCh != nil {
+		return
+	}
+	this.pruneCh = make(chan uint32, 1)
+	this.pruneExit = make(chan bool)
+	this.pruneWg.Add(1)
+	go func() {
+		defer this.pruneWg.Done()
+		for {
+			select {
+			case height := <-this.pruneCh:
+				if height <= this.pruneBlocks {
+					continue
+				}
+				if err := this.pruneTo(height - this.pruneBlocks); err != nil {
+					log.Errorf("prune blocks to height:%d error %s", height-this.pruneBlocks, err)
+				}
+			case <-this.pruneExit:
+				return
+			}
+		}
+	}()
+	this.notifyPruner(this.GetCurrentBlockHeight())
+}
+
+//notifyPruner tell pruner the latest block height, never block the caller
+func (this *LedgerStoreImp) notifyPruner(height uint32) {
+	if this.pruneCh == nil {
+		return
+	}
+	select {
+	case this.pruneCh <- height:
+	default:
+	}
+}
+
+//pruneTo drop the transactions and events of blocks up to height. Genesis block, headers, state merkle roots
+//and cross states roots are always kept, as well as the vbft config blocks needed by consensus.
+func (this *LedgerStoreImp) pruneTo(height uint32) error {
+	for next := this.GetPrunedHeight() + 1; next <= height; next++ {
+		select {
+		case <-this.pruneExit:
+			return nil
+		default:
+		}
+		blockHash := this.GetBlockHash(next)
+		header, err := this.blockStore.GetHeader(blockHash)
+		if err != nil {
+			return fmt.Errorf("GetHeader height:%d error %s", next, err)
+		}
+		if !isConfigBlock(header) {
+			err = this.eventStore.PruneEventNotify(next)
+			if err != nil {
+				return fmt.Errorf("PruneEventNotify height:%d error %s", next, err)
+			}
+			err = this.blockStore.PruneBlock(blockHash)
+			if err != nil {
+				return fmt.Errorf("PruneBlock height:%d error %s", next, err)
+			}
+		}
+		err = this.blockStore.SavePrunedHeight(next)
+		if err != nil {
+			return fmt.Errorf("SavePrunedHeight height:%d error %s", next, err)
+		}
+		this.lock.Lock()
+		this.prunedHeight = next
+		this.lock.Unlock()
+	}
+	return nil
+}
+
+func isConfigBlock(header *types.Header) bool {
+	blkInfo, err := vconfig.VbftBlock(header)
+	if err != nil {
+		return false
+	}
+	return blkInfo.NewChainConfig != nil
+}
+
+//GetPrunedHeight return the height up to which transactions and events of blocks have been pruned
+func (this *LedgerStoreImp) GetPrunedHeight() uint32 {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	return this.prunedHeight
+}
+
+//GetPruneBlocks return the count of latest blocks kept by pruner, 0 means all blocks are kept
+func (this *LedgerStoreImp) GetPruneBlocks() uint32 {
+	return this.pruneBlocks
+}
+
 //Close ledger store.
 func (this *LedgerStoreImp) Close() error {
+	if this.pruneCh != nil {
+		close(this.pruneExit)
+		this.pruneWg.Wait()
+	}
 	err := this.blockStore.Close()
 	if err != nil {
 		return fmt.Errorf("blockStore close error %s", err)
@@ -22,9 +22,18 @@ import (
 	"fmt"
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
 	"github.com/polynetwork/poly/core/genesis"
+	"github.com/polynetwork/poly/core/payload"
+	scom "github.com/polynetwork/poly/core/store/common"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/merkle"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/event"
+	"github.com/polynetwork/poly/native/service/utils"
+	cstates "github.com/polynetwork/poly/native/states"
 	"os"
 	"testing"
 )
@@ -137,3 +146,244 @@ func TestInitLedgerStoreWithGenesisBlock(t *testing.T) {
 		return
 	}
 }
+
+func TestPruneTo(t *testing.T) {
+	ledgerStore, err := NewLedgerStore("test/prune")
+	if err != nil {
+		t.Errorf("NewLedgerStore error %s", err)
+		return
+	}
+	defer ledgerStore.Close()
+
+	txHashes := make([]common.Uint256, 0)
+	for height := uint32(0); height < 4; height++ {
+		tx := &types.Transaction{
+			TxType:  types.Invoke,
+			Nonce:   height,
+			Payload: &payload.InvokeCode{Code: []byte{byte(height)}},
+		}
+		sink := common.NewZeroCopySink(nil)
+		if err = tx.Serialization(sink); err != nil {
+			t.Errorf("Serialization error %s", err)
+			return
+		}
+		tx, err = types.TransactionFromRawBytes(sink.Bytes())
+		if err != nil {
+			t.Errorf("TransactionFromRawBytes error %s", err)
+			return
+		}
+		block := &types.Block{
+			Header:       &types.Header{Height: height},
+			Transactions: []*types.Transaction{tx},
+		}
+		ledgerStore.blockStore.NewBatch()
+		ledgerStore.eventStore.NewBatch()
+		if err = ledgerStore.blockStore.SaveBlock(block); err != nil {
+			t.Errorf("SaveBlock error %s", err)
+			return
+		}
+		txHash := tx.Hash()
+		ledgerStore.eventStore.SaveEventNotifyByTx(txHash, &event.ExecuteNotify{TxHash: txHash})
+		ledgerStore.eventStore.SaveEventNotifyByBlock(height, []common.Uint256{txHash})
+		if err = ledgerStore.blockStore.CommitTo(); err != nil {
+			t.Errorf("blockStore.CommitTo error %s", err)
+			return
+		}
+		if err = ledgerStore.eventStore.CommitTo(); err != nil {
+			t.Errorf("eventStore.CommitTo error %s", err)
+			return
+		}
+		ledgerStore.setHeaderIndex(height, block.Hash())
+		txHashes = append(txHashes, txHash)
+	}
+
+	if err = ledgerStore.pruneTo(2); err != nil {
+		t.Errorf("pruneTo error %s", err)
+		return
+	}
+	if ledgerStore.GetPrunedHeight() != 2 {
+		t.Errorf("TestPruneTo failed PrunedHeight %d != 2", ledgerStore.GetPrunedHeight())
+		return
+	}
+	prunedHeight, err := ledgerStore.blockStore.GetPrunedHeight()
+	if err != nil || prunedHeight != 2 {
+		t.Errorf("TestPruneTo failed stored PrunedHeight %d, error %v", prunedHeight, err)
+		return
+	}
+	for height, txHash := range txHashes {
+		_, txHeight, err := ledgerStore.blockStore.loadTransaction(txHash)
+		pruned := height > 0 && height <= 2
+		if pruned && err != scom.ErrPruned {
+			t.Errorf("TestPruneTo failed tx of height %d should be pruned, error %v", height, err)
+			return
+		}
+		if !pruned && err != nil {
+			t.Errorf("TestPruneTo failed tx of height %d should be kept, error %v", height, err)
+			return
+		}
+		if txHeight != uint32(height) {
+			t.Errorf("TestPruneTo failed tx height %d != %d", txHeight, height)
+			return
+		}
+		exist, err := ledgerStore.blockStore.ContainTransaction(txHash)
+		if err != nil || !exist {
+			t.Errorf("TestPruneTo failed tx of height %d should still be indexed", height)
+			return
+		}
+		_, err = ledgerStore.GetHeaderByHeight(uint32(height))
+		if err != nil {
+			t.Errorf("TestPruneTo failed GetHeaderByHeight %d error %s", height, err)
+			return
+		}
+		_, err = ledgerStore.eventStore.GetEventNotifyByTx(txHash)
+		if pruned && err != scom.ErrNotFound {
+			t.Errorf("TestPruneTo failed event of height %d should be pruned, error %v", height, err)
+			return
+		}
+		if !pruned && err != nil {
+			t.Errorf("TestPruneTo failed event of height %d should be kept, error %v", height, err)
+			return
+		}
+	}
+}
+
+//testPreExecContract stores the args of put, which fails after the write if the args are "fail"
+var testPreExecContract = common.Address{0xff}
+
+func registerTestPreExecContract(service *native.NativeService) {
+	service.Register("put", func(service *native.NativeService) ([]byte, error) {
+		service.GetCacheDB().Put([]byte("key"), service.GetInput())
+		if string(service.GetInput()) == "fail" {
+			return nil, fmt.Errorf("put failed")
+		}
+		return utils.BYTE_TRUE, nil
+	})
+	service.Register("get", func(service *native.NativeService) ([]byte, error) {
+		value, err := service.GetCacheDB().Get([]byte("key"))
+		if err != nil {
+			return nil, err
+		}
+		return value, nil
+	})
+}
+
+func newTestPreExecTx(method string, args []byte) *types.Transaction {
+	invokeParam := &cstates.ContractInvokeParam{Address: testPreExecContract, Method: method, Args: args}
+	sink := common.NewZeroCopySink(nil)
+	invokeParam.Serialization(sink)
+	return &types.Transaction{TxType: types.Invoke, Payload: &payload.InvokeCode{Code: sink.Bytes()}}
+}
+
+func TestPreExecuteContracts(t *testing.T) {
+	consensusType := config.DefConfig.Genesis.ConsensusType
+	config.DefConfig.Genesis.ConsensusType = config.CONSENSUS_TYPE_SOLO
+	defer func() { config.DefConfig.Genesis.ConsensusType = consensusType }()
+	native.Contracts[testPreExecContract] = registerTestPreExecContract
+	defer delete(native.Contracts, testPreExecContract)
+	ledgerStore := newSnapshotLedger(t, "test/preexec", account.NewAccount(""))
+	defer ledgerStore.Close()
+
+	txs := []*types.Transaction{
+		newTestPreExecTx("get", nil),
+		newTestPreExecTx("put", []byte("v1")),
+		newTestPreExecTx("get", nil),
+		newTestPreExecTx("put", []byte("fail")),
+		newTestPreExecTx("get", nil),
+	}
+	results, err := ledgerStore.PreExecuteContracts(txs)
+	if err != nil {
+		t.Errorf("PreExecuteContracts error %s", err)
+		return
+	}
+	if len(results) != len(txs) {
+		t.Errorf("TestPreExecuteContracts failed results %d != %d", len(results), len(txs))
+		return
+	}
+	expected := []struct {
+		state  byte
+		result string
+	}{
+		{event.CONTRACT_STATE_SUCCESS, ""},
+		{event.CONTRACT_STATE_SUCCESS, common.ToHexString(utils.BYTE_TRUE)},
+		// the write of the successful put is seen
+		{event.CONTRACT_STATE_SUCCESS, common.ToHexString([]byte("v1"))},
+		{event.CONTRACT_STATE_FAIL, ""},
+		// the write of the failed put is dropped
+		{event.CONTRACT_STATE_SUCCESS, common.ToHexString([]byte("v1"))},
+	}
+	for i, result := range results {
+		if result.State != expected[i].state {
+			t.Errorf("TestPreExecuteContracts failed tx %d state %d != %d", i, result.State, expected[i].state)
+			return
+		}
+		if result.State == event.CONTRACT_STATE_SUCCESS && result.Result != expected[i].result {
+			t.Errorf("TestPreExecuteContracts failed tx %d result %v != %s", i, result.Result, expected[i].result)
+			return
+		}
+	}
+
+	// pre-execution does not change the state
+	results, err = ledgerStore.PreExecuteContracts(txs[:1])
+	if err != nil || results[0].Result != "" {
+		t.Errorf("TestPreExecuteContracts failed state changed, error %v", err)
+	}
+}
+
+func TestStateTrieRootCommit(t *testing.T) {
+	networkID := config.DefConfig.P2PNode.NetworkId
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+	defer func() { config.DefConfig.P2PNode.NetworkId = networkID }()
+
+	ledgerStore, err := NewLedgerStore("test/trieroot")
+	if err != nil {
+		t.Errorf("NewLedgerStore error %s", err)
+		return
+	}
+	defer ledgerStore.Close()
+
+	crossRoots := make([]common.Uint256, 0)
+	for height := uint32(0); height < 3; height++ {
+		block := &types.Block{Header: &types.Header{Height: height}}
+		result, err := ledgerStore.executeBlock(block)
+		if err != nil {
+			t.Errorf("executeBlock error %s", err)
+			return
+		}
+		ledgerStore.stateStore.NewBatch()
+		if err = ledgerStore.saveBlockToStateStore(block, result); err != nil {
+			t.Errorf("saveBlockToStateStore error %s", err)
+			return
+		}
+		if err = ledgerStore.stateStore.CommitTo(); err != nil {
+			t.Errorf("stateStore.CommitTo error %s", err)
+			return
+		}
+		ledgerStore.setCurrentBlock(height, block.Hash())
+		crossRoots = append(crossRoots, result.CrossStatesRoot)
+	}
+	if crossRoots[0] != common.UINT256_EMPTY {
+		t.Errorf("TestStateTrieRootCommit failed cross states root of genesis block %s", crossRoots[0].ToHexString())
+		return
+	}
+
+	for height := uint32(0); height < 2; height++ {
+		proof, err := ledgerStore.GetStateTrieRootProof(height)
+		if err != nil {
+			t.Errorf("GetStateTrieRootProof height %d error %s", height, err)
+			return
+		}
+		value, err := merkle.MerkleProve(proof, crossRoots[height+1].ToArray())
+		if err != nil {
+			t.Errorf("MerkleProve height %d error %s", height, err)
+			return
+		}
+		root, err := ledgerStore.stateStore.GetStateTrieRoot(height)
+		if err != nil || common.ToHexString(value) != root.ToHexString() {
+			t.Errorf("TestStateTrieRootCommit failed root %x of height %d is not proved, error %v", value, height, err)
+			return
+		}
+	}
+	if _, err = ledgerStore.GetStateTrieRootProof(2); err != scom.ErrNotFound {
+		t.Errorf("TestStateTrieRootCommit failed root of current block should not be committed, error %v", err)
+	}
+}
@@ -0,0 +1,475 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/common/serialization"
+	scom "github.com/polynetwork/poly/core/store/common"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/merkle"
+)
+
+const (
+	SNAPSHOT_VERSION    = byte(1)
+	SNAPSHOT_BATCH_SIZE = 1000 //Count of records committed in one batch when importing snapshot
+)
+
+//SnapshotMetadata describe the ledger state a snapshot is taken at
+type SnapshotMetadata struct {
+	Version         byte
+	Height          uint32
+	BlockHash       common.Uint256
+	BlockTreeSize   uint32
+	BlockTreeHashes []common.Uint256
+	StateTreeSize   uint32
+	StateTreeHashes []common.Uint256
+}
+
+func (this *SnapshotMetadata) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteByte(this.Version)
+	sink.WriteUint32(this.Height)
+	sink.WriteHash(this.BlockHash)
+	sink.WriteUint32(this.BlockTreeSize)
+	sink.WriteVarUint(uint64(len(this.BlockTreeHashes)))
+	for _, hash := range this.BlockTreeHashes {
+		sink.WriteHash(hash)
+	}
+	sink.WriteUint32(this.StateTreeSize)
+	sink.WriteVarUint(uint64(len(this.StateTreeHashes)))
+	for _, hash := range this.StateTreeHashes {
+		sink.WriteHash(hash)
+	}
+}
+
+func (this *SnapshotMetadata) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	this.Version, eof = source.NextByte()
+	if eof {
+		return fmt.Errorf("SnapshotMetadata deserialize version error")
+	}
+	if this.Version != SNAPSHOT_VERSION {
+		return fmt.Errorf("SnapshotMetadata version %d unmatch", this.Version)
+	}
+	this.Height, eof = source.NextUint32()
+	if eof {
+		return fmt.Errorf("SnapshotMetadata deserialize height error")
+	}
+	this.BlockHash, eof = source.NextHash()
+	if eof {
+		return fmt.Errorf("SnapshotMetadata deserialize block hash error")
+	}
+	var err error
+	this.BlockTreeSize, this.BlockTreeHashes, err = deserializeMerkleFrontier(source)
+	if err != nil {
+		return fmt.Errorf("SnapshotMetadata deserialize block tree error: %s", err)
+	}
+	this.StateTreeSize, this.StateTreeHashes, err = deserializeMerkleFrontier(source)
+	if err != nil {
+		return fmt.Errorf("SnapshotMetadata deserialize state tree error: %s", err)
+	}
+	return nil
+}
+
+func deserializeMerkleFrontier(source *common.ZeroCopySource) (uint32, []common.Uint256, error) {
+	treeSize, eof := source.NextUint32()
+	if eof {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	n, eof := source.NextVarUint()
+	if eof {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	hashes := make([]common.Uint256, 0)
+	for i := uint64(0); i < n; i++ {
+		hash, eof := source.NextHash()
+		if eof {
+			return 0, nil, io.ErrUnexpectedEOF
+		}
+		hashes = append(hashes, hash)
+	}
+	return treeSize, hashes, nil
+}
+
+//ExportSnapshot write the ledger at current block height to w. A snapshot is made of the metadata, the header chain
+//with the bodies of the latest blocks and vbft config blocks, the whole state db key space and its digest.
+//The digest covers the metadata and the state records, and should be published out of band, since importers
+//trust the state by it. The ledger should not be written during exporting.
+func (this *LedgerStoreImp) ExportSnapshot(w io.Writer) (uint32, []byte, error) {
+	height, blockHash := this.GetCurrentBlock()
+	meta := &SnapshotMetadata{
+		Version:   SNAPSHOT_VERSION,
+		Height:    height,
+		BlockHash: blockHash,
+	}
+	var err error
+	meta.BlockTreeSize, meta.BlockTreeHashes, err = this.stateStore.GetBlockMerkleTree()
+	if err != nil {
+		return 0, nil, fmt.Errorf("GetBlockMerkleTree error %s", err)
+	}
+	meta.StateTreeSize, meta.StateTreeHashes, err = this.stateStore.GetStateMerkleTree()
+	if err != nil && err != scom.ErrNotFound {
+		return 0, nil, fmt.Errorf("GetStateMerkleTree error %s", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	meta.Serialization(sink)
+	if err = serialization.WriteVarBytes(w, sink.Bytes()); err != nil {
+		return 0, nil, fmt.Errorf("write snapshot metadata error %s", err)
+	}
+	digest := sha256.New()
+	digest.Write(sink.Bytes())
+
+	for h := uint32(1); h <= height; h++ {
+		header, txHashes, err := this.blockStore.loadHeaderWithTx(this.GetBlockHash(h))
+		if err != nil {
+			return 0, nil, fmt.Errorf("load header height:%d error %s", h, err)
+		}
+		sink := common.NewZeroCopySink(nil)
+		header.Serialization(sink)
+		sink.WriteVarUint(uint64(len(txHashes)))
+		for _, txHash := range txHashes {
+			sink.WriteHash(txHash)
+		}
+		withBody := height-h < config.MIN_PRUNE_BLOCKS || isConfigBlock(header)
+		sink.WriteBool(withBody)
+		if withBody {
+			for _, txHash := range txHashes {
+				tx, _, err := this.blockStore.GetTransaction(txHash)
+				if err != nil {
+					return 0, nil, fmt.Errorf("GetTransaction %s height:%d error %s", txHash.ToHexString(), h, err)
+				}
+				sink.WriteVarBytes(tx.Raw)
+			}
+		}
+		if err = serialization.WriteVarBytes(w, sink.Bytes()); err != nil {
+			return 0, nil, fmt.Errorf("write snapshot header height:%d error %s", h, err)
+		}
+	}
+
+	iter := this.stateStore.store.NewIterator(nil)
+	defer iter.Release()
+	for iter.Next() {
+		key := iter.Key()
+		if len(key) == 0 || key[0] == byte(scom.ST_HISTORY) || key[0] == byte(scom.SYS_HISTORY_HEIGHT) {
+			continue
+		}
+		//state trie is rebuilt from the storage when the imported ledger is opened
+		if key[0] == byte(scom.ST_TRIE_NODE) || key[0] == byte(scom.ST_TRIE_REF) ||
+			key[0] == byte(scom.DATA_STATE_TRIE_ROOT) || key[0] == byte(scom.SYS_TRIE_HEIGHT) {
+			continue
+		}
+		sink := common.NewZeroCopySink(nil)
+		sink.WriteVarBytes(key)
+		sink.WriteVarBytes(iter.Value())
+		digest.Write(sink.Bytes())
+		if err = serialization.WriteVarBytes(w, sink.Bytes()); err != nil {
+			return 0, nil, fmt.Errorf("write snapshot state error %s", err)
+		}
+	}
+	if err = iter.Error(); err != nil {
+		return 0, nil, fmt.Errorf("iterate state error %s", err)
+	}
+	//empty record ends the state key space
+	if err = serialization.WriteVarBytes(w, nil); err != nil {
+		return 0, nil, fmt.Errorf("write snapshot state error %s", err)
+	}
+	sum := digest.Sum(nil)
+	if err = serialization.WriteVarBytes(w, sum); err != nil {
+		return 0, nil, fmt.Errorf("write snapshot digest error %s", err)
+	}
+	return height, sum, nil
+}
+
+//ImportSnapshot restore the ledger from snapshot r. The ledger must only have the genesis block.
+//Every header is verified by the bookkeeper signatures from genesis, and the rebuilt block merkle tree
+//must match the BlockRoot in the header at snapshot height. The state is NOT verified against any signed
+//header: the headers only commit to the cross chain states, so the state is trusted as far as trustedDigest,
+//which must be obtained out of band from the exporter, is trusted. The CrossStateRoot and the state merkle
+//root checks only reject inconsistent snapshots.
+//The state is written before the digest can be checked, so the ledger must be removed if importing fails.
+//The blocks below the latest config.MIN_PRUNE_BLOCKS ones are imported as pruned, except vbft config blocks.
+//The ledger should be reopened after importing.
+func (this *LedgerStoreImp) ImportSnapshot(r io.Reader, trustedDigest []byte) (uint32, error) {
+	if len(trustedDigest) != sha256.Size {
+		return 0, fmt.Errorf("invalid trusted snapshot digest length %d", len(trustedDigest))
+	}
+	if this.GetCurrentBlockHeight() != 0 {
+		return 0, fmt.Errorf("ledger is not empty, current block height %d", this.GetCurrentBlockHeight())
+	}
+	data, err := serialization.ReadVarBytes(r)
+	if err != nil {
+		return 0, fmt.Errorf("read snapshot metadata error %s", err)
+	}
+	meta := new(SnapshotMetadata)
+	if err = meta.Deserialization(common.NewZeroCopySource(data)); err != nil {
+		return 0, err
+	}
+	digest := sha256.New()
+	digest.Write(data)
+	if meta.Height == 0 {
+		return 0, nil
+	}
+
+	header, err := this.importSnapshotHeaders(r, meta)
+	if err != nil {
+		return 0, err
+	}
+	if err = this.importSnapshotState(r, digest, trustedDigest); err != nil {
+		return 0, err
+	}
+
+	_, stateHeight, err := this.stateStore.GetCurrentBlock()
+	if err != nil {
+		return 0, fmt.Errorf("stateStore.GetCurrentBlock error %s", err)
+	}
+	if stateHeight != meta.Height {
+		return 0, fmt.Errorf("state height %d unmatch snapshot height %d", stateHeight, meta.Height)
+	}
+	crossStatesRoot, err := this.stateStore.GetCrossStateRoot(meta.Height)
+	if err != nil {
+		return 0, fmt.Errorf("GetCrossStateRoot error %s", err)
+	}
+	if crossStatesRoot != header.CrossStateRoot {
+		return 0, fmt.Errorf("cross states root %s unmatch header %s", crossStatesRoot.ToHexString(), header.CrossStateRoot.ToHexString())
+	}
+	treeSize, hashes, err := this.stateStore.GetStateMerkleTree()
+	if err != nil && err != scom.ErrNotFound {
+		return 0, fmt.Errorf("GetStateMerkleTree error %s", err)
+	}
+	if treeSize != meta.StateTreeSize || !equalHashes(hashes, meta.StateTreeHashes) {
+		return 0, fmt.Errorf("state merkle tree unmatch snapshot metadata")
+	}
+	stateRoot, err := this.stateStore.GetStateMerkleRoot(meta.Height)
+	if err != nil {
+		return 0, fmt.Errorf("GetStateMerkleRoot error %s", err)
+	}
+	if treeSize > 0 && merkle.NewTree(treeSize, hashes, nil).Root() != stateRoot {
+		return 0, fmt.Errorf("state merkle root unmatch state merkle tree")
+	}
+
+	this.eventStore.NewBatch()
+	if err = this.eventStore.SaveCurrentBlock(meta.Height, meta.BlockHash); err != nil {
+		return 0, fmt.Errorf("eventStore.SaveCurrentBlock error %s", err)
+	}
+	if err = this.eventStore.CommitTo(); err != nil {
+		return 0, fmt.Errorf("eventStore.CommitTo error %s", err)
+	}
+	if meta.Height > config.MIN_PRUNE_BLOCKS {
+		if err = this.blockStore.SavePrunedHeight(meta.Height - config.MIN_PRUNE_BLOCKS); err != nil {
+			return 0, fmt.Errorf("SavePrunedHeight error %s", err)
+		}
+	}
+	if this.stateStore.historyEnabled {
+		//history is only available since the snapshot
+		if err = this.stateStore.InitStorageHistory(false); err != nil {
+			return 0, err
+		}
+		if err = this.stateStore.InitStorageHistory(true); err != nil {
+			return 0, err
+		}
+	}
+	return meta.Height, nil
+}
+
+func (this *LedgerStoreImp) importSnapshotHeaders(r io.Reader, meta *SnapshotMetadata) (*types.Header, error) {
+	var header *types.Header
+	vbftPeerInfo := this.vbftPeerInfoheader
+	prevHash := this.GetCurrentBlockHash()
+	this.blockStore.NewBatch()
+	this.stateStore.NewBatch()
+	for h := uint32(1); h <= meta.Height; h++ {
+		data, err := serialization.ReadVarBytes(r)
+		if err != nil {
+			return nil, fmt.Errorf("read snapshot header height:%d error %s", h, err)
+		}
+		source := common.NewZeroCopySource(data)
+		header = new(types.Header)
+		if err = header.Deserialization(source); err != nil {
+			return nil, fmt.Errorf("deserialize header height:%d error %s", h, err)
+		}
+		if header.Height != h {
+			return nil, fmt.Errorf("header height %d unmatch %d", header.Height, h)
+		}
+		txCount, eof := source.NextVarUint()
+		if eof {
+			return nil, fmt.Errorf("deserialize tx count height:%d error", h)
+		}
+		txHashes := make([]common.Uint256, 0)
+		for i := uint64(0); i < txCount; i++ {
+			txHash, eof := source.NextHash()
+			if eof {
+				return nil, fmt.Errorf("deserialize tx hash height:%d error", h)
+			}
+			txHashes = append(txHashes, txHash)
+		}
+		if common.ComputeMerkleRoot(txHashes) != header.TransactionsRoot {
+			return nil, fmt.Errorf("transactions root unmatch height:%d", h)
+		}
+		withBody, eof := source.NextBool()
+		if eof {
+			return nil, fmt.Errorf("deserialize body flag height:%d error", h)
+		}
+		vbftPeerInfo, err = this.verifyHeader(header, vbftPeerInfo)
+		if err != nil {
+			return nil, fmt.Errorf("verifyHeader height:%d error %s", h, err)
+		}
+		blockHash := header.Hash()
+		if withBody {
+			block := &types.Block{Header: header}
+			for _, txHash := range txHashes {
+				raw, eof := source.NextVarBytes()
+				if eof {
+					return nil, fmt.Errorf("deserialize tx height:%d error", h)
+				}
+				tx, err := types.TransactionFromRawBytes(raw)
+				if err != nil {
+					return nil, fmt.Errorf("deserialize tx height:%d error %s", h, err)
+				}
+				if tx.Hash() != txHash {
+					return nil, fmt.Errorf("tx hash unmatch height:%d", h)
+				}
+				block.Transactions = append(block.Transactions, tx)
+			}
+			err = this.blockStore.SaveBlock(block)
+		} else {
+			err = this.blockStore.SavePrunedBlock(header, txHashes)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("save block height:%d error %s", h, err)
+		}
+		this.blockStore.SaveBlockHash(h, blockHash)
+		this.setHeaderIndex(h, blockHash)
+		if err = this.stateStore.AddBlockMerkleTreeRoot(header.PrevBlockHash); err != nil {
+			return nil, fmt.Errorf("AddBlockMerkleTreeRoot height:%d error %s", h, err)
+		}
+		//keep only the previous header in cache for verifying
+		this.addHeaderCache(header)
+		this.delHeaderCache(prevHash)
+		prevHash = blockHash
+
+		if h%SNAPSHOT_BATCH_SIZE == 0 {
+			if err = this.blockStore.CommitTo(); err != nil {
+				return nil, fmt.Errorf("blockStore.CommitTo height:%d error %s", h, err)
+			}
+			if err = this.stateStore.CommitTo(); err != nil {
+				return nil, fmt.Errorf("stateStore.CommitTo height:%d error %s", h, err)
+			}
+			this.blockStore.NewBatch()
+			this.stateStore.NewBatch()
+		}
+	}
+	this.delHeaderCache(prevHash)
+	if prevHash != meta.BlockHash {
+		return nil, fmt.Errorf("block hash %s unmatch snapshot %s", prevHash.ToHexString(), meta.BlockHash.ToHexString())
+	}
+	if this.stateStore.merkleTree.Root() != header.BlockRoot {
+		return nil, fmt.Errorf("block merkle root unmatch header at height %d", meta.Height)
+	}
+	if this.stateStore.merkleTree.TreeSize() != meta.BlockTreeSize || !equalHashes(this.stateStore.merkleTree.Hashes(), meta.BlockTreeHashes) {
+		return nil, fmt.Errorf("block merkle tree unmatch snapshot metadata")
+	}
+
+	this.setCurrentBlock(meta.Height, meta.BlockHash)
+	for {
+		storedIndexCount := this.storedIndexCount
+		if err := this.saveHeaderIndexList(); err != nil {
+			return nil, err
+		}
+		if storedIndexCount == this.storedIndexCount {
+			break
+		}
+	}
+	if err := this.blockStore.SaveCurrentBlock(meta.Height, meta.BlockHash); err != nil {
+		return nil, fmt.Errorf("blockStore.SaveCurrentBlock error %s", err)
+	}
+	if err := this.blockStore.CommitTo(); err != nil {
+		return nil, fmt.Errorf("blockStore.CommitTo error %s", err)
+	}
+	if err := this.stateStore.CommitTo(); err != nil {
+		return nil, fmt.Errorf("stateStore.CommitTo error %s", err)
+	}
+	return header, nil
+}
+
+func (this *LedgerStoreImp) importSnapshotState(r io.Reader, digest hash.Hash, trustedDigest []byte) error {
+	blockTreeKey := this.stateStore.genBlockMerkleTreeKey()
+	this.stateStore.NewBatch()
+	for count := 1; ; count++ {
+		data, err := serialization.ReadVarBytes(r)
+		if err != nil {
+			return fmt.Errorf("read snapshot state error %s", err)
+		}
+		if len(data) == 0 {
+			break
+		}
+		digest.Write(data)
+		source := common.NewZeroCopySource(data)
+		key, eof := source.NextVarBytes()
+		if eof || len(key) == 0 {
+			return fmt.Errorf("deserialize state key error")
+		}
+		value, eof := source.NextVarBytes()
+		if eof {
+			return fmt.Errorf("deserialize state value error")
+		}
+		//block merkle tree has been rebuilt from headers
+		if bytes.Equal(key, blockTreeKey) {
+			continue
+		}
+		this.stateStore.BatchPutRawKeyVal(key, value)
+		if count%SNAPSHOT_BATCH_SIZE == 0 {
+			if err = this.stateStore.CommitTo(); err != nil {
+				return fmt.Errorf("stateStore.CommitTo error %s", err)
+			}
+			this.stateStore.NewBatch()
+		}
+	}
+	if err := this.stateStore.CommitTo(); err != nil {
+		return fmt.Errorf("stateStore.CommitTo error %s", err)
+	}
+	sum, err := serialization.ReadVarBytes(r)
+	if err != nil {
+		return fmt.Errorf("read snapshot digest error %s", err)
+	}
+	if !bytes.Equal(sum, digest.Sum(nil)) {
+		return fmt.Errorf("snapshot state digest unmatch")
+	}
+	if !bytes.Equal(sum, trustedDigest) {
+		return fmt.Errorf("snapshot digest %x unmatch trusted digest %x", sum, trustedDigest)
+	}
+	return nil
+}
+
+func equalHashes(a, b []common.Uint256) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,158 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/core/genesis"
+	"github.com/polynetwork/poly/core/signature"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotMetadata(t *testing.T) {
+	meta := &SnapshotMetadata{
+		Version:         SNAPSHOT_VERSION,
+		Height:          1024,
+		BlockHash:       common.Uint256{1, 2, 3},
+		BlockTreeSize:   1025,
+		BlockTreeHashes: []common.Uint256{{4}, {5}},
+		StateTreeSize:   1024,
+		StateTreeHashes: []common.Uint256{{6}},
+	}
+	sink := common.NewZeroCopySink(nil)
+	meta.Serialization(sink)
+
+	meta2 := new(SnapshotMetadata)
+	assert.Nil(t, meta2.Deserialization(common.NewZeroCopySource(sink.Bytes())))
+	assert.Equal(t, meta, meta2)
+
+	raw := sink.Bytes()
+	raw[0] = SNAPSHOT_VERSION + 1
+	assert.NotNil(t, new(SnapshotMetadata).Deserialization(common.NewZeroCopySource(raw)))
+	assert.NotNil(t, new(SnapshotMetadata).Deserialization(common.NewZeroCopySource(sink.Bytes()[:10])))
+}
+
+func newSnapshotLedger(t *testing.T, dir string, acc *account.Account) *LedgerStoreImp {
+	ledgerStore, err := NewLedgerStore(dir)
+	assert.Nil(t, err)
+	bookkeepers := []keypair.PublicKey{acc.PublicKey}
+	block, err := genesis.BuildGenesisBlock(bookkeepers, config.DefConfig.Genesis)
+	assert.Nil(t, err)
+	assert.Nil(t, ledgerStore.InitLedgerStoreWithGenesisBlock(block, bookkeepers))
+	return ledgerStore
+}
+
+func addSnapshotBlock(t *testing.T, ledgerStore *LedgerStoreImp, acc *account.Account) {
+	height, prevHash := ledgerStore.GetCurrentBlock()
+	prevHeader, err := ledgerStore.GetHeaderByHash(prevHash)
+	assert.Nil(t, err)
+	bookkeepers := []keypair.PublicKey{acc.PublicKey}
+	nextBookkeeper, err := types.AddressFromBookkeepers(bookkeepers)
+	assert.Nil(t, err)
+	block := &types.Block{
+		Header: &types.Header{
+			Version:          types.CURR_HEADER_VERSION,
+			PrevBlockHash:    prevHash,
+			TransactionsRoot: common.ComputeMerkleRoot(nil),
+			CrossStateRoot:   common.UINT256_EMPTY,
+			BlockRoot:        ledgerStore.GetBlockRootWithPreBlockHashes(height+1, []common.Uint256{prevHash}),
+			Timestamp:        prevHeader.Timestamp + 1,
+			Height:           height + 1,
+			NextBookkeeper:   nextBookkeeper,
+			Bookkeepers:      bookkeepers,
+		},
+	}
+	hash := block.Hash()
+	sig, err := signature.Sign(acc, hash[:])
+	assert.Nil(t, err)
+	block.Header.SigData = [][]byte{sig}
+	result, err := ledgerStore.ExecuteBlock(block)
+	assert.Nil(t, err)
+	assert.Nil(t, ledgerStore.SubmitBlock(block, result))
+}
+
+func TestSnapshotExportImport(t *testing.T) {
+	consensusType := config.DefConfig.Genesis.ConsensusType
+	config.DefConfig.Genesis.ConsensusType = config.CONSENSUS_TYPE_SOLO
+	defer func() { config.DefConfig.Genesis.ConsensusType = consensusType }()
+	acc := account.NewAccount("")
+	ledgerStore := newSnapshotLedger(t, "test/snapshot/export", acc)
+	defer ledgerStore.Close()
+	for i := 0; i < 3; i++ {
+		addSnapshotBlock(t, ledgerStore, acc)
+	}
+	buf := new(bytes.Buffer)
+	height, digest, err := ledgerStore.ExportSnapshot(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(3), height)
+	raw := buf.Bytes()
+
+	imported := newSnapshotLedger(t, "test/snapshot/import", acc)
+	defer imported.Close()
+	height, err = imported.ImportSnapshot(bytes.NewReader(raw), digest)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(3), height)
+	assert.Equal(t, ledgerStore.GetCurrentBlockHash(), imported.GetCurrentBlockHash())
+	stateRoot, err := ledgerStore.GetStateMerkleRoot(3)
+	assert.Nil(t, err)
+	importedRoot, err := imported.GetStateMerkleRoot(3)
+	assert.Nil(t, err)
+	assert.Equal(t, stateRoot, importedRoot)
+}
+
+func TestSnapshotImportTampered(t *testing.T) {
+	consensusType := config.DefConfig.Genesis.ConsensusType
+	config.DefConfig.Genesis.ConsensusType = config.CONSENSUS_TYPE_SOLO
+	defer func() { config.DefConfig.Genesis.ConsensusType = consensusType }()
+	acc := account.NewAccount("")
+	ledgerStore := newSnapshotLedger(t, "test/snapshot/origin", acc)
+	defer ledgerStore.Close()
+	addSnapshotBlock(t, ledgerStore, acc)
+	buf := new(bytes.Buffer)
+	_, digest, err := ledgerStore.ExportSnapshot(buf)
+	assert.Nil(t, err)
+
+	// a trusted digest is required
+	imported := newSnapshotLedger(t, "test/snapshot/nodigest", acc)
+	_, err = imported.ImportSnapshot(bytes.NewReader(buf.Bytes()), nil)
+	assert.NotNil(t, err)
+	imported.Close()
+
+	// a consistent snapshot with crafted state, digest rebuilt by the crafter
+	crafted := newSnapshotLedger(t, "test/snapshot/crafted", acc)
+	crafted.stateStore.NewBatch()
+	crafted.stateStore.BatchPutRawKeyVal([]byte{0xff, 1}, []byte{1})
+	assert.Nil(t, crafted.stateStore.CommitTo())
+	addSnapshotBlock(t, crafted, acc)
+	craftedBuf := new(bytes.Buffer)
+	_, craftedDigest, err := crafted.ExportSnapshot(craftedBuf)
+	crafted.Close()
+	assert.Nil(t, err)
+	assert.NotEqual(t, digest, craftedDigest)
+	imported = newSnapshotLedger(t, "test/snapshot/tampered", acc)
+	_, err = imported.ImportSnapshot(bytes.NewReader(craftedBuf.Bytes()), digest)
+	assert.Contains(t, err.Error(), "unmatch trusted digest")
+	imported.Close()
+}
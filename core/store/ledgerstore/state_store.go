@@ -38,6 +38,10 @@ var (
 	BOOKKEEPER = []byte("Bookkeeper") //Bookkeeper store key
 )
 
+const STATE_TRIE_BATCH_SIZE = 10000     //Count of storage items committed in one batch when building state trie
+const STATE_TRIE_PRUNE_BATCH_SIZE = 100 //Count of old state trie roots released at most with one block
+const HISTORY_CLEAR_BATCH_SIZE = 10000  //Count of journaled write sets deleted in one batch when history is disabled
+
 //StateStore saving the data of ledger states. Like balance of account, and the execution result of smart contract
 type StateStore struct {
 	dbDir                string                    //Store file path
@@ -47,6 +51,9 @@ type StateStore struct {
 	deltaMerkleTree      *merkle.CompactMerkleTree //Merkle tree of delta state root
 	merkleHashStore      merkle.HashStore
 	stateHashCheckHeight uint32
+	historyEnabled       bool   //Whether journal the write set of every block
+	historyHeight        uint32 //Storage history is available since this height
+	trieKeepRoots        uint32 //Count of latest state trie roots kept, 0 means keep all
 }
 
 //NewStateStore return state store instance
@@ -368,6 +375,476 @@ func (self *StateStore) GetStorageValue(key []byte) ([]byte, error) {
 	return storageState.Value, nil
 }
 
+//InitStorageHistory enable or disable the storage history journal. History is only available since the height
+//it's enabled, and a disabled journal can not be resumed, since the write sets in between are lost. So the
+//journal is deleted when disabled.
+func (self *StateStore) InitStorageHistory(enable bool) error {
+	key := self.getHistoryHeightKey()
+	if !enable {
+		self.historyEnabled = false
+		if err := self.store.Delete(key); err != nil {
+			return err
+		}
+		return self.clearStorageHistory()
+	}
+	data, err := self.store.Get(key)
+	if err == nil {
+		if len(data) != 4 {
+			return io.ErrUnexpectedEOF
+		}
+		self.historyEnabled = true
+		self.historyHeight = binary.LittleEndian.Uint32(data)
+		return nil
+	}
+	if err != scom.ErrNotFound {
+		return err
+	}
+	//the journal left by an interrupted clear would be taken as the history before the gap
+	if err = self.clearStorageHistory(); err != nil {
+		return err
+	}
+	height := uint32(0)
+	_, currHeight, err := self.GetCurrentBlock()
+	if err == nil {
+		height = currHeight + 1
+	} else if err != scom.ErrNotFound {
+		return err
+	}
+	data = make([]byte, 4)
+	binary.LittleEndian.PutUint32(data, height)
+	if err = self.store.Put(key, data); err != nil {
+		return err
+	}
+	self.historyEnabled = true
+	self.historyHeight = height
+	return nil
+}
+
+//clearStorageHistory deletes all journaled write sets
+func (self *StateStore) clearStorageHistory() error {
+	iter := self.store.NewIterator([]byte{byte(scom.ST_HISTORY)})
+	defer iter.Release()
+	self.store.NewBatch()
+	count := 0
+	for iter.Next() {
+		self.store.BatchDelete(iter.Key())
+		count++
+		if count%HISTORY_CLEAR_BATCH_SIZE == 0 {
+			if err := self.store.BatchCommit(); err != nil {
+				return err
+			}
+			self.store.NewBatch()
+		}
+	}
+	if err := iter.Error(); err != nil {
+		self.store.NewBatch() // reset the batch
+		return err
+	}
+	return self.store.BatchCommit()
+}
+
+//AddStorageHistory journal the write set of block at height
+func (self *StateStore) AddStorageHistory(height uint32, writeSet *overlaydb.MemDB) error {
+	if !self.historyEnabled {
+		return nil
+	}
+	var err error
+	writeSet.ForEach(func(key, val []byte) {
+		if err != nil {
+			return
+		}
+		if self.historyHeight > 0 {
+			err = self.seedStorageHistory(key)
+		}
+		self.store.BatchPut(genHistoryKey(key, height), val)
+	})
+	return err
+}
+
+//seedStorageHistory journal the value before history is enabled, when the key is first written since then
+func (self *StateStore) seedStorageHistory(key []byte) error {
+	iter := self.store.NewIterator(genHistoryPrefix(key))
+	exist := iter.Next()
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	if exist {
+		return nil
+	}
+	value, err := self.store.Get(key)
+	if err != nil && err != scom.ErrNotFound {
+		return err
+	}
+	self.store.BatchPut(genHistoryKey(key, self.historyHeight-1), value)
+	return nil
+}
+
+//GetStorageStateAtHeight return the storage value of the key in smart contract after block at height is executed
+func (self *StateStore) GetStorageStateAtHeight(key *states.StorageKey, height uint32) (*states.StorageItem, error) {
+	if !self.historyEnabled || height < self.historyHeight {
+		return nil, scom.ErrHistoryUnavailable
+	}
+	storeKey, err := self.getStorageKey(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := self.getHistoryValue(storeKey, height)
+	if err != nil {
+		return nil, err
+	}
+	storageState := new(states.StorageItem)
+	err = storageState.Deserialize(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return storageState, nil
+}
+
+func (self *StateStore) getHistoryValue(key []byte, height uint32) ([]byte, error) {
+	iter := self.store.NewIterator(genHistoryPrefix(key))
+	defer iter.Release()
+	journaled := false
+	for iter.Next() {
+		journaled = true
+		historyKey := iter.Key()
+		if ^binary.BigEndian.Uint32(historyKey[len(historyKey)-4:]) > height {
+			continue
+		}
+		if len(iter.Value()) == 0 {
+			return nil, scom.ErrNotFound
+		}
+		return append([]byte{}, iter.Value()...), nil
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	if journaled {
+		return nil, scom.ErrNotFound
+	}
+	//never written since history is enabled
+	return self.store.Get(key)
+}
+
+//GetNode return the state trie node of hash
+func (self *StateStore) GetNode(hash common.Uint256) ([]byte, error) {
+	return self.store.Get(genStateTrieNodeKey(hash))
+}
+
+//GetStorageUsage return the count and the total key and value size of the storage items whose key starts with prefix
+func (self *StateStore) GetStorageUsage(prefix *states.StorageKey) (count, size uint64, err error) {
+	storeKey, err := self.getStorageKey(prefix)
+	if err != nil {
+		return 0, 0, err
+	}
+	iter := self.store.NewIterator(storeKey)
+	defer iter.Release()
+	for iter.Next() {
+		count++
+		size += uint64(len(iter.Key()) + len(iter.Value()))
+	}
+	return count, size, iter.Error()
+}
+
+//InitStateTrie build the state trie from the whole storage if it's missing at current block height,
+//which happens when a ledger created before the state trie or imported from snapshot is opened.
+//Only the latest keepRoots roots are kept with their nodes if keepRoots is not 0.
+func (self *StateStore) InitStateTrie(keepRoots uint32) error {
+	self.trieKeepRoots = keepRoots
+	_, height, err := self.GetCurrentBlock()
+	if err == scom.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = self.GetStateTrieRoot(height)
+	if err != scom.ErrNotFound {
+		return err
+	}
+	log.Infof("building state trie at height %d", height)
+	tree := merkle.NewSparseMerkleTree(common.UINT256_EMPTY, self)
+	iter := self.store.NewIterator([]byte{byte(scom.ST_STORAGE)})
+	defer iter.Release()
+	//the partial tree committed in each batch is referred until the next one is committed
+	partial := common.UINT256_EMPTY
+	commitPartial := func() (*stateTrieRefs, error) {
+		self.store.NewBatch()
+		refs := newStateTrieRefs(self)
+		if err := refs.add(tree.Root(), tree.DirtyNodes()); err != nil {
+			return nil, err
+		}
+		if err := refs.release(partial); err != nil {
+			return nil, err
+		}
+		partial = tree.Root()
+		return refs, nil
+	}
+	count := 0
+	for iter.Next() {
+		if err = putStateTrie(tree, iter.Key(), iter.Value()); err != nil {
+			return err
+		}
+		count++
+		if count%STATE_TRIE_BATCH_SIZE == 0 {
+			refs, err := commitPartial()
+			if err != nil {
+				return err
+			}
+			refs.flush()
+			if err = self.store.BatchCommit(); err != nil {
+				return err
+			}
+		}
+	}
+	if err = iter.Error(); err != nil {
+		return err
+	}
+	refs, err := commitPartial()
+	if err != nil {
+		return err
+	}
+	refs.flush()
+	root := tree.Root()
+	self.store.BatchPut(genStateTrieRootKey(height), root.ToArray())
+	self.store.BatchPut(genStateTrieHeightKey(), genHeightValue(height))
+	if err = self.store.BatchCommit(); err != nil {
+		return err
+	}
+	log.Infof("state trie of %d storage items built, root:%s", count, root.ToHexString())
+	return nil
+}
+
+//AddStateTrieRoot apply the storage write set of block at height to the state trie, and release the roots out
+//of the latest trieKeepRoots ones in the same batch
+func (self *StateStore) AddStateTrieRoot(height uint32, writeSet *overlaydb.MemDB) error {
+	root := common.UINT256_EMPTY
+	if height > 0 {
+		var err error
+		root, err = self.GetStateTrieRoot(height - 1)
+		if err != nil {
+			return fmt.Errorf("GetStateTrieRoot height:%d error %s", height-1, err)
+		}
+	}
+	tree := merkle.NewSparseMerkleTree(root, self)
+	var err error
+	writeSet.ForEach(func(key, val []byte) {
+		if err != nil || len(key) == 0 || key[0] != byte(scom.ST_STORAGE) {
+			return
+		}
+		err = putStateTrie(tree, key, val)
+	})
+	if err != nil {
+		return err
+	}
+	root = tree.Root()
+	refs := newStateTrieRefs(self)
+	if err = refs.add(root, tree.DirtyNodes()); err != nil {
+		return err
+	}
+	self.store.BatchPut(genStateTrieRootKey(height), root.ToArray())
+	if height == 0 {
+		self.store.BatchPut(genStateTrieHeightKey(), genHeightValue(height))
+	}
+	if err = self.pruneStateTrie(refs, height); err != nil {
+		return fmt.Errorf("pruneStateTrie height:%d error %s", height, err)
+	}
+	refs.flush()
+	return nil
+}
+
+//pruneStateTrie release the roots before the latest trieKeepRoots ones at height, a ledger pruned for the first
+//time catches up STATE_TRIE_PRUNE_BATCH_SIZE roots per block
+func (self *StateStore) pruneStateTrie(refs *stateTrieRefs, height uint32) error {
+	if self.trieKeepRoots == 0 || height < self.trieKeepRoots {
+		return nil
+	}
+	data, err := self.store.Get(genStateTrieHeightKey())
+	if err == scom.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(data) != 4 {
+		return io.ErrUnexpectedEOF
+	}
+	start := binary.LittleEndian.Uint32(data)
+	end := height - self.trieKeepRoots + 1
+	if end > start+STATE_TRIE_PRUNE_BATCH_SIZE {
+		end = start + STATE_TRIE_PRUNE_BATCH_SIZE
+	}
+	if end <= start {
+		return nil
+	}
+	for h := start; h < end; h++ {
+		root, err := self.GetStateTrieRoot(h)
+		if err == scom.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err = refs.release(root); err != nil {
+			return err
+		}
+		self.store.BatchDelete(genStateTrieRootKey(h))
+	}
+	self.store.BatchPut(genStateTrieHeightKey(), genHeightValue(end))
+	return nil
+}
+
+//GetStateTrieRoot return the root of state trie after block at height is executed
+func (self *StateStore) GetStateTrieRoot(height uint32) (common.Uint256, error) {
+	data, err := self.store.Get(genStateTrieRootKey(height))
+	if err != nil {
+		return common.UINT256_EMPTY, err
+	}
+	return common.Uint256ParseFromBytes(data)
+}
+
+//GetStorageProof return the storage value of the key in smart contract after block at height is executed, and the
+//proof of it in the state trie of root. A nil value is returned with the proof of absence if key is not set.
+//The root is committed to the cross states of next block from the state trie root height, see
+//LedgerStoreImp.GetStateTrieRootProof.
+func (self *StateStore) GetStorageProof(key *states.StorageKey, height uint32) ([]byte, *merkle.SparseMerkleProof, common.Uint256, error) {
+	root, err := self.GetStateTrieRoot(height)
+	if err == scom.ErrNotFound {
+		return nil, nil, root, scom.ErrHistoryUnavailable
+	}
+	if err != nil {
+		return nil, nil, root, err
+	}
+	storeKey, err := self.getStorageKey(key)
+	if err != nil {
+		return nil, nil, root, err
+	}
+	value, proof, err := merkle.NewSparseMerkleTree(root, self).Prove(storeKey[1:])
+	if err != nil {
+		return nil, nil, root, err
+	}
+	return value, proof, root, nil
+}
+
+//stateTrieRefs tracks the reference counts of state trie nodes changed in a batch. A node is referred by its parent
+//nodes and the root records, and is deleted once it's no longer referred. Nodes are shared by the roots of many
+//heights, so they are never deleted by the roots they belong to.
+type stateTrieRefs struct {
+	store *StateStore
+	refs  map[common.Uint256]uint64
+	nodes map[common.Uint256][]byte //new nodes to persist
+}
+
+func newStateTrieRefs(store *StateStore) *stateTrieRefs {
+	return &stateTrieRefs{
+		store: store,
+		refs:  make(map[common.Uint256]uint64),
+		nodes: make(map[common.Uint256][]byte),
+	}
+}
+
+func (self *stateTrieRefs) get(hash common.Uint256) (uint64, error) {
+	if count, ok := self.refs[hash]; ok {
+		return count, nil
+	}
+	data, err := self.store.store.Get(genStateTrieRefKey(hash))
+	if err == scom.ErrNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(data) != 8 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return binary.LittleEndian.Uint64(data), nil
+}
+
+//add refer the node, a node referred for the first time is taken from the dirty nodes of tree and refers its children
+func (self *stateTrieRefs) add(hash common.Uint256, dirty map[common.Uint256][]byte) error {
+	if hash == common.UINT256_EMPTY {
+		return nil
+	}
+	count, err := self.get(hash)
+	if err != nil {
+		return err
+	}
+	self.refs[hash] = count + 1
+	if count > 0 {
+		return nil
+	}
+	raw, ok := dirty[hash]
+	if !ok {
+		return fmt.Errorf("state trie node %s not found", hash.ToHexString())
+	}
+	self.nodes[hash] = raw
+	for _, child := range merkle.SparseNodeRefs(raw) {
+		if err = self.add(child, dirty); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//release drop a reference of the node, a node no longer referred is deleted and releases its children
+func (self *stateTrieRefs) release(hash common.Uint256) error {
+	if hash == common.UINT256_EMPTY {
+		return nil
+	}
+	count, err := self.get(hash)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("state trie node %s is not referred", hash.ToHexString())
+	}
+	self.refs[hash] = count - 1
+	if count > 1 {
+		return nil
+	}
+	raw, ok := self.nodes[hash]
+	if ok {
+		delete(self.nodes, hash)
+	} else if raw, err = self.store.GetNode(hash); err != nil {
+		return fmt.Errorf("GetNode %s error %s", hash.ToHexString(), err)
+	}
+	for _, child := range merkle.SparseNodeRefs(raw) {
+		if err = self.release(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//flush put the changed nodes and reference counts to the batch of store
+func (self *stateTrieRefs) flush() {
+	for hash, count := range self.refs {
+		if count == 0 {
+			self.store.store.BatchDelete(genStateTrieNodeKey(hash))
+			self.store.store.BatchDelete(genStateTrieRefKey(hash))
+			continue
+		}
+		value := make([]byte, 8)
+		binary.LittleEndian.PutUint64(value, count)
+		self.store.store.BatchPut(genStateTrieRefKey(hash), value)
+	}
+	for hash, raw := range self.nodes {
+		self.store.store.BatchPut(genStateTrieNodeKey(hash), raw)
+	}
+}
+
+//putStateTrie set the storage value in the raw storage item to the state trie, keyed by contract address and key
+func putStateTrie(tree *merkle.SparseMerkleTree, storeKey, raw []byte) error {
+	if len(raw) == 0 {
+		return tree.Delete(storeKey[1:])
+	}
+	item := new(states.StorageItem)
+	if err := item.Deserialize(bytes.NewReader(raw)); err != nil {
+		return fmt.Errorf("deserialize storage item %x error %s", storeKey, err)
+	}
+	return tree.Put(storeKey[1:], item.Value)
+}
+
 //GetCurrentBlock return current block height and current hash in state store
 func (self *StateStore) GetCurrentBlock() (common.Uint256, uint32, error) {
 	key := self.getCurrentBlockKey()
@@ -398,6 +875,10 @@ func (self *StateStore) SaveCurrentBlock(height uint32, blockHash common.Uint256
 	return nil
 }
 
+func (self *StateStore) getHistoryHeightKey() []byte {
+	return []byte{byte(scom.SYS_HISTORY_HEIGHT)}
+}
+
 func (self *StateStore) getCurrentBlockKey() []byte {
 	return []byte{byte(scom.SYS_CURRENT_BLOCK)}
 }
@@ -441,6 +922,47 @@ func (self *StateStore) genStateMerkleTreeKey() []byte {
 	return []byte{byte(scom.SYS_STATE_MERKLE_TREE)}
 }
 
+func genHistoryPrefix(key []byte) []byte {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteByte(byte(scom.ST_HISTORY))
+	sink.WriteVarBytes(key)
+	return sink.Bytes()
+}
+
+//genHistoryKey encode reversed height, so the latest history of key is iterated first
+func genHistoryKey(key []byte, height uint32) []byte {
+	prefix := genHistoryPrefix(key)
+	historyKey := make([]byte, len(prefix)+4)
+	copy(historyKey, prefix)
+	binary.BigEndian.PutUint32(historyKey[len(prefix):], ^height)
+	return historyKey
+}
+
+func genStateTrieNodeKey(hash common.Uint256) []byte {
+	return append([]byte{byte(scom.ST_TRIE_NODE)}, hash[:]...)
+}
+
+func genStateTrieRefKey(hash common.Uint256) []byte {
+	return append([]byte{byte(scom.ST_TRIE_REF)}, hash[:]...)
+}
+
+func genStateTrieHeightKey() []byte {
+	return []byte{byte(scom.SYS_TRIE_HEIGHT)}
+}
+
+func genHeightValue(height uint32) []byte {
+	value := make([]byte, 4)
+	binary.LittleEndian.PutUint32(value, height)
+	return value
+}
+
+func genStateTrieRootKey(height uint32) []byte {
+	key := make([]byte, 5, 5)
+	key[0] = byte(scom.DATA_STATE_TRIE_ROOT)
+	binary.LittleEndian.PutUint32(key[1:], height)
+	return key
+}
+
 func genCrossStatesKey(height uint32) []byte {
 	key := make([]byte, 5, 5)
 	key[0] = byte(scom.SYS_CROSS_STATES)
@@ -19,10 +19,14 @@
 package ledgerstore
 
 import (
+	"fmt"
 	"math/rand"
 	"testing"
 
 	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/states"
+	scom "github.com/polynetwork/poly/core/store/common"
+	"github.com/polynetwork/poly/core/store/overlaydb"
 	"github.com/polynetwork/poly/merkle"
 	"github.com/stretchr/testify/assert"
 )
@@ -71,3 +75,183 @@ func TestStateMerkleRoot(t *testing.T) {
 	}
 
 }
+
+func TestStorageHistory(t *testing.T) {
+	storageKey := &states.StorageKey{ContractAddress: common.Address{1}, Key: []byte("key")}
+	saveBlock := func(db *StateStore, height uint32, value []byte) {
+		key, _ := db.getStorageKey(storageKey)
+		writeSet := overlaydb.NewMemDB(0, 0)
+		if value == nil {
+			writeSet.Delete(key)
+		} else {
+			writeSet.Put(key, states.GenRawStorageItem(value))
+		}
+		db.NewBatch()
+		writeSet.ForEach(func(key, val []byte) {
+			if len(val) == 0 {
+				db.BatchDeleteRawKey(key)
+			} else {
+				db.BatchPutRawKeyVal(key, val)
+			}
+		})
+		assert.Nil(t, db.AddStorageHistory(height, writeSet))
+		assert.Nil(t, db.SaveCurrentBlock(height, common.Uint256{}))
+		assert.Nil(t, db.CommitTo())
+	}
+	checkValue := func(db *StateStore, height uint32, value []byte) {
+		item, err := db.GetStorageStateAtHeight(storageKey, height)
+		if value == nil {
+			assert.Equal(t, scom.ErrNotFound, err)
+			return
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, value, item.Value)
+	}
+
+	db := NewMemStateStore(0)
+	assert.Nil(t, db.InitStorageHistory(true))
+	saveBlock(db, 0, []byte("v0"))
+	saveBlock(db, 1, []byte("v1"))
+	saveBlock(db, 3, nil)
+	saveBlock(db, 4, []byte("v4"))
+	checkValue(db, 0, []byte("v0"))
+	checkValue(db, 1, []byte("v1"))
+	checkValue(db, 2, []byte("v1"))
+	checkValue(db, 3, nil)
+	checkValue(db, 4, []byte("v4"))
+
+	// history enabled after height 1, the value before is seeded when key is first written
+	db = NewMemStateStore(0)
+	assert.Nil(t, db.InitStorageHistory(false))
+	saveBlock(db, 0, []byte("v0"))
+	saveBlock(db, 1, []byte("v1"))
+	assert.Nil(t, db.InitStorageHistory(true))
+	_, err := db.GetStorageStateAtHeight(storageKey, 1)
+	assert.Equal(t, scom.ErrHistoryUnavailable, err)
+	checkValue(db, 2, []byte("v1"))
+	saveBlock(db, 2, []byte("v2"))
+	saveBlock(db, 3, nil)
+	checkValue(db, 2, []byte("v2"))
+	checkValue(db, 3, nil)
+
+	// disabled history can not be resumed
+	assert.Nil(t, db.InitStorageHistory(false))
+	assert.Nil(t, db.InitStorageHistory(true))
+	_, err = db.GetStorageStateAtHeight(storageKey, 3)
+	assert.Equal(t, scom.ErrHistoryUnavailable, err)
+
+	// the journal before a gap is not taken as the value in the gap
+	assert.Nil(t, db.InitStorageHistory(false))
+	saveBlock(db, 4, []byte("v4"))
+	assert.Nil(t, db.InitStorageHistory(true))
+	checkValue(db, 5, []byte("v4"))
+	saveBlock(db, 6, []byte("v6"))
+	checkValue(db, 5, []byte("v4"))
+	checkValue(db, 6, []byte("v6"))
+	_, err = db.GetStorageStateAtHeight(storageKey, 4)
+	assert.Equal(t, scom.ErrHistoryUnavailable, err)
+}
+
+func saveTrieBlock(t *testing.T, db *StateStore, height uint32, values map[string][]byte) {
+	writeSet := overlaydb.NewMemDB(0, 0)
+	for key, value := range values {
+		storeKey, _ := db.getStorageKey(&states.StorageKey{ContractAddress: common.Address{1}, Key: []byte(key)})
+		if value == nil {
+			writeSet.Delete(storeKey)
+		} else {
+			writeSet.Put(storeKey, states.GenRawStorageItem(value))
+		}
+	}
+	db.NewBatch()
+	writeSet.ForEach(func(key, val []byte) {
+		if len(val) == 0 {
+			db.BatchDeleteRawKey(key)
+		} else {
+			db.BatchPutRawKeyVal(key, val)
+		}
+	})
+	assert.Nil(t, db.AddStateTrieRoot(height, writeSet))
+	assert.Nil(t, db.SaveCurrentBlock(height, common.Uint256{}))
+	assert.Nil(t, db.CommitTo())
+}
+
+func checkTrieValue(t *testing.T, db *StateStore, height uint32, key string, value []byte) {
+	storageKey := &states.StorageKey{ContractAddress: common.Address{1}, Key: []byte(key)}
+	val, proof, root, err := db.GetStorageProof(storageKey, height)
+	assert.Nil(t, err)
+	assert.Equal(t, value, val)
+	trieKey := append(storageKey.ContractAddress[:], storageKey.Key...)
+	if value == nil {
+		assert.Nil(t, merkle.VerifySparseMerkleAbsence(root, trieKey, proof))
+	} else {
+		assert.Nil(t, merkle.VerifySparseMerkleInclusion(root, trieKey, value, proof))
+	}
+}
+
+func TestStateTrie(t *testing.T) {
+	db := NewMemStateStore(0)
+	saveTrieBlock(t, db, 0, map[string][]byte{"k1": []byte("v1"), "k2": []byte("v2")})
+	saveTrieBlock(t, db, 1, map[string][]byte{"k1": nil, "k3": []byte("v3")})
+	checkTrieValue(t, db, 0, "k1", []byte("v1"))
+	checkTrieValue(t, db, 0, "k3", nil)
+	checkTrieValue(t, db, 1, "k1", nil)
+	checkTrieValue(t, db, 1, "k2", []byte("v2"))
+	checkTrieValue(t, db, 1, "k3", []byte("v3"))
+
+	// the trie built from the storage of a ledger without trie has the same root
+	root, err := db.GetStateTrieRoot(1)
+	assert.Nil(t, err)
+	db.store.Delete(genStateTrieRootKey(1))
+	assert.Nil(t, db.InitStateTrie(0))
+	rebuilt, err := db.GetStateTrieRoot(1)
+	assert.Nil(t, err)
+	assert.Equal(t, root, rebuilt)
+
+	_, _, _, err = db.GetStorageProof(&states.StorageKey{ContractAddress: common.Address{1}, Key: []byte("k1")}, 2)
+	assert.Equal(t, scom.ErrHistoryUnavailable, err)
+}
+
+func TestStateTriePrune(t *testing.T) {
+	db := NewMemStateStore(0)
+	assert.Nil(t, db.InitStateTrie(2))
+	for height := uint32(0); height < 6; height++ {
+		saveTrieBlock(t, db, height, map[string][]byte{
+			"k1":                       {byte(height)},
+			fmt.Sprintf("k%d", height): {byte(height)},
+		})
+	}
+	for height := uint32(0); height < 4; height++ {
+		_, _, _, err := db.GetStorageProof(&states.StorageKey{ContractAddress: common.Address{1}, Key: []byte("k1")}, height)
+		assert.Equal(t, scom.ErrHistoryUnavailable, err)
+	}
+	checkTrieValue(t, db, 4, "k1", []byte{4})
+	checkTrieValue(t, db, 4, "k5", nil)
+	checkTrieValue(t, db, 5, "k1", []byte{5})
+	checkTrieValue(t, db, 5, "k3", []byte{3})
+
+	// only the nodes of the kept roots are left
+	reachable := make(map[common.Uint256]bool)
+	var walk func(hash common.Uint256)
+	walk = func(hash common.Uint256) {
+		reachable[hash] = true
+		raw, err := db.GetNode(hash)
+		assert.Nil(t, err)
+		for _, child := range merkle.SparseNodeRefs(raw) {
+			walk(child)
+		}
+	}
+	for _, height := range []uint32{4, 5} {
+		root, err := db.GetStateTrieRoot(height)
+		assert.Nil(t, err)
+		walk(root)
+	}
+	for _, prefix := range []scom.DataEntryPrefix{scom.ST_TRIE_NODE, scom.ST_TRIE_REF} {
+		iter := db.store.NewIterator([]byte{byte(prefix)})
+		count := 0
+		for iter.Next() {
+			count++
+		}
+		iter.Release()
+		assert.Equal(t, len(reachable), count)
+	}
+}
@@ -19,9 +19,11 @@
 package ledgerstore
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/common/log"
 	"github.com/polynetwork/poly/core/payload"
 	"github.com/polynetwork/poly/core/store"
 	scommon "github.com/polynetwork/poly/core/store/common"
@@ -29,6 +31,9 @@ import (
 	"github.com/polynetwork/poly/core/types"
 	"github.com/polynetwork/poly/native"
 	"github.com/polynetwork/poly/native/event"
+	ccom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/states"
 	"github.com/polynetwork/poly/native/storage"
 )
 
@@ -42,6 +47,9 @@ func (self *StateStore) HandleInvokeTransaction(store store.LedgerStore, overlay
 		return nil, fmt.Errorf("HandleInvokeTransaction Error: %+v\n", err)
 	}
 	if _, err := service.Invoke(); err != nil {
+		if misbehavior := service.GetMisbehavior(); misbehavior != nil {
+			handleFailedInvoke(cache, tx, block, invoke.Code, misbehavior, notify)
+		}
 		return nil, err
 	}
 	notify.Notify = append(notify.Notify, service.GetNotify()...)
@@ -50,6 +58,34 @@ func (self *StateStore) HandleInvokeTransaction(store store.LedgerStore, overlay
 	return service.GetCrossHashes(), nil
 }
 
+//handleFailedInvoke runs the failed tx handler registered for the contract invoked by tx with the misbehavior
+//failing the invoke, the writes of the failed invoke are dropped and the ones of the handler are committed
+func handleFailedInvoke(cache *storage.CacheDB, tx *types.Transaction, block *types.Block, code []byte,
+	misbehavior error, notify *event.ExecuteNotify) {
+	param := new(states.ContractInvokeParam)
+	if err := param.Deserialization(common.NewZeroCopySource(code)); err != nil {
+		return
+	}
+	handler, ok := native.FailedTxHandlers[param.Address]
+	if !ok {
+		return
+	}
+	cache.Reset()
+	service, err := native.NewNativeService(cache, tx, block.Header.Timestamp, block.Header.Height,
+		block.Hash(), block.Header.ChainID, code, false)
+	if err != nil {
+		return
+	}
+	service.Misbehave(misbehavior)
+	if _, err := handler(service); err != nil {
+		txHash := tx.Hash()
+		log.Debugf("handleFailedInvoke tx %s error %s", txHash.ToHexString(), err)
+		return
+	}
+	notify.Notify = append(notify.Notify, service.GetNotify()...)
+	cache.Commit()
+}
+
 func SaveNotify(eventStore scommon.EventStore, txHash common.Uint256, notify *event.ExecuteNotify) error {
 	if !config.DefConfig.Common.EnableEventLog {
 		return nil
@@ -60,3 +96,64 @@ func SaveNotify(eventStore scommon.EventStore, txHash common.Uint256, notify *ev
 	event.PushSmartCodeEvent(txHash, 0, event.EVENT_NOTIFY, notify)
 	return nil
 }
+
+//SaveCrossChainTxs index the lifecycle notifies of cross chain manager contract in block
+func SaveCrossChainTxs(eventStore *EventStore, height uint32, notifies []*event.ExecuteNotify) error {
+	if !config.DefConfig.Common.EnableEventLog {
+		return nil
+	}
+	// txs saved by batch are not readable before commit, so keep the ones of this block in cache
+	cache := make(map[string]*ccom.CrossChainTx)
+	getCrossChainTx := func(fromChainID uint64, txHash []byte) (*ccom.CrossChainTx, error) {
+		for _, tx := range cache {
+			if tx.FromChainID == fromChainID && bytes.Equal(tx.TxHash, txHash) {
+				return tx, nil
+			}
+		}
+		crossChainID, err := eventStore.GetCrossChainID(fromChainID, txHash)
+		if err != nil {
+			return nil, err
+		}
+		return eventStore.GetCrossChainTx(fromChainID, crossChainID)
+	}
+	for _, notify := range notifies {
+		if notify.State != event.CONTRACT_STATE_SUCCESS {
+			continue
+		}
+		for _, info := range notify.Notify {
+			if info.ContractAddress != utils.CrossChainManagerContractAddress {
+				continue
+			}
+			states, ok := info.States.([]interface{})
+			if !ok || len(states) == 0 || states[0] != ccom.NOTIFY_CROSS_CHAIN_TX {
+				continue
+			}
+			// the index is only an auxiliary lookup, a malformed notify must not stop the block
+			tx, err := ccom.ParseCrossChainTxNotify(states)
+			if err != nil {
+				log.Warnf("SaveCrossChainTxs, parse cross chain tx notify of tx %s error: %s", notify.TxHash.ToHexString(), err)
+				continue
+			}
+			if len(tx.CrossChainID) == 0 {
+				record, err := getCrossChainTx(tx.FromChainID, tx.TxHash)
+				if err == scommon.ErrNotFound {
+					log.Warnf("SaveCrossChainTxs, cross chain tx %x from chain %d is not indexed", tx.TxHash, tx.FromChainID)
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				tx.CrossChainID = record.CrossChainID
+			}
+			tx.PolyTxHash = notify.TxHash
+			tx.Height = height
+			cache[string(utils.GetUint64Bytes(tx.FromChainID))+string(tx.CrossChainID)] = tx
+		}
+	}
+	for _, tx := range cache {
+		if err := eventStore.SaveCrossChainTx(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -19,12 +19,16 @@
 package store
 
 import (
+	"io"
+
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/core/store/overlaydb"
 	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/merkle"
 	"github.com/polynetwork/poly/native/event"
+	ccom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	cstates "github.com/polynetwork/poly/native/states"
 )
 
@@ -62,9 +66,19 @@ type LedgerStore interface {
 	GetBlockRootWithPreBlockHashes(startHeight uint32, txRoots []common.Uint256) common.Uint256
 	GetMerkleProof(raw []byte, m, n uint32) ([]byte, error)
 	GetCrossStatesProof(height uint32, key []byte) ([]byte, error)
+	GetStateTrieRootProof(height uint32) ([]byte, error)
 	GetBookkeeperState() (*states.BookkeeperState, error)
 	GetStorageItem(key *states.StorageKey) (*states.StorageItem, error)
+	GetStorageItemAtHeight(key *states.StorageKey, height uint32) (*states.StorageItem, error)
+	GetStorageProof(key *states.StorageKey, height uint32) ([]byte, *merkle.SparseMerkleProof, common.Uint256, error)
+	GetStorageUsage(prefix *states.StorageKey) (count, size uint64, err error)
 	PreExecuteContract(tx *types.Transaction) (*cstates.PreExecResult, error)
+	PreExecuteContracts(txs []*types.Transaction) ([]*cstates.PreExecResult, error)
 	GetEventNotifyByTx(tx common.Uint256) (*event.ExecuteNotify, error)
 	GetEventNotifyByBlock(height uint32) ([]*event.ExecuteNotify, error)
+	GetCrossChainTx(fromChainID uint64, crossChainID []byte) (*ccom.CrossChainTx, error)
+	ListCrossChainTxs(fromChainID uint64, offset, limit uint32) ([]*ccom.CrossChainTx, error)
+	GetPrunedHeight() uint32
+	ExportSnapshot(w io.Writer) (uint32, []byte, error)
+	ImportSnapshot(r io.Reader, trustedDigest []byte) (uint32, error)
 }
@@ -20,9 +20,10 @@ package actor
 
 import (
 	"github.com/polynetwork/poly/common"
-	"github.com/polynetwork/poly/core/ledger"
 	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/merkle"
 	"github.com/polynetwork/poly/native/event"
+	ccom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	cstate "github.com/polynetwork/poly/native/states"
 )
 
@@ -33,79 +34,119 @@ const (
 
 //GetHeaderByHeight from ledger
 func GetHeaderByHeight(height uint32) (*types.Header, error) {
-	return ledger.DefLedger.GetHeaderByHeight(height)
+	return getLedger().GetHeaderByHeight(height)
 }
 
 //GetBlockByHeight from ledger
 func GetBlockByHeight(height uint32) (*types.Block, error) {
-	return ledger.DefLedger.GetBlockByHeight(height)
+	return getLedger().GetBlockByHeight(height)
 }
 
 //GetBlockHashFromStore from ledger
 func GetBlockHashFromStore(height uint32) common.Uint256 {
-	return ledger.DefLedger.GetBlockHash(height)
+	return getLedger().GetBlockHash(height)
 }
 
 //CurrentBlockHash from ledger
 func CurrentBlockHash() common.Uint256 {
-	return ledger.DefLedger.GetCurrentBlockHash()
+	return getLedger().GetCurrentBlockHash()
 }
 
 // GetStateMerkleRoot from ledger
 func GetStateMerkleRoot(height uint32) (common.Uint256, error) {
-	return ledger.DefLedger.GetStateMerkleRoot(height)
+	return getLedger().GetStateMerkleRoot(height)
 }
 
 //GetBlockFromStore from ledger
 func GetBlockFromStore(hash common.Uint256) (*types.Block, error) {
-	return ledger.DefLedger.GetBlockByHash(hash)
+	return getLedger().GetBlockByHash(hash)
 }
 
 //GetCurrentBlockHeight from ledger
 func GetCurrentBlockHeight() uint32 {
-	return ledger.DefLedger.GetCurrentBlockHeight()
+	return getLedger().GetCurrentBlockHeight()
 }
 
 //GetTransaction from ledger
 func GetTransaction(hash common.Uint256) (*types.Transaction, error) {
-	return ledger.DefLedger.GetTransaction(hash)
+	return getLedger().GetTransaction(hash)
 }
 
 //GetStorageItem from ledger
 func GetStorageItem(address common.Address, key []byte) ([]byte, error) {
-	return ledger.DefLedger.GetStorageItem(address, key)
+	return getLedger().GetStorageItem(address, key)
+}
+
+//GetStorageItemAtHeight from ledger
+func GetStorageItemAtHeight(address common.Address, key []byte, height uint32) ([]byte, error) {
+	return getLedger().GetStorageItemAtHeight(address, key, height)
+}
+
+//GetStorageProof from ledger
+func GetStorageProof(address common.Address, key []byte, height uint32) ([]byte, *merkle.SparseMerkleProof, common.Uint256, error) {
+	return getLedger().GetStorageProof(address, key, height)
 }
 
 //GetTxnWithHeightByTxHash from ledger
 func GetTxnWithHeightByTxHash(hash common.Uint256) (uint32, *types.Transaction, error) {
-	tx, height, err := ledger.DefLedger.GetTransactionWithHeight(hash)
+	tx, height, err := getLedger().GetTransactionWithHeight(hash)
 	return height, tx, err
 }
 
 //PreExecuteContract from ledger
 func PreExecuteContract(tx *types.Transaction) (*cstate.PreExecResult, error) {
-	return ledger.DefLedger.PreExecuteContract(tx)
+	return getLedger().PreExecuteContract(tx)
+}
+
+//PreExecuteContracts from ledger
+func PreExecuteContracts(txs []*types.Transaction) ([]*cstate.PreExecResult, error) {
+	return getLedger().PreExecuteContracts(txs)
 }
 
 //GetEventNotifyByTxHash from ledger
 func GetEventNotifyByTxHash(txHash common.Uint256) (*event.ExecuteNotify, error) {
-	return ledger.DefLedger.GetEventNotifyByTx(txHash)
+	return getLedger().GetEventNotifyByTx(txHash)
 }
 
 //GetEventNotifyByHeight from ledger
 func GetEventNotifyByHeight(height uint32) ([]*event.ExecuteNotify, error) {
-	return ledger.DefLedger.GetEventNotifyByBlock(height)
+	return getLedger().GetEventNotifyByBlock(height)
+}
+
+//GetCrossChainTx from ledger
+func GetCrossChainTx(fromChainID uint64, crossChainID []byte) (*ccom.CrossChainTx, error) {
+	return getLedger().GetCrossChainTx(fromChainID, crossChainID)
+}
+
+//ListCrossChainTxs from ledger
+func ListCrossChainTxs(fromChainID uint64, offset, limit uint32) ([]*ccom.CrossChainTx, error) {
+	return getLedger().ListCrossChainTxs(fromChainID, offset, limit)
+}
+
+//GetStorageUsage from ledger
+func GetStorageUsage(address common.Address, prefix []byte) (uint64, uint64, error) {
+	return getLedger().GetStorageUsage(address, prefix)
+}
+
+//GetPrunedHeight from ledger
+func GetPrunedHeight() uint32 {
+	return getLedger().GetPrunedHeight()
 }
 
 //GetMerkleProof from ledger
 func GetMerkleProof(proofHeight uint32, rootHeight uint32) ([]byte, error) {
-	return ledger.DefLedger.GetMerkleProof(proofHeight, rootHeight)
+	return getLedger().GetMerkleProof(proofHeight, rootHeight)
 }
 
 func GetCrossStatesProof(height uint32, key []byte) ([]byte, error) {
-	return ledger.DefLedger.GetCrossStatesProof(height, key)
+	return getLedger().GetCrossStatesProof(height, key)
+}
+
+//GetStateTrieRootProof from ledger
+func GetStateTrieRootProof(height uint32) ([]byte, error) {
+	return getLedger().GetStateTrieRootProof(height)
 }
 
 func GetCrossStateRoot(height uint32) (common.Uint256, error) {
-	return ledger.DefLedger.GetCrossStateRoot(height)
+	return getLedger().GetCrossStateRoot(height)
 }
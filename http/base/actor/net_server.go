@@ -19,8 +19,8 @@
 package actor
 
 import (
-	"errors"
-	"time"
+	"context"
+	"fmt"
 
 	"github.com/ontio/ontology-eventbus/actor"
 	"github.com/polynetwork/poly/common/log"
@@ -28,179 +28,268 @@ import (
 	"github.com/polynetwork/poly/p2pserver/common"
 )
 
-var netServerPid *actor.PID
+//actorP2PService implements P2PService over the p2p server actor
+type actorP2PService struct {
+	pid *actor.PID
+}
 
-func SetNetServerPID(actr *actor.PID) {
-	netServerPid = actr
+//NewActorP2PService returns a P2PService backed by the p2p server actor
+func NewActorP2PService(pid *actor.PID) P2PService {
+	return &actorP2PService{pid: pid}
 }
 
-//Xmit to netSever actor
-func Xmit(msg interface{}) error {
-	if netServerPid == nil {
-		return nil
-	}
-	netServerPid.Tell(msg)
+func (self *actorP2PService) Xmit(msg interface{}) error {
+	self.pid.Tell(msg)
 	return nil
 }
 
-//GetConnectionCnt from netSever actor
-func GetConnectionCnt() (uint32, error) {
-	if netServerPid == nil {
-		return 1, nil
+func (self *actorP2PService) GetNeighborAddrs(ctx context.Context) ([]common.PeerAddr, error) {
+	result, err := requestActor(ctx, self.pid, &ac.GetNeighborAddrsReq{})
+	if err != nil {
+		return nil, err
 	}
-	future := netServerPid.RequestFuture(&ac.GetConnectionCntReq{}, REQ_TIMEOUT*time.Second)
-	result, err := future.Result()
+	r, ok := result.(*ac.GetNeighborAddrsRsp)
+	if !ok {
+		return nil, fmt.Errorf("unexpected p2p response %T", result)
+	}
+	return r.Addrs, nil
+}
+
+func (self *actorP2PService) GetConnectionCnt(ctx context.Context) (uint32, error) {
+	result, err := requestActor(ctx, self.pid, &ac.GetConnectionCntReq{})
 	if err != nil {
-		log.Errorf(ERR_ACTOR_COMM, err)
 		return 0, err
 	}
 	r, ok := result.(*ac.GetConnectionCntRsp)
 	if !ok {
-		return 0, errors.New("fail")
+		return 0, fmt.Errorf("unexpected p2p response %T", result)
 	}
 	return r.Cnt, nil
 }
 
-//GetNeighborAddrs from netSever actor
+func (self *actorP2PService) GetConnectionState(ctx context.Context) (uint32, error) {
+	result, err := requestActor(ctx, self.pid, &ac.GetConnectionStateReq{})
+	if err != nil {
+		return 0, err
+	}
+	r, ok := result.(*ac.GetConnectionStateRsp)
+	if !ok {
+		return 0, fmt.Errorf("unexpected p2p response %T", result)
+	}
+	return r.State, nil
+}
+
+func (self *actorP2PService) GetTime(ctx context.Context) (int64, error) {
+	result, err := requestActor(ctx, self.pid, &ac.GetTimeReq{})
+	if err != nil {
+		return 0, err
+	}
+	r, ok := result.(*ac.GetTimeRsp)
+	if !ok {
+		return 0, fmt.Errorf("unexpected p2p response %T", result)
+	}
+	return r.Time, nil
+}
+
+func (self *actorP2PService) GetPort(ctx context.Context) (uint16, error) {
+	result, err := requestActor(ctx, self.pid, &ac.GetPortReq{})
+	if err != nil {
+		return 0, err
+	}
+	r, ok := result.(*ac.GetPortRsp)
+	if !ok {
+		return 0, fmt.Errorf("unexpected p2p response %T", result)
+	}
+	return r.SyncPort, nil
+}
+
+func (self *actorP2PService) GetID(ctx context.Context) (uint64, error) {
+	result, err := requestActor(ctx, self.pid, &ac.GetIdReq{})
+	if err != nil {
+		return 0, err
+	}
+	r, ok := result.(*ac.GetIdRsp)
+	if !ok {
+		return 0, fmt.Errorf("unexpected p2p response %T", result)
+	}
+	return r.Id, nil
+}
+
+func (self *actorP2PService) GetRelayState(ctx context.Context) (bool, error) {
+	result, err := requestActor(ctx, self.pid, &ac.GetRelayStateReq{})
+	if err != nil {
+		return false, err
+	}
+	r, ok := result.(*ac.GetRelayStateRsp)
+	if !ok {
+		return false, fmt.Errorf("unexpected p2p response %T", result)
+	}
+	return r.Relay, nil
+}
+
+func (self *actorP2PService) GetVersion(ctx context.Context) (uint32, error) {
+	result, err := requestActor(ctx, self.pid, &ac.GetVersionReq{})
+	if err != nil {
+		return 0, err
+	}
+	r, ok := result.(*ac.GetVersionRsp)
+	if !ok {
+		return 0, fmt.Errorf("unexpected p2p response %T", result)
+	}
+	return r.Version, nil
+}
+
+func (self *actorP2PService) GetNodeType(ctx context.Context) (uint64, error) {
+	result, err := requestActor(ctx, self.pid, &ac.GetNodeTypeReq{})
+	if err != nil {
+		return 0, err
+	}
+	r, ok := result.(*ac.GetNodeTypeRsp)
+	if !ok {
+		return 0, fmt.Errorf("unexpected p2p response %T", result)
+	}
+	return r.NodeType, nil
+}
+
+//Xmit to p2p network
+func Xmit(msg interface{}) error {
+	if p2pService == nil {
+		return nil
+	}
+	return p2pService.Xmit(msg)
+}
+
+//GetNeighborAddrs from p2p network
 func GetNeighborAddrs() []common.PeerAddr {
-	if netServerPid == nil {
+	if p2pService == nil {
 		return []common.PeerAddr{}
 	}
-	future := netServerPid.RequestFuture(&ac.GetNeighborAddrsReq{}, REQ_TIMEOUT*time.Second)
-	result, err := future.Result()
+	ctx, cancel := newRequestContext()
+	defer cancel()
+	addrs, err := p2pService.GetNeighborAddrs(ctx)
 	if err != nil {
 		log.Errorf(ERR_ACTOR_COMM, err)
 		return nil
 	}
-	r, ok := result.(*ac.GetNeighborAddrsRsp)
-	if !ok {
-		return nil
+	return addrs
+}
+
+//GetConnectionCnt from p2p network
+func GetConnectionCnt() (uint32, error) {
+	if p2pService == nil {
+		return 1, nil
+	}
+	ctx, cancel := newRequestContext()
+	defer cancel()
+	v, err := p2pService.GetConnectionCnt(ctx)
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return 0, err
 	}
-	return r.Addrs
+	return v, nil
 }
 
-//GetConnectionState from netSever actor
+//GetConnectionState from p2p network
 func GetConnectionState() (uint32, error) {
-	if netServerPid == nil {
+	if p2pService == nil {
 		return 0, nil
 	}
-	future := netServerPid.RequestFuture(&ac.GetConnectionStateReq{}, REQ_TIMEOUT*time.Second)
-	result, err := future.Result()
+	ctx, cancel := newRequestContext()
+	defer cancel()
+	v, err := p2pService.GetConnectionState(ctx)
 	if err != nil {
 		log.Errorf(ERR_ACTOR_COMM, err)
 		return 0, err
 	}
-	r, ok := result.(*ac.GetConnectionStateRsp)
-	if !ok {
-		return 0, errors.New("fail")
-	}
-	return r.State, nil
+	return v, nil
 }
 
-//GetNodeTime from netSever actor
+//GetNodeTime from p2p network
 func GetNodeTime() (int64, error) {
-	if netServerPid == nil {
+	if p2pService == nil {
 		return 0, nil
 	}
-	future := netServerPid.RequestFuture(&ac.GetTimeReq{}, REQ_TIMEOUT*time.Second)
-	result, err := future.Result()
+	ctx, cancel := newRequestContext()
+	defer cancel()
+	v, err := p2pService.GetTime(ctx)
 	if err != nil {
 		log.Errorf(ERR_ACTOR_COMM, err)
 		return 0, err
 	}
-	r, ok := result.(*ac.GetTimeRsp)
-	if !ok {
-		return 0, errors.New("fail")
-	}
-	return r.Time, nil
+	return v, nil
 }
 
-//GetNodePort from netSever actor
+//GetNodePort from p2p network
 func GetNodePort() (uint16, error) {
-	if netServerPid == nil {
+	if p2pService == nil {
 		return 0, nil
 	}
-	future := netServerPid.RequestFuture(&ac.GetPortReq{}, REQ_TIMEOUT*time.Second)
-	result, err := future.Result()
+	ctx, cancel := newRequestContext()
+	defer cancel()
+	v, err := p2pService.GetPort(ctx)
 	if err != nil {
 		log.Errorf(ERR_ACTOR_COMM, err)
 		return 0, err
 	}
-	r, ok := result.(*ac.GetPortRsp)
-	if !ok {
-		return 0, errors.New("fail")
-	}
-	return r.SyncPort, nil
+	return v, nil
 }
 
-//GetID from netSever actor
+//GetID from p2p network
 func GetID() (uint64, error) {
-	if netServerPid == nil {
+	if p2pService == nil {
 		return 0, nil
 	}
-	future := netServerPid.RequestFuture(&ac.GetIdReq{}, REQ_TIMEOUT*time.Second)
-	result, err := future.Result()
+	ctx, cancel := newRequestContext()
+	defer cancel()
+	v, err := p2pService.GetID(ctx)
 	if err != nil {
 		log.Errorf(ERR_ACTOR_COMM, err)
 		return 0, err
 	}
-	r, ok := result.(*ac.GetIdRsp)
-	if !ok {
-		return 0, errors.New("fail")
-	}
-	return r.Id, nil
+	return v, nil
 }
 
-//GetRelayState from netSever actor
+//GetRelayState from p2p network
 func GetRelayState() (bool, error) {
-	if netServerPid == nil {
+	if p2pService == nil {
 		return false, nil
 	}
-	future := netServerPid.RequestFuture(&ac.GetRelayStateReq{}, REQ_TIMEOUT*time.Second)
-	result, err := future.Result()
+	ctx, cancel := newRequestContext()
+	defer cancel()
+	v, err := p2pService.GetRelayState(ctx)
 	if err != nil {
 		log.Errorf(ERR_ACTOR_COMM, err)
 		return false, err
 	}
-	r, ok := result.(*ac.GetRelayStateRsp)
-	if !ok {
-		return false, errors.New("fail")
-	}
-	return r.Relay, nil
+	return v, nil
 }
 
-//GetVersion from netSever actor
+//GetVersion from p2p network
 func GetVersion() (uint32, error) {
-	if netServerPid == nil {
+	if p2pService == nil {
 		return 0, nil
 	}
-	future := netServerPid.RequestFuture(&ac.GetVersionReq{}, REQ_TIMEOUT*time.Second)
-	result, err := future.Result()
+	ctx, cancel := newRequestContext()
+	defer cancel()
+	v, err := p2pService.GetVersion(ctx)
 	if err != nil {
 		log.Errorf(ERR_ACTOR_COMM, err)
 		return 0, err
 	}
-	r, ok := result.(*ac.GetVersionRsp)
-	if !ok {
-		return 0, errors.New("fail")
-	}
-	return r.Version, nil
+	return v, nil
 }
 
-//GetNodeType from netSever actor
+//GetNodeType from p2p network
 func GetNodeType() (uint64, error) {
-	if netServerPid == nil {
+	if p2pService == nil {
 		return 0, nil
 	}
-	future := netServerPid.RequestFuture(&ac.GetNodeTypeReq{}, REQ_TIMEOUT*time.Second)
-	result, err := future.Result()
+	ctx, cancel := newRequestContext()
+	defer cancel()
+	v, err := p2pService.GetNodeType(ctx)
 	if err != nil {
 		log.Errorf(ERR_ACTOR_COMM, err)
 		return 0, err
 	}
-	r, ok := result.(*ac.GetNodeTypeRsp)
-	if !ok {
-		return 0, errors.New("fail")
-	}
-	return r.NodeType, nil
+	return v, nil
 }
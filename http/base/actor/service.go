@@ -0,0 +1,153 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package actor
+
+import (
+	"context"
+	"time"
+
+	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/ledger"
+	"github.com/polynetwork/poly/core/types"
+	polyErrors "github.com/polynetwork/poly/errors"
+	"github.com/polynetwork/poly/merkle"
+	"github.com/polynetwork/poly/native/event"
+	ccom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	cstate "github.com/polynetwork/poly/native/states"
+	p2pcommon "github.com/polynetwork/poly/p2pserver/common"
+	tcomn "github.com/polynetwork/poly/txnpool/common"
+)
+
+//LedgerReader is the read only ledger api used by the http services,
+//*ledger.Ledger implements it
+type LedgerReader interface {
+	GetHeaderByHeight(height uint32) (*types.Header, error)
+	GetBlockByHeight(height uint32) (*types.Block, error)
+	GetBlockByHash(hash common.Uint256) (*types.Block, error)
+	GetBlockHash(height uint32) common.Uint256
+	GetCurrentBlockHash() common.Uint256
+	GetCurrentBlockHeight() uint32
+	GetStateMerkleRoot(height uint32) (common.Uint256, error)
+	GetTransaction(hash common.Uint256) (*types.Transaction, error)
+	GetTransactionWithHeight(hash common.Uint256) (*types.Transaction, uint32, error)
+	GetStorageItem(address common.Address, key []byte) ([]byte, error)
+	GetStorageItemAtHeight(address common.Address, key []byte, height uint32) ([]byte, error)
+	GetStorageProof(address common.Address, key []byte, height uint32) ([]byte, *merkle.SparseMerkleProof, common.Uint256, error)
+	GetStorageUsage(address common.Address, prefix []byte) (count, size uint64, err error)
+	PreExecuteContract(tx *types.Transaction) (*cstate.PreExecResult, error)
+	PreExecuteContracts(txs []*types.Transaction) ([]*cstate.PreExecResult, error)
+	GetEventNotifyByTx(txHash common.Uint256) (*event.ExecuteNotify, error)
+	GetEventNotifyByBlock(height uint32) ([]*event.ExecuteNotify, error)
+	GetCrossChainTx(fromChainID uint64, crossChainID []byte) (*ccom.CrossChainTx, error)
+	ListCrossChainTxs(fromChainID uint64, offset, limit uint32) ([]*ccom.CrossChainTx, error)
+	GetPrunedHeight() uint32
+	GetMerkleProof(proofHeight uint32, rootHeight uint32) ([]byte, error)
+	GetCrossStatesProof(height uint32, key []byte) ([]byte, error)
+	GetStateTrieRootProof(height uint32) ([]byte, error)
+	GetCrossStateRoot(height uint32) (common.Uint256, error)
+}
+
+//TxPoolService submits transactions to and queries the transaction pool
+type TxPoolService interface {
+	//AppendTx adds the transaction to the pool and waits for the verify result
+	AppendTx(ctx context.Context, txn *types.Transaction) (polyErrors.ErrCode, string)
+	//GetTxnPool returns the verified transactions in the pool
+	GetTxnPool(ctx context.Context, byCount bool) ([]*tcomn.TXEntry, error)
+	//GetTxn returns the pooled transaction with its verify status
+	GetTxn(ctx context.Context, hash common.Uint256) (*tcomn.TXEntry, error)
+	//GetTxnCount returns the count of verified and pending transactions
+	GetTxnCount(ctx context.Context) ([]uint32, error)
+}
+
+//P2PService broadcasts messages to and queries the p2p network
+type P2PService interface {
+	Xmit(msg interface{}) error
+	GetConnectionCnt(ctx context.Context) (uint32, error)
+	GetNeighborAddrs(ctx context.Context) ([]p2pcommon.PeerAddr, error)
+	GetConnectionState(ctx context.Context) (uint32, error)
+	GetTime(ctx context.Context) (int64, error)
+	GetPort(ctx context.Context) (uint16, error)
+	GetID(ctx context.Context) (uint64, error)
+	GetRelayState(ctx context.Context) (bool, error)
+	GetVersion(ctx context.Context) (uint32, error)
+	GetNodeType(ctx context.Context) (uint64, error)
+}
+
+var (
+	ledgerReader  LedgerReader
+	txPoolService TxPoolService
+	p2pService    P2PService
+)
+
+//SetLedgerReader sets the ledger used by the http services, ledger.DefLedger is used if not set
+func SetLedgerReader(reader LedgerReader) {
+	ledgerReader = reader
+}
+
+//SetTxPoolService sets the transaction pool used by the http services
+func SetTxPoolService(service TxPoolService) {
+	txPoolService = service
+}
+
+//SetP2PService sets the p2p network used by the http services
+func SetP2PService(service P2PService) {
+	p2pService = service
+}
+
+func getLedger() LedgerReader {
+	if ledgerReader != nil {
+		return ledgerReader
+	}
+	return ledger.DefLedger
+}
+
+//newRequestContext returns a context bounded by the default request timeout
+func newRequestContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), REQ_TIMEOUT*time.Second)
+}
+
+//requestActor sends msg to pid and waits for the response until ctx is done,
+//a timed out request returns context.DeadlineExceeded
+func requestActor(ctx context.Context, pid *actor.PID, msg interface{}) (interface{}, error) {
+	timeout := REQ_TIMEOUT * time.Second
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+		if timeout <= 0 {
+			return nil, context.DeadlineExceeded
+		}
+	}
+	future := pid.RequestFuture(msg, timeout)
+	done := make(chan struct{})
+	var result interface{}
+	var err error
+	go func() {
+		result, err = future.Result()
+		close(done)
+	}()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-done:
+	}
+	if err == actor.ErrTimeout {
+		return nil, context.DeadlineExceeded
+	}
+	return result, err
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package actor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/types"
+	polyErrors "github.com/polynetwork/poly/errors"
+	cstate "github.com/polynetwork/poly/native/states"
+	tcomn "github.com/polynetwork/poly/txnpool/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeLedger struct {
+	LedgerReader
+	height uint32
+}
+
+func (self *fakeLedger) GetCurrentBlockHeight() uint32 {
+	return self.height
+}
+
+func (self *fakeLedger) PreExecuteContract(tx *types.Transaction) (*cstate.PreExecResult, error) {
+	return &cstate.PreExecResult{}, nil
+}
+
+type fakeTxPool struct {
+	txs []*types.Transaction
+}
+
+func (self *fakeTxPool) AppendTx(ctx context.Context, txn *types.Transaction) (polyErrors.ErrCode, string) {
+	self.txs = append(self.txs, txn)
+	return polyErrors.ErrNoError, ""
+}
+
+func (self *fakeTxPool) GetTxnPool(ctx context.Context, byCount bool) ([]*tcomn.TXEntry, error) {
+	entries := make([]*tcomn.TXEntry, 0, len(self.txs))
+	for _, tx := range self.txs {
+		entries = append(entries, &tcomn.TXEntry{Tx: tx})
+	}
+	return entries, nil
+}
+
+func (self *fakeTxPool) GetTxn(ctx context.Context, hash common.Uint256) (*tcomn.TXEntry, error) {
+	for _, tx := range self.txs {
+		if tx.Hash() == hash {
+			return &tcomn.TXEntry{Tx: tx}, nil
+		}
+	}
+	return nil, context.Canceled
+}
+
+func (self *fakeTxPool) GetTxnCount(ctx context.Context) ([]uint32, error) {
+	return []uint32{uint32(len(self.txs)), 0}, nil
+}
+
+func TestFakeServices(t *testing.T) {
+	SetLedgerReader(&fakeLedger{height: 100})
+	pool := &fakeTxPool{}
+	SetTxPoolService(pool)
+	defer func() {
+		SetLedgerReader(nil)
+		SetTxPoolService(nil)
+	}()
+
+	assert.Equal(t, uint32(100), GetCurrentBlockHeight())
+
+	tx := &types.Transaction{Nonce: 1}
+	code, _ := AppendTxToPool(tx)
+	assert.Equal(t, polyErrors.ErrNoError, code)
+
+	count, err := GetTxnCount()
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{1, 0}, count)
+
+	entry, err := GetTxFromPool(tx.Hash())
+	assert.NoError(t, err)
+	assert.Equal(t, tx, entry.Tx)
+	assert.Len(t, GetTxsFromPool(false), 1)
+
+	//p2p service not set
+	cnt, err := GetConnectionCnt()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), cnt)
+}
+
+func TestRequestActorTimeout(t *testing.T) {
+	pid := actor.Spawn(actor.FromFunc(func(c actor.Context) {}))
+	defer pid.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := NewActorP2PService(pid).GetConnectionCnt(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = NewActorTxPoolService(pid, pid).GetTxnCount(ctx)
+	assert.Equal(t, context.Canceled, err)
+}
@@ -20,10 +20,10 @@
 package actor
 
 import (
+	"context"
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/ontio/ontology-eventbus/actor"
@@ -37,97 +37,144 @@ import (
 	tcomn "github.com/polynetwork/poly/txnpool/common"
 )
 
-var txnPid *actor.PID
-var txnPoolPid *actor.PID
+//actorTxPoolService implements TxPoolService over the txnpool actors
+type actorTxPoolService struct {
+	txnPid     *actor.PID
+	txnPoolPid *actor.PID
+}
 
-func SetTxPid(actr *actor.PID) {
-	txnPid = actr
+//NewActorTxPoolService returns a TxPoolService backed by the tx actor and the txpool actor
+func NewActorTxPoolService(txnPid, txnPoolPid *actor.PID) TxPoolService {
+	return &actorTxPoolService{
+		txnPid:     txnPid,
+		txnPoolPid: txnPoolPid,
+	}
 }
-func SetTxnPoolPid(actr *actor.PID) {
-	txnPoolPid = actr
+
+func (self *actorTxPoolService) AppendTx(ctx context.Context, txn *types.Transaction) (polyErrors.ErrCode, string) {
+	ch := make(chan *tcomn.TxResult, 1)
+	txReq := &tcomn.TxReq{Tx: txn, Sender: tcomn.HttpSender, TxResultCh: ch}
+	self.txnPid.Tell(txReq)
+	select {
+	case msg, ok := <-ch:
+		if ok {
+			return msg.Err, msg.Desc
+		}
+		return polyErrors.ErrUnknown, ""
+	case <-ctx.Done():
+		return polyErrors.ErrUnknown, ctx.Err().Error()
+	}
 }
 
-//append transaction to pool to txpool actor
-func AppendTxToPool(txn *types.Transaction) (polyErrors.ErrCode, string) {
+func (self *actorTxPoolService) GetTxnPool(ctx context.Context, byCount bool) ([]*tcomn.TXEntry, error) {
+	result, err := requestActor(ctx, self.txnPoolPid, &tcomn.GetTxnPoolReq{ByCount: byCount})
+	if err != nil {
+		return nil, err
+	}
+	rsp, ok := result.(*tcomn.GetTxnPoolRsp)
+	if !ok {
+		return nil, fmt.Errorf("unexpected txpool response %T", result)
+	}
+	return rsp.TxnPool, nil
+}
+
+func (self *actorTxPoolService) GetTxn(ctx context.Context, hash common.Uint256) (*tcomn.TXEntry, error) {
+	result, err := requestActor(ctx, self.txnPid, &tcomn.GetTxnReq{Hash: hash})
+	if err != nil {
+		return nil, err
+	}
+	rsp, ok := result.(*tcomn.GetTxnRsp)
+	if !ok {
+		return nil, fmt.Errorf("unexpected txn response %T", result)
+	}
+	if rsp.Txn == nil {
+		return nil, errors.New("fail")
+	}
+	result, err = requestActor(ctx, self.txnPid, &tcomn.GetTxnStatusReq{Hash: hash})
+	if err != nil {
+		return nil, err
+	}
+	txStatus, ok := result.(*tcomn.GetTxnStatusRsp)
+	if !ok {
+		return nil, fmt.Errorf("unexpected txn status response %T", result)
+	}
+	return &tcomn.TXEntry{Tx: rsp.Txn, Attrs: txStatus.TxStatus}, nil
+}
+
+func (self *actorTxPoolService) GetTxnCount(ctx context.Context) ([]uint32, error) {
+	result, err := requestActor(ctx, self.txnPid, &tcomn.GetTxnCountReq{})
+	if err != nil {
+		return nil, err
+	}
+	rsp, ok := result.(*tcomn.GetTxnCountRsp)
+	if !ok {
+		return nil, fmt.Errorf("unexpected txn count response %T", result)
+	}
+	return rsp.Count, nil
+}
 
+//append transaction to pool to txpool
+func AppendTxToPool(txn *types.Transaction) (polyErrors.ErrCode, string) {
+	if txPoolService == nil {
+		return polyErrors.ErrUnknown, "txpool service not set"
+	}
 	//add Pre Execute Contract
 	_, err := PreExecuteContract(txn)
 	if err != nil {
 		return polyErrors.ErrUnknown, err.Error()
 	}
-	ch := make(chan *tcomn.TxResult, 1)
-	txReq := &tcomn.TxReq{txn, tcomn.HttpSender, ch}
-	txnPid.Tell(txReq)
-	if msg, ok := <-ch; ok {
-		return msg.Err, msg.Desc
-	}
-	return polyErrors.ErrUnknown, ""
+	ctx, cancel := newRequestContext()
+	defer cancel()
+	return txPoolService.AppendTx(ctx, txn)
 }
 
-//GetTxsFromPool from txpool actor
+//GetTxsFromPool from txpool
 func GetTxsFromPool(byCount bool) map[common.Uint256]*types.Transaction {
-	future := txnPoolPid.RequestFuture(&tcomn.GetTxnPoolReq{ByCount: byCount}, REQ_TIMEOUT*time.Second)
-	result, err := future.Result()
-	if err != nil {
-		log.Errorf(ERR_ACTOR_COMM, err)
+	if txPoolService == nil {
 		return nil
 	}
-	txpool, ok := result.(*tcomn.GetTxnPoolRsp)
-	if !ok {
+	ctx, cancel := newRequestContext()
+	defer cancel()
+	entries, err := txPoolService.GetTxnPool(ctx, byCount)
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
 		return nil
 	}
 	txMap := make(map[common.Uint256]*types.Transaction)
-	for _, v := range txpool.TxnPool {
+	for _, v := range entries {
 		txMap[v.Tx.Hash()] = v.Tx
 	}
 	return txMap
 
 }
 
-//GetTxFromPool from txpool actor
+//GetTxFromPool from txpool
 func GetTxFromPool(hash common.Uint256) (tcomn.TXEntry, error) {
-
-	future := txnPid.RequestFuture(&tcomn.GetTxnReq{hash}, REQ_TIMEOUT*time.Second)
-	result, err := future.Result()
-	if err != nil {
-		log.Errorf(ERR_ACTOR_COMM, err)
-		return tcomn.TXEntry{}, err
-	}
-	rsp, ok := result.(*tcomn.GetTxnRsp)
-	if !ok {
-		return tcomn.TXEntry{}, errors.New("fail")
+	if txPoolService == nil {
+		return tcomn.TXEntry{}, errors.New("txpool service not set")
 	}
-	if rsp.Txn == nil {
-		return tcomn.TXEntry{}, errors.New("fail")
-	}
-
-	future = txnPid.RequestFuture(&tcomn.GetTxnStatusReq{hash}, REQ_TIMEOUT*time.Second)
-	result, err = future.Result()
+	ctx, cancel := newRequestContext()
+	defer cancel()
+	entry, err := txPoolService.GetTxn(ctx, hash)
 	if err != nil {
-		log.Errorf(ERR_ACTOR_COMM, err)
 		return tcomn.TXEntry{}, err
 	}
-	txStatus, ok := result.(*tcomn.GetTxnStatusRsp)
-	if !ok {
-		return tcomn.TXEntry{}, errors.New("fail")
-	}
-	txnEntry := tcomn.TXEntry{rsp.Txn, txStatus.TxStatus}
-	return txnEntry, nil
+	return *entry, nil
 }
 
-//GetTxnCount from txpool actor
+//GetTxnCount from txpool
 func GetTxnCount() ([]uint32, error) {
-	future := txnPid.RequestFuture(&tcomn.GetTxnCountReq{}, REQ_TIMEOUT*time.Second)
-	result, err := future.Result()
+	if txPoolService == nil {
+		return []uint32{}, errors.New("txpool service not set")
+	}
+	ctx, cancel := newRequestContext()
+	defer cancel()
+	count, err := txPoolService.GetTxnCount(ctx)
 	if err != nil {
 		log.Errorf(ERR_ACTOR_COMM, err)
 		return []uint32{}, err
 	}
-	txnCnt, ok := result.(*tcomn.GetTxnCountRsp)
-	if !ok {
-		return []uint32{}, errors.New("fail")
-	}
-	return txnCnt.Count, nil
+	return count, nil
 }
 
 func UpdatePermittedAddrMap(permittedAddrMap map[common.Address]bool) error {
@@ -20,17 +20,28 @@
 package common
 
 import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/core/payload"
+	scom "github.com/polynetwork/poly/core/store/common"
 	"github.com/polynetwork/poly/core/types"
 	ontErrors "github.com/polynetwork/poly/errors"
 	bactor "github.com/polynetwork/poly/http/base/actor"
 	"github.com/polynetwork/poly/native/event"
+	ccom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	hscom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
 	cstate "github.com/polynetwork/poly/native/states"
 )
 
 const MAX_SEARCH_HEIGHT uint32 = 100
+const MAX_CROSS_CHAIN_TX_LIMIT uint32 = 100
+const MAX_DRY_RUN_HEADERS = 100
 
 type BalanceOfRsp struct {
 	Ont string `json:"ont"`
@@ -63,9 +74,125 @@ type PreExecuteResult struct {
 
 type NotifyEventInfo struct {
 	ContractAddress string
+	EventName       string `json:",omitempty"`
 	States          interface{}
 }
 
+type EventSchema struct {
+	ContractAddress string
+	Name            string
+	Anonymous       bool
+	Fields          []*event.EventField
+}
+
+type CrossChainTx struct {
+	FromChainID  uint64
+	CrossChainID string
+	TxHash       string
+	ToChainID    uint64
+	PolyTxHash   string
+	Height       uint32
+	Status       uint8
+}
+
+//CrossChainProof is a cross chain request made in poly together with the proof to verify it on target chain
+type CrossChainProof struct {
+	TxHash           string // poly tx which makes the request
+	FromChainID      uint64
+	ToChainID        uint64
+	SourceTxHash     string // tx hash in makeProof event
+	Height           uint32 // height of the poly tx
+	Key              string // storage key of the request
+	CrossStatesProof string
+	HeaderHeight     uint32
+	Header           string // raw header whose CrossStateRoot is the root of proof
+}
+
+//CrossChainProofBundle is what a target chain needs to verify the cross chain request in poly
+type CrossChainProofBundle struct {
+	Height           uint32 // height of cross states
+	Key              string
+	CrossStatesProof string
+	HeaderHeight     uint32
+	Header           string // raw header whose CrossStateRoot is the root of proof
+	AnchorHeight     uint32 `json:",omitempty"`
+	AnchorHeader     string `json:",omitempty"` // raw anchor header already synced to target chain
+	HeaderProof      string `json:",omitempty"` // merkle proof of header in the BlockRoot of anchor header
+}
+
+//StorageProof is the storage value at Height and its proof in the state trie of Root, the trie is keyed by
+//contract address + storage key. Once the root is committed, RootProof proves it in the cross states of
+//Height+1, whose root is signed in Header at HeaderHeight.
+type StorageProof struct {
+	Height       uint32
+	Root         string
+	Exist        bool
+	Value        string
+	Proof        string // serialized merkle.SparseMerkleProof, of inclusion if Exist else of absence
+	RootProof    string `json:",omitempty"` // cross states proof of Root
+	HeaderHeight uint32 `json:",omitempty"`
+	Header       string `json:",omitempty"` // raw header whose CrossStateRoot is the root of RootProof
+}
+
+//StorageUsage is the count and total key and value size in bytes of the storage items under Prefix
+type StorageUsage struct {
+	Prefix string
+	Count  uint64
+	Size   uint64
+}
+
+//HeaderStorageUsage is the header sync storage of a side chain with its header retention
+type HeaderStorageUsage struct {
+	ChainID      uint64
+	Count        uint64
+	Size         uint64
+	Prefixes     []StorageUsage
+	KeepHeaders  uint64 // headers kept past the finality depth, 0 means all headers are kept
+	PrunedHeight uint64 // canonical headers below it are pruned
+	PinnedHeight uint64 `json:",omitempty"` // lowest header pinned by a queued cross chain tx
+}
+
+//SideChainStatus is the view of poly on a registered side chain, hashes and validators are hex encoded
+type SideChainStatus struct {
+	ChainID         uint64
+	Router          uint64
+	BlocksToWait    uint64
+	Synced          bool   // false if the genesis header is not synced or the router reports no status
+	FinalizedHeight uint64 // latest synced height which is BlocksToWait deep
+	GenesisHeight   uint64
+	GenesisHash     string `json:",omitempty"` // empty if the handler does not keep the genesis header
+	Height          uint64
+	Hash            string
+	EpochHeight     uint64   // height of the header which set Validators or ValidatorsHash
+	Validators      []string `json:",omitempty"`
+	ValidatorsHash  string   `json:",omitempty"`
+	Error           string   `json:",omitempty"` // reason the status of the chain fails to be queried
+}
+
+//DryRunResult is the result of a relayer tx pre-executed on current state, Error is the reason it fails
+type DryRunResult struct {
+	Method  string
+	Success bool
+	Error   string                `json:",omitempty"`
+	Headers []*HeaderDryRunResult `json:",omitempty"` // results of the headers of a syncBlockHeader batch
+	Notify  []NotifyEventInfo
+}
+
+//HeaderDryRunResult is the result of syncing the header at Index of a syncBlockHeader batch
+type HeaderDryRunResult struct {
+	Index   int
+	Success bool
+	Error   string `json:",omitempty"`
+	Notify  []NotifyEventInfo
+}
+
+type PruneStatus struct {
+	Archive       bool
+	PruneBlocks   uint32
+	PrunedHeight  uint32
+	CurrentHeight uint32
+}
+
 type TxAttributeInfo struct {
 	Usage types.TransactionAttributeUsage
 	Data  string
@@ -155,21 +282,178 @@ func GetExecuteNotify(obj *event.ExecuteNotify) (map[string]bool, ExecuteNotify)
 	evts := []NotifyEventInfo{}
 	var contractAddrs = make(map[string]bool)
 	for _, v := range obj.Notify {
-		evts = append(evts, NotifyEventInfo{v.ContractAddress.ToHexString(), v.States})
+		evts = append(evts, NotifyEventInfo{v.ContractAddress.ToHexString(), v.EventName, v.States})
 		contractAddrs[v.ContractAddress.ToHexString()] = true
 	}
 	txhash := obj.TxHash.ToHexString()
 	return contractAddrs, ExecuteNotify{txhash, obj.State, obj.GasConsumed, evts}
 }
 
+func GetEventSchemas() []EventSchema {
+	schemas := make([]EventSchema, 0)
+	for _, schema := range event.GetEventSchemas() {
+		schemas = append(schemas, EventSchema{
+			ContractAddress: schema.Contract.ToHexString(),
+			Name:            schema.Name,
+			Anonymous:       schema.Anonymous,
+			Fields:          schema.Fields,
+		})
+	}
+	return schemas
+}
+
 func ConvertPreExecuteResult(obj *cstate.PreExecResult) PreExecuteResult {
 	evts := []NotifyEventInfo{}
 	for _, v := range obj.Notify {
-		evts = append(evts, NotifyEventInfo{v.ContractAddress.ToHexString(), v.States})
+		evts = append(evts, NotifyEventInfo{v.ContractAddress.ToHexString(), v.EventName, v.States})
 	}
 	return PreExecuteResult{obj.State, obj.Result, evts}
 }
 
+func GetCrossChainTx(obj *ccom.CrossChainTx) CrossChainTx {
+	return CrossChainTx{
+		FromChainID:  obj.FromChainID,
+		CrossChainID: common.ToHexString(obj.CrossChainID),
+		TxHash:       common.ToHexString(obj.TxHash),
+		ToChainID:    obj.ToChainID,
+		PolyTxHash:   obj.PolyTxHash.ToHexString(),
+		Height:       obj.Height,
+		Status:       obj.Status,
+	}
+}
+
+//GetCrossChainProofs return the cross chain requests made at height whose target chain is accepted by filter,
+//header is the block header at height+1, which commits the cross states root of height
+func GetCrossChainProofs(height uint32, header *types.Header, filter func(toChainID uint64) bool) ([]*CrossChainProof, error) {
+	notifies, err := bactor.GetEventNotifyByHeight(height)
+	if err != nil {
+		if err == scom.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	rawHeader := common.ToHexString(header.ToArray())
+	proofs := make([]*CrossChainProof, 0)
+	for _, execNotify := range notifies {
+		for _, notify := range execNotify.Notify {
+			proof, ok := parseMakeProofNotify(notify)
+			if !ok || !filter(proof.ToChainID) {
+				continue
+			}
+			key, err := common.HexToBytes(proof.Key)
+			if err != nil {
+				return nil, fmt.Errorf("invalid key %s of makeProof event in tx %s", proof.Key, execNotify.TxHash.ToHexString())
+			}
+			path, err := bactor.GetCrossStatesProof(proof.Height, key)
+			if err != nil {
+				return nil, fmt.Errorf("GetCrossStatesProof height:%d key:%s error:%s", proof.Height, proof.Key, err)
+			}
+			proof.TxHash = execNotify.TxHash.ToHexString()
+			proof.CrossStatesProof = common.ToHexString(path)
+			proof.HeaderHeight = header.Height
+			proof.Header = rawHeader
+			proofs = append(proofs, proof)
+		}
+	}
+	return proofs, nil
+}
+
+//parseMakeProofNotify parse the makeProof event, which may be read from event store with json numbers
+func parseMakeProofNotify(notify *event.NotifyEventInfo) (*CrossChainProof, bool) {
+	if notify.ContractAddress != utils.CrossChainManagerContractAddress {
+		return nil, false
+	}
+	states, ok := notify.States.([]interface{})
+	if !ok || len(states) != len(ccom.MakeProofEvent.Fields)+1 || states[0] != ccom.NOTIFY_MAKE_PROOF {
+		return nil, false
+	}
+	fromChainID, ok1 := toUint64(states[1])
+	toChainID, ok2 := toUint64(states[2])
+	txHash, ok3 := states[3].(string)
+	height, ok4 := toUint64(states[4])
+	key, ok5 := states[5].(string)
+	if !(ok1 && ok2 && ok3 && ok4 && ok5) {
+		return nil, false
+	}
+	return &CrossChainProof{
+		FromChainID:  fromChainID,
+		ToChainID:    toChainID,
+		SourceTxHash: txHash,
+		Height:       uint32(height),
+		Key:          key,
+	}, true
+}
+
+//GetHeaderStorageUsage sums up the storage items kept by the header sync of chainID
+func GetHeaderStorageUsage(chainID uint64) (*HeaderStorageUsage, error) {
+	usage := &HeaderStorageUsage{ChainID: chainID, Prefixes: make([]StorageUsage, 0, len(hscom.HeaderStoragePrefixes))}
+	chainIDBytes := utils.GetUint64Bytes(chainID)
+	for _, prefix := range hscom.HeaderStoragePrefixes {
+		count, size, err := bactor.GetStorageUsage(utils.HeaderSyncContractAddress, append([]byte(prefix), chainIDBytes...))
+		if err != nil {
+			return nil, fmt.Errorf("get storage usage of %s error: %v", prefix, err)
+		}
+		usage.Prefixes = append(usage.Prefixes, StorageUsage{Prefix: prefix, Count: count, Size: size})
+		usage.Count += count
+		usage.Size += size
+	}
+
+	value, err := getHeaderSyncStorage(hscom.HEADER_RETENTION, chainIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	if value != nil {
+		retention := new(hscom.HeaderRetention)
+		if err := retention.Deserialization(common.NewZeroCopySource(value)); err != nil {
+			return nil, fmt.Errorf("deserialize header retention error: %v", err)
+		}
+		usage.KeepHeaders = retention.KeepHeaders
+	}
+	value, err = getHeaderSyncStorage(hscom.HEADER_PRUNED_HEIGHT, chainIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	if value != nil {
+		usage.PrunedHeight = utils.GetBytesUint64(value)
+	}
+	value, err = getHeaderSyncStorage(hscom.HEADER_PINNED_HEIGHTS, chainIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	if value != nil {
+		pinned := new(hscom.PinnedHeights)
+		if err := pinned.Deserialization(common.NewZeroCopySource(value)); err != nil {
+			return nil, fmt.Errorf("deserialize pinned heights error: %v", err)
+		}
+		usage.PinnedHeight, _ = pinned.Lowest()
+	}
+	return usage, nil
+}
+
+//getHeaderSyncStorage returns nil if the key is not found
+func getHeaderSyncStorage(prefix string, chainIDBytes []byte) ([]byte, error) {
+	value, err := bactor.GetStorageItem(utils.HeaderSyncContractAddress, append([]byte(prefix), chainIDBytes...))
+	if err == scom.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get %s error: %v", prefix, err)
+	}
+	return value, nil
+}
+
+func toUint64(v interface{}) (uint64, bool) {
+	switch n := v.(type) {
+	case uint64:
+		return n, true
+	case uint32:
+		return uint64(n), true
+	case float64:
+		return uint64(n), n >= 0
+	}
+	return 0, false
+}
+
 func SendTxToPool(txn *types.Transaction) (ontErrors.ErrCode, string) {
 	if errCode, desc := bactor.AppendTxToPool(txn); errCode != ontErrors.ErrNoError {
 		log.Warn("TxnPool verify error:", errCode.Error())
@@ -276,3 +560,143 @@ func GetAddress(str string) (common.Address, error) {
 	}
 	return address, err
 }
+
+//GetSideChainStatus returns the status of all registered side chains, or of chainID only if it is not nil
+func GetSideChainStatus(chainID *uint64) ([]*SideChainStatus, error) {
+	result, err := PreExecuteNativeContract(utils.HeaderSyncContractAddress, hscom.GET_SIDE_CHAIN_STATUS, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	list := new(hscom.SideChainStatusList)
+	if err := list.Deserialization(common.NewZeroCopySource(result)); err != nil {
+		return nil, fmt.Errorf("deserialize side chain status error: %v", err)
+	}
+	statuses := make([]*SideChainStatus, 0, len(list.List))
+	for _, v := range list.List {
+		if chainID != nil && v.ChainID != *chainID {
+			continue
+		}
+		status := &SideChainStatus{
+			ChainID:         v.ChainID,
+			Router:          v.Router,
+			BlocksToWait:    v.BlocksToWait,
+			FinalizedHeight: v.FinalizedHeight,
+			Error:           v.Error,
+		}
+		if v.Sync != nil {
+			status.Synced = true
+			status.GenesisHeight = v.Sync.GenesisHeight
+			status.GenesisHash = hex.EncodeToString(v.Sync.GenesisHash)
+			status.Height = v.Sync.Height
+			status.Hash = hex.EncodeToString(v.Sync.Hash)
+			status.EpochHeight = v.Sync.EpochHeight
+			for _, validator := range v.Sync.Validators {
+				status.Validators = append(status.Validators, hex.EncodeToString(validator))
+			}
+			status.ValidatorsHash = hex.EncodeToString(v.Sync.ValidatorsHash)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+//DryRunSideChainTx pre-executes the unsigned relayer tx calling method with args, method is syncBlockHeader of
+//the header sync contract or importOuterTransfer of the cross chain manager. The relayer check of the tx pool is
+//skipped as the tx is not sent to the pool. A syncBlockHeader batch is run as a tx per header so that every header
+//gets its own result, a header is verified on the state with the successful headers before it synced. A batch is
+//limited to MAX_DRY_RUN_HEADERS headers
+func DryRunSideChainTx(method string, args []byte) (*DryRunResult, error) {
+	var contract common.Address
+	argsList := [][]byte{args}
+	split := false
+	switch method {
+	case hscom.SYNC_BLOCK_HEADER:
+		contract = utils.HeaderSyncContractAddress
+		params := new(hscom.SyncBlockHeaderParam)
+		// a batch failing to decode is run as is to get the error of the contract
+		if err := params.Deserialization(common.NewZeroCopySource(args)); err == nil && len(params.Headers) > 0 {
+			if len(params.Headers) > MAX_DRY_RUN_HEADERS {
+				return nil, fmt.Errorf("batch of %d headers exceeds the limit %d", len(params.Headers), MAX_DRY_RUN_HEADERS)
+			}
+			argsList = make([][]byte, 0, len(params.Headers))
+			for _, header := range params.Headers {
+				param := &hscom.SyncBlockHeaderParam{ChainID: params.ChainID, Address: params.Address, Headers: [][]byte{header}}
+				sink := common.NewZeroCopySink(nil)
+				param.Serialization(sink)
+				argsList = append(argsList, sink.Bytes())
+			}
+			split = true
+		}
+	case ccom.IMPORT_OUTER_TRANSFER_NAME:
+		contract = utils.CrossChainManagerContractAddress
+	default:
+		return nil, fmt.Errorf("method %s can not be dry run", method)
+	}
+	txs := make([]*types.Transaction, 0, len(argsList))
+	for _, v := range argsList {
+		tx, err := newNativeInvokeTx(contract, method, v)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	results, err := bactor.PreExecuteContracts(txs)
+	if err != nil {
+		return nil, fmt.Errorf("pre-execute %s error: %v", method, err)
+	}
+	dryRun := &DryRunResult{Method: method, Success: true, Notify: []NotifyEventInfo{}}
+	for i, result := range results {
+		header := &HeaderDryRunResult{Index: i, Success: result.State == event.CONTRACT_STATE_SUCCESS}
+		if !header.Success {
+			header.Error = fmt.Sprint(result.Result)
+			if dryRun.Success {
+				dryRun.Success = false
+				dryRun.Error = header.Error
+			}
+		}
+		header.Notify = ConvertPreExecuteResult(result).Notify
+		dryRun.Notify = append(dryRun.Notify, header.Notify...)
+		if split {
+			dryRun.Headers = append(dryRun.Headers, header)
+		}
+	}
+	return dryRun, nil
+}
+
+//PreExecuteNativeContract pre-executes method of the native contract with args and returns its result
+func PreExecuteNativeContract(contract common.Address, method string, args []byte) ([]byte, error) {
+	tx, err := newNativeInvokeTx(contract, method, args)
+	if err != nil {
+		return nil, err
+	}
+	result, err := bactor.PreExecuteContract(tx)
+	if err != nil {
+		return nil, fmt.Errorf("pre-execute %s error: %v", method, err)
+	}
+	hexResult, ok := result.Result.(string)
+	if !ok {
+		return nil, fmt.Errorf("pre-execute %s, unexpected result type %T", method, result.Result)
+	}
+	return common.HexToBytes(hexResult)
+}
+
+func newNativeInvokeTx(contract common.Address, method string, args []byte) (*types.Transaction, error) {
+	invokeParam := &cstate.ContractInvokeParam{Address: contract, Method: method, Args: args}
+	sink := common.NewZeroCopySink(nil)
+	invokeParam.Serialization(sink)
+	tx := &types.Transaction{
+		Version: types.CURR_TX_VERSION,
+		TxType:  types.Invoke,
+		Payload: &payload.InvokeCode{Code: sink.Bytes()},
+		Nonce:   uint32(time.Now().Unix()),
+	}
+	sink = common.NewZeroCopySink(nil)
+	if err := tx.Serialization(sink); err != nil {
+		return nil, fmt.Errorf("serialize tx error: %v", err)
+	}
+	tx, err := types.TransactionFromRawBytes(sink.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("deserialize tx error: %v", err)
+	}
+	return tx, nil
+}
@@ -0,0 +1,201 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/payload"
+	scom "github.com/polynetwork/poly/core/store/common"
+	"github.com/polynetwork/poly/core/types"
+	bactor "github.com/polynetwork/poly/http/base/actor"
+	"github.com/polynetwork/poly/native/event"
+	ccom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	hscom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+	cstate "github.com/polynetwork/poly/native/states"
+	"github.com/stretchr/testify/assert"
+)
+
+//dryRunLedger fails the pre-executed txs whose args contain a header in failed
+type dryRunLedger struct {
+	bactor.LedgerReader
+	failed map[string]bool
+	calls  []*cstate.ContractInvokeParam
+}
+
+func (self *dryRunLedger) PreExecuteContracts(txs []*types.Transaction) ([]*cstate.PreExecResult, error) {
+	results := make([]*cstate.PreExecResult, 0, len(txs))
+	for _, tx := range txs {
+		param := new(cstate.ContractInvokeParam)
+		if err := param.Deserialization(common.NewZeroCopySource(tx.Payload.(*payload.InvokeCode).Code)); err != nil {
+			return nil, err
+		}
+		self.calls = append(self.calls, param)
+		headers := new(hscom.SyncBlockHeaderParam)
+		if err := headers.Deserialization(common.NewZeroCopySource(param.Args)); err == nil && len(headers.Headers) == 1 &&
+			self.failed[string(headers.Headers[0])] {
+			results = append(results, &cstate.PreExecResult{State: event.CONTRACT_STATE_FAIL,
+				Result: fmt.Sprintf("invalid header %s", headers.Headers[0])})
+			continue
+		}
+		results = append(results, &cstate.PreExecResult{State: event.CONTRACT_STATE_SUCCESS, Result: "01"})
+	}
+	return results, nil
+}
+
+func TestDryRunSideChainTx(t *testing.T) {
+	ledger := &dryRunLedger{failed: map[string]bool{"h2": true}}
+	bactor.SetLedgerReader(ledger)
+	defer bactor.SetLedgerReader(nil)
+
+	param := &hscom.SyncBlockHeaderParam{ChainID: 2, Headers: [][]byte{[]byte("h1"), []byte("h2"), []byte("h3")}}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	result, err := DryRunSideChainTx(hscom.SYNC_BLOCK_HEADER, sink.Bytes())
+	assert.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, "invalid header h2", result.Error)
+	assert.Len(t, result.Headers, 3)
+	for i, header := range result.Headers {
+		assert.Equal(t, i, header.Index)
+		assert.Equal(t, i != 1, header.Success)
+	}
+	assert.Equal(t, "invalid header h2", result.Headers[1].Error)
+	// a header is run alone in its own tx
+	assert.Len(t, ledger.calls, 3)
+	for i, call := range ledger.calls {
+		assert.Equal(t, utils.HeaderSyncContractAddress, call.Address)
+		headers := new(hscom.SyncBlockHeaderParam)
+		assert.NoError(t, headers.Deserialization(common.NewZeroCopySource(call.Args)))
+		assert.Equal(t, uint64(2), headers.ChainID)
+		assert.Equal(t, [][]byte{param.Headers[i]}, headers.Headers)
+	}
+
+	// a batch failing to decode is run as is
+	ledger.calls = nil
+	result, err = DryRunSideChainTx(hscom.SYNC_BLOCK_HEADER, []byte{0x01})
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Empty(t, result.Headers)
+	assert.Len(t, ledger.calls, 1)
+	assert.Equal(t, []byte{0x01}, ledger.calls[0].Args)
+
+	ledger.calls = nil
+	result, err = DryRunSideChainTx(ccom.IMPORT_OUTER_TRANSFER_NAME, []byte{0x02})
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Len(t, ledger.calls, 1)
+	assert.Equal(t, utils.CrossChainManagerContractAddress, ledger.calls[0].Address)
+
+	_, err = DryRunSideChainTx("registerSideChain", nil)
+	assert.Error(t, err)
+
+	// the headers of a batch are limited
+	ledger.calls = nil
+	param.Headers = make([][]byte, MAX_DRY_RUN_HEADERS+1)
+	for i := range param.Headers {
+		param.Headers[i] = []byte{byte(i)}
+	}
+	sink = common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	_, err = DryRunSideChainTx(hscom.SYNC_BLOCK_HEADER, sink.Bytes())
+	assert.Error(t, err)
+	assert.Empty(t, ledger.calls)
+}
+
+//proofLedger holds the events of a block, and returns the height and key as the cross states proof
+type proofLedger struct {
+	bactor.LedgerReader
+	height   uint32
+	notifies []*event.ExecuteNotify
+}
+
+func (self *proofLedger) GetEventNotifyByBlock(height uint32) ([]*event.ExecuteNotify, error) {
+	if height != self.height {
+		return nil, scom.ErrNotFound
+	}
+	return self.notifies, nil
+}
+
+func (self *proofLedger) GetCrossStatesProof(height uint32, key []byte) ([]byte, error) {
+	return append(utils.GetUint32Bytes(height), key...), nil
+}
+
+func TestGetCrossChainProofs(t *testing.T) {
+	txHash := common.Uint256{1}
+	ledger := &proofLedger{height: 10, notifies: []*event.ExecuteNotify{
+		{
+			TxHash: txHash,
+			Notify: []*event.NotifyEventInfo{
+				ccom.MakeProofEvent.NewNotify(uint64(2), uint64(3), "aa01", uint32(10), "0102"),
+				ccom.CrossChainTxEvent.NewNotify(uint64(2), uint64(3), "aa01", "01", ccom.CROSS_CHAIN_TX_PROVEN),
+				ccom.MakeProofEvent.NewNotify(uint64(2), uint64(4), "aa02", uint32(10), "0304"),
+			},
+		},
+	}}
+	bactor.SetLedgerReader(ledger)
+	defer bactor.SetLedgerReader(nil)
+
+	header := &types.Header{Height: 11, CrossStateRoot: common.Uint256{2}}
+	proofs, err := GetCrossChainProofs(10, header, func(toChainID uint64) bool { return toChainID == 3 })
+	assert.NoError(t, err)
+	assert.Equal(t, []*CrossChainProof{{
+		TxHash:           txHash.ToHexString(),
+		FromChainID:      2,
+		ToChainID:        3,
+		SourceTxHash:     "aa01",
+		Height:           10,
+		Key:              "0102",
+		CrossStatesProof: common.ToHexString(append(utils.GetUint32Bytes(10), 1, 2)),
+		HeaderHeight:     11,
+		Header:           common.ToHexString(header.ToArray()),
+	}}, proofs)
+
+	proofs, err = GetCrossChainProofs(10, header, func(toChainID uint64) bool { return false })
+	assert.NoError(t, err)
+	assert.Empty(t, proofs)
+	proofs, err = GetCrossChainProofs(9, header, func(toChainID uint64) bool { return true })
+	assert.NoError(t, err)
+	assert.Empty(t, proofs)
+}
+
+func TestParseMakeProofNotify(t *testing.T) {
+	// read back from event store with json numbers
+	notify := &event.NotifyEventInfo{
+		ContractAddress: utils.CrossChainManagerContractAddress,
+		States:          []interface{}{ccom.NOTIFY_MAKE_PROOF, float64(2), float64(3), "aa01", float64(10), "0102"},
+	}
+	proof, ok := parseMakeProofNotify(notify)
+	assert.True(t, ok)
+	assert.Equal(t, &CrossChainProof{FromChainID: 2, ToChainID: 3, SourceTxHash: "aa01", Height: 10, Key: "0102"}, proof)
+
+	notify.States = []interface{}{ccom.NOTIFY_MAKE_PROOF, float64(-2), float64(3), "aa01", float64(10), "0102"}
+	_, ok = parseMakeProofNotify(notify)
+	assert.False(t, ok)
+	notify.States = []interface{}{ccom.NOTIFY_MAKE_PROOF, float64(2), float64(3), "aa01", float64(10)}
+	_, ok = parseMakeProofNotify(notify)
+	assert.False(t, ok)
+	notify.States = []interface{}{ccom.NOTIFY_MAKE_PROOF, float64(2), float64(3), "aa01", float64(10), "0102"}
+	notify.ContractAddress = utils.HeaderSyncContractAddress
+	_, ok = parseMakeProofNotify(notify)
+	assert.False(t, ok)
+}
@@ -347,6 +347,79 @@ func GetSmartCodeEventByTxHash(cmd map[string]interface{}) map[string]interface{
 	return resp
 }
 
+//get cross chain tx by source chain id and cross chain id
+func GetCrossChainTx(cmd map[string]interface{}) map[string]interface{} {
+	if !config.DefConfig.Common.EnableEventLog {
+		return ResponsePack(berr.INVALID_METHOD)
+	}
+
+	resp := ResponsePack(berr.SUCCESS)
+	str, ok := cmd["ChainID"].(string)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	chainID, err := strconv.ParseUint(str, 10, 64)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	str, ok = cmd["ID"].(string)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	crossChainID, err := common.HexToBytes(str)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	tx, err := bactor.GetCrossChainTx(chainID, crossChainID)
+	if err != nil {
+		if scom.ErrNotFound == err {
+			return ResponsePack(berr.SUCCESS)
+		}
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	resp["Result"] = bcomn.GetCrossChainTx(tx)
+	return resp
+}
+
+//list cross chain txs of source chain by page, in the order of the height they are first indexed at
+func ListCrossChainTxs(cmd map[string]interface{}) map[string]interface{} {
+	if !config.DefConfig.Common.EnableEventLog {
+		return ResponsePack(berr.INVALID_METHOD)
+	}
+
+	resp := ResponsePack(berr.SUCCESS)
+	str, ok := cmd["ChainID"].(string)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	chainID, err := strconv.ParseUint(str, 10, 64)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	offset, limit := uint64(0), uint64(bcomn.MAX_CROSS_CHAIN_TX_LIMIT)
+	if str, ok := cmd["Offset"].(string); ok && str != "" {
+		if offset, err = strconv.ParseUint(str, 10, 32); err != nil {
+			return ResponsePack(berr.INVALID_PARAMS)
+		}
+	}
+	if str, ok := cmd["Limit"].(string); ok && str != "" {
+		limit, err = strconv.ParseUint(str, 10, 32)
+		if err != nil || limit == 0 || limit > uint64(bcomn.MAX_CROSS_CHAIN_TX_LIMIT) {
+			return ResponsePack(berr.INVALID_PARAMS)
+		}
+	}
+	txs, err := bactor.ListCrossChainTxs(chainID, uint32(offset), uint32(limit))
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	result := make([]bcomn.CrossChainTx, 0, len(txs))
+	for _, tx := range txs {
+		result = append(result, bcomn.GetCrossChainTx(tx))
+	}
+	resp["Result"] = result
+	return resp
+}
+
 //get storage from contract
 func GetStorage(cmd map[string]interface{}) map[string]interface{} {
 	resp := ResponsePack(berr.SUCCESS)
@@ -363,7 +436,19 @@ func GetStorage(cmd map[string]interface{}) map[string]interface{} {
 	if err != nil {
 		return ResponsePack(berr.INVALID_PARAMS)
 	}
-	value, err := bactor.GetStorageItem(address, item)
+	var value []byte
+	if str, ok := cmd["Height"].(string); ok && str != "" {
+		height, err := strconv.ParseUint(str, 10, 32)
+		if err != nil {
+			return ResponsePack(berr.INVALID_PARAMS)
+		}
+		value, err = bactor.GetStorageItemAtHeight(address, item, uint32(height))
+		if err == scom.ErrHistoryUnavailable {
+			return ResponsePack(berr.INVALID_METHOD)
+		}
+	} else {
+		value, err = bactor.GetStorageItem(address, item)
+	}
 	if err != nil {
 		if err == scom.ErrNotFound {
 			return ResponsePack(berr.SUCCESS)
@@ -238,8 +238,8 @@ func GetRawTransaction(params []interface{}) map[string]interface{} {
 	return responseSuccess(common.ToHexString(tx.Raw))
 }
 
-//get storage from contract
-//   {"jsonrpc": "2.0", "method": "getstorage", "params": ["code hash", "key"], "id": 0}
+//get storage from contract, optionally at block height in archive mode
+//   {"jsonrpc": "2.0", "method": "getstorage", "params": ["code hash", "key", height], "id": 0}
 func GetStorage(params []interface{}) map[string]interface{} {
 	if len(params) < 2 {
 		return responsePack(berr.INVALID_PARAMS, nil)
@@ -270,7 +270,20 @@ func GetStorage(params []interface{}) map[string]interface{} {
 	default:
 		return responsePack(berr.INVALID_PARAMS, "")
 	}
-	value, err := bactor.GetStorageItem(address, key)
+	var value []byte
+	var err error
+	if len(params) > 2 {
+		height, ok := params[2].(float64)
+		if !ok || height < 0 {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		value, err = bactor.GetStorageItemAtHeight(address, key, uint32(height))
+		if err == scom.ErrHistoryUnavailable {
+			return responsePack(berr.INVALID_METHOD, "")
+		}
+	} else {
+		value, err = bactor.GetStorageItem(address, key)
+	}
 	if err != nil {
 		if err == scom.ErrNotFound {
 			return responseSuccess(nil)
@@ -280,6 +293,75 @@ func GetStorage(params []interface{}) map[string]interface{} {
 	return responseSuccess(common.ToHexString(value))
 }
 
+//get storage from contract with its proof in the state trie, at current block height if height is omitted.
+//From the state trie root height, the root at height is committed to the cross states of height+1, and the
+//response carries its cross states proof with the header at height+2 signing it, once that header is committed.
+//Roots of pruned blocks are not available in prune mode.
+//   {"jsonrpc": "2.0", "method": "getstorageproof", "params": ["code hash", "key", height], "id": 0}
+func GetStorageProof(params []interface{}) map[string]interface{} {
+	if len(params) < 2 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	address, err := bcomn.GetAddress(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	str, ok = params[1].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	key, err := hex.DecodeString(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	height := bactor.GetCurrentBlockHeight()
+	if len(params) > 2 {
+		h, ok := params[2].(float64)
+		if !ok || h < 0 {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		height = uint32(h)
+	}
+	value, proof, root, err := bactor.GetStorageProof(address, key, height)
+	if err == scom.ErrHistoryUnavailable {
+		return responsePack(berr.INVALID_METHOD, "")
+	}
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	sink := common.NewZeroCopySink(nil)
+	proof.Serialization(sink)
+	result := bcomn.StorageProof{
+		Height: height,
+		Root:   root.ToHexString(),
+		Exist:  value != nil,
+		Value:  common.ToHexString(value),
+		Proof:  common.ToHexString(sink.Bytes()),
+	}
+	// cross states root of height+1 is committed in the header of next block
+	headerHeight := height + 2
+	if headerHeight <= bactor.GetCurrentBlockHeight() {
+		rootProof, err := bactor.GetStateTrieRootProof(height)
+		if err != nil && err != scom.ErrNotFound {
+			return responsePack(berr.INTERNAL_ERROR, err.Error())
+		}
+		if err == nil {
+			header, err := bactor.GetHeaderByHeight(headerHeight)
+			if err != nil {
+				return responsePack(berr.INTERNAL_ERROR, err.Error())
+			}
+			result.RootProof = hex.EncodeToString(rootProof)
+			result.HeaderHeight = headerHeight
+			result.Header = hex.EncodeToString(header.ToArray())
+		}
+	}
+	return responseSuccess(result)
+}
+
 //send raw transaction
 // A JSON example for sendrawtransaction method as following:
 //   {"jsonrpc": "2.0", "method": "sendrawtransaction", "params": ["raw transactioin in hex"], "id": 0}
@@ -385,6 +467,70 @@ func GetSmartCodeEvent(params []interface{}) map[string]interface{} {
 	return responsePack(berr.INVALID_PARAMS, "")
 }
 
+//get cross chain tx by source chain id and cross chain id
+func GetCrossChainTx(params []interface{}) map[string]interface{} {
+	if !config.DefConfig.Common.EnableEventLog {
+		return responsePack(berr.INVALID_METHOD, "")
+	}
+	if len(params) < 2 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	chainID, ok := params[0].(float64)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	str, ok := params[1].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	crossChainID, err := hex.DecodeString(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	tx, err := bactor.GetCrossChainTx(uint64(chainID), crossChainID)
+	if err != nil {
+		if err == scom.ErrNotFound {
+			return responseSuccess(nil)
+		}
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(bcomn.GetCrossChainTx(tx))
+}
+
+//list cross chain txs of source chain by page, in the order of the height they are first indexed at
+func ListCrossChainTxs(params []interface{}) map[string]interface{} {
+	if !config.DefConfig.Common.EnableEventLog {
+		return responsePack(berr.INVALID_METHOD, "")
+	}
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	chainID, ok := params[0].(float64)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	offset, limit := float64(0), float64(bcomn.MAX_CROSS_CHAIN_TX_LIMIT)
+	if len(params) > 1 {
+		if offset, ok = params[1].(float64); !ok || offset < 0 {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	}
+	if len(params) > 2 {
+		if limit, ok = params[2].(float64); !ok || limit <= 0 || limit > float64(bcomn.MAX_CROSS_CHAIN_TX_LIMIT) {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	}
+	txs, err := bactor.ListCrossChainTxs(uint64(chainID), uint32(offset), uint32(limit))
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	result := make([]bcomn.CrossChainTx, 0, len(txs))
+	for _, tx := range txs {
+		result = append(result, bcomn.GetCrossChainTx(tx))
+	}
+	return responseSuccess(result)
+}
+
 //get block height by transaction hash
 func GetBlockHeightByTxHash(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
@@ -457,6 +603,70 @@ func GetCrossStatesProof(params []interface{}) map[string]interface{} {
 	return responseSuccess(bcomn.MerkleProof{"CrossStatesProof", hex.EncodeToString(proof)})
 }
 
+//get cross chain state proof with the headers to verify it, params are height and key of cross state,
+//and the optional anchor height whose header is used to prove the header at height+1
+func GetCrossChainProofBundle(params []interface{}) map[string]interface{} {
+	if len(params) < 2 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	height, ok := params[0].(float64)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	str, ok := params[1].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	key, err := hex.DecodeString(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	var anchorHeight float64
+	if len(params) > 2 {
+		if anchorHeight, ok = params[2].(float64); !ok {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	}
+	// cross states root of height is committed in the header of next block
+	headerHeight := uint32(height) + 1
+	curHeight := bactor.GetCurrentBlockHeight()
+	if headerHeight > curHeight {
+		return responsePack(berr.INVALID_PARAMS, fmt.Sprintf("header at height %d is not committed, current height: %d", headerHeight, curHeight))
+	}
+	if anchorHeight != 0 && (uint32(anchorHeight) <= headerHeight || uint32(anchorHeight) > curHeight) {
+		return responsePack(berr.INVALID_PARAMS, fmt.Sprintf("anchor height should be in (%d, %d]", headerHeight, curHeight))
+	}
+	proof, err := bactor.GetCrossStatesProof(uint32(height), key)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	header, err := bactor.GetHeaderByHeight(headerHeight)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	bundle := bcomn.CrossChainProofBundle{
+		Height:           uint32(height),
+		Key:              str,
+		CrossStatesProof: hex.EncodeToString(proof),
+		HeaderHeight:     headerHeight,
+		Header:           hex.EncodeToString(header.ToArray()),
+	}
+	if anchorHeight != 0 {
+		anchor, err := bactor.GetHeaderByHeight(uint32(anchorHeight))
+		if err != nil {
+			return responsePack(berr.INTERNAL_ERROR, err.Error())
+		}
+		headerProof, err := bactor.GetMerkleProof(headerHeight, uint32(anchorHeight))
+		if err != nil {
+			return responsePack(berr.INTERNAL_ERROR, err.Error())
+		}
+		bundle.AnchorHeight = uint32(anchorHeight)
+		bundle.AnchorHeader = hex.EncodeToString(anchor.ToArray())
+		bundle.HeaderProof = hex.EncodeToString(headerProof)
+	}
+	return responseSuccess(bundle)
+}
+
 func GetHeaderByHeight(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
 		return responsePack(berr.INVALID_PARAMS, nil)
@@ -516,3 +726,88 @@ func GetStateMerkleRoot(params []interface{}) map[string]interface{} {
 	}
 
 }
+
+//get the schemas of events emitted by native contracts
+func GetEventSchemas(params []interface{}) map[string]interface{} {
+	return responseSuccess(bcomn.GetEventSchemas())
+}
+
+//get the header sync storage usage and header retention of a side chain
+//   {"jsonrpc": "2.0", "method": "getheaderstorageusage", "params": [chainID], "id": 0}
+func GetHeaderStorageUsage(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	chainID, ok := params[0].(float64)
+	if !ok || chainID < 0 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	usage, err := bcomn.GetHeaderStorageUsage(uint64(chainID))
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return responseSuccess(usage)
+}
+
+//get the synced header, finalized height, validators and genesis anchor of all side chains, or of chainID only
+//   {"jsonrpc": "2.0", "method": "getsidechainstatus", "params": [], "id": 0}
+//   {"jsonrpc": "2.0", "method": "getsidechainstatus", "params": [chainID], "id": 0}
+func GetSideChainStatus(params []interface{}) map[string]interface{} {
+	var chainID *uint64
+	if len(params) > 0 {
+		id, ok := params[0].(float64)
+		if !ok || id < 0 {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		v := uint64(id)
+		chainID = &v
+	}
+	statuses, err := bcomn.GetSideChainStatus(chainID)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	if chainID != nil {
+		if len(statuses) == 0 {
+			return responsePack(berr.INVALID_PARAMS, "side chain is not registered")
+		}
+		return responseSuccess(statuses[0])
+	}
+	return responseSuccess(statuses)
+}
+
+//dry run the unsigned relayer tx calling syncBlockHeader or importOuterTransfer with the hex encoded contract
+//params on current state, the error reasons of the tx and of every header of a batch are returned, a batch has
+//at most MAX_DRY_RUN_HEADERS headers
+//   {"jsonrpc": "2.0", "method": "dryrunsidechaintx", "params": ["syncBlockHeader", "params in hex"], "id": 0}
+func DryRunSideChainTx(params []interface{}) map[string]interface{} {
+	if len(params) < 2 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	method, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	str, ok := params[1].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	args, err := common.HexToBytes(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	result, err := bcomn.DryRunSideChainTx(method, args)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return responseSuccess(result)
+}
+
+//get the ledger prune mode and progress
+func GetPruneStatus(params []interface{}) map[string]interface{} {
+	return responseSuccess(bcomn.PruneStatus{
+		Archive:       config.DefConfig.Common.ArchiveMode,
+		PruneBlocks:   config.DefConfig.Common.PruneBlocks,
+		PrunedHeight:  bactor.GetPrunedHeight(),
+		CurrentHeight: bactor.GetCurrentBlockHeight(),
+	})
+}
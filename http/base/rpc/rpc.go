@@ -20,6 +20,7 @@
 package rpc
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -32,6 +33,9 @@ import (
 	berr "github.com/polynetwork/poly/http/base/error"
 )
 
+//max number of requests in a batch call
+const MAX_BATCH_SIZE = 100
+
 func init() {
 	mainMux.m = make(map[string]func([]interface{}) map[string]interface{})
 }
@@ -100,6 +104,24 @@ func Handle(w http.ResponseWriter, r *http.Request) {
 		log.Error("HTTP JSON RPC Handle - ioutil.ReadAll: ", err)
 		return
 	}
+	//JSON-RPC 2.0 batch call
+	if body = bytes.TrimSpace(body); len(body) > 0 && body[0] == '[' {
+		var requests []map[string]interface{}
+		if err := json.Unmarshal(body, &requests); err != nil {
+			log.Error("HTTP JSON RPC Handle - json.Unmarshal batch: ", err)
+			return
+		}
+		if len(requests) == 0 || len(requests) > MAX_BATCH_SIZE {
+			writeResponse(w, invalidRequest(nil, fmt.Sprintf("batch size should be in [1, %d]", MAX_BATCH_SIZE)))
+			return
+		}
+		responses := make([]map[string]interface{}, 0, len(requests))
+		for _, request := range requests {
+			responses = append(responses, handleRequest(request))
+		}
+		writeResponse(w, responses)
+		return
+	}
 	request := make(map[string]interface{})
 	err = json.Unmarshal(body, &request)
 	if err != nil {
@@ -110,34 +132,29 @@ func Handle(w http.ResponseWriter, r *http.Request) {
 		log.Error("HTTP JSON RPC Handle - method not found: ")
 		return
 	}
-	method, ok := request["method"].(string)
-	if !ok {
+	if _, ok := request["method"].(string); !ok {
 		log.Error("HTTP JSON RPC Handle - method is not string: ")
 		return
 	}
+	writeResponse(w, handleRequest(request))
+}
+
+//handleRequest call the function of request method, and return the response
+func handleRequest(request map[string]interface{}) map[string]interface{} {
+	method, ok := request["method"].(string)
+	if !ok {
+		return invalidRequest(request["id"], "method is not string")
+	}
+	params, ok := request["params"].([]interface{})
+	if !ok && request["params"] != nil {
+		return invalidRequest(request["id"], "params is not array")
+	}
 	//get the corresponding function
 	function, ok := mainMux.m[method]
-	if ok {
-		response := function(request["params"].([]interface{}))
-		data, err := json.Marshal(map[string]interface{}{
-			"jsonrpc": "2.0",
-			"error":   response["error"],
-			"desc":    response["desc"],
-			"result":  response["result"],
-			"id":      request["id"],
-		})
-		if err != nil {
-			log.Error("HTTP JSON RPC Handle - json.Marshal: ", err)
-			return
-		}
-		w.Header().Add("Access-Control-Allow-Headers", "Content-Type")
-		w.Header().Set("content-type", "application/json;charset=utf-8")
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Write(data)
-	} else {
+	if !ok {
 		//if the function does not exist
 		log.Warn("HTTP JSON RPC Handle - No function to call for ", request["method"])
-		data, err := json.Marshal(map[string]interface{}{
+		return map[string]interface{}{
 			"error": berr.INVALID_METHOD,
 			"result": map[string]interface{}{
 				"code":    -32601,
@@ -145,16 +162,40 @@ func Handle(w http.ResponseWriter, r *http.Request) {
 				"data":    "The called method was not found on the server",
 			},
 			"id": request["id"],
-		})
-		if err != nil {
-			log.Error("HTTP JSON RPC Handle - json.Marshal: ", err)
-			return
 		}
-		w.Header().Add("Access-Control-Allow-Headers", "Content-Type")
-		w.Header().Set("content-type", "application/json;charset=utf-8")
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Write(data)
 	}
+	response := function(params)
+	return map[string]interface{}{
+		"jsonrpc": "2.0",
+		"error":   response["error"],
+		"desc":    response["desc"],
+		"result":  response["result"],
+		"id":      request["id"],
+	}
+}
+
+func invalidRequest(id interface{}, data string) map[string]interface{} {
+	return map[string]interface{}{
+		"error": berr.INVALID_PARAMS,
+		"result": map[string]interface{}{
+			"code":    -32600,
+			"message": "Invalid request",
+			"data":    data,
+		},
+		"id": id,
+	}
+}
+
+func writeResponse(w http.ResponseWriter, response interface{}) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		log.Error("HTTP JSON RPC Handle - json.Marshal: ", err)
+		return
+	}
+	w.Header().Add("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("content-type", "application/json;charset=utf-8")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Write(data)
 }
 
 // Call sends RPC request to server
@@ -0,0 +1,72 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	berr "github.com/polynetwork/poly/http/base/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func call(t *testing.T, body string) []byte {
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	Handle(w, req)
+	return w.Body.Bytes()
+}
+
+func TestHandleBatch(t *testing.T) {
+	HandleFunc("testecho", func(params []interface{}) map[string]interface{} {
+		return responseSuccess(params)
+	})
+
+	var single map[string]interface{}
+	assert.NoError(t, json.Unmarshal(call(t, `{"jsonrpc":"2.0","method":"testecho","params":[1],"id":1}`), &single))
+	assert.Equal(t, []interface{}{float64(1)}, single["result"])
+	assert.Equal(t, float64(1), single["id"])
+
+	var batch []map[string]interface{}
+	body := `[{"jsonrpc":"2.0","method":"testecho","params":["a"],"id":1},
+		{"jsonrpc":"2.0","method":"notexist","params":[],"id":2},
+		{"jsonrpc":"2.0","method":"testecho","params":{},"id":3},
+		{"jsonrpc":"2.0","method":"testecho","id":"4"}]`
+	assert.NoError(t, json.Unmarshal(call(t, body), &batch))
+	assert.Equal(t, 4, len(batch))
+	assert.Equal(t, []interface{}{"a"}, batch[0]["result"])
+	assert.Equal(t, float64(berr.INVALID_METHOD), batch[1]["error"])
+	assert.Equal(t, float64(2), batch[1]["id"])
+	assert.Equal(t, float64(berr.INVALID_PARAMS), batch[2]["error"])
+	assert.Equal(t, float64(berr.SUCCESS), batch[3]["error"])
+	assert.Equal(t, "4", batch[3]["id"])
+
+	requests := make([]string, MAX_BATCH_SIZE+1)
+	for i := range requests {
+		requests[i] = fmt.Sprintf(`{"method":"testecho","params":[],"id":%d}`, i)
+	}
+	var resp map[string]interface{}
+	assert.NoError(t, json.Unmarshal(call(t, "["+strings.Join(requests, ",")+"]"), &resp))
+	assert.Equal(t, float64(berr.INVALID_PARAMS), resp["error"])
+	assert.NoError(t, json.Unmarshal(call(t, "[]"), &resp))
+	assert.Equal(t, float64(berr.INVALID_PARAMS), resp["error"])
+}
@@ -46,6 +46,7 @@ func StartRPCServer() error {
 	rpc.HandleFunc("getrawtransaction", rpc.GetRawTransaction)
 	rpc.HandleFunc("sendrawtransaction", rpc.SendRawTransaction)
 	rpc.HandleFunc("getstorage", rpc.GetStorage)
+	rpc.HandleFunc("getstorageproof", rpc.GetStorageProof)
 	rpc.HandleFunc("getversion", rpc.GetNodeVersion)
 	rpc.HandleFunc("getnetworkid", rpc.GetNetworkId)
 
@@ -53,9 +54,17 @@ func StartRPCServer() error {
 	rpc.HandleFunc("getmempooltxstate", rpc.GetMemPoolTxState)
 	rpc.HandleFunc("getsmartcodeevent", rpc.GetSmartCodeEvent)
 	rpc.HandleFunc("getblockheightbytxhash", rpc.GetBlockHeightByTxHash)
+	rpc.HandleFunc("getcrosschaintx", rpc.GetCrossChainTx)
+	rpc.HandleFunc("listcrosschaintxs", rpc.ListCrossChainTxs)
+	rpc.HandleFunc("getprunestatus", rpc.GetPruneStatus)
+	rpc.HandleFunc("getheaderstorageusage", rpc.GetHeaderStorageUsage)
+	rpc.HandleFunc("getsidechainstatus", rpc.GetSideChainStatus)
+	rpc.HandleFunc("dryrunsidechaintx", rpc.DryRunSideChainTx)
+	rpc.HandleFunc("geteventschemas", rpc.GetEventSchemas)
 
 	rpc.HandleFunc("getmerkleproof", rpc.GetMerkleProof)
 	rpc.HandleFunc("getcrossstatesproof", rpc.GetCrossStatesProof)
+	rpc.HandleFunc("getcrosschainproofbundle", rpc.GetCrossChainProofBundle)
 	rpc.HandleFunc("getheaderbyheight", rpc.GetHeaderByHeight)
 	rpc.HandleFunc("getblocktxsbyheight", rpc.GetBlockTxsByHeight)
 	rpc.HandleFunc("getstatemerkleroot", rpc.GetStateMerkleRoot)
@@ -0,0 +1,41 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package metrics privides a function to start the prometheus metrics server
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	cfg "github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/common/metrics"
+)
+
+const METRICS_DIR string = "/metrics"
+
+func StartServer() error {
+	mux := http.NewServeMux()
+	mux.Handle(METRICS_DIR, metrics.DefRegistry.Handler())
+	err := http.ListenAndServe(":"+strconv.Itoa(int(cfg.DefConfig.Metrics.HttpMetricsPort)), mux)
+	if err != nil {
+		return fmt.Errorf("ListenAndServe error:%s", err)
+	}
+	return nil
+}
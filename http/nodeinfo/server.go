@@ -43,6 +43,8 @@ type Info struct {
 	NodePort      uint16
 	NodeId        string
 	NodeType      string
+	LedgerMode    string
+	PrunedHeight  uint32
 }
 
 const (
@@ -68,7 +70,19 @@ func initPageInfo(blockHeight uint32, curNodeType string, ngbrCnt int, ngbrsInfo
 		HttpJsonPort:  int(config.DefConfig.Rpc.HttpJsonPort),
 		HttpLocalPort: int(config.DefConfig.Rpc.HttpLocalPort),
 		NodePort:      uint16(config.DefConfig.P2PNode.NodePort),
-		NodeId:        id, NodeType: curNodeType}, nil
+		NodeId:        id, NodeType: curNodeType,
+		LedgerMode:   ledgerMode(),
+		PrunedHeight: ledger.DefLedger.GetPrunedHeight()}, nil
+}
+
+func ledgerMode() string {
+	if config.DefConfig.Common.ArchiveMode {
+		return "archive"
+	}
+	if config.DefConfig.Common.PruneBlocks != 0 {
+		return fmt.Sprintf("prune(%d)", config.DefConfig.Common.PruneBlocks)
+	}
+	return "full"
 }
 
 func viewHandler(w http.ResponseWriter, r *http.Request) {
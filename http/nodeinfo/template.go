@@ -61,6 +61,7 @@ const TEMPLATE_PAGE = `
 	<tr><td width="25%">NodeType:</td><td width="25%">{{.NodeType}}</td><td width="25%">NodePort:</td><td width="25%">{{.NodePort}}</td></tr>
 	<tr><td width="25%">HttpRestPort:</td><td width="25%">{{.HttpRestPort}}</td><td width="25%">HttpWsPort:</td><td width="25%">{{.HttpWsPort}}</td></tr>
 	<tr><td width="25%">HttpJsonPort:</td><td width="25%">{{.HttpJsonPort}}</td><td width="25%">HttpLocalPort:</td><td width="25%">{{.HttpLocalPort}}</td></tr>
+	<tr><td width="25%">LedgerMode:</td><td width="25%">{{.LedgerMode}}</td><td width="25%">PrunedHeight:</td><td width="25%">{{.PrunedHeight}}</td></tr>
 	</table>
 </td>
 </tr>
@@ -48,6 +48,7 @@ func sendBlock2WSclient(v interface{}) {
 		go func() {
 			pushBlock(v)
 			pushBlockTransactions(v)
+			pushCrossChainProofs(v)
 		}()
 	}
 }
@@ -123,3 +124,26 @@ func pushBlockTransactions(v interface{}) {
 		ws.BroadcastToSubscribers(nil, websocket.WSTOPIC_TXHASHS, resp)
 	}
 }
+
+//pushCrossChainProofs push the cross chain requests made in previous block, whose cross states root is
+//committed by the header of this block
+func pushCrossChainProofs(v interface{}) {
+	if ws == nil {
+		return
+	}
+	block, ok := v.(types.Block)
+	if !ok || block.Header.Height == 0 {
+		return
+	}
+	proofs, err := bcomn.GetCrossChainProofs(block.Header.Height-1, block.Header, ws.IsCrossChainProofSubscribed)
+	if err != nil {
+		log.Errorf("[pushCrossChainProofs] height:%d error:%s", block.Header.Height-1, err)
+		return
+	}
+	for _, proof := range proofs {
+		resp := rest.ResponsePack(Err.SUCCESS)
+		resp["Action"] = "sendcrosschainproof"
+		resp["Result"] = proof
+		ws.BroadcastCrossChainProof(proof.ToChainID, resp)
+	}
+}
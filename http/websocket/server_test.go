@@ -0,0 +1,155 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	gws "github.com/gorilla/websocket"
+	"github.com/polynetwork/poly/common"
+	cfg "github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/core/types"
+	bactor "github.com/polynetwork/poly/http/base/actor"
+	"github.com/polynetwork/poly/http/base/rest"
+	"github.com/polynetwork/poly/http/websocket/websocket"
+	"github.com/polynetwork/poly/native/event"
+	ccom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/stretchr/testify/assert"
+)
+
+//proofLedger holds the events of a block, and returns the height as the cross states proof
+type proofLedger struct {
+	bactor.LedgerReader
+	height   uint32
+	notifies []*event.ExecuteNotify
+}
+
+func (self *proofLedger) GetEventNotifyByBlock(height uint32) ([]*event.ExecuteNotify, error) {
+	if height != self.height {
+		return nil, nil
+	}
+	return self.notifies, nil
+}
+
+func (self *proofLedger) GetCrossStatesProof(height uint32, key []byte) ([]byte, error) {
+	return []byte{byte(height)}, nil
+}
+
+func freeWsPort(t *testing.T) uint {
+	for {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		port := l.Addr().(*net.TCPAddr).Port
+		l.Close()
+		if port%1000 != rest.TLS_PORT {
+			return uint(port)
+		}
+	}
+}
+
+func dialWs(t *testing.T, port uint, subscribe map[string]interface{}) *gws.Conn {
+	var conn *gws.Conn
+	var err error
+	for i := 0; i < 50; i++ {
+		conn, _, err = gws.DefaultDialer.Dial(fmt.Sprintf("ws://127.0.0.1:%d", port), nil)
+		if err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	subscribe["Action"] = "subscribe"
+	assert.NoError(t, conn.WriteJSON(subscribe))
+	resp := make(map[string]interface{})
+	assert.NoError(t, conn.ReadJSON(&resp))
+	assert.Equal(t, "subscribe", resp["Action"])
+	return conn
+}
+
+func TestPushCrossChainProofs(t *testing.T) {
+	ledger := &proofLedger{height: 10, notifies: []*event.ExecuteNotify{
+		{
+			TxHash: common.Uint256{1},
+			Notify: []*event.NotifyEventInfo{
+				ccom.MakeProofEvent.NewNotify(uint64(2), uint64(3), "aa01", uint32(10), "0102"),
+				ccom.MakeProofEvent.NewNotify(uint64(2), uint64(4), "aa02", uint32(10), "0304"),
+			},
+		},
+	}}
+	bactor.SetLedgerReader(ledger)
+	defer bactor.SetLedgerReader(nil)
+
+	wsPort := cfg.DefConfig.Ws.HttpWsPort
+	cfg.DefConfig.Ws.HttpWsPort = freeWsPort(t)
+	defer func() { cfg.DefConfig.Ws.HttpWsPort = wsPort }()
+	ws = websocket.InitWsServer()
+	go ws.Start()
+	defer func() {
+		ws.Stop()
+		ws = nil
+	}()
+
+	subscriber := dialWs(t, cfg.DefConfig.Ws.HttpWsPort, map[string]interface{}{
+		"SubscribeCrossChainProof": true, "ToChainsFilter": []uint64{3}})
+	defer subscriber.Close()
+	other := dialWs(t, cfg.DefConfig.Ws.HttpWsPort, map[string]interface{}{
+		"SubscribeCrossChainProof": true, "ToChainsFilter": []uint64{5}})
+	defer other.Close()
+	unsubscribed := dialWs(t, cfg.DefConfig.Ws.HttpWsPort, map[string]interface{}{"SubscribeJsonBlock": false})
+	defer unsubscribed.Close()
+
+	// requests made at height 10 are proved against the header at height 11
+	header := &types.Header{Height: 11, CrossStateRoot: common.Uint256{2}}
+	pushCrossChainProofs(types.Block{Header: header})
+
+	subscriber.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, data, err := subscriber.ReadMessage()
+	assert.NoError(t, err)
+	resp := struct {
+		Action string
+		Result struct {
+			ToChainID        uint64
+			Height           uint32
+			Key              string
+			CrossStatesProof string
+			HeaderHeight     uint32
+			Header           string
+		}
+	}{}
+	assert.NoError(t, json.Unmarshal(data, &resp))
+	assert.Equal(t, "sendcrosschainproof", resp.Action)
+	assert.Equal(t, uint64(3), resp.Result.ToChainID)
+	assert.Equal(t, uint32(10), resp.Result.Height)
+	assert.Equal(t, "0102", resp.Result.Key)
+	assert.Equal(t, common.ToHexString([]byte{10}), resp.Result.CrossStatesProof)
+	assert.Equal(t, uint32(11), resp.Result.HeaderHeight)
+	assert.Equal(t, common.ToHexString(header.ToArray()), resp.Result.Header)
+
+	// the request to chain 4 is pushed to no one
+	for _, conn := range []*gws.Conn{subscriber, other, unsubscribed} {
+		conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		_, _, err = conn.ReadMessage()
+		assert.Error(t, err)
+	}
+}
@@ -39,10 +39,11 @@ import (
 )
 
 const (
-	WSTOPIC_EVENT      = 1
-	WSTOPIC_JSON_BLOCK = 2
-	WSTOPIC_RAW_BLOCK  = 3
-	WSTOPIC_TXHASHS    = 4
+	WSTOPIC_EVENT             = 1
+	WSTOPIC_JSON_BLOCK        = 2
+	WSTOPIC_RAW_BLOCK         = 3
+	WSTOPIC_TXHASHS           = 4
+	WSTOPIC_CROSS_CHAIN_PROOF = 5
 )
 
 type handler func(map[string]interface{}) map[string]interface{}
@@ -58,6 +59,9 @@ type subscribe struct {
 	SubscribeJsonBlock    bool     `json:"SubscribeJsonBlock"`
 	SubscribeRawBlock     bool     `json:"SubscribeRawBlock"`
 	SubscribeBlockTxHashs bool     `json:"SubscribeBlockTxHashs"`
+	//push cross chain requests with proofs to the chains in ToChainsFilter, or to all chains if it is empty
+	SubscribeCrossChainProof bool     `json:"SubscribeCrossChainProof"`
+	ToChainsFilter           []uint64 `json:"ToChainsFilter"`
 }
 type WsServer struct {
 	sync.RWMutex
@@ -157,6 +161,17 @@ func (self *WsServer) registryMethod() {
 		if b, ok := cmd["SubscribeBlockTxHashs"].(bool); ok {
 			sub.SubscribeBlockTxHashs = b
 		}
+		if b, ok := cmd["SubscribeCrossChainProof"].(bool); ok {
+			sub.SubscribeCrossChainProof = b
+		}
+		if chains, ok := cmd["ToChainsFilter"].([]interface{}); ok {
+			sub.ToChainsFilter = []uint64{}
+			for _, v := range chains {
+				if chainID, k := v.(float64); k && chainID >= 0 {
+					sub.ToChainsFilter = append(sub.ToChainsFilter, uint64(chainID))
+				}
+			}
+		}
 		if ctsf, ok := cmd["ContractsFilter"].([]interface{}); ok {
 			sub.ContractsFilter = []string{}
 			for _, v := range ctsf {
@@ -438,6 +453,47 @@ func (self *WsServer) BroadcastToSubscribers(contractAddrs map[string]bool, sub
 	}
 }
 
+//IsCrossChainProofSubscribed return true if any session subscribes the cross chain proofs to chain
+func (self *WsServer) IsCrossChainProofSubscribed(toChainID uint64) bool {
+	self.RLock()
+	defer self.RUnlock()
+	for _, v := range self.SubscribeMap {
+		if v.subscribeToChain(toChainID) {
+			return true
+		}
+	}
+	return false
+}
+
+func (self *WsServer) BroadcastCrossChainProof(toChainID uint64, resp map[string]interface{}) {
+	self.Lock()
+	defer self.Unlock()
+	data := marshalResp(resp)
+	for sid, v := range self.SubscribeMap {
+		if !v.subscribeToChain(toChainID) {
+			continue
+		}
+		if s := self.SessionList.GetSessionById(sid); s != nil {
+			s.Send(data)
+		}
+	}
+}
+
+func (self subscribe) subscribeToChain(toChainID uint64) bool {
+	if !self.SubscribeCrossChainProof {
+		return false
+	}
+	if len(self.ToChainsFilter) == 0 {
+		return true
+	}
+	for _, chainID := range self.ToChainsFilter {
+		if chainID == toChainID {
+			return true
+		}
+	}
+	return false
+}
+
 func (self *WsServer) initTlsListen() (net.Listener, error) {
 
 	certPath := cfg.DefConfig.Ws.HttpCertPath
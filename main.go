@@ -46,6 +46,7 @@ import (
 	hserver "github.com/polynetwork/poly/http/base/actor"
 	"github.com/polynetwork/poly/http/jsonrpc"
 	"github.com/polynetwork/poly/http/localrpc"
+	"github.com/polynetwork/poly/http/metrics"
 	"github.com/polynetwork/poly/http/nodeinfo"
 	"github.com/polynetwork/poly/http/restful"
 	"github.com/polynetwork/poly/http/websocket"
@@ -72,6 +73,10 @@ func setupAPP() *cli.App {
 		cmd.InfoCommand,
 		cmd.ImportCommand,
 		cmd.ExportCommand,
+		cmd.ExportSnapshotCommand,
+		cmd.ImportSnapshotCommand,
+		cmd.GovCommand,
+		cmd.InvokeCommand,
 		cmd.SigTxCommand,
 		cmd.MultiSigAddrCommand,
 		cmd.MultiSigTxCommand,
@@ -84,6 +89,8 @@ func setupAPP() *cli.App {
 		utils.LogLevelFlag,
 		utils.DisableEventLogFlag,
 		utils.DataDirFlag,
+		utils.PruneFlag,
+		utils.ArchiveFlag,
 		//account setting
 		utils.WalletFileFlag,
 		utils.AccountAddressFlag,
@@ -120,6 +127,9 @@ func setupAPP() *cli.App {
 		//ws setting
 		utils.WsEnabledFlag,
 		utils.WsPortFlag,
+		//metrics setting
+		utils.MetricsEnableFlag,
+		utils.MetricsPortFlag,
 	}
 	app.Before = func(context *cli.Context) error {
 		runtime.GOMAXPROCS(runtime.NumCPU())
@@ -187,6 +197,7 @@ func startOntology(ctx *cli.Context) {
 	initRestful(ctx)
 	initWs(ctx)
 	initNodeInfo(ctx, p2pSvr)
+	initMetrics(ctx)
 
 	go logCurrBlockHeight()
 	waitToExit()
@@ -259,6 +270,7 @@ func initLedger(ctx *cli.Context) (*ledger.Ledger, error) {
 	if err != nil {
 		return nil, fmt.Errorf("Init ledger error:%s", err)
 	}
+	hserver.SetLedgerReader(ledger.DefLedger)
 
 	log.Infof("Ledger init success")
 	return ledger.DefLedger, nil
@@ -278,8 +290,8 @@ func initTxPool(ctx *cli.Context) (*proc.TXPoolServer, error) {
 	stfValidator, _ := stateful.NewValidator("stateful_validator")
 	stfValidator.Register(txPoolServer.GetPID(tc.VerifyRspActor))
 
-	hserver.SetTxnPoolPid(txPoolServer.GetPID(tc.TxPoolActor))
-	hserver.SetTxPid(txPoolServer.GetPID(tc.TxActor))
+	hserver.SetTxPoolService(hserver.NewActorTxPoolService(txPoolServer.GetPID(tc.TxActor),
+		txPoolServer.GetPID(tc.TxPoolActor)))
 
 	log.Infof("TxPool init success")
 	return txPoolServer, nil
@@ -303,7 +315,7 @@ func initP2PNode(ctx *cli.Context, txpoolSvr *proc.TXPoolServer) (*p2pserver.P2P
 	}
 	netreqactor.SetTxnPoolPid(txpoolSvr.GetPID(tc.TxActor))
 	txpoolSvr.RegisterActor(tc.NetActor, p2pPID)
-	hserver.SetNetServerPID(p2pPID)
+	hserver.SetP2PService(hserver.NewActorP2PService(p2pPID))
 	p2p.WaitForPeersStart()
 	log.Infof("P2P init success")
 	return p2p, p2pPID, nil
@@ -396,6 +408,20 @@ func initWs(ctx *cli.Context) {
 	log.Infof("Ws init success")
 }
 
+func initMetrics(ctx *cli.Context) {
+	if !config.DefConfig.Metrics.EnableHttpMetrics {
+		return
+	}
+	go func() {
+		err := metrics.StartServer()
+		if err != nil {
+			log.Errorf("metrics server error:%s", err)
+		}
+	}()
+
+	log.Infof("Metrics init success")
+}
+
 func initNodeInfo(ctx *cli.Context, p2pSvr *p2pserver.P2PServer) {
 	if config.DefConfig.P2PNode.HttpInfoPort == 0 {
 		return
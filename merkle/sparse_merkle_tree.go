@@ -0,0 +1,386 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package merkle
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+)
+
+const (
+	SPARSE_TREE_DEPTH = 256
+
+	sparseLeafNode     byte = 0
+	sparseInternalNode byte = 1
+	sparseNodeSize          = 1 + 2*common.UINT256_SIZE
+)
+
+// SparseNodeStore reads the persisted nodes of a sparse merkle tree by hash
+type SparseNodeStore interface {
+	GetNode(hash common.Uint256) ([]byte, error)
+}
+
+// SparseMerkleTree is a binary tree of depth 256 over the sha256 of keys. A subtree holding
+// a single leaf is stored as the leaf itself, and an empty subtree hashes to UINT256_EMPTY.
+// Nodes are addressed by hash and never rewritten, so the tree at every committed root stays
+// readable.
+//
+//	leaf     = sha256(0x00 || sha256(key) || sha256(value))
+//	internal = sha256(0x01 || left || right)
+//
+// A leaf node is persisted with its value appended, so values can be read at any root.
+type SparseMerkleTree struct {
+	store SparseNodeStore
+	root  common.Uint256
+	dirty map[common.Uint256][]byte
+}
+
+// NewSparseMerkleTree returns the tree at root, whose nodes are read from store
+func NewSparseMerkleTree(root common.Uint256, store SparseNodeStore) *SparseMerkleTree {
+	return &SparseMerkleTree{
+		store: store,
+		root:  root,
+		dirty: make(map[common.Uint256][]byte),
+	}
+}
+
+// Root returns the root hash of the tree
+func (self *SparseMerkleTree) Root() common.Uint256 {
+	return self.root
+}
+
+// DirtyNodes returns the nodes created since the last call, the caller should persist the ones
+// reachable from the new root before it is used. Nodes replaced in between are included.
+func (self *SparseMerkleTree) DirtyNodes() map[common.Uint256][]byte {
+	dirty := self.dirty
+	self.dirty = make(map[common.Uint256][]byte)
+	return dirty
+}
+
+// Put sets the value of key
+func (self *SparseMerkleTree) Put(key, value []byte) error {
+	keyHash := common.Uint256(sha256.Sum256(key))
+	leaf := self.putNode(sparseLeafNode, keyHash, sha256.Sum256(value), value)
+	root, err := self.insert(self.root, 0, leaf, keyHash)
+	if err != nil {
+		return err
+	}
+	self.root = root
+	return nil
+}
+
+// Delete removes key from the tree
+func (self *SparseMerkleTree) Delete(key []byte) error {
+	root, err := self.remove(self.root, 0, sha256.Sum256(key))
+	if err != nil {
+		return err
+	}
+	self.root = root
+	return nil
+}
+
+// Prove returns the value of key and the proof of its inclusion, or a nil value and the proof
+// of its absence
+func (self *SparseMerkleTree) Prove(key []byte) ([]byte, *SparseMerkleProof, error) {
+	keyHash := common.Uint256(sha256.Sum256(key))
+	proof := &SparseMerkleProof{}
+	node := self.root
+	for depth := 0; node != common.UINT256_EMPTY; depth++ {
+		raw, err := self.getNode(node)
+		if err != nil {
+			return nil, nil, err
+		}
+		left, right := sparseNodeChildren(raw)
+		if raw[0] == sparseLeafNode {
+			proof.LeafKey, proof.LeafValue = left, right
+			if left == keyHash {
+				return append([]byte{}, raw[sparseNodeSize:]...), proof, nil
+			}
+			break
+		}
+		if sparseBit(keyHash, depth) {
+			proof.Siblings = append(proof.Siblings, left)
+			node = right
+		} else {
+			proof.Siblings = append(proof.Siblings, right)
+			node = left
+		}
+	}
+	return nil, proof, nil
+}
+
+func (self *SparseMerkleTree) insert(node common.Uint256, depth int, leaf, keyHash common.Uint256) (common.Uint256, error) {
+	if node == common.UINT256_EMPTY {
+		return leaf, nil
+	}
+	raw, err := self.getNode(node)
+	if err != nil {
+		return common.UINT256_EMPTY, err
+	}
+	left, right := sparseNodeChildren(raw)
+	if raw[0] == sparseLeafNode {
+		if left == keyHash {
+			return leaf, nil
+		}
+		return self.split(node, left, leaf, keyHash, depth), nil
+	}
+	if sparseBit(keyHash, depth) {
+		right, err = self.insert(right, depth+1, leaf, keyHash)
+	} else {
+		left, err = self.insert(left, depth+1, leaf, keyHash)
+	}
+	if err != nil {
+		return common.UINT256_EMPTY, err
+	}
+	return self.putNode(sparseInternalNode, left, right, nil), nil
+}
+
+// split builds the subtree at depth which holds two leaves of different keys
+func (self *SparseMerkleTree) split(leafA, keyA, leafB, keyB common.Uint256, depth int) common.Uint256 {
+	bitA, bitB := sparseBit(keyA, depth), sparseBit(keyB, depth)
+	if bitA == bitB {
+		child := self.split(leafA, keyA, leafB, keyB, depth+1)
+		if bitA {
+			return self.putNode(sparseInternalNode, common.UINT256_EMPTY, child, nil)
+		}
+		return self.putNode(sparseInternalNode, child, common.UINT256_EMPTY, nil)
+	}
+	if bitA {
+		return self.putNode(sparseInternalNode, leafB, leafA, nil)
+	}
+	return self.putNode(sparseInternalNode, leafA, leafB, nil)
+}
+
+// remove deletes keyHash from the subtree at node, a subtree left with a single leaf collapses
+// into the leaf, so the shape of the tree only depends on its keys
+func (self *SparseMerkleTree) remove(node common.Uint256, depth int, keyHash common.Uint256) (common.Uint256, error) {
+	if node == common.UINT256_EMPTY {
+		return node, nil
+	}
+	raw, err := self.getNode(node)
+	if err != nil {
+		return common.UINT256_EMPTY, err
+	}
+	left, right := sparseNodeChildren(raw)
+	if raw[0] == sparseLeafNode {
+		if left == keyHash {
+			return common.UINT256_EMPTY, nil
+		}
+		return node, nil
+	}
+	isRight := sparseBit(keyHash, depth)
+	child, sibling := left, right
+	if isRight {
+		child, sibling = right, left
+	}
+	newChild, err := self.remove(child, depth+1, keyHash)
+	if err != nil {
+		return common.UINT256_EMPTY, err
+	}
+	if newChild == child {
+		return node, nil
+	}
+	if newChild == common.UINT256_EMPTY {
+		isLeaf, err := self.isLeaf(sibling)
+		if err != nil {
+			return common.UINT256_EMPTY, err
+		}
+		if isLeaf {
+			return sibling, nil
+		}
+	} else if sibling == common.UINT256_EMPTY {
+		isLeaf, err := self.isLeaf(newChild)
+		if err != nil {
+			return common.UINT256_EMPTY, err
+		}
+		if isLeaf {
+			return newChild, nil
+		}
+	}
+	if isRight {
+		return self.putNode(sparseInternalNode, sibling, newChild, nil), nil
+	}
+	return self.putNode(sparseInternalNode, newChild, sibling, nil), nil
+}
+
+func (self *SparseMerkleTree) isLeaf(node common.Uint256) (bool, error) {
+	if node == common.UINT256_EMPTY {
+		return false, nil
+	}
+	raw, err := self.getNode(node)
+	if err != nil {
+		return false, err
+	}
+	return raw[0] == sparseLeafNode, nil
+}
+
+func (self *SparseMerkleTree) getNode(hash common.Uint256) ([]byte, error) {
+	raw, ok := self.dirty[hash]
+	if !ok {
+		var err error
+		raw, err = self.store.GetNode(hash)
+		if err != nil {
+			return nil, fmt.Errorf("get sparse merkle node %s error: %s", hash.ToHexString(), err)
+		}
+	}
+	if len(raw) < sparseNodeSize || raw[0] > sparseInternalNode ||
+		(raw[0] == sparseInternalNode && len(raw) != sparseNodeSize) {
+		return nil, fmt.Errorf("invalid sparse merkle node %s", hash.ToHexString())
+	}
+	return raw, nil
+}
+
+func (self *SparseMerkleTree) putNode(kind byte, left, right common.Uint256, value []byte) common.Uint256 {
+	raw := make([]byte, sparseNodeSize+len(value))
+	raw[0] = kind
+	copy(raw[1:], left[:])
+	copy(raw[1+common.UINT256_SIZE:], right[:])
+	copy(raw[sparseNodeSize:], value)
+	hash := common.Uint256(sha256.Sum256(raw[:sparseNodeSize]))
+	self.dirty[hash] = raw
+	return hash
+}
+
+// SparseNodeRefs returns the non-empty children of a persisted internal node, which it keeps
+// alive in the store. A leaf refers to no node.
+func SparseNodeRefs(raw []byte) []common.Uint256 {
+	if len(raw) != sparseNodeSize || raw[0] != sparseInternalNode {
+		return nil
+	}
+	refs := make([]common.Uint256, 0, 2)
+	left, right := sparseNodeChildren(raw)
+	for _, child := range []common.Uint256{left, right} {
+		if child != common.UINT256_EMPTY {
+			refs = append(refs, child)
+		}
+	}
+	return refs
+}
+
+func sparseNodeChildren(raw []byte) (left, right common.Uint256) {
+	copy(left[:], raw[1:])
+	copy(right[:], raw[1+common.UINT256_SIZE:])
+	return
+}
+
+func sparseNodeHash(kind byte, left, right common.Uint256) common.Uint256 {
+	data := make([]byte, 0, sparseNodeSize)
+	data = append(data, kind)
+	data = append(data, left[:]...)
+	data = append(data, right[:]...)
+	return sha256.Sum256(data)
+}
+
+// sparseBit returns whether the path of hash turns right at depth
+func sparseBit(hash common.Uint256, depth int) bool {
+	return hash[depth/8]&(0x80>>uint(depth%8)) != 0
+}
+
+// SparseMerkleProof is the path from the root of a sparse merkle tree to the node where the
+// key is stored or would be stored. The path ends at the leaf of the key, at an empty subtree,
+// or at the leaf of another key sharing the path.
+type SparseMerkleProof struct {
+	Siblings  []common.Uint256 //sibling hashes from the root down
+	LeafKey   common.Uint256   //key hash of the leaf the path ends at, empty if it ends at an empty subtree
+	LeafValue common.Uint256   //value hash of the leaf the path ends at
+}
+
+func (this *SparseMerkleProof) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(uint64(len(this.Siblings)))
+	for _, hash := range this.Siblings {
+		sink.WriteHash(hash)
+	}
+	sink.WriteHash(this.LeafKey)
+	sink.WriteHash(this.LeafValue)
+}
+
+func (this *SparseMerkleProof) Deserialization(source *common.ZeroCopySource) error {
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("SparseMerkleProof deserialize siblings length error")
+	}
+	if n > SPARSE_TREE_DEPTH {
+		return fmt.Errorf("SparseMerkleProof siblings length %d exceeds tree depth", n)
+	}
+	this.Siblings = make([]common.Uint256, 0, n)
+	for i := uint64(0); i < n; i++ {
+		hash, eof := source.NextHash()
+		if eof {
+			return fmt.Errorf("SparseMerkleProof deserialize sibling error")
+		}
+		this.Siblings = append(this.Siblings, hash)
+	}
+	if this.LeafKey, eof = source.NextHash(); eof {
+		return fmt.Errorf("SparseMerkleProof deserialize leaf key error")
+	}
+	if this.LeafValue, eof = source.NextHash(); eof {
+		return fmt.Errorf("SparseMerkleProof deserialize leaf value error")
+	}
+	return nil
+}
+
+// VerifySparseMerkleInclusion checks that key is set to value in the sparse merkle tree of root
+func VerifySparseMerkleInclusion(root common.Uint256, key, value []byte, proof *SparseMerkleProof) error {
+	keyHash := common.Uint256(sha256.Sum256(key))
+	if proof.LeafKey != keyHash {
+		return errors.New("proof does not end at the leaf of key")
+	}
+	if proof.LeafValue != sha256.Sum256(value) {
+		return errors.New("value hash unmatch the leaf")
+	}
+	return verifySparsePath(root, keyHash, sparseNodeHash(sparseLeafNode, proof.LeafKey, proof.LeafValue), proof.Siblings)
+}
+
+// VerifySparseMerkleAbsence checks that key is not set in the sparse merkle tree of root
+func VerifySparseMerkleAbsence(root common.Uint256, key []byte, proof *SparseMerkleProof) error {
+	keyHash := common.Uint256(sha256.Sum256(key))
+	if proof.LeafKey == keyHash {
+		return errors.New("proof ends at the leaf of key")
+	}
+	node := common.UINT256_EMPTY
+	if proof.LeafKey != common.UINT256_EMPTY {
+		for i := range proof.Siblings {
+			if sparseBit(proof.LeafKey, i) != sparseBit(keyHash, i) {
+				return errors.New("proof ends at a leaf off the path of key")
+			}
+		}
+		node = sparseNodeHash(sparseLeafNode, proof.LeafKey, proof.LeafValue)
+	}
+	return verifySparsePath(root, keyHash, node, proof.Siblings)
+}
+
+func verifySparsePath(root, keyHash, node common.Uint256, siblings []common.Uint256) error {
+	if len(siblings) > SPARSE_TREE_DEPTH {
+		return fmt.Errorf("siblings length %d exceeds tree depth", len(siblings))
+	}
+	for i := len(siblings) - 1; i >= 0; i-- {
+		if sparseBit(keyHash, i) {
+			node = sparseNodeHash(sparseInternalNode, siblings[i], node)
+		} else {
+			node = sparseNodeHash(sparseInternalNode, node, siblings[i])
+		}
+	}
+	if node != root {
+		return fmt.Errorf("root unmatch, expect:%s, got:%s", root.ToHexString(), node.ToHexString())
+	}
+	return nil
+}
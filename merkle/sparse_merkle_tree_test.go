@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type memNodeStore map[common.Uint256][]byte
+
+func (self memNodeStore) GetNode(hash common.Uint256) ([]byte, error) {
+	raw, ok := self[hash]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return raw, nil
+}
+
+func (self memNodeStore) commit(tree *SparseMerkleTree) {
+	for hash, raw := range tree.DirtyNodes() {
+		self[hash] = raw
+	}
+}
+
+func TestSparseMerkleTree(t *testing.T) {
+	store := make(memNodeStore)
+	tree := NewSparseMerkleTree(common.UINT256_EMPTY, store)
+	for i := 0; i < 100; i++ {
+		assert.Nil(t, tree.Put([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i))))
+	}
+	store.commit(tree)
+	root := tree.Root()
+
+	// the root only depends on the content of the tree
+	reversed := NewSparseMerkleTree(common.UINT256_EMPTY, make(memNodeStore))
+	for i := 199; i >= 0; i-- {
+		assert.Nil(t, reversed.Put([]byte(fmt.Sprintf("key%d", i)), []byte("stale")))
+	}
+	for i := 199; i >= 100; i-- {
+		assert.Nil(t, reversed.Delete([]byte(fmt.Sprintf("key%d", i))))
+	}
+	for i := 99; i >= 0; i-- {
+		assert.Nil(t, reversed.Put([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i))))
+	}
+	assert.Equal(t, root, reversed.Root())
+
+	for i := 0; i < 100; i++ {
+		key := []byte(fmt.Sprintf("key%d", i))
+		value, proof, err := tree.Prove(key)
+		assert.Nil(t, err)
+		assert.Equal(t, []byte(fmt.Sprintf("value%d", i)), value)
+		assert.Nil(t, VerifySparseMerkleInclusion(root, key, value, proof))
+		assert.NotNil(t, VerifySparseMerkleInclusion(root, key, []byte("fake"), proof))
+		assert.NotNil(t, VerifySparseMerkleAbsence(root, key, proof))
+
+		sink := common.NewZeroCopySink(nil)
+		proof.Serialization(sink)
+		decoded := new(SparseMerkleProof)
+		assert.Nil(t, decoded.Deserialization(common.NewZeroCopySource(sink.Bytes())))
+		assert.Equal(t, proof, decoded)
+	}
+	for i := 100; i < 200; i++ {
+		key := []byte(fmt.Sprintf("key%d", i))
+		value, proof, err := tree.Prove(key)
+		assert.Nil(t, err)
+		assert.Nil(t, value)
+		assert.Nil(t, VerifySparseMerkleAbsence(root, key, proof))
+		assert.NotNil(t, VerifySparseMerkleInclusion(root, key, []byte{}, proof))
+	}
+
+	// nodes of the former root are kept
+	assert.Nil(t, tree.Put([]byte("key0"), []byte("new value")))
+	store.commit(tree)
+	value, proof, err := NewSparseMerkleTree(root, store).Prove([]byte("key0"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("value0"), value)
+	assert.Nil(t, VerifySparseMerkleInclusion(root, []byte("key0"), value, proof))
+
+	for i := 0; i < 100; i++ {
+		assert.Nil(t, tree.Delete([]byte(fmt.Sprintf("key%d", i))))
+	}
+	assert.Equal(t, common.UINT256_EMPTY, tree.Root())
+}
@@ -30,6 +30,7 @@ const (
 // NotifyEventInfo describe smart contract event notify info struct
 type NotifyEventInfo struct {
 	ContractAddress common.Address
+	EventName       string `json:",omitempty"`
 	States          interface{}
 }
 
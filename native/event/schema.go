@@ -0,0 +1,203 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package event
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/polynetwork/poly/common"
+)
+
+// FieldType describe the go type of an event field, which also decides how it is encoded in json
+type FieldType string
+
+const (
+	FIELD_STRING      FieldType = "string"
+	FIELD_HEX         FieldType = "hex" // hex encoded string
+	FIELD_BOOL        FieldType = "bool"
+	FIELD_UINT8       FieldType = "uint8"
+	FIELD_UINT32      FieldType = "uint32"
+	FIELD_UINT64      FieldType = "uint64"
+	FIELD_INT         FieldType = "int"
+	FIELD_INT64       FieldType = "int64"
+	FIELD_BYTES       FieldType = "bytes" // base64 encoded string in json
+	FIELD_STRING_LIST FieldType = "[]string"
+	FIELD_UINT64_LIST FieldType = "[]uint64"
+	FIELD_OBJECT      FieldType = "object" // contract defined struct or map
+	FIELD_ANY         FieldType = "any"
+)
+
+var fieldGoTypes = map[FieldType]reflect.Type{
+	FIELD_STRING:      reflect.TypeOf(""),
+	FIELD_HEX:         reflect.TypeOf(""),
+	FIELD_BOOL:        reflect.TypeOf(false),
+	FIELD_UINT8:       reflect.TypeOf(uint8(0)),
+	FIELD_UINT32:      reflect.TypeOf(uint32(0)),
+	FIELD_UINT64:      reflect.TypeOf(uint64(0)),
+	FIELD_INT:         reflect.TypeOf(int(0)),
+	FIELD_INT64:       reflect.TypeOf(int64(0)),
+	FIELD_BYTES:       reflect.TypeOf([]byte{}),
+	FIELD_STRING_LIST: reflect.TypeOf([]string{}),
+	FIELD_UINT64_LIST: reflect.TypeOf([]uint64{}),
+}
+
+// EventField describe a named field of event
+type EventField struct {
+	Name string
+	Type FieldType
+}
+
+// EventSchema describe the states of an event emitted by native contract. The states of an event are
+// the event name followed by its fields, anonymous event omits the name.
+type EventSchema struct {
+	Contract  common.Address
+	Name      string
+	Anonymous bool
+	Fields    []*EventField
+}
+
+var (
+	schemaLock sync.RWMutex
+	schemas    = make(map[common.Address]map[string]*EventSchema)
+)
+
+// Field is a helper for building event schema
+func Field(name string, fieldType FieldType) *EventField {
+	return &EventField{Name: name, Type: fieldType}
+}
+
+// RegisterEventSchema register the event of native contract, it should be called when the contract
+// package is initialized and panics on duplicate schema
+func RegisterEventSchema(contract common.Address, name string, fields ...*EventField) *EventSchema {
+	return registerEventSchema(&EventSchema{Contract: contract, Name: name, Fields: fields})
+}
+
+// RegisterAnonymousEventSchema register the event whose states do not start with event name
+func RegisterAnonymousEventSchema(contract common.Address, name string, fields ...*EventField) *EventSchema {
+	return registerEventSchema(&EventSchema{Contract: contract, Name: name, Anonymous: true, Fields: fields})
+}
+
+func registerEventSchema(schema *EventSchema) *EventSchema {
+	for _, field := range schema.Fields {
+		if _, ok := fieldGoTypes[field.Type]; !ok && field.Type != FIELD_OBJECT && field.Type != FIELD_ANY {
+			panic(fmt.Sprintf("event %s field %s has unknown type %s", schema.Name, field.Name, field.Type))
+		}
+	}
+	schemaLock.Lock()
+	defer schemaLock.Unlock()
+	contractSchemas, ok := schemas[schema.Contract]
+	if !ok {
+		contractSchemas = make(map[string]*EventSchema)
+		schemas[schema.Contract] = contractSchemas
+	}
+	if _, ok := contractSchemas[schema.Name]; ok {
+		panic(fmt.Sprintf("event %s of contract %s already registered", schema.Name, schema.Contract.ToHexString()))
+	}
+	contractSchemas[schema.Name] = schema
+	return schema
+}
+
+// GetEventSchema return the schema of event, nil if not registered
+func GetEventSchema(contract common.Address, name string) *EventSchema {
+	schemaLock.RLock()
+	defer schemaLock.RUnlock()
+	return schemas[contract][name]
+}
+
+// GetEventSchemas return all registered schemas ordered by contract address and event name
+func GetEventSchemas() []*EventSchema {
+	schemaLock.RLock()
+	defer schemaLock.RUnlock()
+	list := make([]*EventSchema, 0)
+	for _, contractSchemas := range schemas {
+		for _, schema := range contractSchemas {
+			list = append(list, schema)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if c := bytes.Compare(list[i].Contract[:], list[j].Contract[:]); c != 0 {
+			return c < 0
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// NewNotify build the notify of event with values of fields in order
+func (this *EventSchema) NewNotify(values ...interface{}) *NotifyEventInfo {
+	states := make([]interface{}, 0, len(values)+1)
+	if !this.Anonymous {
+		states = append(states, this.Name)
+	}
+	states = append(states, values...)
+	return &NotifyEventInfo{
+		ContractAddress: this.Contract,
+		EventName:       this.Name,
+		States:          states,
+	}
+}
+
+// Validate check the states of notify against the schema
+func (this *EventSchema) Validate(notify *NotifyEventInfo) error {
+	if notify.ContractAddress != this.Contract {
+		return fmt.Errorf("event %s contract %s unmatch %s", this.Name, notify.ContractAddress.ToHexString(),
+			this.Contract.ToHexString())
+	}
+	states, ok := notify.States.([]interface{})
+	if !ok {
+		return fmt.Errorf("event %s states is not a list", this.Name)
+	}
+	if !this.Anonymous {
+		if len(states) == 0 || states[0] != this.Name {
+			return fmt.Errorf("event %s states should start with event name", this.Name)
+		}
+		states = states[1:]
+	}
+	if len(states) != len(this.Fields) {
+		return fmt.Errorf("event %s has %d fields, got %d", this.Name, len(this.Fields), len(states))
+	}
+	for i, field := range this.Fields {
+		switch field.Type {
+		case FIELD_ANY:
+		case FIELD_OBJECT:
+			kind := reflect.ValueOf(states[i]).Kind()
+			if kind != reflect.Map && kind != reflect.Ptr && kind != reflect.Struct {
+				return fmt.Errorf("event %s field %s should be %s, got %T", this.Name, field.Name, field.Type, states[i])
+			}
+		default:
+			if reflect.TypeOf(states[i]) != fieldGoTypes[field.Type] {
+				return fmt.Errorf("event %s field %s should be %s, got %T", this.Name, field.Name, field.Type, states[i])
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateNotify check the notify against the schema registered by its contract
+func ValidateNotify(notify *NotifyEventInfo) error {
+	schema := GetEventSchema(notify.ContractAddress, notify.EventName)
+	if schema == nil {
+		return fmt.Errorf("event %s of contract %s not registered", notify.EventName, notify.ContractAddress.ToHexString())
+	}
+	return schema.Validate(notify)
+}
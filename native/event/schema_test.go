@@ -0,0 +1,59 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package event
+
+import (
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventSchema(t *testing.T) {
+	contract := common.Address{1, 2, 3}
+	schema := RegisterEventSchema(contract, "testEvent",
+		Field("ChainID", FIELD_UINT64),
+		Field("TxHash", FIELD_HEX),
+		Field("Info", FIELD_OBJECT))
+	anonymous := RegisterAnonymousEventSchema(contract, "testAnonymous", Field("Height", FIELD_UINT32))
+
+	assert.Panics(t, func() { RegisterEventSchema(contract, "testEvent") })
+	assert.Panics(t, func() { RegisterEventSchema(contract, "testUnknown", Field("Unknown", "float")) })
+	assert.Equal(t, schema, GetEventSchema(contract, "testEvent"))
+	assert.Nil(t, GetEventSchema(common.Address{}, "testEvent"))
+
+	notify := schema.NewNotify(uint64(2), "0102", map[string]uint64{})
+	assert.Equal(t, []interface{}{"testEvent", uint64(2), "0102", map[string]uint64{}}, notify.States)
+	assert.Nil(t, ValidateNotify(notify))
+	notify = anonymous.NewNotify(uint32(5))
+	assert.Equal(t, []interface{}{uint32(5)}, notify.States)
+	assert.Nil(t, ValidateNotify(notify))
+
+	// drift of field type or count
+	assert.NotNil(t, ValidateNotify(schema.NewNotify(uint32(2), "0102", map[string]uint64{})))
+	assert.NotNil(t, ValidateNotify(schema.NewNotify(uint64(2), "0102")))
+	assert.NotNil(t, ValidateNotify(schema.NewNotify(uint64(2), "0102", "info")))
+	assert.NotNil(t, ValidateNotify(&NotifyEventInfo{ContractAddress: contract, EventName: "testEvent",
+		States: []interface{}{uint64(2), "0102", map[string]uint64{}}}))
+	assert.NotNil(t, ValidateNotify(&NotifyEventInfo{ContractAddress: contract, EventName: "unknown"}))
+
+	schemas := GetEventSchemas()
+	assert.Equal(t, 2, len(schemas))
+	assert.Equal(t, "testAnonymous", schemas[0].Name)
+}
@@ -36,6 +36,10 @@ type (
 
 var (
 	Contracts = make(map[common.Address]RegisterService)
+	// FailedTxHandlers are called by the ledger with the input of an invoke of the contract which fails by
+	// a misbehavior recorded with Misbehave, the writes of the failed invoke are dropped while the ones of
+	// the handler are kept
+	FailedTxHandlers = make(map[common.Address]Handler)
 )
 
 const (
@@ -57,6 +61,7 @@ type NativeService struct {
 	crossHashes   []common.Uint256
 	contexts      []common.Address
 	preExec       bool
+	misbehavior   error
 }
 
 func NewNativeService(cacheDB *storage.CacheDB, tx *types.Transaction,
@@ -226,3 +231,20 @@ func (this *NativeService) GetNotify() []*event.NotifyEventInfo {
 func (this *NativeService) GetCrossHashes() []common.Uint256 {
 	return this.crossHashes
 }
+
+func (this *NativeService) IsPreExec() bool {
+	return this.preExec
+}
+
+// Misbehave records err as a provable misbehavior of the tx sender and returns err, such as a header with
+// invalid seal, an invalid cross chain proof or a header conflicting with a confirmed one. A failure caused
+// by timing or by a duplicate submission is not a misbehavior.
+func (this *NativeService) Misbehave(err error) error {
+	this.misbehavior = err
+	return err
+}
+
+// GetMisbehavior returns the misbehavior recorded in the invoke
+func (this *NativeService) GetMisbehavior() error {
+	return this.misbehavior
+}
@@ -24,7 +24,6 @@ import (
 	"math/big"
 
 	ecommon "github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/light"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -35,6 +34,8 @@ import (
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/header_sync/bsc"
+	"github.com/polynetwork/poly/native/service/header_sync/eth"
+	"github.com/polynetwork/poly/native/service/utils"
 )
 
 // Handler ...
@@ -46,6 +47,10 @@ func NewHandler() *Handler {
 	return &Handler{}
 }
 
+func init() {
+	scom.RegisterChainHandler(utils.BSC_ROUTER, func() scom.ChainHandler { return NewHandler() })
+}
+
 // MakeDepositProposal ...
 func (h *Handler) MakeDepositProposal(service *native.NativeService) (*scom.MakeTxParam, error) {
 	params := new(scom.EntranceParam)
@@ -101,15 +106,15 @@ func verifyFromTx(native *native.NativeService, proof, extra []byte, fromChainID
 
 	proofResult, err := verifyMerkleProof(bscProof, headerWithSum.Header, sideChain.CCMCAddress)
 	if err != nil {
-		return nil, fmt.Errorf("verifyFromTx, verifyMerkleProof error:%v", err)
+		return nil, native.Misbehave(fmt.Errorf("verifyFromTx, verifyMerkleProof error:%v", err))
 	}
 
 	if proofResult == nil {
-		return nil, fmt.Errorf("verifyFromTx, verifyMerkleProof failed")
+		return nil, native.Misbehave(fmt.Errorf("verifyFromTx, verifyMerkleProof failed"))
 	}
 
 	if !checkProofResult(proofResult, extra) {
-		return nil, fmt.Errorf("verifyFromTx, verify proof value hash failed, proof result:%x, extra:%x", proofResult, extra)
+		return nil, native.Misbehave(fmt.Errorf("verifyFromTx, verify proof value hash failed, proof result:%x, extra:%x", proofResult, extra))
 	}
 
 	data := common.NewZeroCopySource(extra)
@@ -146,7 +151,7 @@ type ProofAccount struct {
 	Codehash ecommon.Hash
 }
 
-func verifyMerkleProof(bscProof *Proof, blockData *types.Header, contractAddr []byte) ([]byte, error) {
+func verifyMerkleProof(bscProof *Proof, blockData *eth.Header, contractAddr []byte) ([]byte, error) {
 	//1. prepare verify account
 	nodeList := new(light.NodeList)
 
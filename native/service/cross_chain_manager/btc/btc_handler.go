@@ -29,7 +29,6 @@ import (
 	"github.com/btcsuite/btcutil"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/native"
-	"github.com/polynetwork/poly/native/event"
 	crosscommon "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/utils"
 )
@@ -41,6 +40,11 @@ func NewBTCHandler() *BTCHandler {
 	return &BTCHandler{}
 }
 
+func init() {
+	crosscommon.RegisterChainHandler(utils.BTC_ROUTER, func() crosscommon.ChainHandler { return NewBTCHandler() })
+	crosscommon.RegisterMakeTransaction(utils.BTC_ROUTER, NewBTCHandler().MakeTransaction)
+}
+
 func (this *BTCHandler) MultiSign(service *native.NativeService) error {
 	params := new(crosscommon.MultiSignParam)
 	if err := params.Deserialization(common.NewZeroCopySource(service.GetInput())); err != nil {
@@ -105,11 +109,7 @@ func (this *BTCHandler) MultiSign(service *native.NativeService) error {
 	}
 
 	if len(multiSignInfo.MultiSignInfo) != n {
-		service.AddNotify(
-			&event.NotifyEventInfo{
-				ContractAddress: utils.CrossChainManagerContractAddress,
-				States:          []interface{}{"btcTxMultiSign", params.TxHash, multiSignInfo.MultiSignInfo},
-			})
+		service.AddNotify(btcTxMultiSignEvent.NewNotify(params.TxHash, multiSignInfo.MultiSignInfo))
 	} else {
 		err = addSigToTx(multiSignInfo, addrs, redeemScript, mtx, pkScripts)
 		if err != nil {
@@ -150,12 +150,10 @@ func (this *BTCHandler) MultiSign(service *native.NativeService) error {
 				hex.EncodeToString(params.TxHash), err)
 		}
 		putStxos(service, params.ChainID, params.RedeemKey, stxos)
-		service.AddNotify(
-			&event.NotifyEventInfo{
-				ContractAddress: utils.CrossChainManagerContractAddress,
-				States: []interface{}{"btcTxToRelay", btcFromTxInfo.FromChainID, params.ChainID,
-					hex.EncodeToString(buf.Bytes()), hex.EncodeToString(btcFromTxInfo.FromTxHash), params.RedeemKey},
-			})
+		service.AddNotify(btcTxToRelayEvent.NewNotify(btcFromTxInfo.FromChainID, params.ChainID,
+			hex.EncodeToString(buf.Bytes()), hex.EncodeToString(btcFromTxInfo.FromTxHash), params.RedeemKey))
+		crosscommon.NotifyCrossChainTx(service, btcFromTxInfo.FromChainID, params.ChainID, btcFromTxInfo.FromTxHash,
+			nil, crosscommon.CROSS_CHAIN_TX_DONE)
 	}
 	return nil
 }
@@ -306,11 +304,7 @@ func makeBtcTx(service *native.NativeService, chainID uint64, amounts map[string
 	if err = putBtcFromInfo(service, txHash[:], btcFromInfo); err != nil {
 		return fmt.Errorf("makeBtcTx, putBtcFromInfo failed: %v", err)
 	}
-	service.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.CrossChainManagerContractAddress,
-			States:          []interface{}{"makeBtcTx", hex.EncodeToString(rk), hex.EncodeToString(buf.Bytes()), amts},
-		})
+	service.AddNotify(makeBtcTxEvent.NewNotify(hex.EncodeToString(rk), hex.EncodeToString(buf.Bytes()), amts))
 
 	return nil
 }
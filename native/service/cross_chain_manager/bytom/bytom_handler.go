@@ -35,6 +35,7 @@ import (
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/header_sync/bytom"
+	"github.com/polynetwork/poly/native/service/utils"
 )
 
 // Handler ...
@@ -46,6 +47,10 @@ func NewHandler() *Handler {
 	return &Handler{}
 }
 
+func init() {
+	scom.RegisterChainHandler(utils.BYTOM_ROUTER, func() scom.ChainHandler { return NewHandler() })
+}
+
 // MakeDepositProposal ...
 func (h *Handler) MakeDepositProposal(service *native.NativeService) (*scom.MakeTxParam, error) {
 	params := new(scom.EntranceParam)
@@ -101,15 +106,15 @@ func verifyFromTx(native *native.NativeService, proof, extra []byte, fromChainID
 
 	proofResult, err := verifyMerkleProof(bytomProof, headerWithSum.Header, sideChain.CCMCAddress)
 	if err != nil {
-		return nil, fmt.Errorf("verifyFromTx, verifyMerkleProof error:%v", err)
+		return nil, native.Misbehave(fmt.Errorf("verifyFromTx, verifyMerkleProof error:%v", err))
 	}
 
 	if proofResult == nil {
-		return nil, fmt.Errorf("verifyFromTx, verifyMerkleProof failed")
+		return nil, native.Misbehave(fmt.Errorf("verifyFromTx, verifyMerkleProof failed"))
 	}
 
 	if !checkProofResult(proofResult, extra) {
-		return nil, fmt.Errorf("verifyFromTx, verify proof value hash failed, proof result:%x, extra:%x", proofResult, extra)
+		return nil, native.Misbehave(fmt.Errorf("verifyFromTx, verify proof value hash failed, proof result:%x, extra:%x", proofResult, extra))
 	}
 
 	data := common.NewZeroCopySource(extra)
@@ -0,0 +1,96 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+func contractPolicyKey(chainID uint64, side uint8) []byte {
+	return utils.ConcatKey(utils.CrossChainManagerContractAddress, []byte(CONTRACT_POLICY), utils.GetUint64Bytes(chainID), []byte{side})
+}
+
+func GetContractPolicyList(native *native.NativeService, chainID uint64, side uint8) (*ContractPolicyList, error) {
+	list := new(ContractPolicyList)
+	store, err := native.GetCacheDB().Get(contractPolicyKey(chainID, side))
+	if err != nil {
+		return nil, fmt.Errorf("GetContractPolicyList, get contract policy list error: %v", err)
+	}
+	if store == nil {
+		return list, nil
+	}
+	value, err := states.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("GetContractPolicyList, deserialize from raw storage item err: %v", err)
+	}
+	if err := list.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return nil, fmt.Errorf("GetContractPolicyList, deserialize contract policy list error: %v", err)
+	}
+	return list, nil
+}
+
+func PutContractPolicyList(native *native.NativeService, chainID uint64, side uint8, list *ContractPolicyList) {
+	key := contractPolicyKey(chainID, side)
+	if len(list.Policies) == 0 {
+		native.GetCacheDB().Delete(key)
+		return
+	}
+	sink := common.NewZeroCopySink(nil)
+	list.Serialization(sink)
+	native.GetCacheDB().Put(key, states.GenRawStorageItem(sink.Bytes()))
+}
+
+// CheckContractPolicy rejects the message if the source contract or the target contract and method is
+// denied. Once the source or target list of a chain has allowed entries, only allowed contracts can send
+// from or receive on it respectively.
+func CheckContractPolicy(native *native.NativeService, fromChainID uint64, param *MakeTxParam) error {
+	list, err := GetContractPolicyList(native, fromChainID, CONTRACT_POLICY_SOURCE)
+	if err != nil {
+		return fmt.Errorf("CheckContractPolicy, %v", err)
+	}
+	policy := list.Get(param.FromContractAddress, "")
+	if policy == CONTRACT_POLICY_DENY {
+		return fmt.Errorf("CheckContractPolicy, source contract %x of chain %d is denied",
+			param.FromContractAddress, fromChainID)
+	}
+	if list.HasAllowed() && policy != CONTRACT_POLICY_ALLOW {
+		return fmt.Errorf("CheckContractPolicy, source contract %x of chain %d is not allowed",
+			param.FromContractAddress, fromChainID)
+	}
+
+	list, err = GetContractPolicyList(native, param.ToChainID, CONTRACT_POLICY_TARGET)
+	if err != nil {
+		return fmt.Errorf("CheckContractPolicy, %v", err)
+	}
+	contractPolicy := list.Get(param.ToContractAddress, "")
+	methodPolicy := list.Get(param.ToContractAddress, param.Method)
+	if contractPolicy == CONTRACT_POLICY_DENY || methodPolicy == CONTRACT_POLICY_DENY {
+		return fmt.Errorf("CheckContractPolicy, target contract %x method %s of chain %d is denied",
+			param.ToContractAddress, param.Method, param.ToChainID)
+	}
+	if list.HasAllowed() && contractPolicy != CONTRACT_POLICY_ALLOW && methodPolicy != CONTRACT_POLICY_ALLOW {
+		return fmt.Errorf("CheckContractPolicy, target contract %x method %s of chain %d is not allowed",
+			param.ToContractAddress, param.Method, param.ToChainID)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"testing"
+
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContractPolicyList(t *testing.T) {
+	list := new(ContractPolicyList)
+	list.Set([]byte{1}, "", CONTRACT_POLICY_DENY)
+	list.Set([]byte{2}, "unlock", CONTRACT_POLICY_ALLOW)
+	list.Set([]byte{1}, "", CONTRACT_POLICY_ALLOW)
+	assert.Equal(t, 2, len(list.Policies))
+	assert.Equal(t, CONTRACT_POLICY_ALLOW, list.Get([]byte{1}, ""))
+	assert.Equal(t, CONTRACT_POLICY_NONE, list.Get([]byte{2}, ""))
+	assert.True(t, list.HasAllowed())
+	list.Set([]byte{1}, "", CONTRACT_POLICY_DENY)
+	assert.True(t, list.HasAllowed())
+
+	list.Set([]byte{1}, "", CONTRACT_POLICY_NONE)
+	list.Set([]byte{2}, "unlock", CONTRACT_POLICY_NONE)
+	assert.Equal(t, 0, len(list.Policies))
+	assert.False(t, list.HasAllowed())
+}
+
+func TestCheckContractPolicy(t *testing.T) {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	ns := newNative(storage.NewCacheDB(overlaydb.NewOverlayDB(store)), 100)
+	param := &MakeTxParam{
+		FromContractAddress: []byte{1},
+		ToChainID:           3,
+		ToContractAddress:   []byte{2},
+		Method:              "unlock",
+	}
+	assert.Nil(t, CheckContractPolicy(ns, 2, param))
+
+	// deny source contract
+	list := new(ContractPolicyList)
+	list.Set([]byte{1}, "", CONTRACT_POLICY_DENY)
+	PutContractPolicyList(ns, 2, CONTRACT_POLICY_SOURCE, list)
+	assert.Error(t, CheckContractPolicy(ns, 2, param))
+	PutContractPolicyList(ns, 2, CONTRACT_POLICY_SOURCE, new(ContractPolicyList))
+	assert.Nil(t, CheckContractPolicy(ns, 2, param))
+
+	// allow another method only
+	list = new(ContractPolicyList)
+	list.Set([]byte{2}, "lock", CONTRACT_POLICY_ALLOW)
+	PutContractPolicyList(ns, 3, CONTRACT_POLICY_TARGET, list)
+	assert.Error(t, CheckContractPolicy(ns, 2, param))
+	list.Set([]byte{2}, "unlock", CONTRACT_POLICY_ALLOW)
+	PutContractPolicyList(ns, 3, CONTRACT_POLICY_TARGET, list)
+	assert.Nil(t, CheckContractPolicy(ns, 2, param))
+
+	// method denied although contract is allowed
+	list.Set([]byte{2}, "", CONTRACT_POLICY_ALLOW)
+	list.Set([]byte{2}, "unlock", CONTRACT_POLICY_DENY)
+	PutContractPolicyList(ns, 3, CONTRACT_POLICY_TARGET, list)
+	assert.Error(t, CheckContractPolicy(ns, 2, param))
+
+	stored, err := GetContractPolicyList(ns, 3, CONTRACT_POLICY_TARGET)
+	assert.Nil(t, err)
+	assert.Equal(t, list, stored)
+}
+
+func TestCheckContractPolicySides(t *testing.T) {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	ns := newNative(storage.NewCacheDB(overlaydb.NewOverlayDB(store)), 100)
+	param := &MakeTxParam{
+		FromContractAddress: []byte{1},
+		ToChainID:           3,
+		ToContractAddress:   []byte{2},
+		Method:              "unlock",
+	}
+
+	// target contracts allowed on chain 2 do not block the messages sent from it
+	list := new(ContractPolicyList)
+	list.Set([]byte{5}, "", CONTRACT_POLICY_ALLOW)
+	list.Set([]byte{6}, "unlock", CONTRACT_POLICY_ALLOW)
+	PutContractPolicyList(ns, 2, CONTRACT_POLICY_TARGET, list)
+	assert.Nil(t, CheckContractPolicy(ns, 2, param))
+
+	// source contracts allowed on chain 3 do not block the messages sent to it
+	PutContractPolicyList(ns, 3, CONTRACT_POLICY_SOURCE, list)
+	assert.Nil(t, CheckContractPolicy(ns, 2, param))
+
+	// source contracts allowed on chain 2 do
+	list = new(ContractPolicyList)
+	list.Set([]byte{6}, "", CONTRACT_POLICY_ALLOW)
+	PutContractPolicyList(ns, 2, CONTRACT_POLICY_SOURCE, list)
+	assert.Error(t, CheckContractPolicy(ns, 2, param))
+	list.Set([]byte{1}, "", CONTRACT_POLICY_ALLOW)
+	PutContractPolicyList(ns, 2, CONTRACT_POLICY_SOURCE, list)
+	assert.Nil(t, CheckContractPolicy(ns, 2, param))
+}
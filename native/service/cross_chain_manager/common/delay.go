@@ -0,0 +1,95 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+func PutExecutionDelay(native *native.NativeService, chainID uint64, delay uint32) {
+	contract := utils.CrossChainManagerContractAddress
+	key := utils.ConcatKey(contract, []byte(EXECUTION_DELAY), utils.GetUint64Bytes(chainID))
+	if delay == 0 {
+		native.GetCacheDB().Delete(key)
+		return
+	}
+	native.GetCacheDB().Put(key, states.GenRawStorageItem(utils.GetUint32Bytes(delay)))
+}
+
+// GetExecutionDelay returns the number of blocks messages from chainID wait before release
+func GetExecutionDelay(native *native.NativeService, chainID uint64) (uint32, error) {
+	contract := utils.CrossChainManagerContractAddress
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(EXECUTION_DELAY), utils.GetUint64Bytes(chainID)))
+	if err != nil {
+		return 0, fmt.Errorf("GetExecutionDelay, get execution delay error: %v", err)
+	}
+	if store == nil {
+		return 0, nil
+	}
+	value, err := states.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return 0, fmt.Errorf("GetExecutionDelay, deserialize from raw storage item err: %v", err)
+	}
+	return utils.GetBytesUint32(value), nil
+}
+
+func delayedTxKey(height uint32, txHash []byte) []byte {
+	return utils.ConcatKey(utils.CrossChainManagerContractAddress, []byte(DELAYED_TX), utils.GetUint32Bytes(height), txHash)
+}
+
+func PutDelayedTx(native *native.NativeService, height uint32, merkleValue *ToMerkleValue) {
+	sink := common.NewZeroCopySink(nil)
+	merkleValue.Serialization(sink)
+	native.GetCacheDB().Put(delayedTxKey(height, merkleValue.TxHash), states.GenRawStorageItem(sink.Bytes()))
+}
+
+// GetDelayedTx returns nil if there is no delayed tx of txHash released at height
+func GetDelayedTx(native *native.NativeService, height uint32, txHash []byte) (*ToMerkleValue, error) {
+	store, err := native.GetCacheDB().Get(delayedTxKey(height, txHash))
+	if err != nil {
+		return nil, fmt.Errorf("GetDelayedTx, get delayed tx error: %v", err)
+	}
+	if store == nil {
+		return nil, nil
+	}
+	value, err := states.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("GetDelayedTx, deserialize from raw storage item err: %v", err)
+	}
+	merkleValue := new(ToMerkleValue)
+	if err := merkleValue.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return nil, fmt.Errorf("GetDelayedTx, deserialize ToMerkleValue error: %v", err)
+	}
+	return merkleValue, nil
+}
+
+func DeleteDelayedTx(native *native.NativeService, height uint32, txHash []byte) {
+	native.GetCacheDB().Delete(delayedTxKey(height, txHash))
+}
+
+// NotifyDelayedTx is emitted regardless of the event log config like NotifyCrossChainTx
+func NotifyDelayedTx(native *native.NativeService, merkleValue *ToMerkleValue, height uint32) {
+	native.AddNotify(DelayedTxEvent.NewNotify(merkleValue.FromChainID, merkleValue.MakeTxParam.ToChainID,
+		hex.EncodeToString(merkleValue.MakeTxParam.TxHash), height, hex.EncodeToString(merkleValue.TxHash)))
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"github.com/polynetwork/poly/native/event"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+var (
+	MakeProofEvent = event.RegisterEventSchema(utils.CrossChainManagerContractAddress, NOTIFY_MAKE_PROOF,
+		event.Field("FromChainID", event.FIELD_UINT64),
+		event.Field("ToChainID", event.FIELD_UINT64),
+		event.Field("TxHash", event.FIELD_HEX),
+		event.Field("PolyHeight", event.FIELD_UINT32),
+		event.Field("Key", event.FIELD_HEX))
+	CrossChainTxEvent = event.RegisterEventSchema(utils.CrossChainManagerContractAddress, NOTIFY_CROSS_CHAIN_TX,
+		event.Field("FromChainID", event.FIELD_UINT64),
+		event.Field("ToChainID", event.FIELD_UINT64),
+		event.Field("TxHash", event.FIELD_HEX),
+		event.Field("CrossChainID", event.FIELD_HEX),
+		event.Field("Status", event.FIELD_UINT8))
+	RateLimitedEvent = event.RegisterEventSchema(utils.CrossChainManagerContractAddress, NOTIFY_RATE_LIMITED,
+		event.Field("FromChainID", event.FIELD_UINT64),
+		event.Field("ToChainID", event.FIELD_UINT64),
+		event.Field("TxHash", event.FIELD_HEX),
+		event.Field("PolyHeight", event.FIELD_UINT32),
+		event.Field("Index", event.FIELD_UINT64))
+	DelayedTxEvent = event.RegisterEventSchema(utils.CrossChainManagerContractAddress, NOTIFY_DELAYED_TX,
+		event.Field("FromChainID", event.FIELD_UINT64),
+		event.Field("ToChainID", event.FIELD_UINT64),
+		event.Field("TxHash", event.FIELD_HEX),
+		event.Field("Height", event.FIELD_UINT32),
+		event.Field("PolyTxHash", event.FIELD_HEX))
+	DroppedTxEvent = event.RegisterEventSchema(utils.CrossChainManagerContractAddress, NOTIFY_DROPPED_TX,
+		event.Field("FromChainID", event.FIELD_UINT64),
+		event.Field("ToChainID", event.FIELD_UINT64),
+		event.Field("TxHash", event.FIELD_HEX),
+		event.Field("Reason", event.FIELD_STRING))
+	SetRateLimitEvent = event.RegisterEventSchema(utils.CrossChainManagerContractAddress, SET_RATE_LIMIT,
+		event.Field("FromChainID", event.FIELD_UINT64),
+		event.Field("ToChainID", event.FIELD_UINT64),
+		event.Field("Limit", event.FIELD_UINT64),
+		event.Field("Period", event.FIELD_UINT32))
+	SetContractPolicyEvent = event.RegisterEventSchema(utils.CrossChainManagerContractAddress, SET_CONTRACT_POLICY,
+		event.Field("ChainID", event.FIELD_UINT64),
+		event.Field("Side", event.FIELD_UINT8),
+		event.Field("Contract", event.FIELD_HEX),
+		event.Field("Method", event.FIELD_STRING),
+		event.Field("Policy", event.FIELD_UINT8))
+	SetExecutionDelayEvent = event.RegisterEventSchema(utils.CrossChainManagerContractAddress, SET_EXECUTION_DELAY,
+		event.Field("ChainID", event.FIELD_UINT64),
+		event.Field("Delay", event.FIELD_UINT32))
+	VetoDelayedTxEvent = event.RegisterEventSchema(utils.CrossChainManagerContractAddress, VETO_DELAYED_TX,
+		event.Field("FromChainID", event.FIELD_UINT64),
+		event.Field("ToChainID", event.FIELD_UINT64),
+		event.Field("TxHash", event.FIELD_HEX),
+		event.Field("Height", event.FIELD_UINT32),
+		event.Field("PolyTxHash", event.FIELD_HEX))
+)
@@ -0,0 +1,69 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/native/event"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifySchemas(t *testing.T) {
+	config.DefConfig.Common.EnableEventLog = true
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	ns := newNative(storage.NewCacheDB(overlaydb.NewOverlayDB(store)), 100)
+
+	NotifyMakeProof(ns, 2, 3, "0102", "0304")
+	NotifyCrossChainTx(ns, 2, 3, []byte{1, 2}, []byte{3, 4}, CROSS_CHAIN_TX_DONE)
+	NotifyRateLimited(ns, 2, 3, "0102", 5)
+	NotifyDelayedTx(ns, &ToMerkleValue{
+		TxHash:      []byte{5, 6},
+		FromChainID: 2,
+		MakeTxParam: &MakeTxParam{TxHash: []byte{1, 2}, ToChainID: 3},
+	}, 110)
+	NotifyDroppedTx(ns, 2, &MakeTxParam{TxHash: []byte{1, 2}, ToChainID: 3}, fmt.Errorf("denied"))
+
+	notifies := ns.GetNotify()
+	assert.Equal(t, 6, len(notifies))
+	for _, notify := range notifies {
+		assert.Nil(t, event.ValidateNotify(notify), notify.EventName)
+	}
+
+	tx, err := ParseCrossChainTxNotify(notifies[1].States)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{3, 4}, tx.CrossChainID)
+}
+
+func TestNotifyWithoutEventLog(t *testing.T) {
+	enabled := config.DefConfig.Common.EnableEventLog
+	config.DefConfig.Common.EnableEventLog = false
+	defer func() { config.DefConfig.Common.EnableEventLog = enabled }()
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	ns := newNative(storage.NewCacheDB(overlaydb.NewOverlayDB(store)), 100)
+
+	// the lifecycle notifies do not depend on the config of the node
+	NotifyCrossChainTx(ns, 2, 3, []byte{1, 2}, []byte{3, 4}, CROSS_CHAIN_TX_DONE)
+	NotifyRateLimited(ns, 2, 3, "0102", 5)
+	assert.Equal(t, 2, len(ns.GetNotify()))
+}
@@ -18,6 +18,7 @@ package common
 
 import (
 	"fmt"
+	"math"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/polynetwork/poly/common"
@@ -31,6 +32,14 @@ const (
 	RECONSTRUCT_RIPPLE_TX      = "ReconstructRippleTx"
 	BLACK_CHAIN                = "BlackChain"
 	WHITE_CHAIN                = "WhiteChain"
+	SET_RATE_LIMIT             = "SetRateLimit"
+	RELEASE_RATE_LIMITED_TX    = "ReleaseRateLimitedTx"
+	SET_CONTRACT_POLICY        = "SetContractPolicy"
+	GET_CONTRACT_POLICY        = "GetContractPolicy"
+	GET_CONTRACT_POLICY_LIST   = "GetContractPolicyList"
+	SET_EXECUTION_DELAY        = "SetExecutionDelay"
+	RELEASE_DELAYED_TX         = "ReleaseDelayedTx"
+	VETO_DELAYED_TX            = "VetoDelayedTx"
 
 	BLACKED_CHAIN = "BlackedChain"
 )
@@ -43,8 +52,19 @@ var (
 	DONE_TX             = "doneTx"
 	MULTISIGN_INFO      = "multisignInfo"
 	RIPPLE_TX_INFO      = "rippleTxInfo"
-
-	NOTIFY_MAKE_PROOF = "makeProof"
+	RATE_LIMIT          = "rateLimit"
+	RATE_LIMIT_COUNTER  = "rateLimitCounter"
+	RATE_LIMIT_QUEUE    = "rateLimitQueue"
+	RATE_LIMITED_TX     = "rateLimitedTx"
+	CONTRACT_POLICY     = "contractPolicy"
+	EXECUTION_DELAY     = "executionDelay"
+	DELAYED_TX          = "delayedTx"
+
+	NOTIFY_MAKE_PROOF     = "makeProof"
+	NOTIFY_CROSS_CHAIN_TX = "crossChainTx"
+	NOTIFY_RATE_LIMITED   = "rateLimited"
+	NOTIFY_DELAYED_TX     = "delayedTx"
+	NOTIFY_DROPPED_TX     = "droppedTx"
 )
 
 type ChainHandler interface {
@@ -317,3 +337,273 @@ func (this *BlackChainParam) Deserialization(source *common.ZeroCopySource) erro
 	this.ChainID = chainID
 	return nil
 }
+
+type RateLimitParam struct {
+	FromChainID uint64
+	ToChainID   uint64
+	// max number of messages in every Period poly blocks, 0 means no limit
+	Limit  uint64
+	Period uint32
+}
+
+func (this *RateLimitParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(this.FromChainID)
+	sink.WriteVarUint(this.ToChainID)
+	sink.WriteVarUint(this.Limit)
+	sink.WriteVarUint(uint64(this.Period))
+}
+
+func (this *RateLimitParam) Deserialization(source *common.ZeroCopySource) error {
+	fromChainID, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("RateLimitParam deserialize fromChainID error")
+	}
+	toChainID, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("RateLimitParam deserialize toChainID error")
+	}
+	limit, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("RateLimitParam deserialize limit error")
+	}
+	period, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("RateLimitParam deserialize period error")
+	}
+	if period > math.MaxUint32 {
+		return fmt.Errorf("RateLimitParam deserialize period error: %d is too large", period)
+	}
+
+	this.FromChainID = fromChainID
+	this.ToChainID = toChainID
+	this.Limit = limit
+	this.Period = uint32(period)
+	return nil
+}
+
+type ReleaseRateLimitedTxParam struct {
+	FromChainID uint64
+	ToChainID   uint64
+}
+
+func (this *ReleaseRateLimitedTxParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(this.FromChainID)
+	sink.WriteVarUint(this.ToChainID)
+}
+
+func (this *ReleaseRateLimitedTxParam) Deserialization(source *common.ZeroCopySource) error {
+	fromChainID, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("ReleaseRateLimitedTxParam deserialize fromChainID error")
+	}
+	toChainID, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("ReleaseRateLimitedTxParam deserialize toChainID error")
+	}
+
+	this.FromChainID = fromChainID
+	this.ToChainID = toChainID
+	return nil
+}
+
+type ContractPolicyParam struct {
+	ChainID uint64
+	// CONTRACT_POLICY_SOURCE or CONTRACT_POLICY_TARGET
+	Side     uint8
+	Contract []byte
+	// empty method means the policy applies to the whole contract, only target contracts can have method
+	Method  string
+	Policy  uint8
+	Address common.Address
+}
+
+func (this *ContractPolicyParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(this.ChainID)
+	sink.WriteUint8(this.Side)
+	sink.WriteVarBytes(this.Contract)
+	sink.WriteString(this.Method)
+	sink.WriteUint8(this.Policy)
+	sink.WriteVarBytes(this.Address[:])
+}
+
+func (this *ContractPolicyParam) Deserialization(source *common.ZeroCopySource) error {
+	chainID, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("ContractPolicyParam deserialize chainID error")
+	}
+	side, eof := source.NextUint8()
+	if eof {
+		return fmt.Errorf("ContractPolicyParam deserialize side error")
+	}
+	contract, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("ContractPolicyParam deserialize contract error")
+	}
+	method, eof := source.NextString()
+	if eof {
+		return fmt.Errorf("ContractPolicyParam deserialize method error")
+	}
+	policy, eof := source.NextUint8()
+	if eof {
+		return fmt.Errorf("ContractPolicyParam deserialize policy error")
+	}
+	address, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("ContractPolicyParam deserialize address error")
+	}
+	addr, err := common.AddressParseFromBytes(address)
+	if err != nil {
+		return fmt.Errorf("ContractPolicyParam deserialize address error: %v", err)
+	}
+
+	this.ChainID = chainID
+	this.Side = side
+	this.Contract = contract
+	this.Method = method
+	this.Policy = policy
+	this.Address = addr
+	return nil
+}
+
+// Vote returns the content consensus operators sign for, address is not included
+func (this *ContractPolicyParam) Vote() []byte {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteVarUint(this.ChainID)
+	sink.WriteUint8(this.Side)
+	sink.WriteVarBytes(this.Contract)
+	sink.WriteString(this.Method)
+	sink.WriteUint8(this.Policy)
+	return sink.Bytes()
+}
+
+type GetContractPolicyParam struct {
+	ChainID  uint64
+	Side     uint8
+	Contract []byte
+	Method   string
+}
+
+func (this *GetContractPolicyParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(this.ChainID)
+	sink.WriteUint8(this.Side)
+	sink.WriteVarBytes(this.Contract)
+	sink.WriteString(this.Method)
+}
+
+func (this *GetContractPolicyParam) Deserialization(source *common.ZeroCopySource) error {
+	chainID, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("GetContractPolicyParam deserialize chainID error")
+	}
+	side, eof := source.NextUint8()
+	if eof {
+		return fmt.Errorf("GetContractPolicyParam deserialize side error")
+	}
+	contract, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("GetContractPolicyParam deserialize contract error")
+	}
+	method, eof := source.NextString()
+	if eof {
+		return fmt.Errorf("GetContractPolicyParam deserialize method error")
+	}
+
+	this.ChainID = chainID
+	this.Side = side
+	this.Contract = contract
+	this.Method = method
+	return nil
+}
+
+type GetContractPolicyListParam struct {
+	ChainID uint64
+	Side    uint8
+}
+
+func (this *GetContractPolicyListParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(this.ChainID)
+	sink.WriteUint8(this.Side)
+}
+
+func (this *GetContractPolicyListParam) Deserialization(source *common.ZeroCopySource) error {
+	chainID, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("GetContractPolicyListParam deserialize chainID error")
+	}
+	side, eof := source.NextUint8()
+	if eof {
+		return fmt.Errorf("GetContractPolicyListParam deserialize side error")
+	}
+
+	this.ChainID = chainID
+	this.Side = side
+	return nil
+}
+
+type ExecutionDelayParam struct {
+	ChainID uint64
+	// number of poly blocks messages from the chain wait before release, 0 means no delay
+	Delay uint32
+}
+
+func (this *ExecutionDelayParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(this.ChainID)
+	sink.WriteVarUint(uint64(this.Delay))
+}
+
+func (this *ExecutionDelayParam) Deserialization(source *common.ZeroCopySource) error {
+	chainID, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("ExecutionDelayParam deserialize chainID error")
+	}
+	delay, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("ExecutionDelayParam deserialize delay error")
+	}
+	if delay > math.MaxUint32 {
+		return fmt.Errorf("ExecutionDelayParam deserialize delay error: %d is too large", delay)
+	}
+
+	this.ChainID = chainID
+	this.Delay = uint32(delay)
+	return nil
+}
+
+type DelayedTxParam struct {
+	// release height of the delayed tx
+	Height uint32
+	// hash of the poly tx which verified the message
+	TxHash []byte
+	// consensus peer who vetoes, not used by release
+	Address common.Address
+}
+
+func (this *DelayedTxParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint32(this.Height)
+	sink.WriteVarBytes(this.TxHash)
+	sink.WriteVarBytes(this.Address[:])
+}
+
+func (this *DelayedTxParam) Deserialization(source *common.ZeroCopySource) error {
+	height, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("DelayedTxParam deserialize height error")
+	}
+	txHash, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("DelayedTxParam deserialize txHash error")
+	}
+	address, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("DelayedTxParam deserialize address error")
+	}
+	addr, err := common.AddressParseFromBytes(address)
+	if err != nil {
+		return fmt.Errorf("DelayedTxParam deserialize address error: %v", err)
+	}
+
+	this.Height = height
+	this.TxHash = txHash
+	this.Address = addr
+	return nil
+}
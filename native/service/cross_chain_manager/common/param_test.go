@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/polynetwork/poly/common"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -43,3 +44,35 @@ func TestMakeTxParamWithSender(t *testing.T) {
 
 	assert.Equal(t, value, decoded)
 }
+
+func TestRateLimitParam(t *testing.T) {
+	param := RateLimitParam{FromChainID: 2, ToChainID: 3, Limit: 100, Period: 600}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+
+	var decoded RateLimitParam
+	err := decoded.Deserialization(common.NewZeroCopySource(sink.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, param, decoded)
+}
+
+func TestContractPolicyParam(t *testing.T) {
+	param := ContractPolicyParam{ChainID: 2, Side: CONTRACT_POLICY_TARGET, Contract: []byte{1}, Method: "unlock",
+		Policy: CONTRACT_POLICY_ALLOW, Address: common.Address{1}}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+
+	var decoded ContractPolicyParam
+	err := decoded.Deserialization(common.NewZeroCopySource(sink.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, param, decoded)
+
+	listParam := GetContractPolicyListParam{ChainID: 2, Side: CONTRACT_POLICY_TARGET}
+	sink = common.NewZeroCopySink(nil)
+	listParam.Serialization(sink)
+
+	var decodedList GetContractPolicyListParam
+	err = decodedList.Deserialization(common.NewZeroCopySource(sink.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, listParam, decodedList)
+}
@@ -0,0 +1,209 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+func rateLimitKey(prefix string, fromChainID, toChainID uint64, suffix ...[]byte) []byte {
+	args := append([][]byte{[]byte(prefix), utils.GetUint64Bytes(fromChainID), utils.GetUint64Bytes(toChainID)}, suffix...)
+	return utils.ConcatKey(utils.CrossChainManagerContractAddress, args...)
+}
+
+func PutRateLimit(native *native.NativeService, fromChainID, toChainID uint64, rateLimit *RateLimit) {
+	key := rateLimitKey(RATE_LIMIT, fromChainID, toChainID)
+	if rateLimit.Limit == 0 {
+		native.GetCacheDB().Delete(key)
+		native.GetCacheDB().Delete(rateLimitKey(RATE_LIMIT_COUNTER, fromChainID, toChainID))
+		return
+	}
+	sink := common.NewZeroCopySink(nil)
+	rateLimit.Serialization(sink)
+	native.GetCacheDB().Put(key, states.GenRawStorageItem(sink.Bytes()))
+}
+
+// GetRateLimit returns nil if the messages from fromChainID to toChainID are not limited
+func GetRateLimit(native *native.NativeService, fromChainID, toChainID uint64) (*RateLimit, error) {
+	store, err := native.GetCacheDB().Get(rateLimitKey(RATE_LIMIT, fromChainID, toChainID))
+	if err != nil {
+		return nil, fmt.Errorf("GetRateLimit, get rate limit error: %v", err)
+	}
+	if store == nil {
+		return nil, nil
+	}
+	value, err := states.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("GetRateLimit, deserialize from raw storage item err: %v", err)
+	}
+	rateLimit := new(RateLimit)
+	if err := rateLimit.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return nil, fmt.Errorf("GetRateLimit, deserialize rate limit error: %v", err)
+	}
+	return rateLimit, nil
+}
+
+func getRateLimitCounter(native *native.NativeService, fromChainID, toChainID uint64) (*RateLimitCounter, error) {
+	counter := new(RateLimitCounter)
+	store, err := native.GetCacheDB().Get(rateLimitKey(RATE_LIMIT_COUNTER, fromChainID, toChainID))
+	if err != nil {
+		return nil, fmt.Errorf("getRateLimitCounter, get rate limit counter error: %v", err)
+	}
+	if store == nil {
+		return counter, nil
+	}
+	value, err := states.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("getRateLimitCounter, deserialize from raw storage item err: %v", err)
+	}
+	if err := counter.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return nil, fmt.Errorf("getRateLimitCounter, deserialize rate limit counter error: %v", err)
+	}
+	return counter, nil
+}
+
+// ConsumeRateLimit counts a message from fromChainID to toChainID in the current window, it returns false
+// without counting if the window is already full. Only messages are counted, their value is not capped since
+// the amount is encoded in the args of the target chain proxy contract, which are opaque here
+func ConsumeRateLimit(native *native.NativeService, fromChainID, toChainID uint64) (bool, error) {
+	rateLimit, err := GetRateLimit(native, fromChainID, toChainID)
+	if err != nil {
+		return false, fmt.Errorf("ConsumeRateLimit, %v", err)
+	}
+	if rateLimit == nil {
+		return true, nil
+	}
+	counter, err := getRateLimitCounter(native, fromChainID, toChainID)
+	if err != nil {
+		return false, fmt.Errorf("ConsumeRateLimit, %v", err)
+	}
+	height := native.GetHeight()
+	windowStart := height - height%rateLimit.Period
+	if counter.WindowStart != windowStart {
+		counter.WindowStart, counter.Count = windowStart, 0
+	}
+	if counter.Count >= rateLimit.Limit {
+		return false, nil
+	}
+	counter.Count++
+	sink := common.NewZeroCopySink(nil)
+	counter.Serialization(sink)
+	native.GetCacheDB().Put(rateLimitKey(RATE_LIMIT_COUNTER, fromChainID, toChainID), states.GenRawStorageItem(sink.Bytes()))
+	return true, nil
+}
+
+// ConsumeRateLimitOfNewTx is ConsumeRateLimit for a message not queued yet, it returns false without counting
+// while earlier messages of the pair are still queued, so that queued messages are released first
+func ConsumeRateLimitOfNewTx(native *native.NativeService, fromChainID, toChainID uint64) (bool, error) {
+	queue, err := GetRateLimitQueue(native, fromChainID, toChainID)
+	if err != nil {
+		return false, fmt.Errorf("ConsumeRateLimitOfNewTx, %v", err)
+	}
+	if queue.Head != queue.Tail {
+		return false, nil
+	}
+	return ConsumeRateLimit(native, fromChainID, toChainID)
+}
+
+func GetRateLimitQueue(native *native.NativeService, fromChainID, toChainID uint64) (*RateLimitQueue, error) {
+	queue := new(RateLimitQueue)
+	store, err := native.GetCacheDB().Get(rateLimitKey(RATE_LIMIT_QUEUE, fromChainID, toChainID))
+	if err != nil {
+		return nil, fmt.Errorf("GetRateLimitQueue, get rate limit queue error: %v", err)
+	}
+	if store == nil {
+		return queue, nil
+	}
+	value, err := states.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("GetRateLimitQueue, deserialize from raw storage item err: %v", err)
+	}
+	if err := queue.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return nil, fmt.Errorf("GetRateLimitQueue, deserialize rate limit queue error: %v", err)
+	}
+	return queue, nil
+}
+
+func putRateLimitQueue(native *native.NativeService, fromChainID, toChainID uint64, queue *RateLimitQueue) {
+	key := rateLimitKey(RATE_LIMIT_QUEUE, fromChainID, toChainID)
+	if queue.Head == queue.Tail {
+		native.GetCacheDB().Delete(key)
+		return
+	}
+	sink := common.NewZeroCopySink(nil)
+	queue.Serialization(sink)
+	native.GetCacheDB().Put(key, states.GenRawStorageItem(sink.Bytes()))
+}
+
+// PushRateLimitedTx queues a message over the rate limit, and returns its index in the queue
+func PushRateLimitedTx(native *native.NativeService, fromChainID uint64, param *MakeTxParam) (uint64, error) {
+	queue, err := GetRateLimitQueue(native, fromChainID, param.ToChainID)
+	if err != nil {
+		return 0, fmt.Errorf("PushRateLimitedTx, %v", err)
+	}
+	index := queue.Tail
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	native.GetCacheDB().Put(rateLimitKey(RATE_LIMITED_TX, fromChainID, param.ToChainID, utils.GetUint64Bytes(index)),
+		states.GenRawStorageItem(sink.Bytes()))
+	queue.Tail++
+	putRateLimitQueue(native, fromChainID, param.ToChainID, queue)
+	return index, nil
+}
+
+// PopRateLimitedTx removes the earliest queued message from fromChainID to toChainID, it returns nil if the
+// queue is empty
+func PopRateLimitedTx(native *native.NativeService, fromChainID, toChainID uint64) (*MakeTxParam, error) {
+	queue, err := GetRateLimitQueue(native, fromChainID, toChainID)
+	if err != nil {
+		return nil, fmt.Errorf("PopRateLimitedTx, %v", err)
+	}
+	if queue.Head == queue.Tail {
+		return nil, nil
+	}
+	key := rateLimitKey(RATE_LIMITED_TX, fromChainID, toChainID, utils.GetUint64Bytes(queue.Head))
+	store, err := native.GetCacheDB().Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("PopRateLimitedTx, get rate limited tx error: %v", err)
+	}
+	if store == nil {
+		return nil, fmt.Errorf("PopRateLimitedTx, rate limited tx %d is not found", queue.Head)
+	}
+	value, err := states.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("PopRateLimitedTx, deserialize from raw storage item err: %v", err)
+	}
+	param := new(MakeTxParam)
+	if err := param.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return nil, fmt.Errorf("PopRateLimitedTx, deserialize MakeTxParam error: %v", err)
+	}
+	native.GetCacheDB().Delete(key)
+	queue.Head++
+	putRateLimitQueue(native, fromChainID, toChainID, queue)
+	return param, nil
+}
+
+// NotifyRateLimited is emitted regardless of the event log config like NotifyCrossChainTx
+func NotifyRateLimited(native *native.NativeService, fromChainID, toChainID uint64, txHash string, index uint64) {
+	native.AddNotify(RateLimitedEvent.NewNotify(fromChainID, toChainID, txHash, native.GetHeight(), index))
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func newNative(db *storage.CacheDB, height uint32) *native.NativeService {
+	ns, _ := native.NewNativeService(db, new(types.Transaction), 0, height, common.Uint256{}, 0, nil, false)
+	return ns
+}
+
+func TestConsumeRateLimit(t *testing.T) {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+
+	// not limited
+	for i := 0; i < 5; i++ {
+		ok, err := ConsumeRateLimit(newNative(db, 100), 2, 3)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+	}
+
+	PutRateLimit(newNative(db, 100), 2, 3, &RateLimit{Limit: 2, Period: 10})
+	for _, height := range []uint32{100, 109} {
+		ok, err := ConsumeRateLimit(newNative(db, height), 2, 3)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+	}
+	ok, err := ConsumeRateLimit(newNative(db, 105), 2, 3)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	// other pairs are not affected
+	ok, err = ConsumeRateLimit(newNative(db, 105), 3, 2)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	// next window
+	ok, err = ConsumeRateLimit(newNative(db, 110), 2, 3)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	PutRateLimit(newNative(db, 110), 2, 3, &RateLimit{})
+	rateLimit, err := GetRateLimit(newNative(db, 110), 2, 3)
+	assert.Nil(t, err)
+	assert.Nil(t, rateLimit)
+}
+
+func TestRateLimitedTxQueue(t *testing.T) {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	ns := newNative(storage.NewCacheDB(overlaydb.NewOverlayDB(store)), 100)
+
+	for i := 0; i < 3; i++ {
+		index, err := PushRateLimitedTx(ns, 2, &MakeTxParam{
+			TxHash:       []byte{byte(i)},
+			CrossChainID: []byte{byte(i)},
+			ToChainID:    3,
+			Method:       "unlock",
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(i), index)
+	}
+	for i := 0; i < 3; i++ {
+		param, err := PopRateLimitedTx(ns, 2, 3)
+		assert.Nil(t, err)
+		assert.Equal(t, []byte{byte(i)}, param.TxHash)
+	}
+	param, err := PopRateLimitedTx(ns, 2, 3)
+	assert.Nil(t, err)
+	assert.Nil(t, param)
+
+	queue, err := GetRateLimitQueue(ns, 2, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, &RateLimitQueue{}, queue)
+}
+
+func TestConsumeRateLimitOfNewTx(t *testing.T) {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	PutRateLimit(newNative(db, 100), 2, 3, &RateLimit{Limit: 1, Period: 10})
+
+	ok, err := ConsumeRateLimitOfNewTx(newNative(db, 100), 2, 3)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	ok, err = ConsumeRateLimitOfNewTx(newNative(db, 105), 2, 3)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	_, err = PushRateLimitedTx(newNative(db, 105), 2, &MakeTxParam{TxHash: []byte{1}, ToChainID: 3})
+	assert.Nil(t, err)
+
+	// window has room again, but the queued tx goes first
+	ok, err = ConsumeRateLimitOfNewTx(newNative(db, 110), 2, 3)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	param, err := PopRateLimitedTx(newNative(db, 110), 2, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{1}, param.TxHash)
+	ok, err = ConsumeRateLimit(newNative(db, 110), 2, 3)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = ConsumeRateLimitOfNewTx(newNative(db, 120), 2, 3)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/polynetwork/poly/native"
+)
+
+// MakeTransaction builds the target chain transaction from a verified MakeTxParam
+type MakeTransaction func(service *native.NativeService, param *MakeTxParam, fromChainID uint64) error
+
+var (
+	chainHandlers    = make(map[uint64]func() ChainHandler)
+	makeTransactions = make(map[uint64]MakeTransaction)
+	pendingDeposits  = make(map[uint64]bool)
+)
+
+// RegisterChainHandler is called by a chain package from its init,
+// every router can only be registered once
+func RegisterChainHandler(router uint64, newHandler func() ChainHandler) {
+	if _, ok := chainHandlers[router]; ok {
+		panic(fmt.Sprintf("chain handler of router %d registered twice", router))
+	}
+	chainHandlers[router] = newHandler
+}
+
+// RegisterMakeTransaction replaces the default merkle request for the chains
+// of router when they are the target of a cross chain transaction
+func RegisterMakeTransaction(router uint64, makeTransaction MakeTransaction) {
+	if _, ok := makeTransactions[router]; ok {
+		panic(fmt.Sprintf("make transaction of router %d registered twice", router))
+	}
+	makeTransactions[router] = makeTransaction
+}
+
+// RegisterPendingDeposit lets the handler of router return a nil MakeTxParam
+// without error while the deposit is not ready yet, e.g. waiting for more votes
+func RegisterPendingDeposit(router uint64) {
+	if pendingDeposits[router] {
+		panic(fmt.Sprintf("pending deposit of router %d registered twice", router))
+	}
+	pendingDeposits[router] = true
+}
+
+func GetChainHandler(router uint64) (ChainHandler, error) {
+	newHandler, ok := chainHandlers[router]
+	if !ok {
+		return nil, fmt.Errorf("not a supported router:%d", router)
+	}
+	return newHandler(), nil
+}
+
+// GetMakeTransaction returns nil if router uses the default merkle request
+func GetMakeTransaction(router uint64) MakeTransaction {
+	return makeTransactions[router]
+}
+
+// AllowPendingDeposit returns true if router registered by RegisterPendingDeposit
+func AllowPendingDeposit(router uint64) bool {
+	return pendingDeposits[router]
+}
+
+// GetChainRouters returns all registered routers in ascending order
+func GetChainRouters() []uint64 {
+	routers := make([]uint64, 0, len(chainHandlers))
+	for router := range chainHandlers {
+		routers = append(routers, router)
+	}
+	sort.Slice(routers, func(i, j int) bool { return routers[i] < routers[j] })
+	return routers
+}
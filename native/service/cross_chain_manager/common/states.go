@@ -0,0 +1,339 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+)
+
+const (
+	// proof for the target chain has been made
+	CROSS_CHAIN_TX_PROVEN uint8 = 1
+	// btc or ripple tx is made and waiting for the signatures of relayers
+	CROSS_CHAIN_TX_PENDING_MULTISIGN uint8 = 2
+	// btc or ripple tx is fully signed and ready to relay
+	CROSS_CHAIN_TX_DONE uint8 = 3
+	// queued for exceeding the rate limit of the chain pair
+	CROSS_CHAIN_TX_RATE_LIMITED uint8 = 4
+	// verified but waiting for the execution delay of source chain
+	CROSS_CHAIN_TX_DELAYED uint8 = 5
+	// vetoed by consensus peers during the execution delay
+	CROSS_CHAIN_TX_VETOED uint8 = 6
+	// dropped on release as its contract is denied by the policy changed since it is queued or delayed
+	CROSS_CHAIN_TX_DROPPED uint8 = 7
+)
+
+// CrossChainTx is the lifecycle record of a cross chain tx indexed by the ledger
+type CrossChainTx struct {
+	FromChainID  uint64
+	CrossChainID []byte
+	TxHash       []byte
+	ToChainID    uint64
+	PolyTxHash   common.Uint256
+	Height       uint32
+	Status       uint8
+}
+
+func (this *CrossChainTx) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.FromChainID)
+	sink.WriteVarBytes(this.CrossChainID)
+	sink.WriteVarBytes(this.TxHash)
+	sink.WriteUint64(this.ToChainID)
+	sink.WriteHash(this.PolyTxHash)
+	sink.WriteUint32(this.Height)
+	sink.WriteUint8(this.Status)
+}
+
+func (this *CrossChainTx) Deserialization(source *common.ZeroCopySource) error {
+	fromChainID, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("CrossChainTx deserialize fromChainID error")
+	}
+	crossChainID, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("CrossChainTx deserialize crossChainID error")
+	}
+	txHash, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("CrossChainTx deserialize txHash error")
+	}
+	toChainID, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("CrossChainTx deserialize toChainID error")
+	}
+	polyTxHash, eof := source.NextHash()
+	if eof {
+		return fmt.Errorf("CrossChainTx deserialize polyTxHash error")
+	}
+	height, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("CrossChainTx deserialize height error")
+	}
+	status, eof := source.NextUint8()
+	if eof {
+		return fmt.Errorf("CrossChainTx deserialize status error")
+	}
+
+	this.FromChainID = fromChainID
+	this.CrossChainID = crossChainID
+	this.TxHash = txHash
+	this.ToChainID = toChainID
+	this.PolyTxHash = polyTxHash
+	this.Height = height
+	this.Status = status
+	return nil
+}
+
+// ParseCrossChainTxNotify decodes the states of a NOTIFY_CROSS_CHAIN_TX notify, CrossChainID is
+// empty when the notify only knows the source tx hash
+func ParseCrossChainTxNotify(states interface{}) (*CrossChainTx, error) {
+	list, ok := states.([]interface{})
+	if !ok || len(list) != 6 {
+		return nil, fmt.Errorf("ParseCrossChainTxNotify, wrong states: %v", states)
+	}
+	if name, ok := list[0].(string); !ok || name != NOTIFY_CROSS_CHAIN_TX {
+		return nil, fmt.Errorf("ParseCrossChainTxNotify, not a %s notify", NOTIFY_CROSS_CHAIN_TX)
+	}
+	fromChainID, ok := list[1].(uint64)
+	if !ok {
+		return nil, fmt.Errorf("ParseCrossChainTxNotify, wrong fromChainID: %v", list[1])
+	}
+	toChainID, ok := list[2].(uint64)
+	if !ok {
+		return nil, fmt.Errorf("ParseCrossChainTxNotify, wrong toChainID: %v", list[2])
+	}
+	txHash, err := decodeHexState(list[3])
+	if err != nil {
+		return nil, fmt.Errorf("ParseCrossChainTxNotify, wrong txHash: %v", err)
+	}
+	crossChainID, err := decodeHexState(list[4])
+	if err != nil {
+		return nil, fmt.Errorf("ParseCrossChainTxNotify, wrong crossChainID: %v", err)
+	}
+	status, ok := list[5].(uint8)
+	if !ok {
+		return nil, fmt.Errorf("ParseCrossChainTxNotify, wrong status: %v", list[5])
+	}
+	return &CrossChainTx{
+		FromChainID:  fromChainID,
+		CrossChainID: crossChainID,
+		TxHash:       txHash,
+		ToChainID:    toChainID,
+		Status:       status,
+	}, nil
+}
+
+func decodeHexState(state interface{}) ([]byte, error) {
+	s, ok := state.(string)
+	if !ok {
+		return nil, fmt.Errorf("%v is not a string", state)
+	}
+	return hex.DecodeString(s)
+}
+
+type RateLimit struct {
+	Limit  uint64
+	Period uint32
+}
+
+func (this *RateLimit) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.Limit)
+	sink.WriteUint32(this.Period)
+}
+
+func (this *RateLimit) Deserialization(source *common.ZeroCopySource) error {
+	limit, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("RateLimit deserialize limit error")
+	}
+	period, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("RateLimit deserialize period error")
+	}
+
+	this.Limit = limit
+	this.Period = period
+	return nil
+}
+
+// RateLimitCounter counts the messages of a chain pair in the window starting at WindowStart
+type RateLimitCounter struct {
+	WindowStart uint32
+	Count       uint64
+}
+
+func (this *RateLimitCounter) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint32(this.WindowStart)
+	sink.WriteUint64(this.Count)
+}
+
+func (this *RateLimitCounter) Deserialization(source *common.ZeroCopySource) error {
+	windowStart, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("RateLimitCounter deserialize windowStart error")
+	}
+	count, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("RateLimitCounter deserialize count error")
+	}
+
+	this.WindowStart = windowStart
+	this.Count = count
+	return nil
+}
+
+// RateLimitQueue holds the indexes of queued messages of a chain pair in [Head, Tail)
+type RateLimitQueue struct {
+	Head uint64
+	Tail uint64
+}
+
+func (this *RateLimitQueue) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.Head)
+	sink.WriteUint64(this.Tail)
+}
+
+func (this *RateLimitQueue) Deserialization(source *common.ZeroCopySource) error {
+	head, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("RateLimitQueue deserialize head error")
+	}
+	tail, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("RateLimitQueue deserialize tail error")
+	}
+
+	this.Head = head
+	this.Tail = tail
+	return nil
+}
+
+const (
+	CONTRACT_POLICY_NONE  uint8 = 0
+	CONTRACT_POLICY_ALLOW uint8 = 1
+	CONTRACT_POLICY_DENY  uint8 = 2
+)
+
+// a chain keeps separate lists for the contracts sending from it and the contracts receiving on it
+const (
+	CONTRACT_POLICY_SOURCE uint8 = 0
+	CONTRACT_POLICY_TARGET uint8 = 1
+)
+
+type ContractPolicy struct {
+	Contract []byte
+	Method   string
+	Policy   uint8
+}
+
+func (this *ContractPolicy) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarBytes(this.Contract)
+	sink.WriteString(this.Method)
+	sink.WriteUint8(this.Policy)
+}
+
+func (this *ContractPolicy) Deserialization(source *common.ZeroCopySource) error {
+	contract, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("ContractPolicy deserialize contract error")
+	}
+	method, eof := source.NextString()
+	if eof {
+		return fmt.Errorf("ContractPolicy deserialize method error")
+	}
+	policy, eof := source.NextUint8()
+	if eof {
+		return fmt.Errorf("ContractPolicy deserialize policy error")
+	}
+
+	this.Contract = contract
+	this.Method = method
+	this.Policy = policy
+	return nil
+}
+
+// ContractPolicyList is the allow and deny list of source or target contracts on a chain
+type ContractPolicyList struct {
+	Policies []*ContractPolicy
+}
+
+func (this *ContractPolicyList) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(uint64(len(this.Policies)))
+	for _, policy := range this.Policies {
+		policy.Serialization(sink)
+	}
+}
+
+func (this *ContractPolicyList) Deserialization(source *common.ZeroCopySource) error {
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("ContractPolicyList deserialize length error")
+	}
+	policies := make([]*ContractPolicy, 0, n)
+	for i := uint64(0); i < n; i++ {
+		policy := new(ContractPolicy)
+		if err := policy.Deserialization(source); err != nil {
+			return fmt.Errorf("ContractPolicyList deserialize policy error: %v", err)
+		}
+		policies = append(policies, policy)
+	}
+
+	this.Policies = policies
+	return nil
+}
+
+// Get returns the policy of contract method, CONTRACT_POLICY_NONE if it is not in the list
+func (this *ContractPolicyList) Get(contract []byte, method string) uint8 {
+	for _, policy := range this.Policies {
+		if bytes.Equal(policy.Contract, contract) && policy.Method == method {
+			return policy.Policy
+		}
+	}
+	return CONTRACT_POLICY_NONE
+}
+
+// Set puts the policy of contract method in the list, CONTRACT_POLICY_NONE removes it
+func (this *ContractPolicyList) Set(contract []byte, method string, policy uint8) {
+	for i, p := range this.Policies {
+		if bytes.Equal(p.Contract, contract) && p.Method == method {
+			if policy == CONTRACT_POLICY_NONE {
+				this.Policies = append(this.Policies[:i], this.Policies[i+1:]...)
+			} else {
+				p.Policy = policy
+			}
+			return
+		}
+	}
+	if policy != CONTRACT_POLICY_NONE {
+		this.Policies = append(this.Policies, &ContractPolicy{Contract: contract, Method: method, Policy: policy})
+	}
+}
+
+// HasAllowed reports whether the list is in allow list mode
+func (this *ContractPolicyList) HasAllowed() bool {
+	for _, policy := range this.Policies {
+		if policy.Policy == CONTRACT_POLICY_ALLOW {
+			return true
+		}
+	}
+	return false
+}
@@ -17,13 +17,13 @@
 package common
 
 import (
+	"encoding/hex"
 	"fmt"
 	"strings"
 
 	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/native"
-	"github.com/polynetwork/poly/native/event"
 	"github.com/polynetwork/poly/native/service/utils"
 )
 
@@ -35,11 +35,22 @@ func NotifyMakeProof(native *native.NativeService, fromChainID, toChainID uint64
 	if !config.DefConfig.Common.EnableEventLog {
 		return
 	}
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.CrossChainManagerContractAddress,
-			States:          []interface{}{NOTIFY_MAKE_PROOF, fromChainID, toChainID, txHash, native.GetHeight(), key},
-		})
+	native.AddNotify(MakeProofEvent.NewNotify(fromChainID, toChainID, txHash, native.GetHeight(), key))
+}
+
+// NotifyCrossChainTx emits the lifecycle status of a cross chain tx for the ledger index, crossChainID can
+// be nil if only the source tx hash is known. It is emitted regardless of the event log config so that a tx
+// has the same notifies on every node, the ledger skips indexing it if the event log is disabled
+func NotifyCrossChainTx(native *native.NativeService, fromChainID, toChainID uint64, txHash, crossChainID []byte, status uint8) {
+	native.AddNotify(CrossChainTxEvent.NewNotify(fromChainID, toChainID, hex.EncodeToString(txHash),
+		hex.EncodeToString(crossChainID), status))
+}
+
+// NotifyDroppedTx emits the reason a queued or delayed tx is dropped on release and its lifecycle status
+func NotifyDroppedTx(native *native.NativeService, fromChainID uint64, txParam *MakeTxParam, reason error) {
+	native.AddNotify(DroppedTxEvent.NewNotify(fromChainID, txParam.ToChainID, hex.EncodeToString(txParam.TxHash),
+		reason.Error()))
+	NotifyCrossChainTx(native, fromChainID, txParam.ToChainID, txParam.TxHash, txParam.CrossChainID, CROSS_CHAIN_TX_DROPPED)
 }
 
 func PutDoneTx(native *native.NativeService, crossChainID []byte, chainID uint64) error {
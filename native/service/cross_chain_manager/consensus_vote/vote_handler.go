@@ -22,9 +22,9 @@ import (
 	"fmt"
 
 	"github.com/polynetwork/poly/common"
-	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/native"
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/fork_manager"
 	"github.com/polynetwork/poly/native/service/utils"
 )
 
@@ -35,6 +35,11 @@ func NewVoteHandler() *VoteHandler {
 	return &VoteHandler{}
 }
 
+func init() {
+	scom.RegisterChainHandler(utils.VOTE_ROUTER, func() scom.ChainHandler { return NewVoteHandler() })
+	scom.RegisterPendingDeposit(utils.VOTE_ROUTER)
+}
+
 func (this *VoteHandler) MakeDepositProposal(service *native.NativeService) (*scom.MakeTxParam, error) {
 	params := new(scom.EntranceParam)
 	if err := params.Deserialization(common.NewZeroCopySource(service.GetInput())); err != nil {
@@ -72,7 +77,11 @@ func (this *VoteHandler) MakeDepositProposal(service *native.NativeService) (*sc
 		if err := txParam.Deserialization(data); err != nil {
 			return nil, fmt.Errorf("vote MakeDepositProposal, deserialize MakeTxParam error:%s", err)
 		}
-		if config.NETWORK_ID_TEST_NET != config.DefConfig.P2PNode.NetworkId || service.GetHeight() >= 19954185 {
+		height, err := fork_manager.GetForkHeight(service, fork_manager.VOTE_DONE_TX)
+		if err != nil {
+			return nil, fmt.Errorf("vote MakeDepositProposal, GetForkHeight error:%s", err)
+		}
+		if uint64(service.GetHeight()) >= height {
 			if err := scom.CheckDoneTx(service, txParam.CrossChainID, params.SourceChainID); err != nil {
 				return nil, fmt.Errorf("vote MakeDepositProposal, check done transaction error:%s", err)
 			}
@@ -24,6 +24,7 @@ import (
 	"github.com/polynetwork/poly/native"
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/header_sync/cosmos"
+	"github.com/polynetwork/poly/native/service/utils"
 	"github.com/tendermint/tendermint/crypto/merkle"
 )
 
@@ -33,6 +34,10 @@ func NewCosmosHandler() *CosmosHandler {
 	return &CosmosHandler{}
 }
 
+func init() {
+	scom.RegisterChainHandler(utils.COSMOS_ROUTER, func() scom.ChainHandler { return NewCosmosHandler() })
+}
+
 type CosmosProofValue struct {
 	Kp    string
 	Value []byte
@@ -89,12 +94,12 @@ func (this *CosmosHandler) MakeDepositProposal(service *native.NativeService) (*
 	if len(proofValue.Kp) != 0 {
 		err = prt.VerifyValue(&proof, myHeader.Header.AppHash, proofValue.Kp, proofValue.Value)
 		if err != nil {
-			return nil, fmt.Errorf("Cosmos MakeDepositProposal, proof error: %s", err)
+			return nil, service.Misbehave(fmt.Errorf("Cosmos MakeDepositProposal, proof error: %s", err))
 		}
 	} else {
 		err = prt.VerifyAbsence(&proof, myHeader.Header.AppHash, string(proofValue.Value))
 		if err != nil {
-			return nil, fmt.Errorf("Cosmos MakeDepositProposal, proof error: %s", err)
+			return nil, service.Misbehave(fmt.Errorf("Cosmos MakeDepositProposal, proof error: %s", err))
 		}
 	}
 	data := common.NewZeroCopySource(proofValue.Value)
@@ -17,36 +17,42 @@
 package cross_chain_manager
 
 import (
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/native"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/bsc"
 	"github.com/polynetwork/poly/native/service/cross_chain_manager/btc"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/bytom"
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/cross_chain_manager/consensus_vote"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/cosmos"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/eth"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/harmony"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/heco"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/hsc"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/msc"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/neo"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/neo3"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/okex"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/ont"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/pixiechain"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/polygon"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/quorum"
 	"github.com/polynetwork/poly/native/service/cross_chain_manager/ripple"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/starcoin"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/zilliqa"
-	"github.com/polynetwork/poly/native/service/cross_chain_manager/zilliqalegacy"
+	"github.com/polynetwork/poly/native/service/governance/fork_manager"
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/relayer_manager"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
 	"github.com/polynetwork/poly/native/service/utils"
+
+	// chain packages register their handlers on init
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/bsc"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/bytom"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/cosmos"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/eth"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/harmony"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/heco"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/hsc"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/msc"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/neo"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/neo3"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/okex"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/ont"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/pixiechain"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/polygon"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/quorum"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/starcoin"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/zilliqa"
+	_ "github.com/polynetwork/poly/native/service/cross_chain_manager/zilliqalegacy"
 )
 
 func RegisterCrossChainManagerContract(native *native.NativeService) {
@@ -57,62 +63,28 @@ func RegisterCrossChainManagerContract(native *native.NativeService) {
 
 	native.Register(scom.BLACK_CHAIN, BlackChain)
 	native.Register(scom.WHITE_CHAIN, WhiteChain)
+	native.Register(scom.SET_RATE_LIMIT, SetRateLimit)
+	native.Register(scom.RELEASE_RATE_LIMITED_TX, ReleaseRateLimitedTx)
+	native.Register(scom.SET_CONTRACT_POLICY, SetContractPolicy)
+	native.Register(scom.GET_CONTRACT_POLICY, GetContractPolicy)
+	native.Register(scom.GET_CONTRACT_POLICY_LIST, GetContractPolicyList)
+	native.Register(scom.SET_EXECUTION_DELAY, SetExecutionDelay)
+	native.Register(scom.RELEASE_DELAYED_TX, ReleaseDelayedTx)
+	native.Register(scom.VETO_DELAYED_TX, VetoDelayedTx)
 }
 
 func GetChainHandler(router uint64) (scom.ChainHandler, error) {
-	switch router {
-	case utils.VOTE_ROUTER:
-		return consensus_vote.NewVoteHandler(), nil
-	case utils.BTC_ROUTER:
-		return btc.NewBTCHandler(), nil
-	case utils.ETH_ROUTER:
-		return eth.NewETHHandler(), nil
-	case utils.ONT_ROUTER:
-		return ont.NewONTHandler(), nil
-	case utils.NEO_ROUTER:
-		return neo.NewNEOHandler(), nil
-	case utils.NEO3_ROUTER:
-		return neo3.NewNeo3Handler(), nil
-	case utils.COSMOS_ROUTER:
-		return cosmos.NewCosmosHandler(), nil
-	case utils.QUORUM_ROUTER:
-		return quorum.NewQuorumHandler(), nil
-	case utils.BSC_ROUTER:
-		return bsc.NewHandler(), nil
-	case utils.HECO_ROUTER:
-		return heco.NewHecoHandler(), nil
-	case utils.ZILLIQA_LEGACY_ROUTER:
-		return zilliqalegacy.NewHandler(), nil
-	case utils.ZILLIQA_ROUTER:
-		return zilliqa.NewHandler(), nil
-	case utils.MSC_ROUTER:
-		return msc.NewHandler(), nil
-	case utils.OKEX_ROUTER:
-		return okex.NewHandler(), nil
-	case utils.POLYGON_BOR_ROUTER:
-		return polygon.NewHandler(), nil
-	case utils.PIXIECHAIN_ROUTER:
-		return pixiechain.NewPixieHandler(), nil
-	case utils.STARCOIN_ROUTER:
-		return starcoin.NewHandler(), nil
-	case utils.HSC_ROUTER:
-		return hsc.NewHscHandler(), nil
-	case utils.HARMONY_ROUTER:
-		return harmony.NewHandler(), nil
-	case utils.BYTOM_ROUTER:
-		return bytom.NewHandler(), nil
-	case utils.RIPPLE_ROUTER:
-		return ripple.NewRippleHandler(), nil
-	default:
-		return nil, fmt.Errorf("not a supported router:%d", router)
-	}
+	return scom.GetChainHandler(router)
 }
 
-func ImportExTransfer(native *native.NativeService) ([]byte, error) {
+func ImportExTransfer(native *native.NativeService) (ret []byte, err error) {
 	params := new(scom.EntranceParam)
 	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, contract params deserialize error: %v", err)
 	}
+	if err := relayer_manager.CheckRelayerActive(native); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, %v", err)
+	}
 
 	chainID := params.SourceChainID
 	blacked, err := scom.CheckIfChainBlacked(native, chainID)
@@ -132,11 +104,16 @@ func ImportExTransfer(native *native.NativeService) ([]byte, error) {
 		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, side chain %d is not registered", chainID)
 	}
 
-	handler, err := GetChainHandler(sideChain.Router)
+	router := sideChain.Router
+	defer func() {
+		recordImportExTransfer(native, router, err)
+	}()
+
+	handler, err := GetChainHandler(router)
 	if err != nil {
 		return utils.BYTE_FALSE, err
 	}
-	err = utils.CheckRouterStartBlock(sideChain.Router, native.GetHeight())
+	err = fork_manager.CheckRouterStartBlock(native, router)
 	if err != nil {
 		return utils.BYTE_FALSE, err
 	}
@@ -146,8 +123,12 @@ func ImportExTransfer(native *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, err
 	}
-	if txParam == nil && (sideChain.Router == utils.VOTE_ROUTER || sideChain.Router == utils.RIPPLE_ROUTER) {
-		return utils.BYTE_TRUE, nil
+	if txParam == nil {
+		// handlers such as vote and ripple return nil until the transfer is ready
+		if scom.AllowPendingDeposit(router) {
+			return utils.BYTE_TRUE, nil
+		}
+		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, handler of router %d returns no tx param", router)
 	}
 
 	//2. make target chain tx
@@ -168,27 +149,88 @@ func ImportExTransfer(native *native.NativeService) ([]byte, error) {
 	if sideChain == nil {
 		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, side chain %d is not registered", targetid)
 	}
-	if sideChain.Router == utils.BTC_ROUTER {
-		err := btc.NewBTCHandler().MakeTransaction(native, txParam, chainID)
+
+	err = scom.CheckContractPolicy(native, chainID, txParam)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, %v", err)
+	}
+
+	//queue the tx if the chain pair is over rate limit or earlier txs are still queued
+	queued := false
+	ok, err := scom.ConsumeRateLimitOfNewTx(native, chainID, targetid)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, %v", err)
+	}
+	if !ok {
+		index, err := scom.PushRateLimitedTx(native, chainID, txParam)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, %v", err)
+		}
+		scom.NotifyRateLimited(native, chainID, targetid, hex.EncodeToString(txParam.TxHash), index)
+		scom.NotifyCrossChainTx(native, chainID, targetid, txParam.TxHash, txParam.CrossChainID, scom.CROSS_CHAIN_TX_RATE_LIMITED)
+		queued = true
+	} else {
+		queued, err = makeTargetTransaction(native, txParam, chainID, sideChain.Router)
 		if err != nil {
 			return utils.BYTE_FALSE, err
 		}
-		return utils.BYTE_TRUE, nil
+	}
+	//keep the proven header from pruning until the queued tx is made
+	if queued {
+		err = hscommon.PinHeader(native, chainID, txParam.TxHash, uint64(params.Height))
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, %v", err)
+		}
 	}
 
-	if sideChain.Router == utils.RIPPLE_ROUTER {
-		err := ripple.NewRippleHandler().MakeTransaction(native, txParam, chainID)
+	err = relayer_manager.RecordCrossChainProof(native, chainID, targetid)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, %v", err)
+	}
+	return utils.BYTE_TRUE, nil
+}
+
+//makeTargetTransaction makes the target chain tx of txParam, delayed is true if the tx waits for the execution delay
+func makeTargetTransaction(native *native.NativeService, txParam *scom.MakeTxParam, fromChainID, router uint64) (delayed bool, err error) {
+	txHash := native.GetTx().Hash()
+	merkleValue := &scom.ToMerkleValue{
+		TxHash:      txHash.ToArray(),
+		FromChainID: fromChainID,
+		MakeTxParam: txParam,
+	}
+	delay, err := scom.GetExecutionDelay(native, fromChainID)
+	if err != nil {
+		return false, err
+	}
+	if delay > 0 {
+		height := native.GetHeight() + delay
+		scom.PutDelayedTx(native, height, merkleValue)
+		scom.NotifyDelayedTx(native, merkleValue, height)
+		scom.NotifyCrossChainTx(native, fromChainID, txParam.ToChainID, txParam.TxHash, txParam.CrossChainID, scom.CROSS_CHAIN_TX_DELAYED)
+		return true, nil
+	}
+	return false, dispatchTargetTransaction(native, merkleValue, router)
+}
+
+//dispatchTargetTransaction hands the tx to the maker registered for target router, or stores it as merkle request by default
+func dispatchTargetTransaction(native *native.NativeService, merkleValue *scom.ToMerkleValue, router uint64) error {
+	txParam := merkleValue.MakeTxParam
+	if makeTransaction := scom.GetMakeTransaction(router); makeTransaction != nil {
+		err := makeTransaction(native, txParam, merkleValue.FromChainID)
 		if err != nil {
-			return utils.BYTE_FALSE, err
+			return err
 		}
-		return utils.BYTE_TRUE, nil
+		scom.NotifyCrossChainTx(native, merkleValue.FromChainID, txParam.ToChainID, txParam.TxHash, txParam.CrossChainID, scom.CROSS_CHAIN_TX_PENDING_MULTISIGN)
+		return nil
 	}
+
 	//NOTE, you need to store the tx in this
-	err = MakeTransaction(native, txParam, chainID)
+	err := putMerkleValue(native, merkleValue)
 	if err != nil {
-		return utils.BYTE_FALSE, err
+		return err
 	}
-	return utils.BYTE_TRUE, nil
+	scom.NotifyCrossChainTx(native, merkleValue.FromChainID, txParam.ToChainID, txParam.TxHash, txParam.CrossChainID, scom.CROSS_CHAIN_TX_PROVEN)
+	return nil
 }
 
 func MultiSign(native *native.NativeService) ([]byte, error) {
@@ -229,7 +271,11 @@ func MakeTransaction(service *native.NativeService, params *scom.MakeTxParam, fr
 		FromChainID: fromChainID,
 		MakeTxParam: params,
 	}
+	return putMerkleValue(service, merkleValue)
+}
 
+func putMerkleValue(service *native.NativeService, merkleValue *scom.ToMerkleValue) error {
+	params := merkleValue.MakeTxParam
 	sink := common.NewZeroCopySink(nil)
 	merkleValue.Serialization(sink)
 	err := PutRequest(service, merkleValue.TxHash, params.ToChainID, sink.Bytes())
@@ -239,7 +285,7 @@ func MakeTransaction(service *native.NativeService, params *scom.MakeTxParam, fr
 	service.PutMerkleVal(sink.Bytes())
 	chainIDBytes := utils.GetUint64Bytes(params.ToChainID)
 	key := hex.EncodeToString(utils.ConcatKey(utils.CrossChainManagerContractAddress, []byte(scom.REQUEST), chainIDBytes, merkleValue.TxHash))
-	scom.NotifyMakeProof(service, fromChainID, params.ToChainID, hex.EncodeToString(params.TxHash), key)
+	scom.NotifyMakeProof(service, merkleValue.FromChainID, params.ToChainID, hex.EncodeToString(params.TxHash), key)
 	return nil
 }
 
@@ -291,3 +337,288 @@ func WhiteChain(native *native.NativeService) ([]byte, error) {
 	scom.RemoveBlackChain(native, params.ChainID)
 	return utils.BYTE_TRUE, nil
 }
+
+func SetRateLimit(native *native.NativeService) ([]byte, error) {
+	params := new(scom.RateLimitParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetRateLimit, contract params deserialize error: %v", err)
+	}
+	if params.Limit != 0 && params.Period == 0 {
+		return utils.BYTE_FALSE, fmt.Errorf("SetRateLimit, period of rate limit should be positive")
+	}
+
+	// Get current epoch operator
+	operatorAddress, err := node_manager.GetCurConOperator(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetRateLimit, get current consensus operator address error: %v", err)
+	}
+	//check witness
+	err = utils.ValidateOwner(native, operatorAddress)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetRateLimit, checkWitness error: %v", err)
+	}
+
+	scom.PutRateLimit(native, params.FromChainID, params.ToChainID, &scom.RateLimit{Limit: params.Limit, Period: params.Period})
+	native.AddNotify(scom.SetRateLimitEvent.NewNotify(params.FromChainID, params.ToChainID, params.Limit, params.Period))
+	return utils.BYTE_TRUE, nil
+}
+
+// ReleaseRateLimitedTx makes the target chain tx of the earliest queued message of a chain pair once the
+// rate limit allows, anyone can call it. A message whose contract is denied since it is queued is dropped
+func ReleaseRateLimitedTx(native *native.NativeService) ([]byte, error) {
+	params := new(scom.ReleaseRateLimitedTxParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseRateLimitedTx, contract params deserialize error: %v", err)
+	}
+
+	for _, chainID := range []uint64{params.FromChainID, params.ToChainID} {
+		blacked, err := scom.CheckIfChainBlacked(native, chainID)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("ReleaseRateLimitedTx, CheckIfChainBlacked error: %v", err)
+		}
+		if blacked {
+			return utils.BYTE_FALSE, fmt.Errorf("ReleaseRateLimitedTx, chain %d is blacked", chainID)
+		}
+	}
+	sideChain, err := side_chain_manager.GetSideChain(native, params.ToChainID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseRateLimitedTx, side_chain_manager.GetSideChain error: %v", err)
+	}
+	if sideChain == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseRateLimitedTx, side chain %d is not registered", params.ToChainID)
+	}
+
+	txParam, err := scom.PopRateLimitedTx(native, params.FromChainID, params.ToChainID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseRateLimitedTx, %v", err)
+	}
+	if txParam == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseRateLimitedTx, no tx is queued from chain %d to chain %d",
+			params.FromChainID, params.ToChainID)
+	}
+	// policy may have changed since the tx is queued, a denied tx is dropped so that it does not block the queue
+	if policyErr := scom.CheckContractPolicy(native, params.FromChainID, txParam); policyErr != nil {
+		err = hscommon.UnpinHeader(native, params.FromChainID, txParam.TxHash)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("ReleaseRateLimitedTx, %v", err)
+		}
+		scom.NotifyDroppedTx(native, params.FromChainID, txParam, policyErr)
+		return utils.BYTE_TRUE, nil
+	}
+	ok, err := scom.ConsumeRateLimit(native, params.FromChainID, params.ToChainID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseRateLimitedTx, %v", err)
+	}
+	if !ok {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseRateLimitedTx, chain %d to chain %d is still over rate limit",
+			params.FromChainID, params.ToChainID)
+	}
+
+	delayed, err := makeTargetTransaction(native, txParam, params.FromChainID, sideChain.Router)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	if !delayed {
+		err = hscommon.UnpinHeader(native, params.FromChainID, txParam.TxHash)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("ReleaseRateLimitedTx, %v", err)
+		}
+	}
+	return utils.BYTE_TRUE, nil
+}
+
+func SetContractPolicy(native *native.NativeService) ([]byte, error) {
+	params := new(scom.ContractPolicyParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetContractPolicy, contract params deserialize error: %v", err)
+	}
+	if params.Policy > scom.CONTRACT_POLICY_DENY {
+		return utils.BYTE_FALSE, fmt.Errorf("SetContractPolicy, unknown policy %d", params.Policy)
+	}
+	if params.Side > scom.CONTRACT_POLICY_TARGET {
+		return utils.BYTE_FALSE, fmt.Errorf("SetContractPolicy, unknown side %d", params.Side)
+	}
+	if params.Side == scom.CONTRACT_POLICY_SOURCE && params.Method != "" {
+		return utils.BYTE_FALSE, fmt.Errorf("SetContractPolicy, method is only for target contract")
+	}
+	if len(params.Contract) == 0 {
+		return utils.BYTE_FALSE, fmt.Errorf("SetContractPolicy, contract is empty")
+	}
+
+	//check witness
+	err := utils.ValidateOwner(native, params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetContractPolicy, checkWitness error: %v", err)
+	}
+
+	//check consensus signs
+	ok, err := node_manager.CheckConsensusSigns(native, scom.SET_CONTRACT_POLICY, params.Vote(), params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetContractPolicy, CheckConsensusSigns error: %v", err)
+	}
+	if !ok {
+		return utils.BYTE_TRUE, nil
+	}
+
+	list, err := scom.GetContractPolicyList(native, params.ChainID, params.Side)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetContractPolicy, %v", err)
+	}
+	list.Set(params.Contract, params.Method, params.Policy)
+	scom.PutContractPolicyList(native, params.ChainID, params.Side, list)
+	native.AddNotify(scom.SetContractPolicyEvent.NewNotify(params.ChainID, params.Side,
+		hex.EncodeToString(params.Contract), params.Method, params.Policy))
+	return utils.BYTE_TRUE, nil
+}
+
+func GetContractPolicy(native *native.NativeService) ([]byte, error) {
+	params := new(scom.GetContractPolicyParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("GetContractPolicy, contract params deserialize error: %v", err)
+	}
+	list, err := scom.GetContractPolicyList(native, params.ChainID, params.Side)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("GetContractPolicy, %v", err)
+	}
+	return []byte{list.Get(params.Contract, params.Method)}, nil
+}
+
+func GetContractPolicyList(native *native.NativeService) ([]byte, error) {
+	params := new(scom.GetContractPolicyListParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("GetContractPolicyList, contract params deserialize error: %v", err)
+	}
+	list, err := scom.GetContractPolicyList(native, params.ChainID, params.Side)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("GetContractPolicyList, %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	list.Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+func SetExecutionDelay(native *native.NativeService) ([]byte, error) {
+	params := new(scom.ExecutionDelayParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetExecutionDelay, contract params deserialize error: %v", err)
+	}
+
+	// Get current epoch operator
+	operatorAddress, err := node_manager.GetCurConOperator(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetExecutionDelay, get current consensus operator address error: %v", err)
+	}
+	//check witness
+	err = utils.ValidateOwner(native, operatorAddress)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetExecutionDelay, checkWitness error: %v", err)
+	}
+
+	scom.PutExecutionDelay(native, params.ChainID, params.Delay)
+	native.AddNotify(scom.SetExecutionDelayEvent.NewNotify(params.ChainID, params.Delay))
+	return utils.BYTE_TRUE, nil
+}
+
+// ReleaseDelayedTx puts a delayed message into request storage once its delay passed, anyone can call it.
+// A message whose contract is denied during the delay is dropped
+func ReleaseDelayedTx(native *native.NativeService) ([]byte, error) {
+	params := new(scom.DelayedTxParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseDelayedTx, contract params deserialize error: %v", err)
+	}
+	if native.GetHeight() < params.Height {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseDelayedTx, tx can not be released before height %d", params.Height)
+	}
+
+	merkleValue, err := scom.GetDelayedTx(native, params.Height, params.TxHash)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseDelayedTx, %v", err)
+	}
+	if merkleValue == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseDelayedTx, delayed tx %x at height %d is not found", params.TxHash, params.Height)
+	}
+	for _, chainID := range []uint64{merkleValue.FromChainID, merkleValue.MakeTxParam.ToChainID} {
+		blacked, err := scom.CheckIfChainBlacked(native, chainID)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("ReleaseDelayedTx, CheckIfChainBlacked error: %v", err)
+		}
+		if blacked {
+			return utils.BYTE_FALSE, fmt.Errorf("ReleaseDelayedTx, chain %d is blacked", chainID)
+		}
+	}
+
+	txParam := merkleValue.MakeTxParam
+	sideChain, err := side_chain_manager.GetSideChain(native, txParam.ToChainID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseDelayedTx, side_chain_manager.GetSideChain error: %v", err)
+	}
+	if sideChain == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseDelayedTx, side chain %d is not registered", txParam.ToChainID)
+	}
+
+	scom.DeleteDelayedTx(native, params.Height, params.TxHash)
+	// policy may have changed since the tx is delayed, a denied tx is dropped so that its header is not kept pinned
+	if policyErr := scom.CheckContractPolicy(native, merkleValue.FromChainID, txParam); policyErr != nil {
+		err = hscommon.UnpinHeader(native, merkleValue.FromChainID, txParam.TxHash)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("ReleaseDelayedTx, %v", err)
+		}
+		scom.NotifyDroppedTx(native, merkleValue.FromChainID, txParam, policyErr)
+		return utils.BYTE_TRUE, nil
+	}
+	err = dispatchTargetTransaction(native, merkleValue, sideChain.Router)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseDelayedTx, %v", err)
+	}
+	err = hscommon.UnpinHeader(native, merkleValue.FromChainID, txParam.TxHash)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ReleaseDelayedTx, %v", err)
+	}
+	return utils.BYTE_TRUE, nil
+}
+
+// VetoDelayedTx drops a delayed message once a quorum of consensus peers vetoed it
+func VetoDelayedTx(native *native.NativeService) ([]byte, error) {
+	params := new(scom.DelayedTxParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("VetoDelayedTx, contract params deserialize error: %v", err)
+	}
+
+	//check witness
+	err := utils.ValidateOwner(native, params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("VetoDelayedTx, checkWitness error: %v", err)
+	}
+
+	merkleValue, err := scom.GetDelayedTx(native, params.Height, params.TxHash)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("VetoDelayedTx, %v", err)
+	}
+	if merkleValue == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("VetoDelayedTx, delayed tx %x at height %d is not found", params.TxHash, params.Height)
+	}
+
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteString(scom.VETO_DELAYED_TX)
+	sink.WriteUint32(params.Height)
+	sink.WriteVarBytes(params.TxHash)
+	id := sha256.Sum256(sink.Bytes())
+	ok, err := consensus_vote.CheckVotes(native, id[:], params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("VetoDelayedTx, CheckVotes error: %v", err)
+	}
+	if !ok {
+		return utils.BYTE_TRUE, nil
+	}
+
+	scom.DeleteDelayedTx(native, params.Height, params.TxHash)
+	txParam := merkleValue.MakeTxParam
+	err = hscommon.UnpinHeader(native, merkleValue.FromChainID, txParam.TxHash)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("VetoDelayedTx, %v", err)
+	}
+	native.AddNotify(scom.VetoDelayedTxEvent.NewNotify(merkleValue.FromChainID, txParam.ToChainID,
+		hex.EncodeToString(txParam.TxHash), params.Height, hex.EncodeToString(params.TxHash)))
+	scom.NotifyCrossChainTx(native, merkleValue.FromChainID, txParam.ToChainID, txParam.TxHash, txParam.CrossChainID, scom.CROSS_CHAIN_TX_VETOED)
+	return utils.BYTE_TRUE, nil
+}
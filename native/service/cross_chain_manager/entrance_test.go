@@ -0,0 +1,248 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cross_chain_manager
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+	cstates "github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func putPeerMapPoolAndView(db *storage.CacheDB, conAccts []*account.Account) {
+	peerPoolMap := new(node_manager.PeerPoolMap)
+	peerPoolMap.PeerPoolMap = make(map[string]*node_manager.PeerPoolItem)
+	for i, conAcct := range conAccts {
+		pkStr := vconfig.PubkeyID(conAcct.PublicKey)
+		peerPoolMap.PeerPoolMap[pkStr] = &node_manager.PeerPoolItem{
+			Index:      uint32(i),
+			PeerPubkey: pkStr,
+			Address:    conAcct.Address,
+			Status:     node_manager.ConsensusStatus,
+		}
+	}
+	viewBytes := utils.GetUint32Bytes(0)
+	sink := common.NewZeroCopySink(nil)
+	peerPoolMap.Serialization(sink)
+	db.Put(utils.ConcatKey(utils.NodeManagerContractAddress, []byte(node_manager.PEER_POOL), viewBytes), cstates.GenRawStorageItem(sink.Bytes()))
+
+	govView := node_manager.GovernanceView{View: 0, Height: 10, TxHash: common.UINT256_EMPTY}
+	sink = common.NewZeroCopySink(nil)
+	govView.Serialization(sink)
+	db.Put(utils.ConcatKey(utils.NodeManagerContractAddress, []byte(node_manager.GOVERNANCE_VIEW)), cstates.GenRawStorageItem(sink.Bytes()))
+}
+
+func NewNative(args []byte, tx *types.Transaction, db *storage.CacheDB, height uint32) *native.NativeService {
+	ns, _ := native.NewNativeService(db, tx, 0, height, common.Uint256{0}, 0, args, false)
+	return ns
+}
+
+func TestGetChainHandler(t *testing.T) {
+	routers := []uint64{utils.VOTE_ROUTER, utils.BTC_ROUTER, utils.ETH_ROUTER, utils.ONT_ROUTER, utils.NEO_ROUTER,
+		utils.COSMOS_ROUTER, utils.QUORUM_ROUTER, utils.BSC_ROUTER, utils.HECO_ROUTER, utils.ZILLIQA_LEGACY_ROUTER,
+		utils.MSC_ROUTER, utils.OKEX_ROUTER, utils.POLYGON_BOR_ROUTER, utils.PIXIECHAIN_ROUTER, utils.STARCOIN_ROUTER,
+		utils.HSC_ROUTER, utils.NEO3_ROUTER, utils.HARMONY_ROUTER, utils.BYTOM_ROUTER, utils.RIPPLE_ROUTER,
+		utils.ZILLIQA_ROUTER, utils.BEACON_ROUTER}
+	assert.Equal(t, len(routers), len(scom.GetChainRouters()))
+	for _, router := range routers {
+		handler, err := GetChainHandler(router)
+		assert.NoError(t, err)
+		assert.NotNil(t, handler)
+	}
+
+	_, err := GetChainHandler(utils.POLYGON_HEIMDALL_ROUTER)
+	assert.Error(t, err)
+
+	assert.NotNil(t, scom.GetMakeTransaction(utils.BTC_ROUTER))
+	assert.NotNil(t, scom.GetMakeTransaction(utils.RIPPLE_ROUTER))
+	assert.Nil(t, scom.GetMakeTransaction(utils.ETH_ROUTER))
+
+	assert.True(t, scom.AllowPendingDeposit(utils.VOTE_ROUTER))
+	assert.True(t, scom.AllowPendingDeposit(utils.RIPPLE_ROUTER))
+	assert.False(t, scom.AllowPendingDeposit(utils.ETH_ROUTER))
+}
+
+func TestDelayedTx(t *testing.T) {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	conAccts := make([]*account.Account, 0)
+	for i := 0; i < 7; i++ {
+		conAccts = append(conAccts, account.NewAccount(strconv.Itoa(i)))
+	}
+	putPeerMapPoolAndView(db, conAccts)
+
+	tx := &types.Transaction{Nonce: 1}
+	txHash := tx.Hash()
+	ns := NewNative(nil, tx, db, 100)
+	assert.Nil(t, side_chain_manager.PutSideChain(ns, &side_chain_manager.SideChain{ChainId: 3, Router: utils.ETH_ROUTER}))
+	scom.PutExecutionDelay(ns, 2, 10)
+	txParam := &scom.MakeTxParam{TxHash: []byte{1}, CrossChainID: []byte{1}, FromContractAddress: []byte{2}, ToChainID: 3,
+		ToContractAddress: []byte{3}, Method: "unlock", Args: []byte{4}}
+	delayed, err := makeTargetTransaction(ns, txParam, 2, utils.ETH_ROUTER)
+	assert.Nil(t, err)
+	assert.True(t, delayed)
+	merkleValue, err := scom.GetDelayedTx(ns, 110, txHash[:])
+	assert.Nil(t, err)
+	assert.Equal(t, txParam, merkleValue.MakeTxParam)
+	assert.Equal(t, 0, len(ns.GetCrossHashes()))
+
+	param := &scom.DelayedTxParam{Height: 110, TxHash: txHash[:]}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	_, err = ReleaseDelayedTx(NewNative(sink.Bytes(), new(types.Transaction), db, 109))
+	assert.Error(t, err)
+	ns = NewNative(sink.Bytes(), new(types.Transaction), db, 110)
+	_, err = ReleaseDelayedTx(ns)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(ns.GetCrossHashes()))
+	request, err := db.Get(utils.ConcatKey(utils.CrossChainManagerContractAddress, []byte(scom.REQUEST), utils.GetUint64Bytes(3), txHash[:]))
+	assert.Nil(t, err)
+	assert.NotNil(t, request)
+
+	// vetoed by a quorum of consensus peers
+	tx = &types.Transaction{Nonce: 2}
+	txHash = tx.Hash()
+	delayed, err = makeTargetTransaction(NewNative(nil, tx, db, 100), txParam, 2, utils.ETH_ROUTER)
+	assert.Nil(t, err)
+	assert.True(t, delayed)
+	for i, conAcct := range conAccts {
+		param := &scom.DelayedTxParam{Height: 110, TxHash: txHash[:], Address: conAcct.Address}
+		sink := common.NewZeroCopySink(nil)
+		param.Serialization(sink)
+		ns := NewNative(sink.Bytes(), &types.Transaction{SignedAddr: []common.Address{conAcct.Address}}, db, 105)
+		_, err := VetoDelayedTx(ns)
+		if i > 4 {
+			assert.Error(t, err)
+			continue
+		}
+		assert.Nil(t, err)
+		merkleValue, err := scom.GetDelayedTx(ns, 110, txHash[:])
+		assert.Nil(t, err)
+		assert.Equal(t, i < 4, merkleValue != nil)
+	}
+
+	// target contract denied during the delay
+	tx = &types.Transaction{Nonce: 3}
+	txHash = tx.Hash()
+	delayed, err = makeTargetTransaction(NewNative(nil, tx, db, 100), txParam, 2, utils.ETH_ROUTER)
+	assert.Nil(t, err)
+	assert.True(t, delayed)
+	ns = NewNative(nil, new(types.Transaction), db, 105)
+	assert.Nil(t, hscommon.PinHeader(ns, 2, txParam.TxHash, 50))
+	list := new(scom.ContractPolicyList)
+	list.Set(txParam.ToContractAddress, "", scom.CONTRACT_POLICY_DENY)
+	scom.PutContractPolicyList(ns, 3, scom.CONTRACT_POLICY_TARGET, list)
+	param = &scom.DelayedTxParam{Height: 110, TxHash: txHash[:]}
+	sink = common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	// the denied tx is dropped and its header unpinned
+	ns = NewNative(sink.Bytes(), new(types.Transaction), db, 110)
+	_, err = ReleaseDelayedTx(ns)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(ns.GetCrossHashes()))
+	assert.Equal(t, scom.NOTIFY_DROPPED_TX, ns.GetNotify()[0].States.([]interface{})[0])
+	merkleValue, err = scom.GetDelayedTx(ns, 110, txHash[:])
+	assert.Nil(t, err)
+	assert.Nil(t, merkleValue)
+	pinned, err := hscommon.GetPinnedHeights(ns, 2)
+	assert.Nil(t, err)
+	assert.Empty(t, pinned.Pins)
+}
+
+func TestReleaseDeniedRateLimitedTx(t *testing.T) {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	ns := NewNative(nil, new(types.Transaction), db, 100)
+	assert.Nil(t, side_chain_manager.PutSideChain(ns, &side_chain_manager.SideChain{ChainId: 3, Router: utils.ETH_ROUTER}))
+	scom.PutRateLimit(ns, 2, 3, &scom.RateLimit{Limit: 1, Period: 100})
+	denied := &scom.MakeTxParam{TxHash: []byte{1}, CrossChainID: []byte{1}, FromContractAddress: []byte{2}, ToChainID: 3,
+		ToContractAddress: []byte{3}, Method: "unlock", Args: []byte{4}}
+	next := &scom.MakeTxParam{TxHash: []byte{2}, CrossChainID: []byte{2}, FromContractAddress: []byte{2}, ToChainID: 3,
+		ToContractAddress: []byte{5}, Method: "unlock", Args: []byte{4}}
+	for _, txParam := range []*scom.MakeTxParam{denied, next} {
+		_, err := scom.PushRateLimitedTx(ns, 2, txParam)
+		assert.Nil(t, err)
+		assert.Nil(t, hscommon.PinHeader(ns, 2, txParam.TxHash, 50))
+	}
+	list := new(scom.ContractPolicyList)
+	list.Set(denied.ToContractAddress, "", scom.CONTRACT_POLICY_DENY)
+	scom.PutContractPolicyList(ns, 3, scom.CONTRACT_POLICY_TARGET, list)
+
+	param := &scom.ReleaseRateLimitedTxParam{FromChainID: 2, ToChainID: 3}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	// the denied head is dropped without taking the rate limit
+	ns = NewNative(sink.Bytes(), &types.Transaction{Nonce: 1}, db, 150)
+	_, err := ReleaseRateLimitedTx(ns)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(ns.GetCrossHashes()))
+	assert.Equal(t, scom.NOTIFY_DROPPED_TX, ns.GetNotify()[0].States.([]interface{})[0])
+	pinned, err := hscommon.GetPinnedHeights(ns, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), pinned.Pins[50])
+
+	// the next tx is released
+	tx := &types.Transaction{Nonce: 2}
+	txHash := tx.Hash()
+	ns = NewNative(sink.Bytes(), tx, db, 150)
+	_, err = ReleaseRateLimitedTx(ns)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(ns.GetCrossHashes()))
+	request, err := db.Get(utils.ConcatKey(utils.CrossChainManagerContractAddress, []byte(scom.REQUEST), utils.GetUint64Bytes(3), txHash[:]))
+	assert.Nil(t, err)
+	assert.NotNil(t, request)
+	pinned, err = hscommon.GetPinnedHeights(ns, 2)
+	assert.Nil(t, err)
+	assert.Empty(t, pinned.Pins)
+	queue, err := scom.GetRateLimitQueue(ns, 2, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, queue.Head, queue.Tail)
+}
+
+func TestDelayedTxOfRouterMaker(t *testing.T) {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+
+	// the btc maker is not reached before the delay passes
+	tx := &types.Transaction{Nonce: 1}
+	txHash := tx.Hash()
+	ns := NewNative(nil, tx, db, 100)
+	scom.PutExecutionDelay(ns, 2, 10)
+	txParam := &scom.MakeTxParam{TxHash: []byte{1}, CrossChainID: []byte{1}, FromContractAddress: []byte{2}, ToChainID: 1,
+		ToContractAddress: []byte{3}, Method: "unlock", Args: []byte{4}}
+	delayed, err := makeTargetTransaction(ns, txParam, 2, utils.BTC_ROUTER)
+	assert.Nil(t, err)
+	assert.True(t, delayed)
+	merkleValue, err := scom.GetDelayedTx(ns, 110, txHash[:])
+	assert.Nil(t, err)
+	assert.Equal(t, txParam, merkleValue.MakeTxParam)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/polynetwork/poly/native"
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/utils"
 )
 
 type ETHHandler struct {
@@ -31,6 +32,11 @@ func NewETHHandler() *ETHHandler {
 	return &ETHHandler{}
 }
 
+func init() {
+	scom.RegisterChainHandler(utils.ETH_ROUTER, func() scom.ChainHandler { return NewETHHandler() })
+	scom.RegisterChainHandler(utils.BEACON_ROUTER, func() scom.ChainHandler { return NewETHHandler() })
+}
+
 func (this *ETHHandler) MakeDepositProposal(service *native.NativeService) (*scom.MakeTxParam, error) {
 	params := new(scom.EntranceParam)
 	//parse the EntranceParam from native service data
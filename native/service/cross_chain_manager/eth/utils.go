@@ -35,22 +35,15 @@ import (
 	"github.com/polynetwork/poly/native"
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	cmanager "github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/header_sync/beacon"
 	"github.com/polynetwork/poly/native/service/header_sync/eth"
+	"github.com/polynetwork/poly/native/service/utils"
 )
 
 func verifyFromEthTx(native *native.NativeService, proof, extra []byte, fromChainID uint64, height uint32, sideChain *cmanager.SideChain) (*scom.MakeTxParam, error) {
-	bestHeader, _, err := eth.GetCurrentHeader(native, fromChainID)
+	stateRoot, err := getStateRoot(native, fromChainID, height, sideChain)
 	if err != nil {
-		return nil, fmt.Errorf("VerifyFromEthProof, get current header fail, error:%s", err)
-	}
-	bestHeight := uint32(bestHeader.Number.Uint64())
-	if bestHeight < height || bestHeight-height < uint32(sideChain.BlocksToWait-1) {
-		return nil, fmt.Errorf("VerifyFromEthProof, transaction is not confirmed, current height: %d, input height: %d", bestHeight, height)
-	}
-	//fetch the verified block header data from db
-	blockData, _, err := eth.GetHeaderByHeight(native, uint64(height), fromChainID)
-	if err != nil {
-		return nil, fmt.Errorf("VerifyFromEthProof, get header by height, height:%d, error:%s", height, err)
+		return nil, err
 	}
 	ethProof := new(ETHProof)
 	err = json.Unmarshal(proof, ethProof)
@@ -62,15 +55,15 @@ func verifyFromEthTx(native *native.NativeService, proof, extra []byte, fromChai
 	}
 	//todo 1. verify the proof with header
 	//determine where the k and v from
-	proofResult, err := VerifyMerkleProof(ethProof, blockData, sideChain.CCMCAddress)
+	proofResult, err := VerifyMerkleProofWithRoot(ethProof, stateRoot, sideChain.CCMCAddress)
 	if err != nil {
-		return nil, fmt.Errorf("VerifyFromEthProof, verifyMerkleProof error:%v", err)
+		return nil, native.Misbehave(fmt.Errorf("VerifyFromEthProof, verifyMerkleProof error:%v", err))
 	}
 	if proofResult == nil {
-		return nil, fmt.Errorf("VerifyFromEthProof, verifyMerkleProof failed!")
+		return nil, native.Misbehave(fmt.Errorf("VerifyFromEthProof, verifyMerkleProof failed!"))
 	}
 	if !CheckProofResult(proofResult, extra) {
-		return nil, fmt.Errorf("VerifyFromEthProof, verify proof value hash failed, proof result:%x, extra:%x", proofResult, extra)
+		return nil, native.Misbehave(fmt.Errorf("VerifyFromEthProof, verify proof value hash failed, proof result:%x, extra:%x", proofResult, extra))
 	}
 	data := common.NewZeroCopySource(extra)
 	txParam := new(scom.MakeTxParam)
@@ -80,12 +73,50 @@ func verifyFromEthTx(native *native.NativeService, proof, extra []byte, fromChai
 	return txParam, nil
 }
 
+// getStateRoot returns the state root of the side chain block at height, ethash chains need
+// BlocksToWait confirmations while beacon light clients only keep finalized blocks
+func getStateRoot(native *native.NativeService, fromChainID uint64, height uint32, sideChain *cmanager.SideChain) (ecom.Hash, error) {
+	if sideChain.Router == utils.BEACON_ROUTER {
+		bestHeight, err := beacon.GetCurrentHeight(native, fromChainID)
+		if err != nil {
+			return ecom.Hash{}, fmt.Errorf("VerifyFromEthProof, get current height fail, error:%s", err)
+		}
+		if bestHeight < uint64(height) {
+			return ecom.Hash{}, fmt.Errorf("VerifyFromEthProof, transaction is not finalized, current height: %d, input height: %d", bestHeight, height)
+		}
+		header, err := beacon.GetFinalizedHeader(native, fromChainID, uint64(height))
+		if err != nil {
+			return ecom.Hash{}, fmt.Errorf("VerifyFromEthProof, get finalized header by height, height:%d, error:%s", height, err)
+		}
+		return header.StateRoot, nil
+	}
+
+	bestHeader, _, err := eth.GetCurrentHeader(native, fromChainID)
+	if err != nil {
+		return ecom.Hash{}, fmt.Errorf("VerifyFromEthProof, get current header fail, error:%s", err)
+	}
+	bestHeight := uint32(bestHeader.Number.Uint64())
+	if bestHeight < height || bestHeight-height < uint32(sideChain.BlocksToWait-1) {
+		return ecom.Hash{}, fmt.Errorf("VerifyFromEthProof, transaction is not confirmed, current height: %d, input height: %d", bestHeight, height)
+	}
+	//fetch the verified block header data from db
+	blockData, _, err := eth.GetHeaderByHeight(native, uint64(height), fromChainID)
+	if err != nil {
+		return ecom.Hash{}, fmt.Errorf("VerifyFromEthProof, get header by height, height:%d, error:%s", height, err)
+	}
+	return blockData.Root, nil
+}
+
 // used by quorum
 func VerifyMerkleProofLegacy(ethProof *ETHProof, blockData *types.Header, contractAddr []byte) ([]byte, error) {
 	return VerifyMerkleProof(ethProof, eth.To1559(blockData), contractAddr)
 }
 
 func VerifyMerkleProof(ethProof *ETHProof, blockData *eth.Header, contractAddr []byte) ([]byte, error) {
+	return VerifyMerkleProofWithRoot(ethProof, blockData.Root, contractAddr)
+}
+
+func VerifyMerkleProofWithRoot(ethProof *ETHProof, stateRoot ecom.Hash, contractAddr []byte) ([]byte, error) {
 	//1. prepare verify account
 	nodeList := new(light.NodeList)
 
@@ -102,7 +133,7 @@ func VerifyMerkleProof(ethProof *ETHProof, blockData *eth.Header, contractAddr [
 	acctKey := crypto.Keccak256(addr)
 
 	// 2. verify account proof
-	acctVal, err := trie.VerifyProof(blockData.Root, acctKey, ns)
+	acctVal, err := trie.VerifyProof(stateRoot, acctKey, ns)
 	if err != nil {
 		return nil, fmt.Errorf("verifyMerkleProof, verify account proof error:%s\n", err)
 	}
@@ -36,6 +36,7 @@ import (
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/header_sync/eth"
 	"github.com/polynetwork/poly/native/service/header_sync/heco"
+	"github.com/polynetwork/poly/native/service/utils"
 )
 
 // Handler ...
@@ -47,6 +48,10 @@ func NewHecoHandler() *HecoHandler {
 	return &HecoHandler{}
 }
 
+func init() {
+	scom.RegisterChainHandler(utils.HECO_ROUTER, func() scom.ChainHandler { return NewHecoHandler() })
+}
+
 // MakeDepositProposal ...
 func (h *HecoHandler) MakeDepositProposal(service *native.NativeService) (*scom.MakeTxParam, error) {
 	params := new(scom.EntranceParam)
@@ -102,15 +107,15 @@ func verifyFromHecoTx(native *native.NativeService, proof, extra []byte, fromCha
 
 	proofResult, err := verifyMerkleProof(hecoProof, headerWithSum.Header, sideChain.CCMCAddress)
 	if err != nil {
-		return nil, fmt.Errorf("verifyFromHecoTx, verifyMerkleProof error:%v", err)
+		return nil, native.Misbehave(fmt.Errorf("verifyFromHecoTx, verifyMerkleProof error:%v", err))
 	}
 
 	if proofResult == nil {
-		return nil, fmt.Errorf("verifyFromHecoTx, verifyMerkleProof failed")
+		return nil, native.Misbehave(fmt.Errorf("verifyFromHecoTx, verifyMerkleProof failed"))
 	}
 
 	if !checkProofResult(proofResult, extra) {
-		return nil, fmt.Errorf("verifyFromHecoTx, verify proof value hash failed, proof result:%x, extra:%x", proofResult, extra)
+		return nil, native.Misbehave(fmt.Errorf("verifyFromHecoTx, verify proof value hash failed, proof result:%x, extra:%x", proofResult, extra))
 	}
 
 	data := common.NewZeroCopySource(extra)
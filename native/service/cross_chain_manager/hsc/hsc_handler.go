@@ -36,6 +36,7 @@ import (
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/header_sync/eth"
 	"github.com/polynetwork/poly/native/service/header_sync/hsc"
+	"github.com/polynetwork/poly/native/service/utils"
 )
 
 // Handler ...
@@ -47,6 +48,10 @@ func NewHscHandler() *HscHandler {
 	return &HscHandler{}
 }
 
+func init() {
+	scom.RegisterChainHandler(utils.HSC_ROUTER, func() scom.ChainHandler { return NewHscHandler() })
+}
+
 // MakeDepositProposal ...
 func (h *HscHandler) MakeDepositProposal(service *native.NativeService) (*scom.MakeTxParam, error) {
 	params := new(scom.EntranceParam)
@@ -102,15 +107,15 @@ func verifyFromHscTx(native *native.NativeService, proof, extra []byte, fromChai
 
 	proofResult, err := verifyMerkleProof(hscProof, headerWithSum.Header, sideChain.CCMCAddress)
 	if err != nil {
-		return nil, fmt.Errorf("verifyFromHscTx, verifyMerkleProof error:%v", err)
+		return nil, native.Misbehave(fmt.Errorf("verifyFromHscTx, verifyMerkleProof error:%v", err))
 	}
 
 	if proofResult == nil {
-		return nil, fmt.Errorf("verifyFromHscTx, verifyMerkleProof failed")
+		return nil, native.Misbehave(fmt.Errorf("verifyFromHscTx, verifyMerkleProof failed"))
 	}
 
 	if !checkProofResult(proofResult, extra) {
-		return nil, fmt.Errorf("verifyFromHscTx, verify proof value hash failed, proof result:%x, extra:%x", proofResult, extra)
+		return nil, native.Misbehave(fmt.Errorf("verifyFromHscTx, verify proof value hash failed, proof result:%x, extra:%x", proofResult, extra))
 	}
 
 	data := common.NewZeroCopySource(extra)
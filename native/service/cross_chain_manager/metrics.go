@@ -0,0 +1,41 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cross_chain_manager
+
+import (
+	"strconv"
+
+	"github.com/polynetwork/poly/common/metrics"
+	"github.com/polynetwork/poly/native"
+)
+
+var importExTransferCounter = metrics.NewCounterVec("poly_native_import_ex_transfer_total",
+	"ImportExTransfer executions by source chain router and result", "router", "result")
+
+//recordImportExTransfer counts the ImportExTransfer executed in blocks, pre-executions are skipped
+func recordImportExTransfer(native *native.NativeService, router uint64, err error) {
+	if native.IsPreExec() {
+		return
+	}
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	importExTransferCounter.WithLabelValues(strconv.FormatUint(router, 10), result).Inc()
+}
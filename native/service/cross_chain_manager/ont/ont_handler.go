@@ -28,6 +28,7 @@ import (
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/header_sync/ont"
+	"github.com/polynetwork/poly/native/service/utils"
 )
 
 type ONTHandler struct {
@@ -37,6 +38,10 @@ func NewONTHandler() *ONTHandler {
 	return &ONTHandler{}
 }
 
+func init() {
+	scom.RegisterChainHandler(utils.ONT_ROUTER, func() scom.ChainHandler { return NewONTHandler() })
+}
+
 func (this *ONTHandler) MakeDepositProposal(service *native.NativeService) (*scom.MakeTxParam, error) {
 	params := new(scom.EntranceParam)
 	if err := params.Deserialization(common.NewZeroCopySource(service.GetInput())); err != nil {
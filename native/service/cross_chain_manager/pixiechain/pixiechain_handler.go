@@ -36,6 +36,7 @@ import (
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/header_sync/eth"
 	"github.com/polynetwork/poly/native/service/header_sync/pixiechain"
+	"github.com/polynetwork/poly/native/service/utils"
 )
 
 // NewPixieHandler ...
@@ -43,6 +44,10 @@ func NewPixieHandler() *PixieHandler {
 	return &PixieHandler{}
 }
 
+func init() {
+	scom.RegisterChainHandler(utils.PIXIECHAIN_ROUTER, func() scom.ChainHandler { return NewPixieHandler() })
+}
+
 // MakeDepositProposal ...
 func (h *PixieHandler) MakeDepositProposal(service *native.NativeService) (*scom.MakeTxParam, error) {
 	params := new(scom.EntranceParam)
@@ -98,15 +103,15 @@ func verifyFromPixieTx(native *native.NativeService, proof, extra []byte, fromCh
 
 	proofResult, err := verifyMerkleProof(pixieProof, headerWithSum.Header, sideChain.CCMCAddress)
 	if err != nil {
-		return nil, fmt.Errorf("verifyFromPixieTx, verifyMerkleProof error:%v", err)
+		return nil, native.Misbehave(fmt.Errorf("verifyFromPixieTx, verifyMerkleProof error:%v", err))
 	}
 
 	if proofResult == nil {
-		return nil, fmt.Errorf("verifyFromPixieTx, verifyMerkleProof failed")
+		return nil, native.Misbehave(fmt.Errorf("verifyFromPixieTx, verifyMerkleProof failed"))
 	}
 
 	if !checkProofResult(proofResult, extra) {
-		return nil, fmt.Errorf("verifyFromPixieTx, verify proof value hash failed, proof result:%x, extra:%x", proofResult, extra)
+		return nil, native.Misbehave(fmt.Errorf("verifyFromPixieTx, verify proof value hash failed, proof result:%x, extra:%x", proofResult, extra))
 	}
 
 	data := common.NewZeroCopySource(extra)
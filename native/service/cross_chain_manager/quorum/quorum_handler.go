@@ -26,6 +26,7 @@ import (
 	"github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/header_sync/quorum"
+	"github.com/polynetwork/poly/native/service/utils"
 )
 
 type QuorumHandler struct{}
@@ -34,6 +35,10 @@ func NewQuorumHandler() *QuorumHandler {
 	return &QuorumHandler{}
 }
 
+func init() {
+	common.RegisterChainHandler(utils.QUORUM_ROUTER, func() common.ChainHandler { return NewQuorumHandler() })
+}
+
 func (this *QuorumHandler) MakeDepositProposal(ns *native.NativeService) (*common.MakeTxParam, error) {
 	params := new(common.EntranceParam)
 	if err := params.Deserialization(pcom.NewZeroCopySource(ns.GetInput())); err != nil {
@@ -75,11 +80,11 @@ func (this *QuorumHandler) MakeDepositProposal(ns *native.NativeService) (*commo
 		return nil, fmt.Errorf("Quorum MakeDepositProposal, failed to get quorum validators: %v", err)
 	}
 	if _, err := quorum.VerifyQuorumHeader(vs, header, false); err != nil {
-		return nil, fmt.Errorf("Quorum MakeDepositProposal, failed to verify quorum header %s: %v", header.Hash().String(), err)
+		return nil, ns.Misbehave(fmt.Errorf("Quorum MakeDepositProposal, failed to verify quorum header %s: %v", header.Hash().String(), err))
 	}
 
 	if err := verifyFromQuorumTx(params.Proof, params.Extra, header, sideChain); err != nil {
-		return nil, fmt.Errorf("Quorum MakeDepositProposal, verifyFromEthTx error: %s", err)
+		return nil, ns.Misbehave(fmt.Errorf("Quorum MakeDepositProposal, verifyFromEthTx error: %s", err))
 	}
 
 	return val, nil
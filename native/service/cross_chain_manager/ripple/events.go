@@ -0,0 +1,38 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ripple
+
+import (
+	"github.com/polynetwork/poly/native/event"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+var (
+	multisignedTxJsonEvent = event.RegisterEventSchema(utils.CrossChainManagerContractAddress, "multisignedTxJson",
+		event.Field("FromChainID", event.FIELD_UINT64),
+		event.Field("ToChainID", event.FIELD_UINT64),
+		event.Field("TxHash", event.FIELD_HEX),
+		event.Field("TxJson", event.FIELD_STRING),
+		event.Field("Sequence", event.FIELD_UINT32))
+	rippleTxJsonEvent = event.RegisterEventSchema(utils.CrossChainManagerContractAddress, "rippleTxJson",
+		event.Field("FromChainID", event.FIELD_UINT64),
+		event.Field("ToChainID", event.FIELD_UINT64),
+		event.Field("TxHash", event.FIELD_HEX),
+		event.Field("Tx", event.FIELD_ANY),
+		event.Field("Sequence", event.FIELD_UINT32))
+)
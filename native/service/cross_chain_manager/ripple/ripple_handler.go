@@ -26,7 +26,6 @@ import (
 
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/native"
-	"github.com/polynetwork/poly/native/event"
 	crosscommon "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/cross_chain_manager/consensus_vote"
@@ -43,6 +42,12 @@ func NewRippleHandler() *RippleHandler {
 	return &RippleHandler{}
 }
 
+func init() {
+	crosscommon.RegisterChainHandler(utils.RIPPLE_ROUTER, func() crosscommon.ChainHandler { return NewRippleHandler() })
+	crosscommon.RegisterMakeTransaction(utils.RIPPLE_ROUTER, NewRippleHandler().MakeTransaction)
+	crosscommon.RegisterPendingDeposit(utils.RIPPLE_ROUTER)
+}
+
 func (this *RippleHandler) MakeDepositProposal(service *native.NativeService) (*scom.MakeTxParam, error) {
 	params := new(scom.EntranceParam)
 	if err := params.Deserialization(common.NewZeroCopySource(service.GetInput())); err != nil {
@@ -231,12 +236,10 @@ func (this *RippleHandler) MultiSign(service *native.NativeService) error {
 		if err != nil {
 			return fmt.Errorf("MultiSign, json.Marshal final payment error: %s", err)
 		}
-		service.AddNotify(
-			&event.NotifyEventInfo{
-				ContractAddress: utils.CrossChainManagerContractAddress,
-				States: []interface{}{"multisignedTxJson", params.FromChainId, params.ToChainId,
-					hex.EncodeToString(params.TxHash), string(finalPayment), payment.Sequence},
-			})
+		service.AddNotify(multisignedTxJsonEvent.NewNotify(params.FromChainId, params.ToChainId,
+			hex.EncodeToString(params.TxHash), string(finalPayment), payment.Sequence))
+		crosscommon.NotifyCrossChainTx(service, params.FromChainId, params.ToChainId, params.TxHash,
+			nil, crosscommon.CROSS_CHAIN_TX_DONE)
 		multisignInfo.Status = true
 	}
 	PutMultisignInfo(service, raw, multisignInfo)
@@ -331,12 +334,8 @@ func (this *RippleHandler) MakeTransaction(service *native.NativeService, param
 	if err != nil {
 		return fmt.Errorf("ripple MakeTransaction, data.Raw error: %s", err)
 	}
-	service.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.CrossChainManagerContractAddress,
-			States: []interface{}{"rippleTxJson", fromChainID, param.ToChainID,
-				hex.EncodeToString(param.TxHash), hex.EncodeToString(raw), payment.Sequence},
-		})
+	service.AddNotify(rippleTxJsonEvent.NewNotify(fromChainID, param.ToChainID,
+		hex.EncodeToString(param.TxHash), hex.EncodeToString(raw), payment.Sequence))
 
 	//sequence + 1
 	rippleExtraInfo.Sequence = rippleExtraInfo.Sequence + 1
@@ -394,11 +393,7 @@ func (this *RippleHandler) ReconstructTx(service *native.NativeService) error {
 	if err != nil {
 		return fmt.Errorf("ReconstructTx, json.Marshal tx json error: %v", err)
 	}
-	service.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.CrossChainManagerContractAddress,
-			States: []interface{}{"rippleTxJson", params.FromChainId, params.ToChainId,
-				hex.EncodeToString(params.TxHash), txJsonStr, payment.Sequence},
-		})
+	service.AddNotify(rippleTxJsonEvent.NewNotify(params.FromChainId, params.ToChainId,
+		hex.EncodeToString(params.TxHash), txJsonStr, payment.Sequence))
 	return nil
 }
@@ -187,7 +187,7 @@ func verifyFromStarcoinTx(native *native.NativeService, proof, extra []byte, fro
 	// ///////////////////////////////
 	typeEventV0, err := VerifyEventProof(transactionInfoProof, blockData.BlockHeader.TxnAccumulatorRoot, sideChain.CCMCAddress)
 	if err != nil {
-		return nil, fmt.Errorf("verifyFromStarcoinTx, verifyMerkleProof error:%v", err)
+		return nil, native.Misbehave(fmt.Errorf("verifyFromStarcoinTx, verifyMerkleProof error:%v", err))
 	}
 	// ///////////////////
 	eventData := typeEventV0.EventData
@@ -0,0 +1,111 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package fork_manager
+
+import (
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+const (
+	//function name
+	SCHEDULE_FORK = "scheduleFork"
+
+	//key prefix
+	FORK_HEIGHT = "forkHeight"
+)
+
+// Register methods of fork_manager contract
+func RegisterForkManagerContract(native *native.NativeService) {
+	native.Register(SCHEDULE_FORK, ScheduleFork)
+}
+
+// ScheduleFork sets the activation height of a registered fork once
+// two thirds of consensus operators have voted for the same schedule
+func ScheduleFork(native *native.NativeService) ([]byte, error) {
+	params := new(ScheduleForkParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ScheduleFork, contract params deserialize error: %v", err)
+	}
+
+	//check witness
+	err := utils.ValidateOwner(native, params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ScheduleFork, checkWitness error: %v", err)
+	}
+
+	fork, ok := GetFork(params.Name)
+	if !ok {
+		return utils.BYTE_FALSE, fmt.Errorf("ScheduleFork, fork %s is not registered", params.Name)
+	}
+	if fork.PolyHeight && params.NetworkID == config.DefConfig.P2PNode.NetworkId {
+		height, err := getForkHeight(native, fork, params.NetworkID)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("ScheduleFork, getForkHeight error: %v", err)
+		}
+		if uint64(native.GetHeight()) >= height {
+			return utils.BYTE_FALSE, fmt.Errorf("ScheduleFork, fork %s is already active at height %d", params.Name, height)
+		}
+		if uint64(native.GetHeight()) >= params.Height {
+			return utils.BYTE_FALSE, fmt.Errorf("ScheduleFork, height %d is not in the future", params.Height)
+		}
+	}
+
+	//check consensus signs
+	ok, err = node_manager.CheckConsensusSigns(native, SCHEDULE_FORK, params.Vote(), params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ScheduleFork, CheckConsensusSigns error: %v", err)
+	}
+	if !ok {
+		return utils.BYTE_TRUE, nil
+	}
+
+	putForkHeight(native, params.Name, params.NetworkID, params.Height)
+	native.AddNotify(scheduleForkEvent.NewNotify(params.Name, params.NetworkID, params.Height))
+	return utils.BYTE_TRUE, nil
+}
+
+// GetForkHeight returns the activation height of fork name on current network
+func GetForkHeight(native *native.NativeService, name string) (uint64, error) {
+	fork, ok := GetFork(name)
+	if !ok {
+		return 0, fmt.Errorf("GetForkHeight, fork %s is not registered", name)
+	}
+	return getForkHeight(native, fork, config.DefConfig.P2PNode.NetworkId)
+}
+
+// CheckRouterStartBlock prevents hard forks by rejecting a router before its start height
+func CheckRouterStartBlock(native *native.NativeService, router uint64) error {
+	fork, ok := GetFork(RouterStartFork(router))
+	if !ok {
+		return nil
+	}
+	height, err := getForkHeight(native, fork, config.DefConfig.P2PNode.NetworkId)
+	if err != nil {
+		return fmt.Errorf("CheckRouterStartBlock, getForkHeight error: %v", err)
+	}
+	if uint64(native.GetHeight()) < height {
+		return fmt.Errorf("not a supported router:%d", router)
+	}
+	return nil
+}
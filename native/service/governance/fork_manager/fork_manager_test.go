@@ -0,0 +1,192 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package fork_manager
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/common/constants"
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+	cstates "github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	//registered by the chain packages which are not imported here
+	for _, router := range []uint64{utils.HSC_ROUTER, utils.HARMONY_ROUTER} {
+		RegisterRouterStartBlock(router, config.NETWORK_ID_MAIN_NET, constants.ROUTER_START_HEIGHT_MAINNET)
+	}
+}
+
+var conAccts = func() []*account.Account {
+	accts := make([]*account.Account, 0)
+	for i := 0; i < 7; i++ {
+		accts = append(accts, account.NewAccount(strconv.FormatUint(uint64(i), 10)))
+	}
+	return accts
+}()
+
+func putPeerMapPoolAndView(db *storage.CacheDB) {
+	peerPoolMap := new(node_manager.PeerPoolMap)
+	peerPoolMap.PeerPoolMap = make(map[string]*node_manager.PeerPoolItem)
+	for i, conAcct := range conAccts {
+		pkStr := vconfig.PubkeyID(conAcct.PublicKey)
+		peerPoolMap.PeerPoolMap[pkStr] = &node_manager.PeerPoolItem{
+			Index:      uint32(i),
+			PeerPubkey: pkStr,
+			Address:    conAcct.Address,
+			Status:     node_manager.ConsensusStatus,
+		}
+	}
+	viewBytes := utils.GetUint32Bytes(0)
+	sink := common.NewZeroCopySink(nil)
+	peerPoolMap.Serialization(sink)
+	db.Put(utils.ConcatKey(utils.NodeManagerContractAddress, []byte(node_manager.PEER_POOL), viewBytes), cstates.GenRawStorageItem(sink.Bytes()))
+
+	govView := node_manager.GovernanceView{
+		View:   0,
+		Height: 10,
+		TxHash: common.UINT256_EMPTY,
+	}
+	sink = common.NewZeroCopySink(nil)
+	govView.Serialization(sink)
+	db.Put(utils.ConcatKey(utils.NodeManagerContractAddress, []byte(node_manager.GOVERNANCE_VIEW)), cstates.GenRawStorageItem(sink.Bytes()))
+}
+
+func NewNative(args []byte, tx *types.Transaction, db *storage.CacheDB, height uint32) *native.NativeService {
+	if db == nil {
+		store, _ := leveldbstore.NewMemLevelDBStore()
+		db = storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+		putPeerMapPoolAndView(db)
+	}
+	ns, _ := native.NewNativeService(db, tx, 0, height, common.Uint256{0}, 0, args, false)
+	return ns
+}
+
+func setNetworkID(networkID uint32) func() {
+	old := config.DefConfig.P2PNode.NetworkId
+	config.DefConfig.P2PNode.NetworkId = networkID
+	return func() { config.DefConfig.P2PNode.NetworkId = old }
+}
+
+func scheduleFork(db *storage.CacheDB, acct *account.Account, param *ScheduleForkParam, height uint32) ([]byte, error) {
+	param.Address = acct.Address
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	tx := &types.Transaction{
+		SignedAddr: []common.Address{acct.Address},
+	}
+	return ScheduleFork(NewNative(sink.Bytes(), tx, db, height))
+}
+
+func TestForkDefaults(t *testing.T) {
+	defer setNetworkID(config.NETWORK_ID_MAIN_NET)()
+	ns := NewNative(nil, new(types.Transaction), nil, 0)
+
+	height, err := GetForkHeight(ns, RouterStartFork(utils.HARMONY_ROUTER))
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(constants.ROUTER_START_HEIGHT_MAINNET), height)
+	height, err = GetForkHeight(ns, VOTE_DONE_TX)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), height)
+	height, err = GetForkHeight(ns, ETH_1559)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(constants.ETH1559_HEIGHT_MAINNET), height)
+	_, err = GetForkHeight(ns, "unknown")
+	assert.NotNil(t, err)
+
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_TEST_NET
+	height, err = GetForkHeight(ns, VOTE_DONE_TX)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(constants.VOTE_DONE_TX_HEIGHT_TESTNET), height)
+	height, err = GetForkHeight(ns, RouterStartFork(utils.HARMONY_ROUTER))
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), height)
+}
+
+func TestCheckRouterStartBlock(t *testing.T) {
+	defer setNetworkID(config.NETWORK_ID_MAIN_NET)()
+
+	assert.NotNil(t, CheckRouterStartBlock(NewNative(nil, new(types.Transaction), nil, 18822999), utils.HARMONY_ROUTER))
+	assert.Nil(t, CheckRouterStartBlock(NewNative(nil, new(types.Transaction), nil, 18823000), utils.HARMONY_ROUTER))
+	assert.Nil(t, CheckRouterStartBlock(NewNative(nil, new(types.Transaction), nil, 0), utils.ETH_ROUTER))
+
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_TEST_NET
+	assert.Nil(t, CheckRouterStartBlock(NewNative(nil, new(types.Transaction), nil, 0), utils.HSC_ROUTER))
+}
+
+func TestScheduleFork(t *testing.T) {
+	defer setNetworkID(config.NETWORK_ID_MAIN_NET)()
+	db := NewNative(nil, new(types.Transaction), nil, 0).GetCacheDB()
+	param := &ScheduleForkParam{
+		Name:      ETH_1559,
+		NetworkID: config.NETWORK_ID_MAIN_NET,
+		Height:    13000000,
+	}
+
+	// none consensus acct should not be able to schedule a fork
+	res, err := scheduleFork(db, account.NewAccount("x"), param, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, utils.BYTE_TRUE, res)
+
+	for i, conAcct := range conAccts {
+		res, err := scheduleFork(db, conAcct, param, 100)
+		assert.Nil(t, err)
+		assert.Equal(t, utils.BYTE_TRUE, res)
+
+		height, err := GetForkHeight(NewNative(nil, new(types.Transaction), db, 100), ETH_1559)
+		assert.Nil(t, err)
+		if i+1 < (2*len(conAccts)+2)/3 {
+			assert.Equal(t, uint64(constants.ETH1559_HEIGHT_MAINNET), height)
+		} else {
+			assert.Equal(t, param.Height, height)
+			break
+		}
+	}
+
+	// activation on other networks is untouched
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_TEST_NET
+	height, err := GetForkHeight(NewNative(nil, new(types.Transaction), db, 100), ETH_1559)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(constants.ETH1559_HEIGHT_TESTNET), height)
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_MAIN_NET
+
+	_, err = scheduleFork(db, conAccts[0], &ScheduleForkParam{Name: "unknown", NetworkID: config.NETWORK_ID_MAIN_NET}, 100)
+	assert.NotNil(t, err)
+
+	// poly height forks can only be rescheduled to the future before activation
+	harmony := &ScheduleForkParam{Name: RouterStartFork(utils.HARMONY_ROUTER), NetworkID: config.NETWORK_ID_MAIN_NET, Height: 100}
+	_, err = scheduleFork(db, conAccts[0], harmony, 100)
+	assert.NotNil(t, err)
+	harmony.Height = 200
+	_, err = scheduleFork(db, conAccts[0], harmony, 18823000)
+	assert.NotNil(t, err)
+	_, err = scheduleFork(db, conAccts[0], harmony, 100)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package fork_manager
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/common/constants"
+)
+
+const (
+	//fork name
+	EXTRA_INFO           = "extraInfo"
+	VOTE_DONE_TX         = "voteDoneTx"
+	ETH_1559             = "eth1559"
+	ETH_4345             = "eth4345"
+	HECO_120             = "heco120"
+	POLYGON_SNAP_CHAINID = "polygonSnapChainID"
+	RELAYER_SLASHING     = "relayerSlashing"
+)
+
+// Fork is an activation value which consensus operators can reschedule
+type Fork struct {
+	Name string
+	// PolyHeight is true if the value is a poly block height, such a fork
+	// can not be rescheduled once it is active
+	PolyHeight bool
+	// Default returns the activation value of network if no one is scheduled
+	Default func(networkID uint32) uint64
+}
+
+var forks = make(map[string]*Fork)
+
+func init() {
+	RegisterFork(&Fork{
+		Name:       EXTRA_INFO,
+		PolyHeight: true,
+		Default: func(networkID uint32) uint64 {
+			return uint64(config.GetExtraInfoHeight(networkID))
+		},
+	})
+	RegisterFork(&Fork{
+		Name:       VOTE_DONE_TX,
+		PolyHeight: true,
+		Default:    networkDefault(config.NETWORK_ID_TEST_NET, constants.VOTE_DONE_TX_HEIGHT_TESTNET),
+	})
+	RegisterFork(&Fork{Name: ETH_1559, Default: config.GetEth1559Height})
+	RegisterFork(&Fork{Name: ETH_4345, Default: config.GetEth4345Height})
+	RegisterFork(&Fork{Name: HECO_120, Default: config.GetHeco120Height})
+	RegisterFork(&Fork{
+		Name: POLYGON_SNAP_CHAINID,
+		Default: func(networkID uint32) uint64 {
+			return uint64(config.GetPolygonSnapChainID(networkID))
+		},
+	})
+	RegisterFork(&Fork{
+		Name:       RELAYER_SLASHING,
+		PolyHeight: true,
+		Default: func(networkID uint32) uint64 {
+			//disabled on the public networks until scheduled by the consensus nodes
+			if networkID == config.NETWORK_ID_MAIN_NET || networkID == config.NETWORK_ID_TEST_NET {
+				return math.MaxUint32
+			}
+			return 0
+		},
+	})
+}
+
+// networkDefault returns value on networkID and 0 on the others
+func networkDefault(networkID uint32, value uint64) func(uint32) uint64 {
+	return func(id uint32) uint64 {
+		if id == networkID {
+			return value
+		}
+		return 0
+	}
+}
+
+// RouterStartFork is the name of the fork before which router is not supported
+func RouterStartFork(router uint64) string {
+	return fmt.Sprintf("router%dStart", router)
+}
+
+// RegisterRouterStartBlock makes router unavailable on networkID before height unless the consensus
+// nodes reschedule it, it is called by a chain package from its init
+func RegisterRouterStartBlock(router uint64, networkID uint32, height uint64) {
+	RegisterFork(&Fork{
+		Name:       RouterStartFork(router),
+		PolyHeight: true,
+		Default:    networkDefault(networkID, height),
+	})
+}
+
+// RegisterFork adds fork to the registry, every name can only be registered once
+func RegisterFork(fork *Fork) {
+	if _, ok := forks[fork.Name]; ok {
+		panic(fmt.Sprintf("fork %s registered twice", fork.Name))
+	}
+	forks[fork.Name] = fork
+}
+
+func GetFork(name string) (*Fork, bool) {
+	fork, ok := forks[name]
+	return fork, ok
+}
+
+// GetForkNames returns all registered fork names in ascending order
+func GetForkNames() []string {
+	names := make([]string, 0, len(forks))
+	for name := range forks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
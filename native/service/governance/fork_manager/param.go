@@ -0,0 +1,75 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package fork_manager
+
+import (
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+)
+
+type ScheduleForkParam struct {
+	Name      string
+	NetworkID uint32
+	Height    uint64
+	Address   common.Address
+}
+
+func (this *ScheduleForkParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteString(this.Name)
+	sink.WriteUint32(this.NetworkID)
+	sink.WriteUint64(this.Height)
+	sink.WriteVarBytes(this.Address[:])
+}
+
+func (this *ScheduleForkParam) Deserialization(source *common.ZeroCopySource) error {
+	name, eof := source.NextString()
+	if eof {
+		return fmt.Errorf("source.NextString, deserialize name error")
+	}
+	networkID, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("source.NextUint32, deserialize network id error")
+	}
+	height, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("source.NextUint64, deserialize height error")
+	}
+	address, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("source.NextVarBytes, deserialize address error")
+	}
+	addr, err := common.AddressParseFromBytes(address)
+	if err != nil {
+		return fmt.Errorf("common.AddressParseFromBytes, deserialize address error: %s", err)
+	}
+	this.Name = name
+	this.NetworkID = networkID
+	this.Height = height
+	this.Address = addr
+	return nil
+}
+
+// Vote returns the content consensus operators sign for, address is not included
+func (this *ScheduleForkParam) Vote() []byte {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteString(this.Name)
+	sink.WriteUint32(this.NetworkID)
+	sink.WriteUint64(this.Height)
+	return sink.Bytes()
+}
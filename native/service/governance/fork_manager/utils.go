@@ -0,0 +1,71 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package fork_manager
+
+import (
+	"fmt"
+
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/core/ledger"
+	cstates "github.com/polynetwork/poly/core/states"
+	scom "github.com/polynetwork/poly/core/store/common"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+func forkHeightKey(name string, networkID uint32) []byte {
+	return append(append([]byte(FORK_HEIGHT), utils.GetUint32Bytes(networkID)...), name...)
+}
+
+func putForkHeight(native *native.NativeService, name string, networkID uint32, height uint64) {
+	native.GetCacheDB().Put(utils.ConcatKey(utils.ForkManagerContractAddress, forkHeightKey(name, networkID)),
+		cstates.GenRawStorageItem(utils.GetUint64Bytes(height)))
+}
+
+func getForkHeight(native *native.NativeService, fork *Fork, networkID uint32) (uint64, error) {
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(utils.ForkManagerContractAddress, forkHeightKey(fork.Name, networkID)))
+	if err != nil {
+		return 0, fmt.Errorf("getForkHeight, get height store error: %v", err)
+	}
+	if store == nil {
+		return fork.Default(networkID), nil
+	}
+	value, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return 0, fmt.Errorf("getForkHeight, deserialize from raw storage item err:%v", err)
+	}
+	return utils.GetBytesUint64(value), nil
+}
+
+// GetCommittedForkHeight reads the activation height of fork name on current network from
+// the ledger, it is used where no native service is available
+func GetCommittedForkHeight(name string) (uint64, error) {
+	fork, ok := GetFork(name)
+	if !ok {
+		return 0, fmt.Errorf("GetCommittedForkHeight, fork %s is not registered", name)
+	}
+	networkID := config.DefConfig.P2PNode.NetworkId
+	value, err := ledger.DefLedger.GetStorageItem(utils.ForkManagerContractAddress, forkHeightKey(name, networkID))
+	if err == scom.ErrNotFound {
+		return fork.Default(networkID), nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("GetCommittedForkHeight, get height store error: %v", err)
+	}
+	return utils.GetBytesUint64(value), nil
+}
@@ -0,0 +1,34 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package neo3_state_manager
+
+import (
+	"github.com/polynetwork/poly/native/event"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+var (
+	approveRegisterStateValidatorEvent = event.RegisterEventSchema(utils.Neo3StateManagerContractAddress, "ApproveRegisterStateValidator",
+		event.Field("ID", event.FIELD_UINT64))
+	approveRemoveStateValidatorEvent = event.RegisterEventSchema(utils.Neo3StateManagerContractAddress, "ApproveRemoveStateValidator",
+		event.Field("ID", event.FIELD_UINT64))
+	putStateValidatorApplyEvent = event.RegisterEventSchema(utils.Neo3StateManagerContractAddress, "putStateValidatorApply",
+		event.Field("ID", event.FIELD_UINT64))
+	putStateValidatorRemoveEvent = event.RegisterEventSchema(utils.Neo3StateManagerContractAddress, "putStateValidatorRemove",
+		event.Field("ID", event.FIELD_UINT64))
+)
@@ -21,7 +21,6 @@ import (
 	"fmt"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/native"
-	"github.com/polynetwork/poly/native/event"
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
 	"github.com/polynetwork/poly/native/service/utils"
 )
@@ -104,11 +103,7 @@ func ApproveRegisterStateValidator(native *native.NativeService) ([]byte, error)
 	}
 
 	native.GetCacheDB().Delete(utils.ConcatKey(utils.Neo3StateManagerContractAddress, []byte(STATE_VALIDATOR_APPLY), utils.GetUint64Bytes(params.ID)))
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.Neo3StateManagerContractAddress,
-			States:          []interface{}{"ApproveRegisterStateValidator", params.ID},
-		})
+	native.AddNotify(approveRegisterStateValidatorEvent.NewNotify(params.ID))
 	return utils.BYTE_TRUE, nil
 }
 
@@ -158,10 +153,6 @@ func ApproveRemoveStateValidator(native *native.NativeService) ([]byte, error) {
 	}
 
 	native.GetCacheDB().Delete(utils.ConcatKey(utils.Neo3StateManagerContractAddress, []byte(STATE_VALIDATOR_REMOVE), utils.GetUint64Bytes(params.ID)))
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.Neo3StateManagerContractAddress,
-			States:          []interface{}{"ApproveRemoveStateValidator", params.ID},
-		})
+	native.AddNotify(approveRemoveStateValidatorEvent.NewNotify(params.ID))
 	return utils.BYTE_TRUE, nil
 }
@@ -22,7 +22,6 @@ import (
 	"github.com/polynetwork/poly/common"
 	cstates "github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/native"
-	"github.com/polynetwork/poly/native/event"
 	"github.com/polynetwork/poly/native/service/utils"
 )
 
@@ -171,11 +170,7 @@ func putStateValidatorApply(native *native.NativeService, stateValidatorListPara
 	sink := common.NewZeroCopySink(nil)
 	stateValidatorListParam.Serialization(sink)
 	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(STATE_VALIDATOR_APPLY), utils.GetUint64Bytes(applyID)), cstates.GenRawStorageItem(sink.Bytes()))
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: contract,
-			States:          []interface{}{"putStateValidatorApply", applyID},
-		})
+	native.AddNotify(putStateValidatorApplyEvent.NewNotify(applyID))
 	return nil
 }
 
@@ -238,11 +233,7 @@ func putStateValidatorRemove(native *native.NativeService, svListParam *StateVal
 	sink := common.NewZeroCopySink(nil)
 	svListParam.Serialization(sink)
 	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(STATE_VALIDATOR_REMOVE), utils.GetUint64Bytes(removeID)), cstates.GenRawStorageItem(sink.Bytes()))
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: contract,
-			States:          []interface{}{"putStateValidatorRemove", removeID},
-		})
+	native.AddNotify(putStateValidatorRemoveEvent.NewNotify(removeID))
 	return nil
 }
 
@@ -0,0 +1,43 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package node_manager
+
+import (
+	"github.com/polynetwork/poly/native/event"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+var (
+	checkConsensusSignsEvent = event.RegisterEventSchema(utils.NodeManagerContractAddress, "CheckConsensusSigns",
+		event.Field("SignCount", event.FIELD_INT))
+	registerCandidateEvent = event.RegisterEventSchema(utils.NodeManagerContractAddress, "registerCandidate",
+		event.Field("PeerPubkey", event.FIELD_STRING))
+	unRegisterCandidateEvent = event.RegisterEventSchema(utils.NodeManagerContractAddress, "unRegisterCandidate",
+		event.Field("PeerPubkey", event.FIELD_STRING))
+	approveCandidateEvent = event.RegisterEventSchema(utils.NodeManagerContractAddress, "approveCandidate",
+		event.Field("PeerPubkey", event.FIELD_STRING))
+	blackNodeEvent = event.RegisterEventSchema(utils.NodeManagerContractAddress, "blackNode",
+		event.Field("PeerPubkeyList", event.FIELD_STRING_LIST))
+	whiteNodeEvent = event.RegisterEventSchema(utils.NodeManagerContractAddress, "whiteNode",
+		event.Field("PeerPubkey", event.FIELD_STRING))
+	quitNodeEvent = event.RegisterEventSchema(utils.NodeManagerContractAddress, "quitNode",
+		event.Field("PeerPubkey", event.FIELD_STRING))
+	commitDposEvent   = event.RegisterEventSchema(utils.NodeManagerContractAddress, "commitDpos")
+	updateConfigEvent = event.RegisterEventSchema(utils.NodeManagerContractAddress, "updateConfig",
+		event.Field("Configuration", event.FIELD_OBJECT))
+)
@@ -25,7 +25,6 @@ import (
 	"github.com/polynetwork/poly/core/genesis"
 	cstates "github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/native"
-	"github.com/polynetwork/poly/native/event"
 	"github.com/polynetwork/poly/native/service/utils"
 )
 
@@ -58,6 +57,9 @@ const (
 
 	//const
 	MIN_PEER_NUM = 4
+	//min consensus timeout and max timeout backoff accepted by UpdateConfig
+	MIN_CONSENSUS_TIMEOUT = 100
+	MAX_TIMEOUT_BACKOFF   = 64
 )
 
 //Register methods of node_manager contract
@@ -214,11 +216,7 @@ func RegisterCandidate(native *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("registerCandidate, put putPeerApply error: %v", err)
 	}
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.NodeManagerContractAddress,
-			States:          []interface{}{"registerCandidate", params.PeerPubkey},
-		})
+	native.AddNotify(registerCandidateEvent.NewNotify(params.PeerPubkey))
 	return utils.BYTE_TRUE, nil
 }
 
@@ -254,11 +252,7 @@ func UnRegisterCandidate(native *native.NativeService) ([]byte, error) {
 		return utils.BYTE_FALSE, fmt.Errorf("unRegisterCandidate, peerPubkey format error: %v", err)
 	}
 	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(PEER_APPLY), peerPubkeyPrefix))
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.NodeManagerContractAddress,
-			States:          []interface{}{"unRegisterCandidate", params.PeerPubkey},
-		})
+	native.AddNotify(unRegisterCandidateEvent.NewNotify(params.PeerPubkey))
 	return utils.BYTE_TRUE, nil
 }
 
@@ -348,11 +342,7 @@ func ApproveCandidate(native *native.NativeService) ([]byte, error) {
 
 	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(PEER_APPLY), peerPubkeyPrefix))
 
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.NodeManagerContractAddress,
-			States:          []interface{}{"approveCandidate", params.PeerPubkey},
-		})
+	native.AddNotify(approveCandidateEvent.NewNotify(params.PeerPubkey))
 	return utils.BYTE_TRUE, nil
 }
 
@@ -451,11 +441,7 @@ func BlackNode(native *native.NativeService) ([]byte, error) {
 			return utils.BYTE_FALSE, fmt.Errorf("blackNode, executeCommitDpos error: %v", err)
 		}
 	}
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.NodeManagerContractAddress,
-			States:          []interface{}{"blackNode", params.PeerPubkeyList},
-		})
+	native.AddNotify(blackNodeEvent.NewNotify(params.PeerPubkeyList))
 	return utils.BYTE_TRUE, nil
 }
 
@@ -497,11 +483,7 @@ func WhiteNode(native *native.NativeService) ([]byte, error) {
 
 	//remove peer from black list
 	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(BLACK_LIST), peerPubkeyPrefix))
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.NodeManagerContractAddress,
-			States:          []interface{}{"whiteNode", params.PeerPubkey},
-		})
+	native.AddNotify(whiteNodeEvent.NewNotify(params.PeerPubkey))
 	return utils.BYTE_TRUE, nil
 }
 
@@ -557,11 +539,7 @@ func QuitNode(native *native.NativeService) ([]byte, error) {
 
 	peerPoolMap.PeerPoolMap[params.PeerPubkey] = peerPoolItem
 	putPeerPoolMap(native, peerPoolMap, view)
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.NodeManagerContractAddress,
-			States:          []interface{}{"quitNode", params.PeerPubkey},
-		})
+	native.AddNotify(quitNodeEvent.NewNotify(params.PeerPubkey))
 	return utils.BYTE_TRUE, nil
 }
 
@@ -598,11 +576,7 @@ func CommitDpos(native *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("executeCommitDpos, executeCommitDpos error: %v", err)
 	}
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.NodeManagerContractAddress,
-			States:          []interface{}{"commitDpos"},
-		})
+	native.AddNotify(commitDposEvent.NewNotify())
 	return utils.BYTE_TRUE, nil
 }
 
@@ -638,12 +612,11 @@ func UpdateConfig(native *native.NativeService) ([]byte, error) {
 	if params.Configuration.MaxBlockChangeView < 10000 {
 		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. MaxBlockChangeView must >= 10000")
 	}
+	if err := checkConsensusTimeouts(params.Configuration); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. %v", err)
+	}
 
 	putConfig(native, params.Configuration)
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.NodeManagerContractAddress,
-			States:          []interface{}{"updateConfig", params.Configuration},
-		})
+	native.AddNotify(updateConfigEvent.NewNotify(params.Configuration))
 	return utils.BYTE_TRUE, nil
 }
@@ -228,6 +228,21 @@ type Configuration struct {
 	HashMsgDelay         uint32
 	PeerHandshakeTimeout uint32
 	MaxBlockChangeView   uint32
+
+	//optional consensus timeouts in milliseconds, the unset ones are derived from the msg delays
+	MakeProposalTimeout uint32
+	EndorseBlockTimeout uint32
+	CommitBlockTimeout  uint32
+	ZeroTxBlockTimeout  uint32
+	TxPoolTimeout       uint32
+	//max factor the timeouts are scaled by when rounds are slow or time out, 0 or 1 to disable
+	MaxTimeoutBackoff uint32
+}
+
+//HasTimeouts returns true if any of the optional consensus timeouts is set
+func (this *Configuration) HasTimeouts() bool {
+	return this.MakeProposalTimeout != 0 || this.EndorseBlockTimeout != 0 || this.CommitBlockTimeout != 0 ||
+		this.ZeroTxBlockTimeout != 0 || this.TxPoolTimeout != 0 || this.MaxTimeoutBackoff != 0
 }
 
 func (this *Configuration) Serialization(sink *common.ZeroCopySink) {
@@ -235,6 +250,15 @@ func (this *Configuration) Serialization(sink *common.ZeroCopySink) {
 	sink.WriteUint32(this.HashMsgDelay)
 	sink.WriteUint32(this.PeerHandshakeTimeout)
 	sink.WriteUint32(this.MaxBlockChangeView)
+	//the configurations without timeouts keep the original encoding
+	if this.HasTimeouts() {
+		sink.WriteUint32(this.MakeProposalTimeout)
+		sink.WriteUint32(this.EndorseBlockTimeout)
+		sink.WriteUint32(this.CommitBlockTimeout)
+		sink.WriteUint32(this.ZeroTxBlockTimeout)
+		sink.WriteUint32(this.TxPoolTimeout)
+		sink.WriteUint32(this.MaxTimeoutBackoff)
+	}
 }
 
 func (this *Configuration) Deserialization(source *common.ZeroCopySource) error {
@@ -259,5 +283,39 @@ func (this *Configuration) Deserialization(source *common.ZeroCopySource) error
 	this.HashMsgDelay = hashMsgDelay
 	this.PeerHandshakeTimeout = peerHandshakeTimeout
 	this.MaxBlockChangeView = maxBlockChangeView
+	if source.Len() == 0 {
+		return nil
+	}
+
+	makeProposalTimeout, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("source.NextUint32, deserialize makeProposalTimeout error")
+	}
+	endorseBlockTimeout, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("source.NextUint32, deserialize endorseBlockTimeout error")
+	}
+	commitBlockTimeout, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("source.NextUint32, deserialize commitBlockTimeout error")
+	}
+	zeroTxBlockTimeout, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("source.NextUint32, deserialize zeroTxBlockTimeout error")
+	}
+	txPoolTimeout, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("source.NextUint32, deserialize txPoolTimeout error")
+	}
+	maxTimeoutBackoff, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("source.NextUint32, deserialize maxTimeoutBackoff error")
+	}
+	this.MakeProposalTimeout = makeProposalTimeout
+	this.EndorseBlockTimeout = endorseBlockTimeout
+	this.CommitBlockTimeout = commitBlockTimeout
+	this.ZeroTxBlockTimeout = zeroTxBlockTimeout
+	this.TxPoolTimeout = txPoolTimeout
+	this.MaxTimeoutBackoff = maxTimeoutBackoff
 	return nil
 }
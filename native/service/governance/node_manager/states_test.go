@@ -38,3 +38,30 @@ func Test_Deserialize_GovernanceView(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, *govView, *govView1)
 }
+
+func Test_Deserialize_Configuration(t *testing.T) {
+	config := &Configuration{
+		BlockMsgDelay:        10000,
+		HashMsgDelay:         10000,
+		PeerHandshakeTimeout: 10,
+		MaxBlockChangeView:   120000,
+	}
+	sink := common.NewZeroCopySink(nil)
+	config.Serialization(sink)
+	//configuration without timeouts keeps the original encoding
+	assert.Equal(t, 16, len(sink.Bytes()))
+	config1 := new(Configuration)
+	assert.Nil(t, config1.Deserialization(common.NewZeroCopySource(sink.Bytes())))
+	assert.Equal(t, *config, *config1)
+
+	config.MakeProposalTimeout = 3000
+	config.TxPoolTimeout = 500
+	config.MaxTimeoutBackoff = 4
+	sink = common.NewZeroCopySink(nil)
+	config.Serialization(sink)
+	config2 := new(Configuration)
+	assert.Nil(t, config2.Deserialization(common.NewZeroCopySource(sink.Bytes())))
+	assert.Equal(t, *config, *config2)
+
+	assert.NotNil(t, new(Configuration).Deserialization(common.NewZeroCopySource(sink.Bytes()[:20])))
+}
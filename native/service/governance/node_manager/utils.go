@@ -22,7 +22,6 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
-	"github.com/polynetwork/poly/native/event"
 
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/common"
@@ -102,6 +101,29 @@ func putPeerPoolMap(native *native.NativeService, peerPoolMap *PeerPoolMap, view
 	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(PEER_POOL), viewBytes), cstates.GenRawStorageItem(sink.Bytes()))
 }
 
+//checkConsensusTimeouts checks the optional consensus timeouts of configuration, 0 leaves a timeout unset
+func checkConsensusTimeouts(configuration *Configuration) error {
+	timeouts := []struct {
+		name  string
+		value uint32
+	}{
+		{"MakeProposalTimeout", configuration.MakeProposalTimeout},
+		{"EndorseBlockTimeout", configuration.EndorseBlockTimeout},
+		{"CommitBlockTimeout", configuration.CommitBlockTimeout},
+		{"ZeroTxBlockTimeout", configuration.ZeroTxBlockTimeout},
+		{"TxPoolTimeout", configuration.TxPoolTimeout},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value != 0 && timeout.value < MIN_CONSENSUS_TIMEOUT {
+			return fmt.Errorf("%s must be 0 or >= %d", timeout.name, MIN_CONSENSUS_TIMEOUT)
+		}
+	}
+	if configuration.MaxTimeoutBackoff > MAX_TIMEOUT_BACKOFF {
+		return fmt.Errorf("MaxTimeoutBackoff must <= %d", MAX_TIMEOUT_BACKOFF)
+	}
+	return nil
+}
+
 func CheckVBFTConfig(configuration *config.VBFTConfig) error {
 	if configuration.BlockMsgDelay < 5000 {
 		return fmt.Errorf("initConfig. BlockMsgDelay must >= 5000")
@@ -276,11 +298,7 @@ func CheckConsensusSigns(native *native.NativeService, method string, input []by
 		return false, fmt.Errorf("CheckConsensusSigns, GetConsensusSigns error: %v", err)
 	}
 	consensusSigns.SignsMap[address] = true
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.NodeManagerContractAddress,
-			States:          []interface{}{"CheckConsensusSigns", len(consensusSigns.SignsMap)},
-		})
+	native.AddNotify(checkConsensusSignsEvent.NewNotify(len(consensusSigns.SignsMap)))
 	//check signs num
 	//get view
 	view, err := GetView(native)
@@ -0,0 +1,177 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package relayer_manager
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/polynetwork/poly/common"
+	cstates "github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+//RecordHeaderSync credits a successful SyncBlockHeader of count headers to the relayer signing the tx
+func RecordHeaderSync(native *native.NativeService, chainID uint64, count int) error {
+	relayer, ok, err := getTxRelayer(native)
+	if err != nil || !ok {
+		return err
+	}
+	work, err := getRelayerWork(native, relayer, chainID)
+	if err != nil {
+		return fmt.Errorf("RecordHeaderSync, %v", err)
+	}
+	work.HeaderSyncs++
+	work.Headers += uint64(count)
+	work.LastHeight = native.GetHeight()
+	return putRelayerWork(native, work)
+}
+
+//RecordCrossChainProof credits a successful ImportOuterTransfer from fromChainID to toChainID to the relayer
+//signing the tx, the fee of the proof is taken from the fee schedule of the target chain
+func RecordCrossChainProof(native *native.NativeService, fromChainID, toChainID uint64) error {
+	relayer, ok, err := getTxRelayer(native)
+	if err != nil || !ok {
+		return err
+	}
+	work, err := getRelayerWork(native, relayer, fromChainID)
+	if err != nil {
+		return fmt.Errorf("RecordCrossChainProof, %v", err)
+	}
+	fee, err := side_chain_manager.GetFee(native, toChainID)
+	if err != nil {
+		return fmt.Errorf("RecordCrossChainProof, %v", err)
+	}
+	work.Proofs++
+	work.Fee = new(big.Int).Add(work.Fee, fee.Fee)
+	work.LastHeight = native.GetHeight()
+	return putRelayerWork(native, work)
+}
+
+//getTxRelayer returns the first signer of the tx which is a registered relayer
+func getTxRelayer(native *native.NativeService) (common.Address, bool, error) {
+	addresses, err := native.GetTx().GetSignatureAddresses()
+	if err != nil {
+		return common.ADDRESS_EMPTY, false, fmt.Errorf("getTxRelayer, GetSignatureAddresses error: %v", err)
+	}
+	for _, address := range addresses {
+		ok, err := isRelayer(native, address)
+		if err != nil {
+			return common.ADDRESS_EMPTY, false, err
+		}
+		if ok {
+			return address, true, nil
+		}
+	}
+	return common.ADDRESS_EMPTY, false, nil
+}
+
+func isRelayer(native *native.NativeService, address common.Address) (bool, error) {
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(utils.RelayerManagerContractAddress, []byte(RELAYER), address[:]))
+	if err != nil {
+		return false, fmt.Errorf("isRelayer, get relayer store error: %v", err)
+	}
+	return store != nil, nil
+}
+
+func getRelayerWork(native *native.NativeService, relayer common.Address, chainID uint64) (*RelayerWork, error) {
+	contract := utils.RelayerManagerContractAddress
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(RELAYER_WORK), relayer[:], utils.GetUint64Bytes(chainID)))
+	if err != nil {
+		return nil, fmt.Errorf("getRelayerWork, get relayer work store error: %v", err)
+	}
+	work := &RelayerWork{
+		Address: relayer,
+		ChainID: chainID,
+		Fee:     new(big.Int),
+	}
+	if store == nil {
+		return work, nil
+	}
+	workBytes, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("getRelayerWork, deserialize from raw storage item err:%v", err)
+	}
+	if err := work.Deserialization(common.NewZeroCopySource(workBytes)); err != nil {
+		return nil, fmt.Errorf("getRelayerWork, deserialize relayer work err:%v", err)
+	}
+	return work, nil
+}
+
+func putRelayerWork(native *native.NativeService, work *RelayerWork) error {
+	contract := utils.RelayerManagerContractAddress
+	chainIDs, err := getRelayerWorkChains(native, work.Address)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, chainID := range chainIDs {
+		if chainID == work.ChainID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		chainIDs = append(chainIDs, work.ChainID)
+		sink := common.NewZeroCopySink(nil)
+		sink.WriteVarUint(uint64(len(chainIDs)))
+		for _, chainID := range chainIDs {
+			sink.WriteUint64(chainID)
+		}
+		native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(RELAYER_WORK_CHAINS), work.Address[:]),
+			cstates.GenRawStorageItem(sink.Bytes()))
+	}
+	sink := common.NewZeroCopySink(nil)
+	work.Serialization(sink)
+	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(RELAYER_WORK), work.Address[:], utils.GetUint64Bytes(work.ChainID)),
+		cstates.GenRawStorageItem(sink.Bytes()))
+	return nil
+}
+
+//getRelayerWorkChains returns the chains the relayer has worked for
+func getRelayerWorkChains(native *native.NativeService, relayer common.Address) ([]uint64, error) {
+	contract := utils.RelayerManagerContractAddress
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(RELAYER_WORK_CHAINS), relayer[:]))
+	if err != nil {
+		return nil, fmt.Errorf("getRelayerWorkChains, get store error: %v", err)
+	}
+	if store == nil {
+		return nil, nil
+	}
+	data, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("getRelayerWorkChains, deserialize from raw storage item err:%v", err)
+	}
+	source := common.NewZeroCopySource(data)
+	n, eof := source.NextVarUint()
+	if eof {
+		return nil, fmt.Errorf("getRelayerWorkChains, deserialize length error")
+	}
+	chainIDs := make([]uint64, 0, n)
+	for i := uint64(0); i < n; i++ {
+		chainID, eof := source.NextUint64()
+		if eof {
+			return nil, fmt.Errorf("getRelayerWorkChains, deserialize chain id error")
+		}
+		chainIDs = append(chainIDs, chainID)
+	}
+	return chainIDs, nil
+}
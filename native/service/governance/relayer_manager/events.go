@@ -0,0 +1,45 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package relayer_manager
+
+import (
+	"github.com/polynetwork/poly/native/event"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+var (
+	approveRegisterRelayerEvent = event.RegisterEventSchema(utils.RelayerManagerContractAddress, "ApproveRegisterRelayer",
+		event.Field("ID", event.FIELD_UINT64))
+	approveRemoveRelayerEvent = event.RegisterEventSchema(utils.RelayerManagerContractAddress, "ApproveRemoveRelayer",
+		event.Field("ID", event.FIELD_UINT64))
+	putRelayerApplyEvent = event.RegisterEventSchema(utils.RelayerManagerContractAddress, "putRelayerApply",
+		event.Field("ID", event.FIELD_UINT64))
+	putRelayerRemoveEvent = event.RegisterEventSchema(utils.RelayerManagerContractAddress, "putRelayerRemove",
+		event.Field("ID", event.FIELD_UINT64))
+	setRelayerBondEvent = event.RegisterEventSchema(utils.RelayerManagerContractAddress, "SetRelayerBond",
+		event.Field("Relayer", event.FIELD_STRING),
+		event.Field("Bond", event.FIELD_STRING))
+	relayerMisbehavedEvent = event.RegisterEventSchema(utils.RelayerManagerContractAddress, "RelayerMisbehaved",
+		event.Field("Relayer", event.FIELD_STRING),
+		event.Field("Misbehavior", event.FIELD_STRING),
+		event.Field("Failures", event.FIELD_UINT64))
+	relayerSlashedEvent = event.RegisterEventSchema(utils.RelayerManagerContractAddress, "RelayerSlashed",
+		event.Field("Relayer", event.FIELD_STRING),
+		event.Field("Amount", event.FIELD_STRING),
+		event.Field("Failures", event.FIELD_UINT64))
+)
@@ -19,6 +19,8 @@ package relayer_manager
 
 import (
 	"fmt"
+	"math/big"
+
 	"github.com/polynetwork/poly/common"
 )
 
@@ -94,3 +96,119 @@ func (this *ApproveRelayerParam) Deserialization(source *common.ZeroCopySource)
 	this.Address = addr
 	return nil
 }
+
+type GetRelayerWorkParam struct {
+	Address common.Address
+	ChainID uint64 //0 for all the chains
+}
+
+func (this *GetRelayerWorkParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarBytes(this.Address[:])
+	sink.WriteVarUint(this.ChainID)
+}
+
+func (this *GetRelayerWorkParam) Deserialization(source *common.ZeroCopySource) error {
+	address, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("source.NextVarBytes, deserialize address error")
+	}
+	addr, err := common.AddressParseFromBytes(address)
+	if err != nil {
+		return fmt.Errorf("common.AddressParseFromBytes, deserialize address error: %s", err)
+	}
+	chainID, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("source.NextVarUint, deserialize ChainID error")
+	}
+	this.Address = addr
+	this.ChainID = chainID
+	return nil
+}
+
+type GetFeeScheduleParam struct {
+	ChainIDs []uint64
+}
+
+func (this *GetFeeScheduleParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(uint64(len(this.ChainIDs)))
+	for _, v := range this.ChainIDs {
+		sink.WriteVarUint(v)
+	}
+}
+
+func (this *GetFeeScheduleParam) Deserialization(source *common.ZeroCopySource) error {
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("source.NextVarUint, deserialize ChainIDs length error")
+	}
+	chainIDs := make([]uint64, 0)
+	for i := 0; uint64(i) < n; i++ {
+		chainID, eof := source.NextVarUint()
+		if eof {
+			return fmt.Errorf("source.NextVarUint, deserialize ChainID error")
+		}
+		chainIDs = append(chainIDs, chainID)
+	}
+	this.ChainIDs = chainIDs
+	return nil
+}
+
+type RelayerBondParam struct {
+	Relayer common.Address
+	Bond    *big.Int
+	Address common.Address
+}
+
+func (this *RelayerBondParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarBytes(this.Relayer[:])
+	sink.WriteVarBytes(this.Bond.Bytes())
+	sink.WriteVarBytes(this.Address[:])
+}
+
+func (this *RelayerBondParam) Deserialization(source *common.ZeroCopySource) error {
+	relayer, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("source.NextVarBytes, deserialize relayer error")
+	}
+	relayerAddr, err := common.AddressParseFromBytes(relayer)
+	if err != nil {
+		return fmt.Errorf("common.AddressParseFromBytes, deserialize relayer error: %s", err)
+	}
+	bond, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("source.NextVarBytes, deserialize bond error")
+	}
+	address, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("source.NextVarBytes, deserialize address error")
+	}
+	addr, err := common.AddressParseFromBytes(address)
+	if err != nil {
+		return fmt.Errorf("common.AddressParseFromBytes, deserialize address error: %s", err)
+	}
+	this.Relayer = relayerAddr
+	this.Bond = new(big.Int).SetBytes(bond)
+	this.Address = addr
+	return nil
+}
+
+type GetRelayerStatusParam struct {
+	Address common.Address
+}
+
+func (this *GetRelayerStatusParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarBytes(this.Address[:])
+}
+
+func (this *GetRelayerStatusParam) Deserialization(source *common.ZeroCopySource) error {
+	address, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("source.NextVarBytes, deserialize address error")
+	}
+	addr, err := common.AddressParseFromBytes(address)
+	if err != nil {
+		return fmt.Errorf("common.AddressParseFromBytes, deserialize address error: %s", err)
+	}
+	this.Address = addr
+	return nil
+}
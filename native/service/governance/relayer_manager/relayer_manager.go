@@ -19,11 +19,11 @@ package relayer_manager
 
 import (
 	"fmt"
-	"github.com/polynetwork/poly/native/event"
 
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/native"
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/utils"
 )
 
@@ -33,6 +33,10 @@ const (
 	APPROVE_REGISTER_RELAYER = "approveRegisterRelayer"
 	REMOVE_RELAYER           = "RemoveRelayer"
 	APPROVE_REMOVE_RELAYER   = "approveRemoveRelayer"
+	GET_RELAYER_WORK         = "getRelayerWork"
+	GET_FEE_SCHEDULE         = "getFeeSchedule"
+	SET_RELAYER_BOND         = "setRelayerBond"
+	GET_RELAYER_STATUS       = "getRelayerStatus"
 
 	//key prefix
 	RELAYER        = "relayer"
@@ -40,6 +44,10 @@ const (
 	RELAYER_REMOVE = "relayerRemove"
 	APPLY_ID       = "applyID"
 	REMOVE_ID      = "removeID"
+
+	RELAYER_WORK        = "relayerWork"
+	RELAYER_WORK_CHAINS = "relayerWorkChains"
+	RELAYER_STATUS      = "relayerStatus"
 )
 
 //Register methods of node_manager contract
@@ -48,6 +56,10 @@ func RegisterRelayerManagerContract(native *native.NativeService) {
 	native.Register(APPROVE_REGISTER_RELAYER, ApproveRegisterRelayer)
 	native.Register(REMOVE_RELAYER, RemoveRelayer)
 	native.Register(APPROVE_REMOVE_RELAYER, ApproveRemoveRelayer)
+	native.Register(GET_RELAYER_WORK, GetRelayerWork)
+	native.Register(GET_FEE_SCHEDULE, GetFeeSchedule)
+	native.Register(SET_RELAYER_BOND, SetRelayerBond)
+	native.Register(GET_RELAYER_STATUS, GetRelayerStatus)
 }
 
 func RegisterRelayer(native *native.NativeService) ([]byte, error) {
@@ -98,11 +110,7 @@ func ApproveRegisterRelayer(native *native.NativeService) ([]byte, error) {
 		}
 	}
 	native.GetCacheDB().Delete(utils.ConcatKey(utils.RelayerManagerContractAddress, []byte(RELAYER_APPLY), utils.GetUint64Bytes(params.ID)))
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.RelayerManagerContractAddress,
-			States:          []interface{}{"ApproveRegisterRelayer", params.ID},
-		})
+	native.AddNotify(approveRegisterRelayerEvent.NewNotify(params.ID))
 	return utils.BYTE_TRUE, nil
 }
 
@@ -151,10 +159,52 @@ func ApproveRemoveRelayer(native *native.NativeService) ([]byte, error) {
 	for _, address := range relayerListParam.AddressList {
 		native.GetCacheDB().Delete(utils.ConcatKey(utils.RelayerManagerContractAddress, []byte(RELAYER), address[:]))
 	}
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.RelayerManagerContractAddress,
-			States:          []interface{}{"ApproveRemoveRelayer", params.ID},
-		})
+	native.AddNotify(approveRemoveRelayerEvent.NewNotify(params.ID))
 	return utils.BYTE_TRUE, nil
 }
+
+//GetRelayerWork returns the work done by the relayer for the chain, or for all the chains if chain id is 0
+func GetRelayerWork(native *native.NativeService) ([]byte, error) {
+	params := new(GetRelayerWorkParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("GetRelayerWork, contract params deserialize error: %v", err)
+	}
+	chainIDs := []uint64{params.ChainID}
+	if params.ChainID == 0 {
+		var err error
+		chainIDs, err = getRelayerWorkChains(native, params.Address)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("GetRelayerWork, %v", err)
+		}
+	}
+	list := &RelayerWorkList{List: make([]*RelayerWork, 0, len(chainIDs))}
+	for _, chainID := range chainIDs {
+		work, err := getRelayerWork(native, params.Address, chainID)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("GetRelayerWork, %v", err)
+		}
+		list.List = append(list.List, work)
+	}
+	sink := common.NewZeroCopySink(nil)
+	list.Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+//GetFeeSchedule returns the fee credited for a proof targeting each of the chains
+func GetFeeSchedule(native *native.NativeService) ([]byte, error) {
+	params := new(GetFeeScheduleParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("GetFeeSchedule, contract params deserialize error: %v", err)
+	}
+	schedule := &FeeSchedule{Items: make([]*FeeScheduleItem, 0, len(params.ChainIDs))}
+	for _, chainID := range params.ChainIDs {
+		fee, err := side_chain_manager.GetFee(native, chainID)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("GetFeeSchedule, %v", err)
+		}
+		schedule.Items = append(schedule.Items, &FeeScheduleItem{ChainID: chainID, View: fee.View, Fee: fee.Fee})
+	}
+	sink := common.NewZeroCopySink(nil)
+	schedule.Serialization(sink)
+	return sink.Bytes(), nil
+}
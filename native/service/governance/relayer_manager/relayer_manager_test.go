@@ -17,9 +17,11 @@
 package relayer_manager
 
 import (
+	"fmt"
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/account"
 	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
 	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
 	"github.com/polynetwork/poly/core/genesis"
 	cstates "github.com/polynetwork/poly/core/states"
@@ -27,10 +29,16 @@ import (
 	"github.com/polynetwork/poly/core/store/overlaydb"
 	"github.com/polynetwork/poly/core/types"
 	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/event"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
 	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/states"
 	"github.com/polynetwork/poly/native/storage"
 	"github.com/stretchr/testify/assert"
+	"math/big"
 	"strconv"
 	"testing"
 )
@@ -147,6 +155,9 @@ func TestRegisterRelayer(t *testing.T) {
 		res, err := ApproveRegisterRelayer(nativeService)
 		assert.Nil(t, err)
 		assert.Equal(t, utils.BYTE_TRUE, res)
+		for _, notify := range nativeService.GetNotify() {
+			assert.Nil(t, event.ValidateNotify(notify))
+		}
 		if i < (2*len(conAccts())+2)/3 {
 			ok, err := node_manager.CheckConsensusSigns(nativeService, APPROVE_REGISTER_RELAYER, utils.GetUint64Bytes(0), conAcct.Address)
 			assert.Nil(t, err)
@@ -197,3 +208,124 @@ func TestRemoveRelayer(t *testing.T) {
 		}
 	}
 }
+
+func TestRelayerWork(t *testing.T) {
+	relayer := common.Address{1, 2, 3}
+	nativeService = NewNative(nil, &types.Transaction{SignedAddr: []common.Address{acct.Address, relayer}}, nil)
+	putRelayer(nativeService, relayer)
+	side_chain_manager.PutFee(nativeService, 3, &side_chain_manager.Fee{View: 1, Fee: big.NewInt(100)})
+
+	assert.Nil(t, RecordHeaderSync(nativeService, 2, 5))
+	assert.Nil(t, RecordHeaderSync(nativeService, 2, 3))
+	assert.Nil(t, RecordCrossChainProof(nativeService, 2, 3))
+	assert.Nil(t, RecordCrossChainProof(nativeService, 3, 2))
+
+	//signer which is not a relayer is not recorded
+	other := NewNative(nil, &types.Transaction{SignedAddr: []common.Address{acct.Address}}, nativeService.GetCacheDB())
+	assert.Nil(t, RecordHeaderSync(other, 2, 1))
+
+	sink := common.NewZeroCopySink(nil)
+	(&GetRelayerWorkParam{Address: relayer}).Serialization(sink)
+	res, err := GetRelayerWork(NewNative(sink.Bytes(), new(types.Transaction), nativeService.GetCacheDB()))
+	assert.Nil(t, err)
+	list := new(RelayerWorkList)
+	assert.Nil(t, list.Deserialization(common.NewZeroCopySource(res)))
+	assert.Equal(t, []*RelayerWork{
+		{Address: relayer, ChainID: 2, HeaderSyncs: 2, Headers: 8, Proofs: 1, Fee: big.NewInt(100)},
+		{Address: relayer, ChainID: 3, Proofs: 1, Fee: new(big.Int)},
+	}, list.List)
+
+	sink = common.NewZeroCopySink(nil)
+	(&GetFeeScheduleParam{ChainIDs: []uint64{3, 4}}).Serialization(sink)
+	res, err = GetFeeSchedule(NewNative(sink.Bytes(), new(types.Transaction), nativeService.GetCacheDB()))
+	assert.Nil(t, err)
+	schedule := new(FeeSchedule)
+	assert.Nil(t, schedule.Deserialization(common.NewZeroCopySource(res)))
+	assert.Equal(t, []*FeeScheduleItem{
+		{ChainID: 3, View: 1, Fee: big.NewInt(100)},
+		{ChainID: 4, Fee: new(big.Int)},
+	}, schedule.Items)
+}
+
+func TestRelayerSlashing(t *testing.T) {
+	networkID := config.DefConfig.P2PNode.NetworkId
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+	defer func() { config.DefConfig.P2PNode.NetworkId = networkID }()
+
+	relayer := common.Address{4, 5, 6}
+	accts := conAccts()
+	nativeService = NewNative(nil, new(types.Transaction), nil)
+	putPeerMapPoolAndView(nativeService.GetCacheDB(), accts)
+	putRelayer(nativeService, relayer)
+
+	setBond := func(bond *big.Int) {
+		for _, conAcct := range accts {
+			sink := common.NewZeroCopySink(nil)
+			(&RelayerBondParam{Relayer: relayer, Bond: bond, Address: conAcct.Address}).Serialization(sink)
+			tx := &types.Transaction{SignedAddr: []common.Address{conAcct.Address}}
+			res, err := SetRelayerBond(NewNative(sink.Bytes(), tx, nativeService.GetCacheDB()))
+			assert.Nil(t, err)
+			assert.Equal(t, utils.BYTE_TRUE, res)
+		}
+	}
+	getStatus := func() *RelayerStatus {
+		sink := common.NewZeroCopySink(nil)
+		(&GetRelayerStatusParam{Address: relayer}).Serialization(sink)
+		res, err := GetRelayerStatus(NewNative(sink.Bytes(), new(types.Transaction), nativeService.GetCacheDB()))
+		assert.Nil(t, err)
+		status := new(RelayerStatus)
+		assert.Nil(t, status.Deserialization(common.NewZeroCopySource(res)))
+		return status
+	}
+	recordFailure := func(contract common.Address, method string, misbehavior error) []byte {
+		sink := common.NewZeroCopySink(nil)
+		(&states.ContractInvokeParam{Address: contract, Method: method}).Serialization(sink)
+		tx := &types.Transaction{SignedAddr: []common.Address{relayer}}
+		ns := NewNative(sink.Bytes(), tx, nativeService.GetCacheDB())
+		if misbehavior != nil {
+			ns.Misbehave(misbehavior)
+		}
+		res, err := RecordFailedTx(ns)
+		assert.Nil(t, err)
+		return res
+	}
+	recordFailedTx := func(contract common.Address, method string) []byte {
+		return recordFailure(contract, method, fmt.Errorf("invalid signer"))
+	}
+	checkActive := func() error {
+		return CheckRelayerActive(NewNative(nil, &types.Transaction{SignedAddr: []common.Address{relayer}}, nativeService.GetCacheDB()))
+	}
+
+	setBond(big.NewInt(1000))
+	assert.Equal(t, big.NewInt(1000), getStatus().Bond)
+
+	//failures without a misbehavior, like a duplicate header, are not counted
+	assert.Equal(t, utils.BYTE_FALSE, recordFailure(utils.HeaderSyncContractAddress, hscommon.SYNC_BLOCK_HEADER, nil))
+	assert.Equal(t, uint64(0), getStatus().Failures)
+
+	for i := uint64(1); i < MAX_RELAYER_FAILURES; i++ {
+		assert.Equal(t, utils.BYTE_TRUE, recordFailedTx(utils.HeaderSyncContractAddress, hscommon.SYNC_BLOCK_HEADER))
+	}
+	//failures of the other methods are not counted
+	assert.Equal(t, utils.BYTE_FALSE, recordFailedTx(utils.HeaderSyncContractAddress, hscommon.SYNC_GENESIS_HEADER))
+	status := getStatus()
+	assert.Equal(t, MAX_RELAYER_FAILURES-1, status.Failures)
+	assert.False(t, status.Deactivated)
+
+	assert.Equal(t, utils.BYTE_TRUE, recordFailedTx(utils.CrossChainManagerContractAddress, scom.IMPORT_OUTER_TRANSFER_NAME))
+	status = getStatus()
+	assert.True(t, status.Deactivated)
+	assert.Equal(t, big.NewInt(900), status.Bond)
+	assert.Equal(t, big.NewInt(100), status.Slashed)
+	assert.Error(t, checkActive())
+	assert.Nil(t, CheckRelayerActive(NewNative(nil, &types.Transaction{SignedAddr: []common.Address{accts[0].Address}}, nativeService.GetCacheDB())))
+
+	//a new bond reactivates the relayer
+	setBond(big.NewInt(500))
+	status = getStatus()
+	assert.False(t, status.Deactivated)
+	assert.Equal(t, uint64(0), status.Failures)
+	assert.Equal(t, big.NewInt(500), status.Bond)
+	assert.Equal(t, big.NewInt(100), status.Slashed)
+	assert.Nil(t, checkActive())
+}
@@ -0,0 +1,223 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package relayer_manager
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/polynetwork/poly/common"
+	cstates "github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/fork_manager"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/states"
+)
+
+const (
+	//misbehaviors a relayer may commit in a failure window before it is slashed
+	MAX_RELAYER_FAILURES uint64 = 20
+	//length of a failure window in poly blocks
+	RELAYER_FAILURE_WINDOW uint32 = 2000
+	//percentage of the bond slashed on deactivation
+	SLASH_PERCENT int64 = 10
+)
+
+//SetRelayerBond records the bond posted by a relayer once approved by the consensus nodes,
+//a deactivated relayer is reactivated with the new bond
+func SetRelayerBond(native *native.NativeService) ([]byte, error) {
+	params := new(RelayerBondParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetRelayerBond, contract params deserialize error: %v", err)
+	}
+	//check witness
+	if err := utils.ValidateOwner(native, params.Address); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetRelayerBond, checkWitness error: %v", err)
+	}
+	ok, err := isRelayer(native, params.Relayer)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetRelayerBond, %v", err)
+	}
+	if !ok {
+		return utils.BYTE_FALSE, fmt.Errorf("SetRelayerBond, %s is not a registered relayer", params.Relayer.ToBase58())
+	}
+
+	//check consensus signs
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteVarBytes(params.Relayer[:])
+	sink.WriteVarBytes(params.Bond.Bytes())
+	ok, err = node_manager.CheckConsensusSigns(native, SET_RELAYER_BOND, sink.Bytes(), params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetRelayerBond, CheckConsensusSigns error: %v", err)
+	}
+	if !ok {
+		return utils.BYTE_TRUE, nil
+	}
+
+	status, err := getRelayerStatus(native, params.Relayer)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetRelayerBond, %v", err)
+	}
+	status.Bond = params.Bond
+	status.Failures = 0
+	status.WindowStart = native.GetHeight()
+	status.Deactivated = false
+	status.DeactivatedHeight = 0
+	putRelayerStatus(native, status)
+	native.AddNotify(setRelayerBondEvent.NewNotify(params.Relayer.ToBase58(), params.Bond.String()))
+	return utils.BYTE_TRUE, nil
+}
+
+//GetRelayerStatus returns the bond and failure record of the relayer
+func GetRelayerStatus(native *native.NativeService) ([]byte, error) {
+	params := new(GetRelayerStatusParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("GetRelayerStatus, contract params deserialize error: %v", err)
+	}
+	status, err := getRelayerStatus(native, params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("GetRelayerStatus, %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	status.Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+//RecordFailedTx is the failed tx handler of the header sync and cross chain manager contracts, a SyncBlockHeader
+//or ImportOuterTransfer failed by a provable misbehavior, such as an invalid seal or proof or a conflicting header,
+//is counted against the relayer signing the tx, which is slashed and deactivated once it commits MAX_RELAYER_FAILURES
+//misbehaviors in a failure window. Failures like duplicate submissions or unconfirmed txs are not counted.
+func RecordFailedTx(native *native.NativeService) ([]byte, error) {
+	misbehavior := native.GetMisbehavior()
+	if misbehavior == nil {
+		return utils.BYTE_FALSE, nil
+	}
+	forkHeight, err := fork_manager.GetForkHeight(native, fork_manager.RELAYER_SLASHING)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("RecordFailedTx, %v", err)
+	}
+	if uint64(native.GetHeight()) < forkHeight {
+		return utils.BYTE_FALSE, nil
+	}
+	invokeParam := new(states.ContractInvokeParam)
+	if err := invokeParam.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("RecordFailedTx, deserialize invoke param error: %v", err)
+	}
+	if !isSlashableMethod(invokeParam.Address, invokeParam.Method) {
+		return utils.BYTE_FALSE, nil
+	}
+	relayer, ok, err := getTxRelayer(native)
+	if err != nil || !ok {
+		return utils.BYTE_FALSE, err
+	}
+	status, err := getRelayerStatus(native, relayer)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("RecordFailedTx, %v", err)
+	}
+	if status.Deactivated {
+		return utils.BYTE_FALSE, nil
+	}
+	height := native.GetHeight()
+	if status.Failures == 0 || height-status.WindowStart >= RELAYER_FAILURE_WINDOW {
+		status.Failures = 0
+		status.WindowStart = height
+	}
+	status.Failures++
+	native.AddNotify(relayerMisbehavedEvent.NewNotify(relayer.ToBase58(), misbehavior.Error(), status.Failures))
+	if status.Failures >= MAX_RELAYER_FAILURES {
+		amount := new(big.Int).Div(new(big.Int).Mul(status.Bond, big.NewInt(SLASH_PERCENT)), big.NewInt(100))
+		status.Bond = new(big.Int).Sub(status.Bond, amount)
+		status.Slashed = new(big.Int).Add(status.Slashed, amount)
+		status.Deactivated = true
+		status.DeactivatedHeight = height
+		native.AddNotify(relayerSlashedEvent.NewNotify(relayer.ToBase58(), amount.String(), status.Failures))
+	}
+	putRelayerStatus(native, status)
+	return utils.BYTE_TRUE, nil
+}
+
+//CheckRelayerActive returns error if a registered relayer signing the tx has been deactivated by slashing,
+//the header sync and cross chain manager contracts reject the txs of deactivated relayers
+func CheckRelayerActive(native *native.NativeService) error {
+	addresses, err := native.GetTx().GetSignatureAddresses()
+	if err != nil {
+		return fmt.Errorf("CheckRelayerActive, GetSignatureAddresses error: %v", err)
+	}
+	for _, address := range addresses {
+		ok, err := isRelayer(native, address)
+		if err != nil {
+			return fmt.Errorf("CheckRelayerActive, %v", err)
+		}
+		if !ok {
+			continue
+		}
+		status, err := getRelayerStatus(native, address)
+		if err != nil {
+			return fmt.Errorf("CheckRelayerActive, %v", err)
+		}
+		if status.Deactivated {
+			return fmt.Errorf("CheckRelayerActive, relayer %s is deactivated at height %d", address.ToBase58(), status.DeactivatedHeight)
+		}
+	}
+	return nil
+}
+
+//isSlashableMethod returns true for the relayer methods whose failures are counted
+func isSlashableMethod(contract common.Address, method string) bool {
+	switch contract {
+	case utils.HeaderSyncContractAddress:
+		return method == hscommon.SYNC_BLOCK_HEADER
+	case utils.CrossChainManagerContractAddress:
+		return method == scom.IMPORT_OUTER_TRANSFER_NAME
+	}
+	return false
+}
+
+func getRelayerStatus(native *native.NativeService, relayer common.Address) (*RelayerStatus, error) {
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(utils.RelayerManagerContractAddress, []byte(RELAYER_STATUS), relayer[:]))
+	if err != nil {
+		return nil, fmt.Errorf("getRelayerStatus, get relayer status store error: %v", err)
+	}
+	status := &RelayerStatus{
+		Address: relayer,
+		Bond:    new(big.Int),
+		Slashed: new(big.Int),
+	}
+	if store == nil {
+		return status, nil
+	}
+	statusBytes, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("getRelayerStatus, deserialize from raw storage item err:%v", err)
+	}
+	if err := status.Deserialization(common.NewZeroCopySource(statusBytes)); err != nil {
+		return nil, fmt.Errorf("getRelayerStatus, deserialize relayer status err:%v", err)
+	}
+	return status, nil
+}
+
+func putRelayerStatus(native *native.NativeService, status *RelayerStatus) {
+	sink := common.NewZeroCopySink(nil)
+	status.Serialization(sink)
+	native.GetCacheDB().Put(utils.ConcatKey(utils.RelayerManagerContractAddress, []byte(RELAYER_STATUS), status.Address[:]),
+		cstates.GenRawStorageItem(sink.Bytes()))
+}
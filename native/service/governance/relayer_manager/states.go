@@ -0,0 +1,227 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package relayer_manager
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/polynetwork/poly/common"
+)
+
+//RelayerWork records the work done by a relayer for a side chain
+type RelayerWork struct {
+	Address     common.Address
+	ChainID     uint64
+	HeaderSyncs uint64   //count of successful SyncBlockHeader calls
+	Headers     uint64   //count of headers submitted in the SyncBlockHeader calls
+	Proofs      uint64   //count of successful ImportOuterTransfer calls
+	Fee         *big.Int //fee accrued by the proofs according to the fee schedule
+	LastHeight  uint32   //poly height of the latest work
+}
+
+func (this *RelayerWork) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarBytes(this.Address[:])
+	sink.WriteUint64(this.ChainID)
+	sink.WriteUint64(this.HeaderSyncs)
+	sink.WriteUint64(this.Headers)
+	sink.WriteUint64(this.Proofs)
+	sink.WriteVarBytes(this.Fee.Bytes())
+	sink.WriteUint32(this.LastHeight)
+}
+
+func (this *RelayerWork) Deserialization(source *common.ZeroCopySource) error {
+	address, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("RelayerWork deserialize address error")
+	}
+	addr, err := common.AddressParseFromBytes(address)
+	if err != nil {
+		return fmt.Errorf("RelayerWork deserialize address error: %s", err)
+	}
+	chainID, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("RelayerWork deserialize chain id error")
+	}
+	headerSyncs, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("RelayerWork deserialize header syncs error")
+	}
+	headers, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("RelayerWork deserialize headers error")
+	}
+	proofs, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("RelayerWork deserialize proofs error")
+	}
+	fee, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("RelayerWork deserialize fee error")
+	}
+	lastHeight, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("RelayerWork deserialize last height error")
+	}
+	this.Address = addr
+	this.ChainID = chainID
+	this.HeaderSyncs = headerSyncs
+	this.Headers = headers
+	this.Proofs = proofs
+	this.Fee = new(big.Int).SetBytes(fee)
+	this.LastHeight = lastHeight
+	return nil
+}
+
+type RelayerWorkList struct {
+	List []*RelayerWork
+}
+
+func (this *RelayerWorkList) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(uint64(len(this.List)))
+	for _, v := range this.List {
+		v.Serialization(sink)
+	}
+}
+
+func (this *RelayerWorkList) Deserialization(source *common.ZeroCopySource) error {
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("RelayerWorkList deserialize length error")
+	}
+	list := make([]*RelayerWork, 0, n)
+	for i := uint64(0); i < n; i++ {
+		work := new(RelayerWork)
+		if err := work.Deserialization(source); err != nil {
+			return fmt.Errorf("RelayerWorkList deserialize no.%d work error: %v", i+1, err)
+		}
+		list = append(list, work)
+	}
+	this.List = list
+	return nil
+}
+
+//FeeScheduleItem is the fee credited for a proof targeting the chain, it is the median of the UpdateFee votes
+type FeeScheduleItem struct {
+	ChainID uint64
+	View    uint64
+	Fee     *big.Int
+}
+
+type FeeSchedule struct {
+	Items []*FeeScheduleItem
+}
+
+func (this *FeeSchedule) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(uint64(len(this.Items)))
+	for _, v := range this.Items {
+		sink.WriteUint64(v.ChainID)
+		sink.WriteUint64(v.View)
+		sink.WriteVarBytes(v.Fee.Bytes())
+	}
+}
+
+func (this *FeeSchedule) Deserialization(source *common.ZeroCopySource) error {
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("FeeSchedule deserialize length error")
+	}
+	items := make([]*FeeScheduleItem, 0, n)
+	for i := uint64(0); i < n; i++ {
+		chainID, eof := source.NextUint64()
+		if eof {
+			return fmt.Errorf("FeeSchedule deserialize no.%d chain id error", i+1)
+		}
+		view, eof := source.NextUint64()
+		if eof {
+			return fmt.Errorf("FeeSchedule deserialize no.%d view error", i+1)
+		}
+		fee, eof := source.NextVarBytes()
+		if eof {
+			return fmt.Errorf("FeeSchedule deserialize no.%d fee error", i+1)
+		}
+		items = append(items, &FeeScheduleItem{ChainID: chainID, View: view, Fee: new(big.Int).SetBytes(fee)})
+	}
+	this.Items = items
+	return nil
+}
+
+//RelayerStatus records the bond of a relayer and the failed txs it has sent
+type RelayerStatus struct {
+	Address           common.Address
+	Bond              *big.Int //bond currently held
+	Slashed           *big.Int //total bond slashed
+	Failures          uint64   //count of failed txs in the current failure window
+	WindowStart       uint32   //poly height the current failure window starts at
+	Deactivated       bool
+	DeactivatedHeight uint32
+}
+
+func (this *RelayerStatus) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarBytes(this.Address[:])
+	sink.WriteVarBytes(this.Bond.Bytes())
+	sink.WriteVarBytes(this.Slashed.Bytes())
+	sink.WriteUint64(this.Failures)
+	sink.WriteUint32(this.WindowStart)
+	sink.WriteBool(this.Deactivated)
+	sink.WriteUint32(this.DeactivatedHeight)
+}
+
+func (this *RelayerStatus) Deserialization(source *common.ZeroCopySource) error {
+	address, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("RelayerStatus deserialize address error")
+	}
+	addr, err := common.AddressParseFromBytes(address)
+	if err != nil {
+		return fmt.Errorf("RelayerStatus deserialize address error: %s", err)
+	}
+	bond, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("RelayerStatus deserialize bond error")
+	}
+	slashed, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("RelayerStatus deserialize slashed error")
+	}
+	failures, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("RelayerStatus deserialize failures error")
+	}
+	windowStart, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("RelayerStatus deserialize window start error")
+	}
+	deactivated, eof := source.NextBool()
+	if eof {
+		return fmt.Errorf("RelayerStatus deserialize deactivated error")
+	}
+	deactivatedHeight, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("RelayerStatus deserialize deactivated height error")
+	}
+	this.Address = addr
+	this.Bond = new(big.Int).SetBytes(bond)
+	this.Slashed = new(big.Int).SetBytes(slashed)
+	this.Failures = failures
+	this.WindowStart = windowStart
+	this.Deactivated = deactivated
+	this.DeactivatedHeight = deactivatedHeight
+	return nil
+}
@@ -23,7 +23,6 @@ import (
 	"github.com/polynetwork/poly/common"
 	cstates "github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/native"
-	"github.com/polynetwork/poly/native/event"
 	"github.com/polynetwork/poly/native/service/utils"
 )
 
@@ -48,11 +47,7 @@ func putRelayerApply(native *native.NativeService, relayerListParam *RelayerList
 	relayerListParam.Serialization(sink)
 	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(RELAYER_APPLY), utils.GetUint64Bytes(applyID)),
 		cstates.GenRawStorageItem(sink.Bytes()))
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.NodeManagerContractAddress,
-			States:          []interface{}{"putRelayerApply", applyID},
-		})
+	native.AddNotify(putRelayerApplyEvent.NewNotify(applyID))
 	return nil
 }
 
@@ -117,11 +112,7 @@ func putRelayerRemove(native *native.NativeService, relayerListParam *RelayerLis
 	relayerListParam.Serialization(sink)
 	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(RELAYER_REMOVE), utils.GetUint64Bytes(removeID)),
 		cstates.GenRawStorageItem(sink.Bytes()))
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.NodeManagerContractAddress,
-			States:          []interface{}{"putRelayerRemove", removeID},
-		})
+	native.AddNotify(putRelayerRemoveEvent.NewNotify(removeID))
 	return nil
 }
 
@@ -19,7 +19,6 @@ package replenish
 
 import (
 	"fmt"
-	"github.com/polynetwork/poly/native/event"
 
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/native"
@@ -42,10 +41,6 @@ func ReplenishTx(native *native.NativeService) ([]byte, error) {
 		return utils.BYTE_FALSE, fmt.Errorf("ReplenishTx, contract params deserialize error: %v", err)
 	}
 
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.ReplenishContractAddress,
-			States:          []interface{}{"ReplenishTx", params.TxHashes, params.ChainId},
-		})
+	native.AddNotify(replenishTxEvent.NewNotify(params.TxHashes, params.ChainId))
 	return utils.BYTE_TRUE, nil
 }
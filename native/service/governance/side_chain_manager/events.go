@@ -0,0 +1,52 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package side_chain_manager
+
+import (
+	"github.com/polynetwork/poly/native/event"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+var (
+	registerSideChainEvent = event.RegisterEventSchema(utils.SideChainManagerContractAddress, "RegisterSideChain",
+		event.Field("ChainID", event.FIELD_UINT64),
+		event.Field("Router", event.FIELD_UINT64),
+		event.Field("Name", event.FIELD_STRING),
+		event.Field("BlocksToWait", event.FIELD_UINT64))
+	approveRegisterSideChainEvent = event.RegisterEventSchema(utils.SideChainManagerContractAddress, "ApproveRegisterSideChain",
+		event.Field("ChainID", event.FIELD_UINT64))
+	updateSideChainEvent = event.RegisterEventSchema(utils.SideChainManagerContractAddress, "UpdateSideChain",
+		event.Field("ChainID", event.FIELD_UINT64),
+		event.Field("Router", event.FIELD_UINT64),
+		event.Field("Name", event.FIELD_STRING),
+		event.Field("BlocksToWait", event.FIELD_UINT64))
+	approveUpdateSideChainEvent = event.RegisterEventSchema(utils.SideChainManagerContractAddress, "ApproveUpdateSideChain",
+		event.Field("ChainID", event.FIELD_UINT64))
+	quitSideChainEvent = event.RegisterEventSchema(utils.SideChainManagerContractAddress, "QuitSideChain",
+		event.Field("ChainID", event.FIELD_UINT64))
+	approveQuitSideChainEvent = event.RegisterEventSchema(utils.SideChainManagerContractAddress, "ApproveQuitSideChain",
+		event.Field("ChainID", event.FIELD_UINT64))
+	registerRedeemEvent = event.RegisterEventSchema(utils.SideChainManagerContractAddress, "RegisterRedeem",
+		event.Field("RedeemKey", event.FIELD_HEX),
+		event.Field("ContractAddress", event.FIELD_HEX))
+	setBtcTxParamEvent = event.RegisterEventSchema(utils.SideChainManagerContractAddress, "SetBtcTxParam",
+		event.Field("RedeemKey", event.FIELD_HEX),
+		event.Field("RedeemChainID", event.FIELD_UINT64),
+		event.Field("FeeRate", event.FIELD_UINT64),
+		event.Field("MinChange", event.FIELD_UINT64))
+)
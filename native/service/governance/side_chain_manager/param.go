@@ -22,8 +22,6 @@ import (
 	"sort"
 
 	"github.com/polynetwork/poly/common"
-	"github.com/polynetwork/poly/common/config"
-	"github.com/polynetwork/poly/core/ledger"
 )
 
 type RegisterSideChainParam struct {
@@ -44,8 +42,11 @@ func (this *RegisterSideChainParam) Serialization(sink *common.ZeroCopySink) err
 	sink.WriteVarUint(this.BlocksToWait)
 	sink.WriteVarBytes(this.CCMCAddress)
 
-	height := config.GetExtraInfoHeight(config.DefConfig.P2PNode.NetworkId)
-	if !config.EXTRA_INFO_HEIGHT_FORK_CHECK || ledger.DefLedger.GetCurrentBlockHeight() >= height {
+	withExtraInfo, err := isExtraInfoActive()
+	if err != nil {
+		return fmt.Errorf("isExtraInfoActive error: %v", err)
+	}
+	if withExtraInfo {
 		sink.WriteVarBytes(this.ExtraInfo)
 	}
 
@@ -29,7 +29,6 @@ import (
 	"github.com/btcsuite/btcutil"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/native"
-	"github.com/polynetwork/poly/native/event"
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
 	"github.com/polynetwork/poly/native/service/utils"
 )
@@ -117,11 +116,7 @@ func RegisterSideChain(native *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("RegisterSideChain, putRegisterSideChain error: %v", err)
 	}
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.NodeManagerContractAddress,
-			States:          []interface{}{"RegisterSideChain", params.ChainId, params.Router, params.Name, params.BlocksToWait},
-		})
+	native.AddNotify(registerSideChainEvent.NewNotify(params.ChainId, params.Router, params.Name, params.BlocksToWait))
 	return utils.BYTE_TRUE, nil
 }
 
@@ -160,11 +155,7 @@ func ApproveRegisterSideChain(native *native.NativeService) ([]byte, error) {
 		return utils.BYTE_FALSE, fmt.Errorf("ApproveRegisterSideChain, putSideChain error: %v", err)
 	}
 	native.GetCacheDB().Delete(utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(SIDE_CHAIN_APPLY), utils.GetUint64Bytes(params.Chainid)))
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.NodeManagerContractAddress,
-			States:          []interface{}{"ApproveRegisterSideChain", params.Chainid},
-		})
+	native.AddNotify(approveRegisterSideChainEvent.NewNotify(params.Chainid))
 	return utils.BYTE_TRUE, nil
 }
 
@@ -203,11 +194,7 @@ func UpdateSideChain(native *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("UpdateSideChain, putUpdateSideChain error: %v", err)
 	}
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.NodeManagerContractAddress,
-			States:          []interface{}{"UpdateSideChain", params.ChainId, params.Router, params.Name, params.BlocksToWait},
-		})
+	native.AddNotify(updateSideChainEvent.NewNotify(params.ChainId, params.Router, params.Name, params.BlocksToWait))
 	return utils.BYTE_TRUE, nil
 }
 
@@ -247,11 +234,7 @@ func ApproveUpdateSideChain(native *native.NativeService) ([]byte, error) {
 	}
 	chainidByte := utils.GetUint64Bytes(params.Chainid)
 	native.GetCacheDB().Delete(utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(UPDATE_SIDE_CHAIN_REQUEST), chainidByte))
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.NodeManagerContractAddress,
-			States:          []interface{}{"ApproveUpdateSideChain", params.Chainid},
-		})
+	native.AddNotify(approveUpdateSideChainEvent.NewNotify(params.Chainid))
 	return utils.BYTE_TRUE, nil
 }
 
@@ -282,11 +265,7 @@ func QuitSideChain(native *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("QuitSideChain, putUpdateSideChain error: %v", err)
 	}
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.NodeManagerContractAddress,
-			States:          []interface{}{"QuitSideChain", params.Chainid},
-		})
+	native.AddNotify(quitSideChainEvent.NewNotify(params.Chainid))
 	return utils.BYTE_TRUE, nil
 }
 
@@ -320,11 +299,7 @@ func ApproveQuitSideChain(native *native.NativeService) ([]byte, error) {
 	chainidByte := utils.GetUint64Bytes(params.Chainid)
 	native.GetCacheDB().Delete(utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(QUIT_SIDE_CHAIN), chainidByte))
 	native.GetCacheDB().Delete(utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(SIDE_CHAIN), chainidByte))
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.NodeManagerContractAddress,
-			States:          []interface{}{"ApproveQuitSideChain", params.Chainid},
-		})
+	native.AddNotify(approveQuitSideChainEvent.NewNotify(params.Chainid))
 	return utils.BYTE_TRUE, nil
 }
 
@@ -374,11 +349,8 @@ func RegisterRedeem(native *native.NativeService) ([]byte, error) {
 		if err = putBtcRedeemScript(native, hex.EncodeToString(rk), params.Redeem, params.RedeemChainID); err != nil {
 			return utils.BYTE_FALSE, fmt.Errorf("RegisterRedeem, failed to save redeemscript %v with key %v, error: %v", hex.EncodeToString(params.Redeem), rk, err)
 		}
-		native.AddNotify(
-			&event.NotifyEventInfo{
-				ContractAddress: utils.SideChainManagerContractAddress,
-				States:          []interface{}{"RegisterRedeem", hex.EncodeToString(rk), hex.EncodeToString(params.ContractAddress)},
-			})
+		native.AddNotify(registerRedeemEvent.NewNotify(hex.EncodeToString(rk),
+			hex.EncodeToString(params.ContractAddress)))
 	}
 
 	return utils.BYTE_TRUE, nil
@@ -435,12 +407,8 @@ func SetBtcTxParam(native *native.NativeService) ([]byte, error) {
 		if err = putBtcTxParam(native, rk, params.RedeemChainId, params.Detial); err != nil {
 			return utils.BYTE_FALSE, fmt.Errorf("SetBtcTxParam, failed to put btcTxParam: %v", err)
 		}
-		native.AddNotify(
-			&event.NotifyEventInfo{
-				ContractAddress: utils.SideChainManagerContractAddress,
-				States: []interface{}{"SetBtcTxParam", hex.EncodeToString(rk), params.RedeemChainId,
-					params.Detial.FeeRate, params.Detial.MinChange},
-			})
+		native.AddNotify(setBtcTxParamEvent.NewNotify(hex.EncodeToString(rk), params.RedeemChainId,
+			params.Detial.FeeRate, params.Detial.MinChange))
 	}
 	return utils.BYTE_TRUE, nil
 }
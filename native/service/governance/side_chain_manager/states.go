@@ -25,6 +25,7 @@ import (
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/core/ledger"
+	"github.com/polynetwork/poly/native/service/governance/fork_manager"
 )
 
 type SideChain struct {
@@ -37,6 +38,18 @@ type SideChain struct {
 	ExtraInfo    []byte
 }
 
+// isExtraInfoActive checks the extra info fork against the committed ledger height
+func isExtraInfoActive() (bool, error) {
+	if !config.EXTRA_INFO_HEIGHT_FORK_CHECK {
+		return true, nil
+	}
+	height, err := fork_manager.GetCommittedForkHeight(fork_manager.EXTRA_INFO)
+	if err != nil {
+		return false, err
+	}
+	return uint64(ledger.DefLedger.GetCurrentBlockHeight()) >= height, nil
+}
+
 func (this *SideChain) Serialization(sink *common.ZeroCopySink) error {
 	sink.WriteVarBytes(this.Address[:])
 	sink.WriteVarUint(this.ChainId)
@@ -44,8 +57,11 @@ func (this *SideChain) Serialization(sink *common.ZeroCopySink) error {
 	sink.WriteVarBytes([]byte(this.Name))
 	sink.WriteVarUint(this.BlocksToWait)
 	sink.WriteVarBytes(this.CCMCAddress)
-	height := config.GetExtraInfoHeight(config.DefConfig.P2PNode.NetworkId)
-	if !config.EXTRA_INFO_HEIGHT_FORK_CHECK || ledger.DefLedger.GetCurrentBlockHeight() >= height {
+	withExtraInfo, err := isExtraInfoActive()
+	if err != nil {
+		return fmt.Errorf("isExtraInfoActive error: %v", err)
+	}
+	if withExtraInfo {
 		sink.WriteVarBytes(this.ExtraInfo)
 	}
 	return nil
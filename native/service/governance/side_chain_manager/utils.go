@@ -96,6 +96,33 @@ func GetSideChain(native *native.NativeService, chainID uint64) (*SideChain, err
 
 }
 
+//GetSideChainList returns all registered side chains in the order of chain id bytes
+func GetSideChainList(native *native.NativeService) ([]*SideChain, error) {
+	prefix := utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(SIDE_CHAIN))
+	iter := native.GetCacheDB().NewIterator(prefix)
+	defer iter.Release()
+	sideChains := make([]*SideChain, 0)
+	for has := iter.First(); has; has = iter.Next() {
+		// skip the keys of sideChainApply and other prefixes starting with sideChain
+		if len(iter.Key()) != len(prefix)+8 {
+			continue
+		}
+		sideChainBytes, err := cstates.GetValueFromRawStorageItem(iter.Value())
+		if err != nil {
+			return nil, fmt.Errorf("GetSideChainList, deserialize from raw storage item err:%v", err)
+		}
+		sideChain := new(SideChain)
+		if err := sideChain.Deserialization(common.NewZeroCopySource(sideChainBytes)); err != nil {
+			return nil, fmt.Errorf("GetSideChainList, deserialize sideChain error: %v", err)
+		}
+		sideChains = append(sideChains, sideChain)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("GetSideChainList, iterate side chains error: %v", err)
+	}
+	return sideChains, nil
+}
+
 func PutSideChain(native *native.NativeService, sideChain *SideChain) error {
 	contract := utils.SideChainManagerContractAddress
 	chainidByte := utils.GetUint64Bytes(sideChain.ChainId)
@@ -22,7 +22,6 @@ import (
 
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/native"
-	"github.com/polynetwork/poly/native/event"
 	"github.com/polynetwork/poly/native/service/utils"
 )
 
@@ -57,11 +56,7 @@ func AddSignature(native *native.NativeService) ([]byte, error) {
 		return utils.BYTE_TRUE, nil
 	}
 
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.SignatureManagerContractAddress,
-			States:          []interface{}{"AddSignatureQuorum", id, params.Subject, params.SideChainID},
-		})
+	native.AddNotify(addSignatureQuorumEvent.NewNotify(id, params.Subject, params.SideChainID))
 	return utils.BYTE_TRUE, nil
 
 }
@@ -0,0 +1,293 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package beacon
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+)
+
+const (
+	pubkeyLength    = 48
+	signatureLength = 96
+	fpLength        = 48
+)
+
+// signatureDST is the domain separation tag of the eth2 proof-of-possession ciphersuite
+var signatureDST = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+
+var (
+	fieldModulus, _ = new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+	halfModulus     = new(big.Int).Rsh(new(big.Int).Sub(fieldModulus, big.NewInt(1)), 1)
+	sqrtExponent    = new(big.Int).Rsh(new(big.Int).Add(fieldModulus, big.NewInt(1)), 2)
+	fp2SqrtExp1     = new(big.Int).Rsh(new(big.Int).Sub(fieldModulus, big.NewInt(3)), 2)
+	fp2SqrtExp2     = new(big.Int).Rsh(new(big.Int).Sub(fieldModulus, big.NewInt(1)), 1)
+)
+
+// DecompressPublicKey parses a zcash-style compressed G1 point and makes sure it is a
+// valid, non-infinity member of the prime order subgroup
+func DecompressPublicKey(in []byte) (*bls12381.PointG1, error) {
+	if len(in) != pubkeyLength {
+		return nil, fmt.Errorf("DecompressPublicKey, invalid length: %d", len(in))
+	}
+	if in[0]&0x80 == 0 {
+		return nil, errors.New("DecompressPublicKey, compression flag not set")
+	}
+	if in[0]&0x40 != 0 {
+		return nil, errors.New("DecompressPublicKey, infinity public key")
+	}
+	largest := in[0]&0x20 != 0
+	xBytes := make([]byte, fpLength)
+	copy(xBytes, in)
+	xBytes[0] &= 0x1f
+	x := new(big.Int).SetBytes(xBytes)
+	if x.Cmp(fieldModulus) >= 0 {
+		return nil, errors.New("DecompressPublicKey, x is not a field element")
+	}
+	// y^2 = x^3 + 4
+	y2 := new(big.Int).Exp(x, big.NewInt(3), fieldModulus)
+	y2.Add(y2, big.NewInt(4)).Mod(y2, fieldModulus)
+	y := new(big.Int).Exp(y2, sqrtExponent, fieldModulus)
+	if new(big.Int).Exp(y, big.NewInt(2), fieldModulus).Cmp(y2) != 0 {
+		return nil, errors.New("DecompressPublicKey, point is not on curve")
+	}
+	if (y.Cmp(halfModulus) > 0) != largest {
+		y.Sub(fieldModulus, y)
+	}
+	raw := make([]byte, 2*fpLength)
+	copy(raw[:fpLength], xBytes)
+	fillBytes(y, raw[fpLength:])
+
+	g1 := bls12381.NewG1()
+	p, err := g1.FromBytes(raw)
+	if err != nil {
+		return nil, fmt.Errorf("DecompressPublicKey, %v", err)
+	}
+	if !g1.InCorrectSubgroup(p) {
+		return nil, errors.New("DecompressPublicKey, point is not in correct subgroup")
+	}
+	return p, nil
+}
+
+// DecompressSignature parses a zcash-style compressed G2 point and makes sure it is a
+// member of the prime order subgroup
+func DecompressSignature(in []byte) (*bls12381.PointG2, error) {
+	if len(in) != signatureLength {
+		return nil, fmt.Errorf("DecompressSignature, invalid length: %d", len(in))
+	}
+	if in[0]&0x80 == 0 {
+		return nil, errors.New("DecompressSignature, compression flag not set")
+	}
+	g2 := bls12381.NewG2()
+	if in[0]&0x40 != 0 {
+		return g2.Zero(), nil
+	}
+	largest := in[0]&0x20 != 0
+	xBytes := make([]byte, 2*fpLength)
+	copy(xBytes, in)
+	xBytes[0] &= 0x1f
+	x := &fp2{c1: new(big.Int).SetBytes(xBytes[:fpLength]), c0: new(big.Int).SetBytes(xBytes[fpLength:])}
+	if x.c0.Cmp(fieldModulus) >= 0 || x.c1.Cmp(fieldModulus) >= 0 {
+		return nil, errors.New("DecompressSignature, x is not a field element")
+	}
+	// y^2 = x^3 + 4(1 + i)
+	y2 := x.mul(x).mul(x).add(&fp2{c0: big.NewInt(4), c1: big.NewInt(4)})
+	y, ok := y2.sqrt()
+	if !ok {
+		return nil, errors.New("DecompressSignature, point is not on curve")
+	}
+	if y.lexicographicallyLargest() != largest {
+		y = y.neg()
+	}
+	raw := make([]byte, 4*fpLength)
+	copy(raw[:2*fpLength], xBytes)
+	fillBytes(y.c1, raw[2*fpLength:3*fpLength])
+	fillBytes(y.c0, raw[3*fpLength:])
+
+	p, err := g2.FromBytes(raw)
+	if err != nil {
+		return nil, fmt.Errorf("DecompressSignature, %v", err)
+	}
+	if !g2.InCorrectSubgroup(p) {
+		return nil, errors.New("DecompressSignature, point is not in correct subgroup")
+	}
+	return p, nil
+}
+
+// HashToG2 implements hash_to_curve of the BLS12381G2_XMD:SHA-256_SSWU_RO_ suite
+func HashToG2(msg, dst []byte) (*bls12381.PointG2, error) {
+	uniform, err := expandMessageXMD(msg, dst, 4*64)
+	if err != nil {
+		return nil, err
+	}
+	g2 := bls12381.NewG2()
+	points := make([]*bls12381.PointG2, 2)
+	for i := 0; i < 2; i++ {
+		c0 := new(big.Int).SetBytes(uniform[128*i : 128*i+64])
+		c1 := new(big.Int).SetBytes(uniform[128*i+64 : 128*i+128])
+		in := make([]byte, 2*fpLength)
+		fillBytes(c1.Mod(c1, fieldModulus), in[:fpLength])
+		fillBytes(c0.Mod(c0, fieldModulus), in[fpLength:])
+		// MapToCurve clears the cofactor of each point, which is linear so
+		// the sum still equals clear_cofactor(Q0 + Q1)
+		points[i], err = g2.MapToCurve(in)
+		if err != nil {
+			return nil, fmt.Errorf("HashToG2, map to curve error: %v", err)
+		}
+	}
+	return g2.Affine(g2.Add(g2.New(), points[0], points[1])), nil
+}
+
+// FastAggregateVerify checks an aggregate signature of several public keys over the same message
+func FastAggregateVerify(pubkeys []*bls12381.PointG1, msg []byte, sig *bls12381.PointG2) (bool, error) {
+	if len(pubkeys) == 0 {
+		return false, errors.New("FastAggregateVerify, no public key")
+	}
+	g1 := bls12381.NewG1()
+	aggregate := g1.Zero()
+	for _, pk := range pubkeys {
+		g1.Add(aggregate, aggregate, pk)
+	}
+	h, err := HashToG2(msg, signatureDST)
+	if err != nil {
+		return false, err
+	}
+	engine := bls12381.NewPairingEngine()
+	engine.AddPair(aggregate, h)
+	engine.AddPairInv(g1.One(), sig)
+	return engine.Check(), nil
+}
+
+// expandMessageXMD implements expand_message_xmd of rfc9380 with sha256
+func expandMessageXMD(msg, dst []byte, length int) ([]byte, error) {
+	ell := (length + sha256.Size - 1) / sha256.Size
+	if ell > 255 || len(dst) > 255 {
+		return nil, errors.New("expandMessageXMD, invalid length")
+	}
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	h := sha256.New()
+	h.Write(make([]byte, sha256.BlockSize))
+	h.Write(msg)
+	h.Write([]byte{byte(length >> 8), byte(length), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	h.Reset()
+	h.Write(b0)
+	h.Write([]byte{1})
+	h.Write(dstPrime)
+	bi := h.Sum(nil)
+
+	out := make([]byte, 0, ell*sha256.Size)
+	out = append(out, bi...)
+	for i := 2; i <= ell; i++ {
+		tmp := make([]byte, sha256.Size)
+		for j := range tmp {
+			tmp[j] = b0[j] ^ bi[j]
+		}
+		h.Reset()
+		h.Write(tmp)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(nil)
+		out = append(out, bi...)
+	}
+	return out[:length], nil
+}
+
+// fp2 is an element c0 + c1*i of the quadratic extension field, only used for decompression
+type fp2 struct {
+	c0, c1 *big.Int
+}
+
+func (a *fp2) add(b *fp2) *fp2 {
+	c0 := new(big.Int).Add(a.c0, b.c0)
+	c1 := new(big.Int).Add(a.c1, b.c1)
+	return &fp2{c0: c0.Mod(c0, fieldModulus), c1: c1.Mod(c1, fieldModulus)}
+}
+
+func (a *fp2) mul(b *fp2) *fp2 {
+	t0 := new(big.Int).Mul(a.c0, b.c0)
+	t1 := new(big.Int).Mul(a.c1, b.c1)
+	c0 := new(big.Int).Sub(t0, t1)
+	c1 := new(big.Int).Add(new(big.Int).Mul(a.c0, b.c1), new(big.Int).Mul(a.c1, b.c0))
+	return &fp2{c0: c0.Mod(c0, fieldModulus), c1: c1.Mod(c1, fieldModulus)}
+}
+
+func (a *fp2) neg() *fp2 {
+	c0 := new(big.Int).Sub(fieldModulus, a.c0)
+	c1 := new(big.Int).Sub(fieldModulus, a.c1)
+	return &fp2{c0: c0.Mod(c0, fieldModulus), c1: c1.Mod(c1, fieldModulus)}
+}
+
+func (a *fp2) exp(e *big.Int) *fp2 {
+	r := &fp2{c0: big.NewInt(1), c1: big.NewInt(0)}
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		r = r.mul(r)
+		if e.Bit(i) == 1 {
+			r = r.mul(a)
+		}
+	}
+	return r
+}
+
+func (a *fp2) equal(b *fp2) bool {
+	return a.c0.Cmp(b.c0) == 0 && a.c1.Cmp(b.c1) == 0
+}
+
+// sqrt follows algorithm 9 of https://eprint.iacr.org/2012/685.pdf for p = 3 mod 4
+func (a *fp2) sqrt() (*fp2, bool) {
+	a1 := a.exp(fp2SqrtExp1)
+	alpha := a1.mul(a1).mul(a)
+	x0 := a1.mul(a)
+	minusOne := &fp2{c0: new(big.Int).Sub(fieldModulus, big.NewInt(1)), c1: big.NewInt(0)}
+	var x *fp2
+	if alpha.equal(minusOne) {
+		x = &fp2{c0: new(big.Int).Sub(fieldModulus, x0.c1), c1: new(big.Int).Set(x0.c0)}
+		x.c0.Mod(x.c0, fieldModulus)
+	} else {
+		b := alpha.add(&fp2{c0: big.NewInt(1), c1: big.NewInt(0)}).exp(fp2SqrtExp2)
+		x = b.mul(x0)
+	}
+	if !x.mul(x).equal(a) {
+		return nil, false
+	}
+	return x, true
+}
+
+// fillBytes writes x as a big-endian number right aligned in buf
+func fillBytes(x *big.Int, buf []byte) []byte {
+	for i := range buf {
+		buf[i] = 0
+	}
+	b := x.Bytes()
+	copy(buf[len(buf)-len(b):], b)
+	return buf
+}
+
+func (a *fp2) lexicographicallyLargest() bool {
+	if a.c1.Sign() != 0 {
+		return a.c1.Cmp(halfModulus) > 0
+	}
+	return a.c0.Cmp(halfModulus) > 0
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package beacon
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+	"github.com/stretchr/testify/assert"
+)
+
+func compressG1(p *bls12381.PointG1) []byte {
+	raw := bls12381.NewG1().ToBytes(p)
+	out := append([]byte{}, raw[:fpLength]...)
+	out[0] |= 0x80
+	if new(big.Int).SetBytes(raw[fpLength:]).Cmp(halfModulus) > 0 {
+		out[0] |= 0x20
+	}
+	return out
+}
+
+func compressG2(p *bls12381.PointG2) []byte {
+	raw := bls12381.NewG2().ToBytes(p)
+	out := append([]byte{}, raw[:2*fpLength]...)
+	out[0] |= 0x80
+	y := &fp2{c1: new(big.Int).SetBytes(raw[2*fpLength : 3*fpLength]), c0: new(big.Int).SetBytes(raw[3*fpLength:])}
+	if y.lexicographicallyLargest() {
+		out[0] |= 0x20
+	}
+	return out
+}
+
+func sign(sk *big.Int, msg []byte) *bls12381.PointG2 {
+	g2 := bls12381.NewG2()
+	h, err := HashToG2(msg, signatureDST)
+	if err != nil {
+		panic(err)
+	}
+	return g2.MulScalar(g2.New(), h, sk)
+}
+
+func TestDecompressGenerators(t *testing.T) {
+	g1Bytes, _ := hex.DecodeString("97f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb")
+	p1, err := DecompressPublicKey(g1Bytes)
+	assert.NoError(t, err)
+	g1 := bls12381.NewG1()
+	assert.True(t, g1.Equal(p1, g1.One()))
+	assert.Equal(t, g1Bytes, compressG1(p1))
+
+	g2Bytes, _ := hex.DecodeString("93e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e024aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8")
+	p2, err := DecompressSignature(g2Bytes)
+	assert.NoError(t, err)
+	g2 := bls12381.NewG2()
+	assert.True(t, g2.Equal(p2, g2.One()))
+	assert.Equal(t, g2Bytes, compressG2(p2))
+}
+
+func TestDecompressInvalid(t *testing.T) {
+	_, err := DecompressPublicKey(make([]byte, pubkeyLength))
+	assert.Error(t, err)
+	infinity := make([]byte, pubkeyLength)
+	infinity[0] = 0xc0
+	_, err = DecompressPublicKey(infinity)
+	assert.Error(t, err)
+	_, err = DecompressSignature(make([]byte, signatureLength-1))
+	assert.Error(t, err)
+}
+
+func TestExpandMessageXMD(t *testing.T) {
+	out, err := expandMessageXMD([]byte{}, []byte("QUUX-V01-CS02-with-expander-SHA256-128"), 0x20)
+	assert.NoError(t, err)
+	assert.Equal(t, "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235", hex.EncodeToString(out))
+}
+
+func TestHashToG2(t *testing.T) {
+	p, err := HashToG2([]byte{}, []byte("QUUX-V01-CS02-with-BLS12381G2_XMD:SHA-256_SSWU_RO_"))
+	assert.NoError(t, err)
+	raw := bls12381.NewG2().ToBytes(p)
+	assert.Equal(t, "05cb8437535e20ecffaef7752baddf98034139c38452458baeefab379ba13dff5bf5dd71b72418717047f5b0f37da03d", hex.EncodeToString(raw[:fpLength]))
+	assert.Equal(t, "0141ebfbdca40eb85b87142e130ab689c673cf60f1a3e98d69335266f30d9b8d4ac44c1038e9dcdd5393faf5c41fb78a", hex.EncodeToString(raw[fpLength:2*fpLength]))
+}
+
+func TestFastAggregateVerify(t *testing.T) {
+	g1, g2 := bls12381.NewG1(), bls12381.NewG2()
+	msg := []byte("poly beacon light client")
+	var pubkeys []*bls12381.PointG1
+	aggregate := g2.Zero()
+	for i := int64(1); i <= 4; i++ {
+		sk := big.NewInt(i * 7919)
+		pk := g1.MulScalar(g1.New(), g1.One(), sk)
+		decompressed, err := DecompressPublicKey(compressG1(pk))
+		assert.NoError(t, err)
+		pubkeys = append(pubkeys, decompressed)
+		g2.Add(aggregate, aggregate, sign(sk, msg))
+	}
+	sig, err := DecompressSignature(compressG2(aggregate))
+	assert.NoError(t, err)
+
+	ok, err := FastAggregateVerify(pubkeys, msg, sig)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = FastAggregateVerify(pubkeys[1:], msg, sig)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = FastAggregateVerify(pubkeys, []byte("other message"), sig)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
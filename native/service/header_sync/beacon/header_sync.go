@@ -0,0 +1,300 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package beacon
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/common/constants"
+	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/fork_manager"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	scom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+// BeaconHandler is the light client of the ethereum proof-of-stake chain, it follows
+// finalized beacon blocks signed by the sync committee and keeps the state roots
+// of their execution payloads for cross chain proof verification
+type BeaconHandler struct {
+}
+
+func NewBeaconHandler() *BeaconHandler {
+	return &BeaconHandler{}
+}
+
+func init() {
+	scom.RegisterHeaderSyncHandler(utils.BEACON_ROUTER, func() scom.HeaderSyncHandler { return NewBeaconHandler() })
+	fork_manager.RegisterRouterStartBlock(utils.BEACON_ROUTER, config.NETWORK_ID_MAIN_NET, constants.BEACON_ROUTER_START_HEIGHT_MAINNET)
+}
+
+// SyncGenesisHeader stores a trusted LightClientBootstrap, the header of it is
+// regarded as finalized and its sync committee as the committee of its period
+func (this *BeaconHandler) SyncGenesisHeader(native *native.NativeService) error {
+	params := new(scom.SyncGenesisHeaderParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return fmt.Errorf("BeaconHandler SyncGenesisHeader, contract params deserialize error: %v", err)
+	}
+	// Get current epoch operator
+	operatorAddress, err := node_manager.GetCurConOperator(native)
+	if err != nil {
+		return fmt.Errorf("BeaconHandler SyncGenesisHeader, get current consensus operator address error: %v", err)
+	}
+	//check witness
+	err = utils.ValidateOwner(native, operatorAddress)
+	if err != nil {
+		return fmt.Errorf("BeaconHandler SyncGenesisHeader, checkWitness error: %v", err)
+	}
+	stored, err := getGenesis(native, params.ChainID)
+	if err != nil {
+		return fmt.Errorf("BeaconHandler SyncGenesisHeader, getGenesis error: %v", err)
+	}
+	if stored != nil {
+		return fmt.Errorf("BeaconHandler SyncGenesisHeader, genesis header had been initialized")
+	}
+
+	var genesis GenesisHeader
+	if err := json.Unmarshal(params.GenesisHeader, &genesis); err != nil {
+		return fmt.Errorf("BeaconHandler SyncGenesisHeader, deserialize genesis header err: %v", err)
+	}
+	if err := genesis.Config.validate(); err != nil {
+		return fmt.Errorf("BeaconHandler SyncGenesisHeader, invalid chain config: %v", err)
+	}
+	if err := verifyLightClientHeader(&genesis.Config, &genesis.Header); err != nil {
+		return fmt.Errorf("BeaconHandler SyncGenesisHeader, verifyLightClientHeader error: %v", err)
+	}
+	if err := genesis.CurrentSyncCommittee.validate(); err != nil {
+		return fmt.Errorf("BeaconHandler SyncGenesisHeader, invalid sync committee: %v", err)
+	}
+	slot := uint64(genesis.Header.Beacon.Slot)
+	if !isValidMerkleBranch(genesis.CurrentSyncCommittee.HashTreeRoot(), genesis.CurrentSyncCommitteeBranch,
+		genesis.Config.currentSyncCommitteeGindex(computeEpoch(slot)), genesis.Header.Beacon.StateRoot) {
+		return fmt.Errorf("BeaconHandler SyncGenesisHeader, invalid current sync committee branch")
+	}
+
+	err = putGenesis(native, params.ChainID, &Genesis{Config: genesis.Config, Header: genesis.Header})
+	if err != nil {
+		return fmt.Errorf("BeaconHandler SyncGenesisHeader, putGenesis error: %v", err)
+	}
+	putSyncCommittee(native, params.ChainID, computePeriod(slot), &genesis.CurrentSyncCommittee)
+	putFinalized(native, params.ChainID, &genesis.Header)
+	return nil
+}
+
+// SyncBlockHeader processes a batch of LightClientUpdate, every update must carry a finalized header
+// and be signed by a supermajority of the sync committee of its signature period
+func (this *BeaconHandler) SyncBlockHeader(native *native.NativeService) error {
+	headerParams := new(scom.SyncBlockHeaderParam)
+	if err := headerParams.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return fmt.Errorf("BeaconHandler SyncBlockHeader, contract params deserialize error: %v", err)
+	}
+	genesis, err := getGenesis(native, headerParams.ChainID)
+	if err != nil {
+		return fmt.Errorf("BeaconHandler SyncBlockHeader, getGenesis error: %v", err)
+	}
+	if genesis == nil {
+		return fmt.Errorf("BeaconHandler SyncBlockHeader, genesis header is not initialized")
+	}
+	for _, v := range headerParams.Headers {
+		var update LightClientUpdate
+		if err := json.Unmarshal(v, &update); err != nil {
+			return fmt.Errorf("BeaconHandler SyncBlockHeader, deserialize update err: %v", err)
+		}
+		applied, err := processLightClientUpdate(native, headerParams.ChainID, &genesis.Config, &update)
+		if err != nil {
+			return fmt.Errorf("BeaconHandler SyncBlockHeader, signature slot: %d, error: %v", update.SignatureSlot, err)
+		}
+		if !applied {
+			log.Warnf("BeaconHandler SyncBlockHeader, update of signature slot %d is not relevant", update.SignatureSlot)
+		}
+	}
+	return nil
+}
+
+func (this *BeaconHandler) SyncCrossChainMsg(native *native.NativeService) error {
+	return nil
+}
+
+// GetCurrentHeight returns the latest finalized execution block number
+func GetCurrentHeight(native *native.NativeService, chainID uint64) (uint64, error) {
+	store, err := GetLightClientStore(native, chainID)
+	if err != nil {
+		return 0, err
+	}
+	return store.FinalizedBlockNumber, nil
+}
+
+// processLightClientUpdate follows validate_light_client_update and apply_light_client_update of the
+// altair light client sync protocol, it returns false if the update does not advance the store
+func processLightClientUpdate(native *native.NativeService, chainID uint64, config *ChainConfig, update *LightClientUpdate) (bool, error) {
+	store, err := GetLightClientStore(native, chainID)
+	if err != nil {
+		return false, err
+	}
+	if update.SyncAggregate.participants()*3 < SYNC_COMMITTEE_SIZE*2 {
+		return false, fmt.Errorf("insufficient sync committee participants: %d", update.SyncAggregate.participants())
+	}
+	if update.FinalizedHeader == nil {
+		return false, fmt.Errorf("finalized header is missing")
+	}
+	attested, finalized := &update.AttestedHeader, update.FinalizedHeader
+	attestedSlot, finalizedSlot, signatureSlot := uint64(attested.Beacon.Slot), uint64(finalized.Beacon.Slot), uint64(update.SignatureSlot)
+	if !(signatureSlot > attestedSlot && attestedSlot >= finalizedSlot) {
+		return false, fmt.Errorf("invalid slot order, signature: %d, attested: %d, finalized: %d", signatureSlot, attestedSlot, finalizedSlot)
+	}
+
+	storePeriod := computePeriod(store.FinalizedSlot)
+	signaturePeriod := computePeriod(signatureSlot)
+	attestedPeriod := computePeriod(attestedSlot)
+	nextCommittee, err := getSyncCommittee(native, chainID, storePeriod+1)
+	if err != nil {
+		return false, err
+	}
+	if signaturePeriod != storePeriod && (nextCommittee == nil || signaturePeriod != storePeriod+1) {
+		return false, fmt.Errorf("signature period %d is out of range, store period %d", signaturePeriod, storePeriod)
+	}
+	hasNextCommittee := update.NextSyncCommittee != nil && len(update.NextSyncCommitteeBranch) > 0
+	learnsNextCommittee := hasNextCommittee && nextCommittee == nil && attestedPeriod == storePeriod
+	if finalizedSlot <= store.FinalizedSlot && !learnsNextCommittee {
+		return false, nil
+	}
+
+	if err := verifyLightClientHeader(config, attested); err != nil {
+		return false, fmt.Errorf("invalid attested header: %v", err)
+	}
+	if err := verifyLightClientHeader(config, finalized); err != nil {
+		return false, fmt.Errorf("invalid finalized header: %v", err)
+	}
+	attestedEpoch := computeEpoch(attestedSlot)
+	if !isValidMerkleBranch(finalized.Beacon.HashTreeRoot(), update.FinalityBranch, config.finalizedRootGindex(attestedEpoch), attested.Beacon.StateRoot) {
+		return false, fmt.Errorf("invalid finality branch")
+	}
+	if hasNextCommittee {
+		if err := update.NextSyncCommittee.validate(); err != nil {
+			return false, fmt.Errorf("invalid next sync committee: %v", err)
+		}
+		if !isValidMerkleBranch(update.NextSyncCommittee.HashTreeRoot(), update.NextSyncCommitteeBranch, config.nextSyncCommitteeGindex(attestedEpoch), attested.Beacon.StateRoot) {
+			return false, fmt.Errorf("invalid next sync committee branch")
+		}
+		if attestedPeriod == storePeriod && nextCommittee != nil && nextCommittee.HashTreeRoot() != update.NextSyncCommittee.HashTreeRoot() {
+			return false, fmt.Errorf("next sync committee conflicts with the known one")
+		}
+	}
+
+	committee := nextCommittee
+	if signaturePeriod == storePeriod {
+		committee, err = getSyncCommittee(native, chainID, storePeriod)
+		if err != nil {
+			return false, err
+		}
+		if committee == nil {
+			return false, fmt.Errorf("sync committee of period %d is missing", storePeriod)
+		}
+	}
+	if err := verifySyncAggregate(config, committee, update); err != nil {
+		return false, err
+	}
+
+	if hasNextCommittee && computePeriod(finalizedSlot) == attestedPeriod {
+		known, err := getSyncCommittee(native, chainID, attestedPeriod+1)
+		if err != nil {
+			return false, err
+		}
+		if known == nil {
+			putSyncCommittee(native, chainID, attestedPeriod+1, update.NextSyncCommittee)
+		}
+	}
+	if finalizedSlot > store.FinalizedSlot {
+		putFinalized(native, chainID, finalized)
+	}
+	return true, nil
+}
+
+// verifyLightClientHeader checks the execution payload header against the beacon block body root
+func verifyLightClientHeader(config *ChainConfig, header *LightClientHeader) error {
+	epoch := computeEpoch(uint64(header.Beacon.Slot))
+	if !config.isActive(FORK_CAPELLA, epoch) {
+		return fmt.Errorf("header of slot %d is before %s", header.Beacon.Slot, FORK_CAPELLA)
+	}
+	if header.Execution == nil {
+		return fmt.Errorf("execution header is missing")
+	}
+	root, err := header.Execution.HashTreeRoot(config.isActive(FORK_DENEB, epoch))
+	if err != nil {
+		return err
+	}
+	if !isValidMerkleBranch(root, header.ExecutionBranch, EXECUTION_PAYLOAD_GINDEX, header.Beacon.BodyRoot) {
+		return fmt.Errorf("invalid execution branch")
+	}
+	return nil
+}
+
+func verifySyncAggregate(config *ChainConfig, committee *SyncCommittee, update *LightClientUpdate) error {
+	bits := update.SyncAggregate.SyncCommitteeBits
+	if len(bits) != SYNC_COMMITTEE_SIZE/8 {
+		return fmt.Errorf("invalid sync committee bits length: %d", len(bits))
+	}
+	var participants []*bls12381.PointG1
+	for i, pk := range committee.Pubkeys {
+		if bits[i/8]&(1<<uint(i%8)) == 0 {
+			continue
+		}
+		point, err := DecompressPublicKey(pk)
+		if err != nil {
+			return fmt.Errorf("invalid pubkey %d of sync committee: %v", i, err)
+		}
+		participants = append(participants, point)
+	}
+	sig, err := DecompressSignature(update.SyncAggregate.SyncCommitteeSignature)
+	if err != nil {
+		return fmt.Errorf("invalid sync committee signature: %v", err)
+	}
+
+	forkVersionSlot := uint64(update.SignatureSlot)
+	if forkVersionSlot > 0 {
+		forkVersionSlot--
+	}
+	domain := computeDomain(DOMAIN_SYNC_COMMITTEE, config.forkVersion(computeEpoch(forkVersionSlot)), config.GenesisValidatorsRoot)
+	signingRoot := computeSigningRoot(update.AttestedHeader.Beacon.HashTreeRoot(), domain)
+	ok, err := FastAggregateVerify(participants, signingRoot[:], sig)
+	if err != nil {
+		return fmt.Errorf("verify sync committee signature error: %v", err)
+	}
+	if !ok {
+		return fmt.Errorf("invalid sync committee signature")
+	}
+	return nil
+}
+
+func putFinalized(native *native.NativeService, chainID uint64, header *LightClientHeader) {
+	finalized := &FinalizedHeader{
+		Slot:        uint64(header.Beacon.Slot),
+		BeaconRoot:  header.Beacon.HashTreeRoot(),
+		BlockNumber: uint64(header.Execution.BlockNumber),
+		BlockHash:   header.Execution.BlockHash,
+		StateRoot:   header.Execution.StateRoot,
+	}
+	putFinalizedHeader(native, chainID, finalized)
+	putLightClientStore(native, chainID, &LightClientStore{FinalizedSlot: finalized.Slot, FinalizedBlockNumber: finalized.BlockNumber})
+	scom.NotifyPutHeader(native, chainID, finalized.BlockNumber, finalized.BlockHash.Hex())
+}
@@ -0,0 +1,311 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package beacon
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+	"github.com/polynetwork/poly/core/genesis"
+	"github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	scom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+
+	ecommon "github.com/ethereum/go-ethereum/common"
+)
+
+const testChainID = 24
+
+var (
+	acct = account.NewAccount("")
+
+	testConfig = ChainConfig{
+		GenesisValidatorsRoot: ecommon.HexToHash("0x4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95"),
+		Forks: []Fork{
+			{Name: FORK_ALTAIR, Version: hexutil.Bytes{1, 0, 0, 0}},
+			{Name: "bellatrix", Version: hexutil.Bytes{2, 0, 0, 0}},
+			{Name: FORK_CAPELLA, Version: hexutil.Bytes{3, 0, 0, 0}},
+			{Name: FORK_DENEB, Version: hexutil.Bytes{4, 0, 0, 0}},
+		},
+	}
+)
+
+func init() {
+	genesis.GenesisBookkeepers = []keypair.PublicKey{acct.PublicKey}
+}
+
+func NewNative(args []byte, tx *types.Transaction, db *storage.CacheDB) *native.NativeService {
+	if db == nil {
+		store, _ := leveldbstore.NewMemLevelDBStore()
+		db = storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+		sink := common.NewZeroCopySink(nil)
+		view := &node_manager.GovernanceView{
+			TxHash: common.UINT256_EMPTY,
+			Height: 0,
+			View:   0,
+		}
+		view.Serialization(sink)
+		db.Put(utils.ConcatKey(utils.NodeManagerContractAddress, []byte(node_manager.GOVERNANCE_VIEW)), states.GenRawStorageItem(sink.Bytes()))
+
+		peerPoolMap := &node_manager.PeerPoolMap{
+			PeerPoolMap: map[string]*node_manager.PeerPoolItem{
+				vconfig.PubkeyID(acct.PublicKey): {
+					Address:    acct.Address,
+					Status:     node_manager.ConsensusStatus,
+					PeerPubkey: vconfig.PubkeyID(acct.PublicKey),
+					Index:      0,
+				},
+			},
+		}
+		sink.Reset()
+		peerPoolMap.Serialization(sink)
+		db.Put(utils.ConcatKey(utils.NodeManagerContractAddress,
+			[]byte(node_manager.PEER_POOL), utils.GetUint32Bytes(0)), states.GenRawStorageItem(sink.Bytes()))
+	}
+	ret, _ := native.NewNativeService(db, tx, 0, 0, common.Uint256{0}, 0, args, false)
+	return ret
+}
+
+// sparseTree is a merkle tree of the given depth with only some nodes set
+type sparseTree struct {
+	depth int
+	nodes map[uint64]ecommon.Hash
+}
+
+func (this *sparseTree) node(gindex uint64) ecommon.Hash {
+	if v, ok := this.nodes[gindex]; ok {
+		return v
+	}
+	if gindex >= 1<<uint(this.depth) {
+		return ecommon.Hash{}
+	}
+	return hashPair(this.node(2*gindex), this.node(2*gindex+1))
+}
+
+func (this *sparseTree) branch(gindex uint64) (branch []ecommon.Hash) {
+	for ; gindex > 1; gindex >>= 1 {
+		branch = append(branch, this.node(gindex^1))
+	}
+	return
+}
+
+type testCommittee struct {
+	secret    *big.Int
+	committee *SyncCommittee
+}
+
+func newTestCommittee(seed int64) *testCommittee {
+	g1 := bls12381.NewG1()
+	c := &testCommittee{secret: new(big.Int), committee: new(SyncCommittee)}
+	aggregate := g1.Zero()
+	for i := int64(0); i < SYNC_COMMITTEE_SIZE; i++ {
+		sk := big.NewInt(seed + i)
+		pk := g1.MulScalar(g1.New(), g1.One(), sk)
+		g1.Add(aggregate, aggregate, pk)
+		c.secret.Add(c.secret, sk)
+		c.committee.Pubkeys = append(c.committee.Pubkeys, compressG1(pk))
+	}
+	c.committee.AggregatePubkey = compressG1(aggregate)
+	return c
+}
+
+func newTestHeader(slot, number uint64) LightClientHeader {
+	execution := &ExecutionPayloadHeader{
+		StateRoot:     ecommon.BigToHash(new(big.Int).SetUint64(number * 1000)),
+		LogsBloom:     make([]byte, 256),
+		BlockNumber:   Uint64(number),
+		BaseFeePerGas: (*Uint256)(big.NewInt(7)),
+		BlockHash:     ecommon.BigToHash(new(big.Int).SetUint64(number)),
+		ExtraData:     []byte("poly"),
+	}
+	root, _ := execution.HashTreeRoot(true)
+	body := &sparseTree{depth: 4, nodes: map[uint64]ecommon.Hash{EXECUTION_PAYLOAD_GINDEX: root}}
+	return LightClientHeader{
+		Beacon: BeaconBlockHeader{
+			Slot:      Uint64(slot),
+			StateRoot: ecommon.BigToHash(new(big.Int).SetUint64(slot)),
+			BodyRoot:  body.node(1),
+		},
+		Execution:       execution,
+		ExecutionBranch: body.branch(EXECUTION_PAYLOAD_GINDEX),
+	}
+}
+
+func newTestGenesis(slot, number uint64, c *testCommittee) []byte {
+	header := newTestHeader(slot, number)
+	state := &sparseTree{depth: 5, nodes: map[uint64]ecommon.Hash{54: c.committee.HashTreeRoot()}}
+	header.Beacon.StateRoot = state.node(1)
+	genesisHeader := &GenesisHeader{
+		Config:                     testConfig,
+		Header:                     header,
+		CurrentSyncCommittee:       *c.committee,
+		CurrentSyncCommitteeBranch: state.branch(54),
+	}
+	raw, _ := json.Marshal(genesisHeader)
+	return raw
+}
+
+func newTestUpdate(signer *testCommittee, next *testCommittee, signatureSlot, attestedSlot uint64, finalized LightClientHeader) []byte {
+	attested := newTestHeader(attestedSlot, uint64(finalized.Execution.BlockNumber)+10)
+	state := &sparseTree{depth: 6, nodes: map[uint64]ecommon.Hash{105: finalized.Beacon.HashTreeRoot()}}
+	update := &LightClientUpdate{
+		FinalizedHeader: &finalized,
+		SignatureSlot:   Uint64(signatureSlot),
+	}
+	if next != nil {
+		state.nodes[55] = next.committee.HashTreeRoot()
+		update.NextSyncCommittee = next.committee
+	}
+	attested.Beacon.StateRoot = state.node(1)
+	update.AttestedHeader = attested
+	update.FinalityBranch = state.branch(105)
+	if next != nil {
+		update.NextSyncCommitteeBranch = state.branch(55)
+	}
+
+	domain := computeDomain(DOMAIN_SYNC_COMMITTEE, testConfig.forkVersion(computeEpoch(signatureSlot-1)), testConfig.GenesisValidatorsRoot)
+	signingRoot := computeSigningRoot(attested.Beacon.HashTreeRoot(), domain)
+	bits := make([]byte, SYNC_COMMITTEE_SIZE/8)
+	for i := range bits {
+		bits[i] = 0xff
+	}
+	update.SyncAggregate = SyncAggregate{
+		SyncCommitteeBits:      bits,
+		SyncCommitteeSignature: compressG2(sign(signer.secret, signingRoot[:])),
+	}
+	raw, _ := json.Marshal(update)
+	return raw
+}
+
+func syncGenesis(t *testing.T, genesisHeader []byte, tx *types.Transaction) (*native.NativeService, error) {
+	param := &scom.SyncGenesisHeaderParam{ChainID: testChainID, GenesisHeader: genesisHeader}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	native := NewNative(sink.Bytes(), tx, nil)
+	return native, NewBeaconHandler().SyncGenesisHeader(native)
+}
+
+func syncUpdates(native *native.NativeService, updates ...[]byte) error {
+	param := &scom.SyncBlockHeaderParam{ChainID: testChainID, Address: acct.Address, Headers: updates}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	native = NewNative(sink.Bytes(), &types.Transaction{}, native.GetCacheDB())
+	return NewBeaconHandler().SyncBlockHeader(native)
+}
+
+func TestSyncGenesisHeader(t *testing.T) {
+	c := newTestCommittee(1)
+	tx := &types.Transaction{SignedAddr: []common.Address{acct.Address}}
+
+	_, err := syncGenesis(t, newTestGenesis(64, 100, c), &types.Transaction{})
+	assert.Error(t, err)
+
+	native, err := syncGenesis(t, newTestGenesis(64, 100, c), tx)
+	assert.NoError(t, err)
+	height, err := GetCurrentHeight(native, testChainID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), height)
+	header, err := GetFinalizedHeader(native, testChainID, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(64), header.Slot)
+	assert.Equal(t, ecommon.BigToHash(big.NewInt(100000)), header.StateRoot)
+
+	native = NewNative(native.GetInput(), tx, native.GetCacheDB())
+	err = NewBeaconHandler().SyncGenesisHeader(native)
+	assert.Error(t, err)
+
+	var genesisHeader GenesisHeader
+	assert.NoError(t, json.Unmarshal(newTestGenesis(64, 100, c), &genesisHeader))
+	genesisHeader.CurrentSyncCommittee.Pubkeys[0] = genesisHeader.CurrentSyncCommittee.Pubkeys[1]
+	raw, _ := json.Marshal(genesisHeader)
+	_, err = syncGenesis(t, raw, tx)
+	assert.Error(t, err)
+}
+
+func TestSyncBlockHeader(t *testing.T) {
+	current, next := newTestCommittee(1), newTestCommittee(100001)
+	tx := &types.Transaction{SignedAddr: []common.Address{acct.Address}}
+	native, err := syncGenesis(t, newTestGenesis(64, 100, current), tx)
+	assert.NoError(t, err)
+
+	// finalize a header of the same period and learn the next sync committee
+	err = syncUpdates(native, newTestUpdate(current, next, 97, 96, newTestHeader(80, 110)))
+	assert.NoError(t, err)
+	height, _ := GetCurrentHeight(native, testChainID)
+	assert.Equal(t, uint64(110), height)
+	committee, err := getSyncCommittee(native, testChainID, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, next.committee.HashTreeRoot(), committee.HashTreeRoot())
+
+	// an update of the next period must be signed by the next committee
+	period := uint64(SLOTS_PER_EPOCH * EPOCHS_PER_SYNC_COMMITTEE_PERIOD)
+	err = syncUpdates(native, newTestUpdate(current, nil, period+10, period+8, newTestHeader(period+1, 200)))
+	assert.Error(t, err)
+	err = syncUpdates(native, newTestUpdate(next, nil, period+10, period+8, newTestHeader(period+1, 200)))
+	assert.NoError(t, err)
+	store, err := GetLightClientStore(native, testChainID)
+	assert.NoError(t, err)
+	assert.Equal(t, period+1, store.FinalizedSlot)
+	assert.Equal(t, uint64(200), store.FinalizedBlockNumber)
+	header, err := GetFinalizedHeader(native, testChainID, 200)
+	assert.NoError(t, err)
+	assert.Equal(t, ecommon.BigToHash(big.NewInt(200000)), header.StateRoot)
+
+	// stale updates are ignored, and periods without a known committee are rejected
+	err = syncUpdates(native, newTestUpdate(next, nil, period+10, period+8, newTestHeader(period+1, 200)))
+	assert.NoError(t, err)
+	err = syncUpdates(native, newTestUpdate(next, nil, 2*period+10, 2*period+8, newTestHeader(2*period+1, 300)))
+	assert.Error(t, err)
+}
+
+func TestSyncBlockHeaderInvalidProof(t *testing.T) {
+	current := newTestCommittee(1)
+	tx := &types.Transaction{SignedAddr: []common.Address{acct.Address}}
+	native, err := syncGenesis(t, newTestGenesis(64, 100, current), tx)
+	assert.NoError(t, err)
+
+	var update LightClientUpdate
+	assert.NoError(t, json.Unmarshal(newTestUpdate(current, nil, 97, 96, newTestHeader(80, 110)), &update))
+	update.FinalizedHeader.Execution.StateRoot = ecommon.HexToHash("0x01")
+	raw, _ := json.Marshal(update)
+	err = syncUpdates(native, raw)
+	assert.Error(t, err)
+
+	assert.NoError(t, json.Unmarshal(newTestUpdate(current, nil, 97, 96, newTestHeader(80, 110)), &update))
+	update.SyncAggregate.SyncCommitteeBits[0] = 0
+	raw, _ = json.Marshal(update)
+	err = syncUpdates(native, raw)
+	assert.Error(t, err)
+
+	height, _ := GetCurrentHeight(native, testChainID)
+	assert.Equal(t, uint64(100), height)
+}
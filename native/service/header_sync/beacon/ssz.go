@@ -0,0 +1,138 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package beacon
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+
+	ecommon "github.com/ethereum/go-ethereum/common"
+)
+
+// zeroHashes[i] is the root of a zero filled subtree of depth i
+var zeroHashes [10]ecommon.Hash
+
+func init() {
+	for i := 1; i < len(zeroHashes); i++ {
+		zeroHashes[i] = hashPair(zeroHashes[i-1], zeroHashes[i-1])
+	}
+}
+
+func hashPair(a, b ecommon.Hash) ecommon.Hash {
+	h := sha256.New()
+	h.Write(a[:])
+	h.Write(b[:])
+	var out ecommon.Hash
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// merkleize computes the ssz root of chunks padded with zero chunks to 2^depth leaves
+func merkleize(chunks []ecommon.Hash, depth int) ecommon.Hash {
+	layer := append([]ecommon.Hash{}, chunks...)
+	for d := 0; d < depth; d++ {
+		if len(layer)%2 == 1 {
+			layer = append(layer, zeroHashes[d])
+		}
+		next := make([]ecommon.Hash, len(layer)/2)
+		for i := range next {
+			next[i] = hashPair(layer[2*i], layer[2*i+1])
+		}
+		layer = next
+	}
+	if len(layer) == 0 {
+		return zeroHashes[depth]
+	}
+	return layer[0]
+}
+
+func uint64Chunk(v uint64) (chunk ecommon.Hash) {
+	binary.LittleEndian.PutUint64(chunk[:8], v)
+	return
+}
+
+func uint256Chunk(v *big.Int) (chunk ecommon.Hash) {
+	if v == nil {
+		return
+	}
+	be := v.Bytes()
+	for i := 0; i < len(be) && i < len(chunk); i++ {
+		chunk[i] = be[len(be)-1-i]
+	}
+	return
+}
+
+func bytesChunk(b []byte) (chunk ecommon.Hash) {
+	copy(chunk[:], b)
+	return
+}
+
+// bytesRoot computes the root of a fixed size byte vector
+func bytesRoot(b []byte, depth int) ecommon.Hash {
+	var chunks []ecommon.Hash
+	for i := 0; i < len(b); i += 32 {
+		end := i + 32
+		if end > len(b) {
+			end = len(b)
+		}
+		chunks = append(chunks, bytesChunk(b[i:end]))
+	}
+	return merkleize(chunks, depth)
+}
+
+func mixInLength(root ecommon.Hash, length uint64) ecommon.Hash {
+	return hashPair(root, uint64Chunk(length))
+}
+
+func pubkeyRoot(pubkey []byte) ecommon.Hash {
+	return bytesRoot(pubkey, 1)
+}
+
+// isValidMerkleBranch checks that leaf sits at the generalized index gindex of the tree of root
+func isValidMerkleBranch(leaf ecommon.Hash, branch []ecommon.Hash, gindex uint64, root ecommon.Hash) bool {
+	depth := 0
+	for g := gindex; g > 1; g >>= 1 {
+		depth++
+	}
+	if len(branch) != depth {
+		return false
+	}
+	value := leaf
+	for i := 0; i < depth; i++ {
+		if (gindex>>uint(i))&1 == 1 {
+			value = hashPair(branch[i], value)
+		} else {
+			value = hashPair(value, branch[i])
+		}
+	}
+	return value == root
+}
+
+func computeSigningRoot(objectRoot ecommon.Hash, domain ecommon.Hash) ecommon.Hash {
+	return hashPair(objectRoot, domain)
+}
+
+func computeDomain(domainType [4]byte, forkVersion [4]byte, genesisValidatorsRoot ecommon.Hash) (domain ecommon.Hash) {
+	var versionChunk ecommon.Hash
+	copy(versionChunk[:], forkVersion[:])
+	forkDataRoot := hashPair(versionChunk, genesisValidatorsRoot)
+	copy(domain[:4], domainType[:])
+	copy(domain[4:], forkDataRoot[:28])
+	return
+}
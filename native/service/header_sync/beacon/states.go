@@ -0,0 +1,408 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package beacon
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	ecommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/polynetwork/poly/common"
+)
+
+const (
+	SLOTS_PER_EPOCH                  = 32
+	EPOCHS_PER_SYNC_COMMITTEE_PERIOD = 256
+	SYNC_COMMITTEE_SIZE              = 512
+
+	FORK_ALTAIR  = "altair"
+	FORK_CAPELLA = "capella"
+	FORK_DENEB   = "deneb"
+	FORK_ELECTRA = "electra"
+)
+
+var DOMAIN_SYNC_COMMITTEE = [4]byte{0x07, 0x00, 0x00, 0x00}
+
+// Uint64 decodes the quoted decimal integers used by the beacon node api
+type Uint64 uint64
+
+func (u Uint64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatUint(uint64(u), 10))
+}
+
+func (u *Uint64) UnmarshalJSON(input []byte) error {
+	v, err := strconv.ParseUint(strings.Trim(string(input), `"`), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid uint64 %s: %v", string(input), err)
+	}
+	*u = Uint64(v)
+	return nil
+}
+
+// Uint256 decodes the quoted decimal big integers used by the beacon node api
+type Uint256 big.Int
+
+func (u *Uint256) MarshalJSON() ([]byte, error) {
+	return json.Marshal((*big.Int)(u).String())
+}
+
+func (u *Uint256) UnmarshalJSON(input []byte) error {
+	if _, ok := (*big.Int)(u).SetString(strings.Trim(string(input), `"`), 10); !ok {
+		return fmt.Errorf("invalid uint256 %s", string(input))
+	}
+	return nil
+}
+
+type Fork struct {
+	Name    string        `json:"name"`
+	Version hexutil.Bytes `json:"version"`
+	Epoch   Uint64        `json:"epoch"`
+}
+
+// ChainConfig describes the beacon chain the light client follows
+type ChainConfig struct {
+	GenesisValidatorsRoot ecommon.Hash `json:"genesis_validators_root"`
+	Forks                 []Fork       `json:"forks"`
+}
+
+func (this *ChainConfig) validate() error {
+	if len(this.Forks) == 0 {
+		return fmt.Errorf("empty fork schedule")
+	}
+	for i, fork := range this.Forks {
+		if len(fork.Version) != 4 {
+			return fmt.Errorf("invalid version of fork %s", fork.Name)
+		}
+		if i > 0 && fork.Epoch < this.Forks[i-1].Epoch {
+			return fmt.Errorf("fork %s is not in ascending order", fork.Name)
+		}
+	}
+	if this.forkEpoch(FORK_CAPELLA) == nil {
+		return fmt.Errorf("fork %s is required", FORK_CAPELLA)
+	}
+	return nil
+}
+
+func (this *ChainConfig) forkEpoch(name string) *uint64 {
+	for _, fork := range this.Forks {
+		if fork.Name == name {
+			epoch := uint64(fork.Epoch)
+			return &epoch
+		}
+	}
+	return nil
+}
+
+func (this *ChainConfig) isActive(name string, epoch uint64) bool {
+	forkEpoch := this.forkEpoch(name)
+	return forkEpoch != nil && epoch >= *forkEpoch
+}
+
+func (this *ChainConfig) forkVersion(epoch uint64) (version [4]byte) {
+	for _, fork := range this.Forks {
+		if epoch >= uint64(fork.Epoch) {
+			copy(version[:], fork.Version)
+		}
+	}
+	return
+}
+
+func (this *ChainConfig) finalizedRootGindex(epoch uint64) uint64 {
+	if this.isActive(FORK_ELECTRA, epoch) {
+		return 169
+	}
+	return 105
+}
+
+func (this *ChainConfig) currentSyncCommitteeGindex(epoch uint64) uint64 {
+	if this.isActive(FORK_ELECTRA, epoch) {
+		return 86
+	}
+	return 54
+}
+
+func (this *ChainConfig) nextSyncCommitteeGindex(epoch uint64) uint64 {
+	if this.isActive(FORK_ELECTRA, epoch) {
+		return 87
+	}
+	return 55
+}
+
+const EXECUTION_PAYLOAD_GINDEX = 25
+
+type BeaconBlockHeader struct {
+	Slot          Uint64       `json:"slot"`
+	ProposerIndex Uint64       `json:"proposer_index"`
+	ParentRoot    ecommon.Hash `json:"parent_root"`
+	StateRoot     ecommon.Hash `json:"state_root"`
+	BodyRoot      ecommon.Hash `json:"body_root"`
+}
+
+func (this *BeaconBlockHeader) HashTreeRoot() ecommon.Hash {
+	return merkleize([]ecommon.Hash{
+		uint64Chunk(uint64(this.Slot)),
+		uint64Chunk(uint64(this.ProposerIndex)),
+		this.ParentRoot,
+		this.StateRoot,
+		this.BodyRoot,
+	}, 3)
+}
+
+type ExecutionPayloadHeader struct {
+	ParentHash       ecommon.Hash    `json:"parent_hash"`
+	FeeRecipient     ecommon.Address `json:"fee_recipient"`
+	StateRoot        ecommon.Hash    `json:"state_root"`
+	ReceiptsRoot     ecommon.Hash    `json:"receipts_root"`
+	LogsBloom        hexutil.Bytes   `json:"logs_bloom"`
+	PrevRandao       ecommon.Hash    `json:"prev_randao"`
+	BlockNumber      Uint64          `json:"block_number"`
+	GasLimit         Uint64          `json:"gas_limit"`
+	GasUsed          Uint64          `json:"gas_used"`
+	Timestamp        Uint64          `json:"timestamp"`
+	ExtraData        hexutil.Bytes   `json:"extra_data"`
+	BaseFeePerGas    *Uint256        `json:"base_fee_per_gas"`
+	BlockHash        ecommon.Hash    `json:"block_hash"`
+	TransactionsRoot ecommon.Hash    `json:"transactions_root"`
+	WithdrawalsRoot  ecommon.Hash    `json:"withdrawals_root"`
+	BlobGasUsed      Uint64          `json:"blob_gas_used"`
+	ExcessBlobGas    Uint64          `json:"excess_blob_gas"`
+}
+
+// HashTreeRoot computes the root of the capella header, or of the deneb header which adds the blob gas fields
+func (this *ExecutionPayloadHeader) HashTreeRoot(deneb bool) (ecommon.Hash, error) {
+	if len(this.LogsBloom) != 256 {
+		return ecommon.Hash{}, fmt.Errorf("invalid logs bloom length: %d", len(this.LogsBloom))
+	}
+	if len(this.ExtraData) > 32 {
+		return ecommon.Hash{}, fmt.Errorf("extra data too long: %d", len(this.ExtraData))
+	}
+	if this.BaseFeePerGas == nil {
+		return ecommon.Hash{}, fmt.Errorf("missing base fee")
+	}
+	var feeRecipient ecommon.Hash
+	copy(feeRecipient[:], this.FeeRecipient[:])
+	fields := []ecommon.Hash{
+		this.ParentHash,
+		feeRecipient,
+		this.StateRoot,
+		this.ReceiptsRoot,
+		bytesRoot(this.LogsBloom, 3),
+		this.PrevRandao,
+		uint64Chunk(uint64(this.BlockNumber)),
+		uint64Chunk(uint64(this.GasLimit)),
+		uint64Chunk(uint64(this.GasUsed)),
+		uint64Chunk(uint64(this.Timestamp)),
+		mixInLength(bytesChunk(this.ExtraData), uint64(len(this.ExtraData))),
+		uint256Chunk((*big.Int)(this.BaseFeePerGas)),
+		this.BlockHash,
+		this.TransactionsRoot,
+		this.WithdrawalsRoot,
+	}
+	if !deneb {
+		return merkleize(fields, 4), nil
+	}
+	fields = append(fields, uint64Chunk(uint64(this.BlobGasUsed)), uint64Chunk(uint64(this.ExcessBlobGas)))
+	return merkleize(fields, 5), nil
+}
+
+type LightClientHeader struct {
+	Beacon          BeaconBlockHeader       `json:"beacon"`
+	Execution       *ExecutionPayloadHeader `json:"execution"`
+	ExecutionBranch []ecommon.Hash          `json:"execution_branch"`
+}
+
+type SyncCommittee struct {
+	Pubkeys         []hexutil.Bytes `json:"pubkeys"`
+	AggregatePubkey hexutil.Bytes   `json:"aggregate_pubkey"`
+}
+
+func (this *SyncCommittee) HashTreeRoot() ecommon.Hash {
+	leaves := make([]ecommon.Hash, len(this.Pubkeys))
+	for i, pk := range this.Pubkeys {
+		leaves[i] = pubkeyRoot(pk)
+	}
+	return hashPair(merkleize(leaves, 9), pubkeyRoot(this.AggregatePubkey))
+}
+
+func (this *SyncCommittee) validate() error {
+	if len(this.Pubkeys) != SYNC_COMMITTEE_SIZE {
+		return fmt.Errorf("invalid sync committee size: %d", len(this.Pubkeys))
+	}
+	for i, pk := range this.Pubkeys {
+		if len(pk) != pubkeyLength {
+			return fmt.Errorf("invalid length of pubkey %d", i)
+		}
+	}
+	if len(this.AggregatePubkey) != pubkeyLength {
+		return fmt.Errorf("invalid length of aggregate pubkey")
+	}
+	return nil
+}
+
+func (this *SyncCommittee) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(uint64(len(this.Pubkeys)))
+	for _, pk := range this.Pubkeys {
+		sink.WriteVarBytes(pk)
+	}
+	sink.WriteVarBytes(this.AggregatePubkey)
+}
+
+func (this *SyncCommittee) Deserialization(source *common.ZeroCopySource) error {
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("SyncCommittee deserialize pubkey count error")
+	}
+	pubkeys := make([]hexutil.Bytes, 0, n)
+	for i := uint64(0); i < n; i++ {
+		pk, eof := source.NextVarBytes()
+		if eof {
+			return fmt.Errorf("SyncCommittee deserialize pubkey error")
+		}
+		pubkeys = append(pubkeys, pk)
+	}
+	aggregate, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("SyncCommittee deserialize aggregate pubkey error")
+	}
+	this.Pubkeys = pubkeys
+	this.AggregatePubkey = aggregate
+	return nil
+}
+
+type SyncAggregate struct {
+	SyncCommitteeBits      hexutil.Bytes `json:"sync_committee_bits"`
+	SyncCommitteeSignature hexutil.Bytes `json:"sync_committee_signature"`
+}
+
+func (this *SyncAggregate) participants() int {
+	n := 0
+	for i := 0; i < SYNC_COMMITTEE_SIZE && i/8 < len(this.SyncCommitteeBits); i++ {
+		if this.SyncCommitteeBits[i/8]&(1<<uint(i%8)) != 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// LightClientUpdate is what a relayer submits as one header of SyncBlockHeader
+type LightClientUpdate struct {
+	AttestedHeader          LightClientHeader  `json:"attested_header"`
+	NextSyncCommittee       *SyncCommittee     `json:"next_sync_committee"`
+	NextSyncCommitteeBranch []ecommon.Hash     `json:"next_sync_committee_branch"`
+	FinalizedHeader         *LightClientHeader `json:"finalized_header"`
+	FinalityBranch          []ecommon.Hash     `json:"finality_branch"`
+	SyncAggregate           SyncAggregate      `json:"sync_aggregate"`
+	SignatureSlot           Uint64             `json:"signature_slot"`
+}
+
+// GenesisHeader is the trusted LightClientBootstrap used to initialize the light client
+type GenesisHeader struct {
+	Config                     ChainConfig       `json:"config"`
+	Header                     LightClientHeader `json:"header"`
+	CurrentSyncCommittee       SyncCommittee     `json:"current_sync_committee"`
+	CurrentSyncCommitteeBranch []ecommon.Hash    `json:"current_sync_committee_branch"`
+}
+
+// Genesis is stored with key GENESIS_HEADER, the bootstrap committee is stored apart
+type Genesis struct {
+	Config ChainConfig       `json:"config"`
+	Header LightClientHeader `json:"header"`
+}
+
+// FinalizedHeader records the execution block of a finalized beacon block
+type FinalizedHeader struct {
+	Slot        uint64
+	BeaconRoot  ecommon.Hash
+	BlockNumber uint64
+	BlockHash   ecommon.Hash
+	StateRoot   ecommon.Hash
+}
+
+func (this *FinalizedHeader) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.Slot)
+	sink.WriteVarBytes(this.BeaconRoot[:])
+	sink.WriteUint64(this.BlockNumber)
+	sink.WriteVarBytes(this.BlockHash[:])
+	sink.WriteVarBytes(this.StateRoot[:])
+}
+
+func (this *FinalizedHeader) Deserialization(source *common.ZeroCopySource) error {
+	slot, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("FinalizedHeader deserialize slot error")
+	}
+	beaconRoot, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("FinalizedHeader deserialize beacon root error")
+	}
+	number, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("FinalizedHeader deserialize block number error")
+	}
+	blockHash, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("FinalizedHeader deserialize block hash error")
+	}
+	stateRoot, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("FinalizedHeader deserialize state root error")
+	}
+	this.Slot = slot
+	this.BeaconRoot = ecommon.BytesToHash(beaconRoot)
+	this.BlockNumber = number
+	this.BlockHash = ecommon.BytesToHash(blockHash)
+	this.StateRoot = ecommon.BytesToHash(stateRoot)
+	return nil
+}
+
+// LightClientStore is the latest finalized view of the light client
+type LightClientStore struct {
+	FinalizedSlot        uint64
+	FinalizedBlockNumber uint64
+}
+
+func (this *LightClientStore) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.FinalizedSlot)
+	sink.WriteUint64(this.FinalizedBlockNumber)
+}
+
+func (this *LightClientStore) Deserialization(source *common.ZeroCopySource) error {
+	slot, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("LightClientStore deserialize finalized slot error")
+	}
+	number, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("LightClientStore deserialize finalized block number error")
+	}
+	this.FinalizedSlot = slot
+	this.FinalizedBlockNumber = number
+	return nil
+}
+
+func computeEpoch(slot uint64) uint64 {
+	return slot / SLOTS_PER_EPOCH
+}
+
+func computePeriod(slot uint64) uint64 {
+	return computeEpoch(slot) / EPOCHS_PER_SYNC_COMMITTEE_PERIOD
+}
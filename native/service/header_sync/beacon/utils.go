@@ -0,0 +1,142 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package beacon
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	cstates "github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+	scom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+func getGenesis(native *native.NativeService, chainID uint64) (*Genesis, error) {
+	genesisStore, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.GENESIS_HEADER), utils.GetUint64Bytes(chainID)))
+	if err != nil {
+		return nil, fmt.Errorf("getGenesis, get genesisStore error: %v", err)
+	}
+	if genesisStore == nil {
+		return nil, nil
+	}
+	genesisBytes, err := cstates.GetValueFromRawStorageItem(genesisStore)
+	if err != nil {
+		return nil, fmt.Errorf("getGenesis, deserialize from raw storage item err: %v", err)
+	}
+	genesis := new(Genesis)
+	if err := json.Unmarshal(genesisBytes, genesis); err != nil {
+		return nil, fmt.Errorf("getGenesis, unmarshal genesis err: %v", err)
+	}
+	return genesis, nil
+}
+
+func putGenesis(native *native.NativeService, chainID uint64, genesis *Genesis) error {
+	genesisBytes, err := json.Marshal(genesis)
+	if err != nil {
+		return fmt.Errorf("putGenesis, marshal genesis err: %v", err)
+	}
+	native.GetCacheDB().Put(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.GENESIS_HEADER), utils.GetUint64Bytes(chainID)),
+		cstates.GenRawStorageItem(genesisBytes))
+	return nil
+}
+
+func getSyncCommittee(native *native.NativeService, chainID, period uint64) (*SyncCommittee, error) {
+	committeeStore, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.SYNC_COMMITTEE),
+		utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(period)))
+	if err != nil {
+		return nil, fmt.Errorf("getSyncCommittee, get committeeStore error: %v", err)
+	}
+	if committeeStore == nil {
+		return nil, nil
+	}
+	committeeBytes, err := cstates.GetValueFromRawStorageItem(committeeStore)
+	if err != nil {
+		return nil, fmt.Errorf("getSyncCommittee, deserialize from raw storage item err: %v", err)
+	}
+	committee := new(SyncCommittee)
+	if err := committee.Deserialization(common.NewZeroCopySource(committeeBytes)); err != nil {
+		return nil, fmt.Errorf("getSyncCommittee, deserialize committee err: %v", err)
+	}
+	return committee, nil
+}
+
+func putSyncCommittee(native *native.NativeService, chainID, period uint64, committee *SyncCommittee) {
+	sink := common.NewZeroCopySink(nil)
+	committee.Serialization(sink)
+	native.GetCacheDB().Put(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.SYNC_COMMITTEE),
+		utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(period)), cstates.GenRawStorageItem(sink.Bytes()))
+}
+
+// GetLightClientStore returns the finalized view of the light client of chainID
+func GetLightClientStore(native *native.NativeService, chainID uint64) (*LightClientStore, error) {
+	storeBytes, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.LIGHT_CLIENT_STORE), utils.GetUint64Bytes(chainID)))
+	if err != nil {
+		return nil, fmt.Errorf("GetLightClientStore, get storeBytes error: %v", err)
+	}
+	if storeBytes == nil {
+		return nil, fmt.Errorf("GetLightClientStore, light client of chain %d is not initialized", chainID)
+	}
+	value, err := cstates.GetValueFromRawStorageItem(storeBytes)
+	if err != nil {
+		return nil, fmt.Errorf("GetLightClientStore, deserialize from raw storage item err: %v", err)
+	}
+	store := new(LightClientStore)
+	if err := store.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return nil, fmt.Errorf("GetLightClientStore, deserialize store err: %v", err)
+	}
+	return store, nil
+}
+
+func putLightClientStore(native *native.NativeService, chainID uint64, store *LightClientStore) {
+	sink := common.NewZeroCopySink(nil)
+	store.Serialization(sink)
+	native.GetCacheDB().Put(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.LIGHT_CLIENT_STORE), utils.GetUint64Bytes(chainID)),
+		cstates.GenRawStorageItem(sink.Bytes()))
+	native.GetCacheDB().Put(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.CURRENT_HEADER_HEIGHT), utils.GetUint64Bytes(chainID)),
+		cstates.GenRawStorageItem(utils.GetUint64Bytes(store.FinalizedBlockNumber)))
+}
+
+// GetFinalizedHeader returns the finalized execution header of height, which must have been synced
+func GetFinalizedHeader(native *native.NativeService, chainID, height uint64) (*FinalizedHeader, error) {
+	headerStore, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.MAIN_CHAIN),
+		utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(height)))
+	if err != nil {
+		return nil, fmt.Errorf("GetFinalizedHeader, get headerStore error: %v", err)
+	}
+	if headerStore == nil {
+		return nil, fmt.Errorf("GetFinalizedHeader, can not find finalized header of height %d", height)
+	}
+	headerBytes, err := cstates.GetValueFromRawStorageItem(headerStore)
+	if err != nil {
+		return nil, fmt.Errorf("GetFinalizedHeader, deserialize from raw storage item err: %v", err)
+	}
+	header := new(FinalizedHeader)
+	if err := header.Deserialization(common.NewZeroCopySource(headerBytes)); err != nil {
+		return nil, fmt.Errorf("GetFinalizedHeader, deserialize header err: %v", err)
+	}
+	return header, nil
+}
+
+func putFinalizedHeader(native *native.NativeService, chainID uint64, header *FinalizedHeader) {
+	sink := common.NewZeroCopySink(nil)
+	header.Serialization(sink)
+	native.GetCacheDB().Put(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.MAIN_CHAIN),
+		utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(header.BlockNumber)), cstates.GenRawStorageItem(sink.Bytes()))
+}
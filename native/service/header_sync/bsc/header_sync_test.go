@@ -41,6 +41,7 @@ import (
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	scom "github.com/polynetwork/poly/native/service/header_sync/common"
+	heth "github.com/polynetwork/poly/native/service/header_sync/eth"
 	"github.com/polynetwork/poly/native/service/utils"
 	"github.com/polynetwork/poly/native/storage"
 	"gotest.tools/assert"
@@ -195,7 +196,7 @@ func getGenesisHeaderByHeight(t *testing.T, epochHeight uint64) *GenesisHeader {
 	pvalidators, err := ParseValidators(phdr.Extra[32 : len(phdr.Extra)-65])
 	assert.NilError(t, err)
 
-	genesisHeader := GenesisHeader{Header: *hdr, PrevValidators: []HeightAndValidators{
+	genesisHeader := GenesisHeader{Header: *heth.To1559(hdr), PrevValidators: []HeightAndValidators{
 		{Height: big.NewInt(int64(pEpochHeight)), Validators: pvalidators},
 	}}
 
@@ -541,7 +542,7 @@ func TestSyncBlockHeaderForked(t *testing.T) {
 		param.Address = acct.Address
 
 		header := getBlockHeader(t, interestedHeight)
-		signer, err := ecrecover(header, testnetChainID)
+		signer, err := ecrecover(heth.To1559(header), testnetChainID)
 		assert.Equal(t, SUCCESS, typeOfError(err), err)
 		mockSigner = signer
 		oldHash := header.Hash()
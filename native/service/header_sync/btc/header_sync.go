@@ -41,6 +41,10 @@ func NewBTCHandler() *BTCHandler {
 	return &BTCHandler{}
 }
 
+func init() {
+	scom.RegisterHeaderSyncHandler(utils.BTC_ROUTER, func() scom.HeaderSyncHandler { return NewBTCHandler() })
+}
+
 func (this *BTCHandler) SyncGenesisHeader(native *native.NativeService) error {
 	params := new(scom.SyncGenesisHeaderParam)
 	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
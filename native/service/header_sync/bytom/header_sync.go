@@ -30,9 +30,12 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/common/constants"
 	"github.com/polynetwork/poly/common/log"
 	cstates "github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/fork_manager"
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	scom "github.com/polynetwork/poly/native/service/header_sync/common"
@@ -49,6 +52,11 @@ func NewHandler() *Handler {
 	return &Handler{}
 }
 
+func init() {
+	scom.RegisterHeaderSyncHandler(utils.BYTOM_ROUTER, func() scom.HeaderSyncHandler { return NewHandler() })
+	fork_manager.RegisterRouterStartBlock(utils.BYTOM_ROUTER, config.NETWORK_ID_MAIN_NET, constants.ROUTER_START_HEIGHT_MAINNET)
+}
+
 // GenesisHeader ...
 type GenesisHeader struct {
 	Header         types.Header
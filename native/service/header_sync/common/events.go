@@ -0,0 +1,52 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"github.com/polynetwork/poly/native/event"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+var (
+	SyncHeaderEvent = event.RegisterEventSchema(utils.HeaderSyncContractAddress, SYNC_HEADER_NAME,
+		event.Field("ChainID", event.FIELD_UINT64),
+		event.Field("Height", event.FIELD_UINT64),
+		event.Field("BlockHash", event.FIELD_STRING),
+		event.Field("PolyHeight", event.FIELD_UINT32))
+	SyncCrossChainMsgEvent = event.RegisterEventSchema(utils.HeaderSyncContractAddress, SYNC_CROSSCHAIN_MSG,
+		event.Field("ChainID", event.FIELD_UINT64),
+		event.Field("Height", event.FIELD_UINT32),
+		event.Field("PolyHeight", event.FIELD_UINT32))
+	// EpochSwitchEvent is emitted by the header sync of tendermint based chains without event name
+	EpochSwitchEvent = event.RegisterAnonymousEventSchema(utils.HeaderSyncContractAddress, EPOCH_SWITCH,
+		event.Field("ChainID", event.FIELD_UINT64),
+		event.Field("BlockHash", event.FIELD_STRING),
+		event.Field("Height", event.FIELD_INT64),
+		event.Field("NextValidatorsHash", event.FIELD_STRING),
+		event.Field("SideChainID", event.FIELD_STRING),
+		event.Field("PolyHeight", event.FIELD_UINT32))
+	SetHeaderRetentionEvent = event.RegisterEventSchema(utils.HeaderSyncContractAddress, SET_HEADER_RETENTION,
+		event.Field("ChainID", event.FIELD_UINT64),
+		event.Field("KeepHeaders", event.FIELD_UINT64))
+	// HeaderPrunedEvent is emitted when the canonical headers in [From, To) are pruned
+	HeaderPrunedEvent = event.RegisterEventSchema(utils.HeaderSyncContractAddress, HEADER_PRUNED,
+		event.Field("ChainID", event.FIELD_UINT64),
+		event.Field("From", event.FIELD_UINT64),
+		event.Field("To", event.FIELD_UINT64),
+		event.Field("PolyHeight", event.FIELD_UINT32))
+)
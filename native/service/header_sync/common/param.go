@@ -23,8 +23,6 @@ import (
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/native"
-	"github.com/polynetwork/poly/native/event"
-	"github.com/polynetwork/poly/native/service/utils"
 )
 
 const (
@@ -44,6 +42,8 @@ const (
 	SYNC_HEADER_NAME            = "syncHeader"
 	SYNC_CROSSCHAIN_MSG         = "syncCrossChainMsg"
 	POLYGON_SPAN                = "polygonSpan"
+	SYNC_COMMITTEE              = "syncCommittee"
+	LIGHT_CLIENT_STORE          = "lightClientStore"
 )
 
 const (
@@ -172,20 +172,12 @@ func NotifyPutHeader(native *native.NativeService, chainID uint64, height uint64
 	if !config.DefConfig.Common.EnableEventLog {
 		return
 	}
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.HeaderSyncContractAddress,
-			States:          []interface{}{SYNC_HEADER_NAME, chainID, height, blockHash, native.GetHeight()},
-		})
+	native.AddNotify(SyncHeaderEvent.NewNotify(chainID, height, blockHash, native.GetHeight()))
 }
 
 func NotifyPutCrossChainMsg(native *native.NativeService, chainID uint64, height uint32) {
 	if !config.DefConfig.Common.EnableEventLog {
 		return
 	}
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.HeaderSyncContractAddress,
-			States:          []interface{}{SYNC_CROSSCHAIN_MSG, chainID, height, native.GetHeight()},
-		})
+	native.AddNotify(SyncCrossChainMsgEvent.NewNotify(chainID, height, native.GetHeight()))
 }
@@ -0,0 +1,351 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/polynetwork/poly/common"
+	cstates "github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+const (
+	//key prefix
+	HEADER_RETENTION      = "headerRetention"
+	HEADER_PRUNED_HEIGHT  = "headerPrunedHeight"
+	HEADER_PIN            = "pinnedHeader"
+	HEADER_PINNED_HEIGHTS = "pinnedHeights"
+	HEADER_HEIGHT_INDEX   = "headerHeightIndex"
+
+	SET_HEADER_RETENTION = "setHeaderRetention"
+	HEADER_PRUNED        = "headerPruned"
+
+	// MIN_KEEP_HEADERS covers the epoch and snapshot lookback of the handlers verifying new headers
+	MIN_KEEP_HEADERS = 1024
+	// HEADER_PRUNE_BATCH is the max number of heights pruned by one SyncBlockHeader
+	HEADER_PRUNE_BATCH = 256
+)
+
+// HeaderStoragePrefixes are the key prefixes under which handlers keep the headers of a side chain,
+// all of them are followed by the side chain id
+var HeaderStoragePrefixes = []string{BLOCK_HEADER, HEADER_INDEX, MAIN_CHAIN, HEADER_HEIGHT_INDEX}
+
+// HeaderPruner is implemented by handlers whose canonical headers can be pruned by the header retention policy
+type HeaderPruner interface {
+	// GetHeaderRange returns the height of the genesis header and the latest canonical header
+	GetHeaderRange(service *native.NativeService, chainID uint64) (genesis, current uint64, err error)
+	// PruneHeader deletes the canonical header at height, and the headers of side forks indexed by IndexHeader
+	PruneHeader(service *native.NativeService, chainID uint64, height uint64) error
+}
+
+// HeaderRetention keeps the last KeepHeaders headers past the finality depth (BlocksToWait) of a side chain
+type HeaderRetention struct {
+	ChainID uint64
+	// 0 means all headers are kept
+	KeepHeaders uint64
+}
+
+func (this *HeaderRetention) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(this.ChainID)
+	sink.WriteVarUint(this.KeepHeaders)
+}
+
+func (this *HeaderRetention) Deserialization(source *common.ZeroCopySource) error {
+	chainID, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("HeaderRetention deserialize chainID error")
+	}
+	keepHeaders, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("HeaderRetention deserialize keepHeaders error")
+	}
+	this.ChainID = chainID
+	this.KeepHeaders = keepHeaders
+	return nil
+}
+
+func PutHeaderRetention(native *native.NativeService, retention *HeaderRetention) {
+	key := utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(HEADER_RETENTION), utils.GetUint64Bytes(retention.ChainID))
+	if retention.KeepHeaders == 0 {
+		native.GetCacheDB().Delete(key)
+		return
+	}
+	sink := common.NewZeroCopySink(nil)
+	retention.Serialization(sink)
+	native.GetCacheDB().Put(key, cstates.GenRawStorageItem(sink.Bytes()))
+}
+
+// GetHeaderRetention returns nil if all headers of chainID are kept
+func GetHeaderRetention(native *native.NativeService, chainID uint64) (*HeaderRetention, error) {
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(HEADER_RETENTION),
+		utils.GetUint64Bytes(chainID)))
+	if err != nil {
+		return nil, fmt.Errorf("GetHeaderRetention, get header retention error: %v", err)
+	}
+	if store == nil {
+		return nil, nil
+	}
+	value, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("GetHeaderRetention, deserialize from raw storage item err: %v", err)
+	}
+	retention := new(HeaderRetention)
+	if err := retention.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return nil, fmt.Errorf("GetHeaderRetention, deserialize HeaderRetention error: %v", err)
+	}
+	return retention, nil
+}
+
+// IndexHeader records the hash of a header stored under HEADER_INDEX at its height, so the headers of side forks
+// are pruned with the canonical one. Nothing is recorded for chains without retention policy, so the headers of
+// side forks stored before the policy is set are kept.
+func IndexHeader(native *native.NativeService, chainID uint64, height uint64, hash []byte) error {
+	retention, err := GetHeaderRetention(native, chainID)
+	if err != nil {
+		return err
+	}
+	if retention == nil {
+		return nil
+	}
+	hashes, err := getHeaderHeightIndex(native, chainID, height)
+	if err != nil {
+		return err
+	}
+	for _, h := range hashes {
+		if bytes.Equal(h, hash) {
+			return nil
+		}
+	}
+	hashes = append(hashes, hash)
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteVarUint(uint64(len(hashes)))
+	for _, h := range hashes {
+		sink.WriteVarBytes(h)
+	}
+	native.GetCacheDB().Put(headerHeightIndexKey(chainID, height), cstates.GenRawStorageItem(sink.Bytes()))
+	return nil
+}
+
+// PruneIndexedHeaders deletes the headers recorded by IndexHeader at height from HEADER_INDEX
+func PruneIndexedHeaders(native *native.NativeService, chainID uint64, height uint64) error {
+	hashes, err := getHeaderHeightIndex(native, chainID, height)
+	if err != nil {
+		return err
+	}
+	for _, hash := range hashes {
+		native.GetCacheDB().Delete(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(HEADER_INDEX),
+			utils.GetUint64Bytes(chainID), hash))
+	}
+	native.GetCacheDB().Delete(headerHeightIndexKey(chainID, height))
+	return nil
+}
+
+func getHeaderHeightIndex(native *native.NativeService, chainID uint64, height uint64) ([][]byte, error) {
+	store, err := native.GetCacheDB().Get(headerHeightIndexKey(chainID, height))
+	if err != nil {
+		return nil, fmt.Errorf("getHeaderHeightIndex, get header height index error: %v", err)
+	}
+	if store == nil {
+		return nil, nil
+	}
+	value, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("getHeaderHeightIndex, deserialize from raw storage item err: %v", err)
+	}
+	source := common.NewZeroCopySource(value)
+	n, eof := source.NextVarUint()
+	if eof {
+		return nil, fmt.Errorf("getHeaderHeightIndex, deserialize length error")
+	}
+	hashes := make([][]byte, 0, n)
+	for i := uint64(0); i < n; i++ {
+		hash, eof := source.NextVarBytes()
+		if eof {
+			return nil, fmt.Errorf("getHeaderHeightIndex, deserialize hash error")
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, nil
+}
+
+func headerHeightIndexKey(chainID uint64, height uint64) []byte {
+	return utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(HEADER_HEIGHT_INDEX), utils.GetUint64Bytes(chainID),
+		utils.GetUint64Bytes(height))
+}
+
+func PutHeaderPrunedHeight(native *native.NativeService, chainID uint64, height uint64) {
+	native.GetCacheDB().Put(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(HEADER_PRUNED_HEIGHT), utils.GetUint64Bytes(chainID)),
+		cstates.GenRawStorageItem(utils.GetUint64Bytes(height)))
+}
+
+// GetHeaderPrunedHeight returns the height below which the canonical headers of chainID are pruned,
+// ok is false if nothing is pruned yet
+func GetHeaderPrunedHeight(native *native.NativeService, chainID uint64) (height uint64, ok bool, err error) {
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(HEADER_PRUNED_HEIGHT),
+		utils.GetUint64Bytes(chainID)))
+	if err != nil {
+		return 0, false, fmt.Errorf("GetHeaderPrunedHeight, get pruned height error: %v", err)
+	}
+	if store == nil {
+		return 0, false, nil
+	}
+	value, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return 0, false, fmt.Errorf("GetHeaderPrunedHeight, deserialize from raw storage item err: %v", err)
+	}
+	return utils.GetBytesUint64(value), true, nil
+}
+
+// PinnedHeights counts the pins of every pinned height of a side chain
+type PinnedHeights struct {
+	Pins map[uint64]uint64
+}
+
+func (this *PinnedHeights) Serialization(sink *common.ZeroCopySink) {
+	heights := make([]uint64, 0, len(this.Pins))
+	for height := range this.Pins {
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	sink.WriteVarUint(uint64(len(heights)))
+	for _, height := range heights {
+		sink.WriteVarUint(height)
+		sink.WriteVarUint(this.Pins[height])
+	}
+}
+
+func (this *PinnedHeights) Deserialization(source *common.ZeroCopySource) error {
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("PinnedHeights deserialize length error")
+	}
+	pins := make(map[uint64]uint64)
+	for i := uint64(0); i < n; i++ {
+		height, eof := source.NextVarUint()
+		if eof {
+			return fmt.Errorf("PinnedHeights deserialize height error")
+		}
+		count, eof := source.NextVarUint()
+		if eof {
+			return fmt.Errorf("PinnedHeights deserialize count error")
+		}
+		pins[height] = count
+	}
+	this.Pins = pins
+	return nil
+}
+
+// Lowest returns the lowest pinned height, ok is false if no height is pinned
+func (this *PinnedHeights) Lowest() (height uint64, ok bool) {
+	for h := range this.Pins {
+		if !ok || h < height {
+			height, ok = h, true
+		}
+	}
+	return
+}
+
+func GetPinnedHeights(native *native.NativeService, chainID uint64) (*PinnedHeights, error) {
+	pinned := &PinnedHeights{Pins: make(map[uint64]uint64)}
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(HEADER_PINNED_HEIGHTS),
+		utils.GetUint64Bytes(chainID)))
+	if err != nil {
+		return nil, fmt.Errorf("GetPinnedHeights, get pinned heights error: %v", err)
+	}
+	if store == nil {
+		return pinned, nil
+	}
+	value, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("GetPinnedHeights, deserialize from raw storage item err: %v", err)
+	}
+	if err := pinned.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return nil, fmt.Errorf("GetPinnedHeights, deserialize PinnedHeights error: %v", err)
+	}
+	return pinned, nil
+}
+
+func putPinnedHeights(native *native.NativeService, chainID uint64, pinned *PinnedHeights) {
+	key := utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(HEADER_PINNED_HEIGHTS), utils.GetUint64Bytes(chainID))
+	if len(pinned.Pins) == 0 {
+		native.GetCacheDB().Delete(key)
+		return
+	}
+	sink := common.NewZeroCopySink(nil)
+	pinned.Serialization(sink)
+	native.GetCacheDB().Put(key, cstates.GenRawStorageItem(sink.Bytes()))
+}
+
+// PinHeader keeps the header of chainID at height from pruning until UnpinHeader is called with the same id,
+// it is used for the header a cross chain proof is verified against while the proven tx is still queued
+func PinHeader(native *native.NativeService, chainID uint64, id []byte, height uint64) error {
+	key := utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(HEADER_PIN), utils.GetUint64Bytes(chainID), id)
+	store, err := native.GetCacheDB().Get(key)
+	if err != nil {
+		return fmt.Errorf("PinHeader, get header pin error: %v", err)
+	}
+	if store != nil {
+		return nil
+	}
+	pinned, err := GetPinnedHeights(native, chainID)
+	if err != nil {
+		return fmt.Errorf("PinHeader, %v", err)
+	}
+	pinned.Pins[height]++
+	putPinnedHeights(native, chainID, pinned)
+	native.GetCacheDB().Put(key, cstates.GenRawStorageItem(utils.GetUint64Bytes(height)))
+	return nil
+}
+
+// UnpinHeader releases the pin of id, it does nothing if id pinned nothing
+func UnpinHeader(native *native.NativeService, chainID uint64, id []byte) error {
+	key := utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(HEADER_PIN), utils.GetUint64Bytes(chainID), id)
+	store, err := native.GetCacheDB().Get(key)
+	if err != nil {
+		return fmt.Errorf("UnpinHeader, get header pin error: %v", err)
+	}
+	if store == nil {
+		return nil
+	}
+	value, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return fmt.Errorf("UnpinHeader, deserialize from raw storage item err: %v", err)
+	}
+	height := utils.GetBytesUint64(value)
+	pinned, err := GetPinnedHeights(native, chainID)
+	if err != nil {
+		return fmt.Errorf("UnpinHeader, %v", err)
+	}
+	if pinned.Pins[height] <= 1 {
+		delete(pinned.Pins, height)
+	} else {
+		pinned.Pins[height]--
+	}
+	putPinnedHeights(native, chainID, pinned)
+	native.GetCacheDB().Delete(key)
+	return nil
+}
+
+// NotifyHeaderPruned is emitted regardless of the event log config so that a tx has the same notifies on every node
+func NotifyHeaderPruned(native *native.NativeService, chainID uint64, from, to uint64) {
+	native.AddNotify(HeaderPrunedEvent.NewNotify(chainID, from, to, native.GetHeight()))
+}
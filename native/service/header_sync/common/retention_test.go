@@ -0,0 +1,103 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestNative() *native.NativeService {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	ns, _ := native.NewNativeService(db, &types.Transaction{}, 0, 0, common.Uint256{0}, 0, nil, false)
+	return ns
+}
+
+func TestHeaderRetention(t *testing.T) {
+	param := HeaderRetention{ChainID: 2, KeepHeaders: 10000}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	var p HeaderRetention
+	assert.NoError(t, p.Deserialization(common.NewZeroCopySource(sink.Bytes())))
+	assert.Equal(t, param, p)
+
+	ns := newTestNative()
+	retention, err := GetHeaderRetention(ns, 2)
+	assert.NoError(t, err)
+	assert.Nil(t, retention)
+	PutHeaderRetention(ns, &param)
+	retention, err = GetHeaderRetention(ns, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, param, *retention)
+	PutHeaderRetention(ns, &HeaderRetention{ChainID: 2})
+	retention, err = GetHeaderRetention(ns, 2)
+	assert.NoError(t, err)
+	assert.Nil(t, retention)
+
+	_, ok, err := GetHeaderPrunedHeight(ns, 2)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	PutHeaderPrunedHeight(ns, 2, 100)
+	height, ok, err := GetHeaderPrunedHeight(ns, 2)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(100), height)
+}
+
+func TestPinHeader(t *testing.T) {
+	ns := newTestNative()
+	lowest := func() (uint64, bool) {
+		pinned, err := GetPinnedHeights(ns, 2)
+		assert.NoError(t, err)
+		return pinned.Lowest()
+	}
+
+	_, ok := lowest()
+	assert.False(t, ok)
+
+	assert.NoError(t, PinHeader(ns, 2, []byte{1}, 200))
+	assert.NoError(t, PinHeader(ns, 2, []byte{2}, 100))
+	assert.NoError(t, PinHeader(ns, 2, []byte{3}, 100))
+	// pinning the same id again is ignored
+	assert.NoError(t, PinHeader(ns, 2, []byte{1}, 50))
+	// pins of other chains are apart
+	assert.NoError(t, PinHeader(ns, 3, []byte{1}, 10))
+	height, ok := lowest()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(100), height)
+
+	assert.NoError(t, UnpinHeader(ns, 2, []byte{2}))
+	height, _ = lowest()
+	assert.Equal(t, uint64(100), height)
+	assert.NoError(t, UnpinHeader(ns, 2, []byte{3}))
+	height, _ = lowest()
+	assert.Equal(t, uint64(200), height)
+	// unpinning an unknown id does nothing
+	assert.NoError(t, UnpinHeader(ns, 2, []byte{3}))
+	assert.NoError(t, UnpinHeader(ns, 2, []byte{1}))
+	_, ok = lowest()
+	assert.False(t, ok)
+}
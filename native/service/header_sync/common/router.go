@@ -0,0 +1,52 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"fmt"
+	"sort"
+)
+
+var headerSyncHandlers = make(map[uint64]func() HeaderSyncHandler)
+
+// RegisterHeaderSyncHandler is called by a chain package from its init,
+// every router can only be registered once
+func RegisterHeaderSyncHandler(router uint64, newHandler func() HeaderSyncHandler) {
+	if _, ok := headerSyncHandlers[router]; ok {
+		panic(fmt.Sprintf("header sync handler of router %d registered twice", router))
+	}
+	headerSyncHandlers[router] = newHandler
+}
+
+func GetHeaderSyncHandler(router uint64) (HeaderSyncHandler, error) {
+	newHandler, ok := headerSyncHandlers[router]
+	if !ok {
+		return nil, fmt.Errorf("not a supported router:%d", router)
+	}
+	return newHandler(), nil
+}
+
+// GetHeaderSyncRouters returns all registered routers in ascending order
+func GetHeaderSyncRouters() []uint64 {
+	routers := make([]uint64, 0, len(headerSyncHandlers))
+	for router := range headerSyncHandlers {
+		routers = append(routers, router)
+	}
+	sort.Slice(routers, func(i, j int) bool { return routers[i] < routers[j] })
+	return routers
+}
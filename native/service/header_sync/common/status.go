@@ -0,0 +1,194 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native"
+)
+
+const GET_SIDE_CHAIN_STATUS = "getSideChainStatus"
+
+// HeaderSyncQuerier is implemented by handlers which can report how far the headers of a side chain are synced.
+// The handlers of the btc, neo, neo3, quorum, zilliqa, okex, starcoin, harmony, bytom and beacon routers (and of
+// the legacy zilliqa and neo3 routers) do not implement it, their chains are reported without Sync
+type HeaderSyncQuerier interface {
+	// GetHeaderSyncStatus returns nil if the genesis header of chainID is not synced
+	GetHeaderSyncStatus(service *native.NativeService, chainID uint64) (*HeaderSyncStatus, error)
+}
+
+// HeaderSyncStatus is the latest header of a side chain synced to poly and the validators verifying its children
+type HeaderSyncStatus struct {
+	// GenesisHeight and GenesisHash are the anchor synced by SyncGenesisHeader,
+	// GenesisHash is empty if the handler does not keep the genesis header
+	GenesisHeight uint64
+	GenesisHash   []byte
+	Height        uint64
+	Hash          []byte
+	// EpochHeight is the height of the header which set Validators or ValidatorsHash
+	EpochHeight uint64
+	Validators  [][]byte
+	// ValidatorsHash is set instead of Validators by handlers which only keep the commitment of the validators
+	ValidatorsHash []byte
+}
+
+func (this *HeaderSyncStatus) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(this.GenesisHeight)
+	sink.WriteVarBytes(this.GenesisHash)
+	sink.WriteVarUint(this.Height)
+	sink.WriteVarBytes(this.Hash)
+	sink.WriteVarUint(this.EpochHeight)
+	sink.WriteVarUint(uint64(len(this.Validators)))
+	for _, v := range this.Validators {
+		sink.WriteVarBytes(v)
+	}
+	sink.WriteVarBytes(this.ValidatorsHash)
+}
+
+func (this *HeaderSyncStatus) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	this.GenesisHeight, eof = source.NextVarUint()
+	if eof {
+		return fmt.Errorf("HeaderSyncStatus deserialize genesisHeight error")
+	}
+	this.GenesisHash, eof = source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("HeaderSyncStatus deserialize genesisHash error")
+	}
+	this.Height, eof = source.NextVarUint()
+	if eof {
+		return fmt.Errorf("HeaderSyncStatus deserialize height error")
+	}
+	this.Hash, eof = source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("HeaderSyncStatus deserialize hash error")
+	}
+	this.EpochHeight, eof = source.NextVarUint()
+	if eof {
+		return fmt.Errorf("HeaderSyncStatus deserialize epochHeight error")
+	}
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("HeaderSyncStatus deserialize validators length error")
+	}
+	validators := make([][]byte, 0)
+	for i := uint64(0); i < n; i++ {
+		v, eof := source.NextVarBytes()
+		if eof {
+			return fmt.Errorf("HeaderSyncStatus deserialize validator error")
+		}
+		validators = append(validators, v)
+	}
+	this.Validators = validators
+	this.ValidatorsHash, eof = source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("HeaderSyncStatus deserialize validatorsHash error")
+	}
+	return nil
+}
+
+// SideChainStatus is the view of poly on a registered side chain
+type SideChainStatus struct {
+	ChainID      uint64
+	Router       uint64
+	BlocksToWait uint64
+	// FinalizedHeight is the latest synced height which is BlocksToWait deep
+	FinalizedHeight uint64
+	// Sync is nil if the handler of Router is no HeaderSyncQuerier or the genesis header is not synced
+	Sync *HeaderSyncStatus
+	// Error is the reason the status of the chain fails to be queried, empty on success
+	Error string
+}
+
+func (this *SideChainStatus) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(this.ChainID)
+	sink.WriteVarUint(this.Router)
+	sink.WriteVarUint(this.BlocksToWait)
+	sink.WriteVarUint(this.FinalizedHeight)
+	sink.WriteBool(this.Sync != nil)
+	if this.Sync != nil {
+		this.Sync.Serialization(sink)
+	}
+	sink.WriteString(this.Error)
+}
+
+func (this *SideChainStatus) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	this.ChainID, eof = source.NextVarUint()
+	if eof {
+		return fmt.Errorf("SideChainStatus deserialize chainID error")
+	}
+	this.Router, eof = source.NextVarUint()
+	if eof {
+		return fmt.Errorf("SideChainStatus deserialize router error")
+	}
+	this.BlocksToWait, eof = source.NextVarUint()
+	if eof {
+		return fmt.Errorf("SideChainStatus deserialize blocksToWait error")
+	}
+	this.FinalizedHeight, eof = source.NextVarUint()
+	if eof {
+		return fmt.Errorf("SideChainStatus deserialize finalizedHeight error")
+	}
+	synced, eof := source.NextBool()
+	if eof {
+		return fmt.Errorf("SideChainStatus deserialize sync flag error")
+	}
+	this.Sync = nil
+	if synced {
+		this.Sync = new(HeaderSyncStatus)
+		if err := this.Sync.Deserialization(source); err != nil {
+			return fmt.Errorf("SideChainStatus deserialize sync error: %v", err)
+		}
+	}
+	this.Error, eof = source.NextString()
+	if eof {
+		return fmt.Errorf("SideChainStatus deserialize error message error")
+	}
+	return nil
+}
+
+type SideChainStatusList struct {
+	List []*SideChainStatus
+}
+
+func (this *SideChainStatusList) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(uint64(len(this.List)))
+	for _, status := range this.List {
+		status.Serialization(sink)
+	}
+}
+
+func (this *SideChainStatusList) Deserialization(source *common.ZeroCopySource) error {
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("SideChainStatusList deserialize length error")
+	}
+	list := make([]*SideChainStatus, 0)
+	for i := uint64(0); i < n; i++ {
+		status := new(SideChainStatus)
+		if err := status.Deserialization(source); err != nil {
+			return fmt.Errorf("SideChainStatusList deserialize status error: %v", err)
+		}
+		list = append(list, status)
+	}
+	this.List = list
+	return nil
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSideChainStatusList(t *testing.T) {
+	list := &SideChainStatusList{List: []*SideChainStatus{
+		{ChainID: 2, Router: 2, BlocksToWait: 12, FinalizedHeight: 188, Sync: &HeaderSyncStatus{
+			GenesisHeight:  100,
+			GenesisHash:    []byte{1},
+			Height:         200,
+			Hash:           []byte{2},
+			EpochHeight:    150,
+			Validators:     [][]byte{{3}, {4}},
+			ValidatorsHash: []byte{},
+		}},
+		{ChainID: 5, Router: 5, BlocksToWait: 1},
+		{ChainID: 6, Router: 6, BlocksToWait: 21, Error: "no header"},
+	}}
+	sink := common.NewZeroCopySink(nil)
+	list.Serialization(sink)
+	var got SideChainStatusList
+	assert.NoError(t, got.Deserialization(common.NewZeroCopySource(sink.Bytes())))
+	assert.Equal(t, list, &got)
+
+	// truncated
+	assert.Error(t, got.Deserialization(common.NewZeroCopySource(sink.Bytes()[:sink.Size()-1])))
+}
@@ -45,6 +45,7 @@ var Cdc = codec.New()
 
 func init() {
 	RegisterCodec(Cdc)
+	hscommon.RegisterHeaderSyncHandler(utils.COSMOS_ROUTER, func() hscommon.HeaderSyncHandler { return NewCosmosHandler() })
 }
 
 func RegisterCodec(cdc *codec.Codec) {
@@ -137,3 +138,26 @@ func (this *CosmosHandler) SyncBlockHeader(native *native.NativeService) error {
 func (this *CosmosHandler) SyncCrossChainMsg(native *native.NativeService) error {
 	return nil
 }
+
+// GetHeaderSyncStatus implements hscommon.HeaderSyncQuerier, only the latest epoch switch header is kept,
+// so it is reported as the synced header, the genesis anchor is unknown and the validators are given by their hash
+func (this *CosmosHandler) GetHeaderSyncStatus(native *native.NativeService, chainID uint64) (*hscommon.HeaderSyncStatus, error) {
+	val, err := native.GetCacheDB().Get(
+		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(hscommon.EPOCH_SWITCH), utils.GetUint64Bytes(chainID)))
+	if err != nil {
+		return nil, fmt.Errorf("CosmosHandler GetHeaderSyncStatus, get epoch switch info error: %v", err)
+	}
+	if val == nil {
+		return nil, nil
+	}
+	info, err := GetEpochSwitchInfo(native, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("CosmosHandler GetHeaderSyncStatus, %v", err)
+	}
+	return &hscommon.HeaderSyncStatus{
+		Height:         uint64(info.Height),
+		Hash:           info.BlockHash,
+		EpochHeight:    uint64(info.Height),
+		ValidatorsHash: info.NextValidatorsHash,
+	}, nil
+}
@@ -26,7 +26,6 @@ import (
 	"github.com/polynetwork/poly/common/config"
 	cstates "github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/native"
-	"github.com/polynetwork/poly/native/event"
 	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
 	"github.com/polynetwork/poly/native/service/utils"
 
@@ -47,12 +46,8 @@ func notifyEpochSwitchInfo(native *native.NativeService, chainID uint64, info *C
 	if !config.DefConfig.Common.EnableEventLog {
 		return
 	}
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.HeaderSyncContractAddress,
-			States: []interface{}{chainID, info.BlockHash.String(), info.Height,
-				info.NextValidatorsHash.String(), info.ChainID, native.GetHeight()},
-		})
+	native.AddNotify(hscommon.EpochSwitchEvent.NewNotify(chainID, info.BlockHash.String(), info.Height,
+		info.NextValidatorsHash.String(), info.ChainID, native.GetHeight()))
 }
 
 func GetEpochSwitchInfo(service *native.NativeService, chainId uint64) (*CosmosEpochSwitchInfo, error) {
@@ -22,29 +22,34 @@ import (
 
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/fork_manager"
+	"github.com/polynetwork/poly/native/service/governance/relayer_manager"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
-	"github.com/polynetwork/poly/native/service/header_sync/bsc"
-	"github.com/polynetwork/poly/native/service/header_sync/btc"
-	"github.com/polynetwork/poly/native/service/header_sync/bytom"
 	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
-	"github.com/polynetwork/poly/native/service/header_sync/cosmos"
-	"github.com/polynetwork/poly/native/service/header_sync/eth"
-	"github.com/polynetwork/poly/native/service/header_sync/harmony"
-	"github.com/polynetwork/poly/native/service/header_sync/heco"
-	"github.com/polynetwork/poly/native/service/header_sync/hsc"
-	"github.com/polynetwork/poly/native/service/header_sync/msc"
-	"github.com/polynetwork/poly/native/service/header_sync/neo"
-	"github.com/polynetwork/poly/native/service/header_sync/neo3"
-	"github.com/polynetwork/poly/native/service/header_sync/neo3legacy"
-	"github.com/polynetwork/poly/native/service/header_sync/okex"
-	"github.com/polynetwork/poly/native/service/header_sync/ont"
-	"github.com/polynetwork/poly/native/service/header_sync/pixiechain"
-	"github.com/polynetwork/poly/native/service/header_sync/polygon"
-	"github.com/polynetwork/poly/native/service/header_sync/quorum"
-	"github.com/polynetwork/poly/native/service/header_sync/starcoin"
-	"github.com/polynetwork/poly/native/service/header_sync/zilliqa"
-	"github.com/polynetwork/poly/native/service/header_sync/zilliqalegacy"
 	"github.com/polynetwork/poly/native/service/utils"
+
+	// chain packages register their header sync handlers on init
+	_ "github.com/polynetwork/poly/native/service/header_sync/beacon"
+	_ "github.com/polynetwork/poly/native/service/header_sync/bsc"
+	_ "github.com/polynetwork/poly/native/service/header_sync/btc"
+	_ "github.com/polynetwork/poly/native/service/header_sync/bytom"
+	_ "github.com/polynetwork/poly/native/service/header_sync/cosmos"
+	_ "github.com/polynetwork/poly/native/service/header_sync/eth"
+	_ "github.com/polynetwork/poly/native/service/header_sync/harmony"
+	_ "github.com/polynetwork/poly/native/service/header_sync/heco"
+	_ "github.com/polynetwork/poly/native/service/header_sync/hsc"
+	_ "github.com/polynetwork/poly/native/service/header_sync/msc"
+	_ "github.com/polynetwork/poly/native/service/header_sync/neo"
+	_ "github.com/polynetwork/poly/native/service/header_sync/neo3"
+	_ "github.com/polynetwork/poly/native/service/header_sync/neo3legacy"
+	_ "github.com/polynetwork/poly/native/service/header_sync/okex"
+	_ "github.com/polynetwork/poly/native/service/header_sync/ont"
+	_ "github.com/polynetwork/poly/native/service/header_sync/pixiechain"
+	_ "github.com/polynetwork/poly/native/service/header_sync/polygon"
+	_ "github.com/polynetwork/poly/native/service/header_sync/quorum"
+	_ "github.com/polynetwork/poly/native/service/header_sync/starcoin"
+	_ "github.com/polynetwork/poly/native/service/header_sync/zilliqa"
+	_ "github.com/polynetwork/poly/native/service/header_sync/zilliqalegacy"
 )
 
 //Register methods of node_manager contract
@@ -52,55 +57,12 @@ func RegisterHeaderSyncContract(native *native.NativeService) {
 	native.Register(hscommon.SYNC_GENESIS_HEADER, SyncGenesisHeader)
 	native.Register(hscommon.SYNC_BLOCK_HEADER, SyncBlockHeader)
 	native.Register(hscommon.SYNC_CROSS_CHAIN_MSG, SyncCrossChainMsg)
+	native.Register(hscommon.SET_HEADER_RETENTION, SetHeaderRetention)
+	native.Register(hscommon.GET_SIDE_CHAIN_STATUS, GetSideChainStatus)
 }
 
 func GetChainHandler(router uint64) (hscommon.HeaderSyncHandler, error) {
-	switch router {
-	case utils.BTC_ROUTER:
-		return btc.NewBTCHandler(), nil
-	case utils.ETH_ROUTER:
-		return eth.NewETHHandler(), nil
-	case utils.ONT_ROUTER:
-		return ont.NewONTHandler(), nil
-	case utils.NEO_ROUTER:
-		return neo.NewNEOHandler(), nil
-	case utils.NEO3_ROUTER:
-		return neo3.NewNeo3Handler(), nil
-	case utils.NEO3_LEGACY_ROUTER:
-		return neo3legacy.NewNeo3Handler(), nil
-	case utils.COSMOS_ROUTER:
-		return cosmos.NewCosmosHandler(), nil
-	case utils.QUORUM_ROUTER:
-		return quorum.NewQuorumHandler(), nil
-	case utils.BSC_ROUTER:
-		return bsc.NewHandler(), nil
-	case utils.HECO_ROUTER:
-		return heco.NewHecoHandler(), nil
-	case utils.ZILLIQA_LEGACY_ROUTER:
-		return zilliqalegacy.NewHandler(), nil
-	case utils.ZILLIQA_ROUTER:
-		return zilliqa.NewHandler(), nil
-	case utils.MSC_ROUTER:
-		return msc.NewHandler(), nil
-	case utils.OKEX_ROUTER:
-		return okex.NewHandler(), nil
-	case utils.POLYGON_HEIMDALL_ROUTER:
-		return polygon.NewHeimdallHandler(), nil
-	case utils.POLYGON_BOR_ROUTER:
-		return polygon.NewBorHandler(), nil
-	case utils.PIXIECHAIN_ROUTER:
-		return pixiechain.NewPixieHandler(), nil
-	case utils.STARCOIN_ROUTER:
-		return starcoin.NewSTCHandler(), nil
-	case utils.HSC_ROUTER:
-		return hsc.NewHscHandler(), nil
-	case utils.HARMONY_ROUTER:
-		return harmony.NewHandler(), nil
-	case utils.BYTOM_ROUTER:
-		return bytom.NewHandler(), nil
-	default:
-		return nil, fmt.Errorf("not a supported router:%d", router)
-	}
+	return hscommon.GetHeaderSyncHandler(router)
 }
 
 func SyncGenesisHeader(native *native.NativeService) ([]byte, error) {
@@ -123,7 +85,7 @@ func SyncGenesisHeader(native *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, err
 	}
-	err = utils.CheckRouterStartBlock(sideChain.Router, native.GetHeight())
+	err = fork_manager.CheckRouterStartBlock(native, sideChain.Router)
 	if err != nil {
 		return utils.BYTE_FALSE, err
 	}
@@ -135,11 +97,14 @@ func SyncGenesisHeader(native *native.NativeService) ([]byte, error) {
 	return utils.BYTE_TRUE, nil
 }
 
-func SyncBlockHeader(native *native.NativeService) ([]byte, error) {
+func SyncBlockHeader(native *native.NativeService) (ret []byte, err error) {
 	params := new(hscommon.SyncBlockHeaderParam)
 	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("SyncBlockHeader, contract params deserialize error: %v", err)
 	}
+	if err := relayer_manager.CheckRelayerActive(native); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SyncBlockHeader, %v", err)
+	}
 	chainID := params.ChainID
 
 	//check if chainid exist
@@ -151,11 +116,16 @@ func SyncBlockHeader(native *native.NativeService) ([]byte, error) {
 		return utils.BYTE_FALSE, fmt.Errorf("SyncBlockHeader, side chain is not registered")
 	}
 
-	handler, err := GetChainHandler(sideChain.Router)
+	router := sideChain.Router
+	defer func() {
+		recordSyncBlockHeader(native, router, err)
+	}()
+
+	handler, err := GetChainHandler(router)
 	if err != nil {
 		return utils.BYTE_FALSE, err
 	}
-	err = utils.CheckRouterStartBlock(sideChain.Router, native.GetHeight())
+	err = fork_manager.CheckRouterStartBlock(native, router)
 	if err != nil {
 		return utils.BYTE_FALSE, err
 	}
@@ -164,6 +134,14 @@ func SyncBlockHeader(native *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, err
 	}
+	err = pruneHeaders(native, handler, sideChain)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SyncBlockHeader, pruneHeaders error: %v", err)
+	}
+	err = relayer_manager.RecordHeaderSync(native, chainID, len(params.Headers))
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SyncBlockHeader, %v", err)
+	}
 	return utils.BYTE_TRUE, nil
 }
 
@@ -187,7 +165,7 @@ func SyncCrossChainMsg(native *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, err
 	}
-	err = utils.CheckRouterStartBlock(sideChain.Router, native.GetHeight())
+	err = fork_manager.CheckRouterStartBlock(native, sideChain.Router)
 	if err != nil {
 		return utils.BYTE_FALSE, err
 	}
@@ -0,0 +1,53 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package header_sync
+
+import (
+	"testing"
+
+	"github.com/polynetwork/poly/native/service/governance/fork_manager"
+	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetChainHandler(t *testing.T) {
+	routers := []uint64{utils.BTC_ROUTER, utils.ETH_ROUTER, utils.ONT_ROUTER, utils.NEO_ROUTER, utils.COSMOS_ROUTER,
+		utils.QUORUM_ROUTER, utils.BSC_ROUTER, utils.HECO_ROUTER, utils.ZILLIQA_LEGACY_ROUTER, utils.MSC_ROUTER,
+		utils.OKEX_ROUTER, utils.POLYGON_HEIMDALL_ROUTER, utils.POLYGON_BOR_ROUTER, utils.PIXIECHAIN_ROUTER,
+		utils.NEO3_LEGACY_ROUTER, utils.STARCOIN_ROUTER, utils.HSC_ROUTER, utils.NEO3_ROUTER, utils.HARMONY_ROUTER,
+		utils.BYTOM_ROUTER, utils.ZILLIQA_ROUTER, utils.BEACON_ROUTER}
+	assert.Equal(t, len(routers), len(hscommon.GetHeaderSyncRouters()))
+	for _, router := range routers {
+		handler, err := GetChainHandler(router)
+		assert.NoError(t, err)
+		assert.NotNil(t, handler)
+	}
+
+	_, err := GetChainHandler(utils.VOTE_ROUTER)
+	assert.Error(t, err)
+}
+
+func TestRouterStartForks(t *testing.T) {
+	for _, router := range []uint64{utils.HSC_ROUTER, utils.BYTOM_ROUTER, utils.BEACON_ROUTER} {
+		_, ok := fork_manager.GetFork(fork_manager.RouterStartFork(router))
+		assert.True(t, ok)
+	}
+	_, ok := fork_manager.GetFork(fork_manager.RouterStartFork(utils.ETH_ROUTER))
+	assert.False(t, ok)
+}
@@ -31,7 +31,6 @@ import (
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
-	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/native/service/header_sync/eth/rlp"
 	"golang.org/x/crypto/sha3"
 )
@@ -239,19 +238,18 @@ var (
 	testLondonHeight uint64
 )
 
-func isLondon(h *Header) bool {
+func isLondon(h *Header, londonHeight uint64) bool {
 	if isTest {
 		return h.Number.Uint64() >= testLondonHeight
 	}
-	return h.BaseFee != nil || h.Number.Uint64() >= config.GetEth1559Height(config.DefConfig.P2PNode.NetworkId)
+	return h.BaseFee != nil || h.Number.Uint64() >= londonHeight
 }
 
-func isArrowGlacier(h *Header) bool {
-	forkHeight := config.GetEth4345Height(config.DefConfig.P2PNode.NetworkId)
-	if forkHeight == 0 {
+func isArrowGlacier(h *Header, arrowGlacierHeight uint64) bool {
+	if arrowGlacierHeight == 0 {
 		return false
 	}
-	return h.Number.Uint64() >= forkHeight
+	return h.Number.Uint64() >= arrowGlacierHeight
 }
 
 // VerifyGaslimit verifies the header gas limit according increase/decrease
@@ -281,10 +279,10 @@ const (
 // VerifyEip1559Header verifies some header attributes which were changed in EIP-1559,
 // - gas limit check
 // - basefee check
-func VerifyEip1559Header(parent, header *Header) error {
+func VerifyEip1559Header(parent, header *Header, londonHeight uint64) error {
 	// Verify that the gas limit remains within allowed bounds
 	parentGasLimit := parent.GasLimit
-	if !isLondon(parent) {
+	if !isLondon(parent, londonHeight) {
 		parentGasLimit = parent.GasLimit * ElasticityMultiplier
 	}
 	if err := VerifyGaslimit(parentGasLimit, header.GasLimit); err != nil {
@@ -295,7 +293,7 @@ func VerifyEip1559Header(parent, header *Header) error {
 		return fmt.Errorf("header is missing baseFee")
 	}
 	// Verify the baseFee is correct based on the parent header.
-	expectedBaseFee := CalcBaseFee(parent)
+	expectedBaseFee := CalcBaseFee(parent, londonHeight)
 	if header.BaseFee.Cmp(expectedBaseFee) != 0 {
 		return fmt.Errorf("invalid baseFee: have %s, want %s, parentBaseFee %s, parentGasUsed %d",
 			expectedBaseFee, header.BaseFee, parent.BaseFee, parent.GasUsed)
@@ -304,9 +302,9 @@ func VerifyEip1559Header(parent, header *Header) error {
 }
 
 // CalcBaseFee calculates the basefee of the header.
-func CalcBaseFee(parent *Header) *big.Int {
+func CalcBaseFee(parent *Header, londonHeight uint64) *big.Int {
 	// If the current block is the first EIP-1559 block, return the InitialBaseFee.
-	if !isLondon(parent) {
+	if !isLondon(parent, londonHeight) {
 		return new(big.Int).SetUint64(InitialBaseFee)
 	}
 
@@ -27,7 +27,9 @@ import (
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/native/service/governance/fork_manager"
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/header_sync/eth/rlp"
 	"golang.org/x/crypto/sha3"
 
@@ -58,6 +60,10 @@ func NewETHHandler() *ETHHandler {
 	return &ETHHandler{}
 }
 
+func init() {
+	scom.RegisterHeaderSyncHandler(utils.ETH_ROUTER, func() scom.HeaderSyncHandler { return NewETHHandler() })
+}
+
 func (this *ETHHandler) SyncGenesisHeader(native *native.NativeService) error {
 	params := new(scom.SyncGenesisHeaderParam)
 	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
@@ -101,6 +107,14 @@ func (this *ETHHandler) SyncBlockHeader(native *native.NativeService) error {
 	if err := headerParams.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
 		return fmt.Errorf("SyncBlockHeader, contract params deserialize error: %v", err)
 	}
+	londonHeight, err := fork_manager.GetForkHeight(native, fork_manager.ETH_1559)
+	if err != nil {
+		return fmt.Errorf("SyncBlockHeader, GetForkHeight error: %v", err)
+	}
+	arrowGlacierHeight, err := fork_manager.GetForkHeight(native, fork_manager.ETH_4345)
+	if err != nil {
+		return fmt.Errorf("SyncBlockHeader, GetForkHeight error: %v", err)
+	}
 	caches := NewCaches(3, native)
 	for _, v := range headerParams.Headers {
 		var header Header
@@ -160,8 +174,8 @@ func (this *ETHHandler) SyncBlockHeader(native *native.NativeService) error {
 			return fmt.Errorf("SyncBlockHeader, invalid gasUsed: have %d, gasLimit %d, header: %s", header.GasUsed, header.GasLimit, string(v))
 		}
 		//London hard fork
-		if isLondon(&header) {
-			err = VerifyEip1559Header(parentHeader, &header)
+		if isLondon(&header, londonHeight) {
+			err = VerifyEip1559Header(parentHeader, &header, londonHeight)
 		} else {
 			err = VerifyGaslimit(parentHeader.GasLimit, header.GasLimit)
 		}
@@ -171,9 +185,9 @@ func (this *ETHHandler) SyncBlockHeader(native *native.NativeService) error {
 
 		//verify difficulty
 		var expected *big.Int
-		if isArrowGlacier(&header) {
+		if isArrowGlacier(&header, arrowGlacierHeight) {
 			expected = makeDifficultyCalculator(big.NewInt(10_700_000))(header.Time, parentHeader)
-		} else if isLondon(&header) {
+		} else if isLondon(&header, londonHeight) {
 			expected = makeDifficultyCalculator(big.NewInt(9700000))(header.Time, parentHeader)
 		} else {
 			expected = difficultyCalculator(new(big.Int).SetUint64(header.Time), parentHeader)
@@ -186,6 +200,9 @@ func (this *ETHHandler) SyncBlockHeader(native *native.NativeService) error {
 		if err != nil {
 			return fmt.Errorf("SyncBlockHeader, verify header error: %v, header: %s", err, string(v))
 		}
+		if err := checkConflict(native, &header, headerParams.ChainID); err != nil {
+			return err
+		}
 		headerDifficultySum := new(big.Int).Add(header.Difficulty, parentDifficultySum)
 		//block header storage store the mapping between block header hash and header data into poly
 		err = putBlockHeader(native, header, headerDifficultySum, headerParams.ChainID)
@@ -217,6 +234,46 @@ func (this *ETHHandler) SyncCrossChainMsg(native *native.NativeService) error {
 	return nil
 }
 
+// GetHeaderRange implements scom.HeaderPruner
+func (this *ETHHandler) GetHeaderRange(native *native.NativeService, chainID uint64) (genesis, current uint64, err error) {
+	genesisHeader, err := getStoredGenesisHeader(native, chainID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("ETHHandler GetHeaderRange, %v", err)
+	}
+	if genesisHeader == nil {
+		return 0, 0, fmt.Errorf("ETHHandler GetHeaderRange, genesis header is not synced")
+	}
+	current, err = GetCurrentHeaderHeight(native, chainID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("ETHHandler GetHeaderRange, %v", err)
+	}
+	return genesisHeader.Number.Uint64(), current, nil
+}
+
+// PruneHeader implements scom.HeaderPruner
+func (this *ETHHandler) PruneHeader(native *native.NativeService, chainID uint64, height uint64) error {
+	return pruneHeader(native, height, chainID)
+}
+
+// GetHeaderSyncStatus implements scom.HeaderSyncQuerier, ethereum has no validators
+func (this *ETHHandler) GetHeaderSyncStatus(native *native.NativeService, chainID uint64) (*scom.HeaderSyncStatus, error) {
+	genesisHeader, err := getStoredGenesisHeader(native, chainID)
+	if err != nil || genesisHeader == nil {
+		return nil, err
+	}
+	header, _, err := GetCurrentHeader(native, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("ETHHandler GetHeaderSyncStatus, %v", err)
+	}
+	genesisHash, hash := genesisHeader.Hash(), header.Hash()
+	return &scom.HeaderSyncStatus{
+		GenesisHeight: genesisHeader.Number.Uint64(),
+		GenesisHash:   genesisHash.Bytes(),
+		Height:        header.Number.Uint64(),
+		Hash:          hash.Bytes(),
+	}, nil
+}
+
 func getGenesisHeader(input []byte) (Header, error) {
 	params := new(scom.SyncGenesisHeaderParam)
 	if err := params.Deserialization(common.NewZeroCopySource(input)); err != nil {
@@ -326,16 +383,54 @@ func (this *ETHHandler) verifyHeader(header *Header, caches *Caches) error {
 	result := crypto.Keccak256(append(seed, digest...))
 	// Verify the calculated digest against the ones provided in the header
 	if !bytes.Equal(header.MixDigest[:], digest) {
-		return fmt.Errorf("invalid mix digest!")
+		return caches.native.Misbehave(fmt.Errorf("invalid mix digest!"))
 	}
 	// compare result hash with target hash
 	target := new(big.Int).Div(two256, header.Difficulty)
 	if new(big.Int).SetBytes(result).Cmp(target) > 0 {
-		return fmt.Errorf("invalid proof-of-work!")
+		return caches.native.Misbehave(fmt.Errorf("invalid proof-of-work!"))
 	}
 	return nil
 }
 
+//checkConflict returns the misbehavior if header forks off the main chain header at its height, which has been
+//confirmed by the blocks to wait of the side chain and may have been used to verify cross chain txs. Such
+//headers are accepted before the relayer slashing fork
+func checkConflict(native *native.NativeService, header *Header, chainID uint64) error {
+	forkHeight, err := fork_manager.GetForkHeight(native, fork_manager.RELAYER_SLASHING)
+	if err != nil {
+		return fmt.Errorf("SyncBlockHeader, GetForkHeight error: %v", err)
+	}
+	if uint64(native.GetHeight()) < forkHeight {
+		return nil
+	}
+	side, err := side_chain_manager.GetSideChain(native, chainID)
+	if err != nil {
+		return fmt.Errorf("SyncBlockHeader, GetSideChain error: %v", err)
+	}
+	if side == nil || side.BlocksToWait == 0 {
+		return nil
+	}
+	number := header.Number.Uint64()
+	height, err := GetCurrentHeaderHeight(native, chainID)
+	if err != nil {
+		return fmt.Errorf("SyncBlockHeader, get current header height error: %v", err)
+	}
+	if number > height || height-number+1 < side.BlocksToWait {
+		return nil
+	}
+	confirmed, _, err := GetHeaderByHeight(native, number, chainID)
+	if err != nil {
+		// pruned or below genesis
+		return nil
+	}
+	if confirmed.Hash() == header.Hash() {
+		return nil
+	}
+	return native.Misbehave(fmt.Errorf("SyncBlockHeader, header %s conflicts with confirmed header %s at height %d",
+		header.Hash().Hex(), confirmed.Hash().Hex(), number))
+}
+
 func HashHeader(header *Header) (hash ethcommon.Hash) {
 	hasher := sha3.NewLegacyKeccak256()
 	enc := []interface{}{
@@ -21,12 +21,14 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
+	"math/big"
 	"strings"
 	"testing"
 
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/account"
 	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
 	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
 	"github.com/polynetwork/poly/core/genesis"
 	"github.com/polynetwork/poly/core/states"
@@ -36,6 +38,7 @@ import (
 	"github.com/polynetwork/poly/core/types"
 	"github.com/polynetwork/poly/native"
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	scom "github.com/polynetwork/poly/native/service/header_sync/common"
 	"github.com/polynetwork/poly/native/service/utils"
 	"github.com/polynetwork/poly/native/storage"
@@ -901,3 +904,32 @@ func TestSyncBlockHeaderForked2(t *testing.T) {
 		assert.Equal(t, true, bytes.Equal(ethcommon.HexToHash("e6acebf6898e62b9652be56b9bc81d2215627ebd4c0ad7247a017c9666b7131c").Bytes(), header7155391Hash.Bytes()))
 	}
 }
+
+func TestCheckConflict(t *testing.T) {
+	native := NewNative(nil, &types.Transaction{}, nil)
+	assert.Nil(t, side_chain_manager.PutSideChain(native, &side_chain_manager.SideChain{ChainId: 2, Router: utils.ETH_ROUTER, BlocksToWait: 3}))
+	for i := int64(100); i < 105; i++ {
+		header := Header{Number: big.NewInt(i), Difficulty: big.NewInt(2)}
+		if i == 100 {
+			assert.Nil(t, putGenesisBlockHeader(native, header, 2))
+			continue
+		}
+		assert.Nil(t, putBlockHeader(native, header, big.NewInt(2*(i-99)), 2))
+		assert.Nil(t, appendHeader2Main(native, uint64(i), header.Hash(), 2))
+	}
+	conflict := &Header{Number: big.NewInt(102), Difficulty: big.NewInt(1)}
+
+	// accepted before the relayer slashing fork, which is not scheduled on main net
+	networkID := config.DefConfig.P2PNode.NetworkId
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_MAIN_NET
+	defer func() { config.DefConfig.P2PNode.NetworkId = networkID }()
+	assert.Nil(t, checkConflict(native, conflict, 2))
+
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+	assert.Nil(t, checkConflict(native, &Header{Number: big.NewInt(102), Difficulty: big.NewInt(2)}, 2))
+	assert.Nil(t, checkConflict(native, &Header{Number: big.NewInt(103), Difficulty: big.NewInt(1)}, 2))
+	assert.Nil(t, native.GetMisbehavior())
+	err := checkConflict(native, conflict, 2)
+	assert.Error(t, err)
+	assert.Equal(t, err, native.GetMisbehavior())
+}
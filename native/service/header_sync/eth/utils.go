@@ -88,7 +88,7 @@ func putBlockHeader(native *native.NativeService, blockHeader Header, difficulty
 	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(scom.HEADER_INDEX), utils.GetUint64Bytes(chainID), blockHeader.Hash().Bytes()),
 		cstates.GenRawStorageItem(storeBytes))
 	scom.NotifyPutHeader(native, chainID, blockHeader.Number.Uint64(), blockHeader.Hash().String())
-	return nil
+	return scom.IndexHeader(native, chainID, blockHeader.Number.Uint64(), blockHeader.Hash().Bytes())
 }
 //appendHeader2Main stores the mapping between block height and block header hash with key MAIN_CHAIN and update the CURRENT_HEADER_HEIGHT
 func appendHeader2Main(native *native.NativeService, height uint64, txhash common.Hash, chainID uint64) error {
@@ -181,6 +181,48 @@ func IsHeaderExist(native *native.NativeService, hash []byte, chainID uint64) (b
 		return true, nil
 	}
 }
+
+//getStoredGenesisHeader returns the genesis header stored by SyncGenesisHeader, it is nil if not synced
+func getStoredGenesisHeader(native *native.NativeService, chainID uint64) (*Header, error) {
+	genesisStore, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress,
+		[]byte(scom.GENESIS_HEADER), utils.GetUint64Bytes(chainID)))
+	if err != nil {
+		return nil, fmt.Errorf("getStoredGenesisHeader, get genesis header error: %v", err)
+	}
+	if genesisStore == nil {
+		return nil, nil
+	}
+	storeBytes, err := cstates.GetValueFromRawStorageItem(genesisStore)
+	if err != nil {
+		return nil, fmt.Errorf("getStoredGenesisHeader, deserialize headerBytes from raw storage item err:%v", err)
+	}
+	var headerWithDifficultySum HeaderWithDifficultySum
+	if err := json.Unmarshal(storeBytes, &headerWithDifficultySum); err != nil {
+		return nil, fmt.Errorf("getStoredGenesisHeader, deserialize header error: %v", err)
+	}
+	return &headerWithDifficultySum.Header, nil
+}
+
+//pruneHeader deletes the main chain header at height with its HEADER_INDEX and MAIN_CHAIN records, and the
+//indexed headers of side forks at height
+func pruneHeader(native *native.NativeService, height, chainID uint64) error {
+	contract := utils.HeaderSyncContractAddress
+	mainKey := utils.ConcatKey(contract, []byte(scom.MAIN_CHAIN), utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(height))
+	hashStore, err := native.GetCacheDB().Get(mainKey)
+	if err != nil {
+		return fmt.Errorf("pruneHeader, get blockHashStore error: %v", err)
+	}
+	if hashStore != nil {
+		hashBytes, err := cstates.GetValueFromRawStorageItem(hashStore)
+		if err != nil {
+			return fmt.Errorf("pruneHeader, deserialize hashBytes from raw storage item err:%v", err)
+		}
+		native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(scom.HEADER_INDEX), utils.GetUint64Bytes(chainID), hashBytes))
+		native.GetCacheDB().Delete(mainKey)
+	}
+	return scom.PruneIndexedHeaders(native, chainID, height)
+}
+
 func RestructChain(native *native.NativeService, current, new *Header, chainID uint64) error {
 	si, ti := current.Number.Uint64(), new.Number.Uint64()
 	var err error
@@ -21,7 +21,10 @@ import (
 	"fmt"
 
 	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/common/constants"
 	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/fork_manager"
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	scom "github.com/polynetwork/poly/native/service/header_sync/common"
@@ -35,6 +38,11 @@ func NewHandler() *Handler {
 	return new(Handler)
 }
 
+func init() {
+	scom.RegisterHeaderSyncHandler(utils.HARMONY_ROUTER, func() scom.HeaderSyncHandler { return NewHandler() })
+	fork_manager.RegisterRouterStartBlock(utils.HARMONY_ROUTER, config.NETWORK_ID_MAIN_NET, constants.ROUTER_START_HEIGHT_MAINNET)
+}
+
 // Sync Genesis header
 func (h *Handler) SyncGenesisHeader(native *native.NativeService) (err error) {
 	params := new(scom.SyncGenesisHeaderParam)
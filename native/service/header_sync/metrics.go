@@ -0,0 +1,41 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package header_sync
+
+import (
+	"strconv"
+
+	"github.com/polynetwork/poly/common/metrics"
+	"github.com/polynetwork/poly/native"
+)
+
+var syncBlockHeaderCounter = metrics.NewCounterVec("poly_native_sync_block_header_total",
+	"SyncBlockHeader executions by side chain router and result", "router", "result")
+
+//recordSyncBlockHeader counts the SyncBlockHeader executed in blocks, pre-executions are skipped
+func recordSyncBlockHeader(native *native.NativeService, router uint64, err error) {
+	if native.IsPreExec() {
+		return
+	}
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	syncBlockHeaderCounter.WithLabelValues(strconv.FormatUint(router, 10), result).Inc()
+}
@@ -31,11 +31,11 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/log"
-	cstates "github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/native"
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	scom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/header_sync/posa"
 	"github.com/polynetwork/poly/native/service/utils"
 )
 
@@ -48,6 +48,10 @@ func NewHandler() *Handler {
 	return &Handler{}
 }
 
+func init() {
+	scom.RegisterHeaderSyncHandler(utils.MSC_ROUTER, func() scom.HeaderSyncHandler { return NewHandler() })
+}
+
 // SyncGenesisHeader ...
 func (h *Handler) SyncGenesisHeader(native *native.NativeService) (err error) {
 	params := new(scom.SyncGenesisHeaderParam)
@@ -82,7 +86,7 @@ func (h *Handler) SyncGenesisHeader(native *native.NativeService) (err error) {
 	}
 
 	// can only store once
-	stored, err := isGenesisStored(native, params)
+	stored, err := store.IsGenesisStored(native, params.ChainID)
 	if err != nil {
 		return fmt.Errorf("msc Handler SyncGenesisHeader, isGenesisStored error: %v", err)
 	}
@@ -104,7 +108,8 @@ func (h *Handler) SyncGenesisHeader(native *native.NativeService) (err error) {
 		return fmt.Errorf("invalid signer list, signersBytes:%d", signersBytes)
 	}
 
-	err = storeGenesis(native, params, &genesis)
+	headerWithSum := &HeaderWithDifficultySum{Header: &genesis, DifficultySum: genesis.Difficulty}
+	err = store.StoreGenesis(native, params.ChainID, &genesis, headerWithSum)
 	if err != nil {
 		return fmt.Errorf("msc Handler SyncGenesisHeader, storeGenesis error: %v", err)
 	}
@@ -112,68 +117,12 @@ func (h *Handler) SyncGenesisHeader(native *native.NativeService) (err error) {
 	return
 }
 
-func isGenesisStored(native *native.NativeService, params *scom.SyncGenesisHeaderParam) (stored bool, err error) {
-	genesis, err := getGenesis(native, params.ChainID)
-	if err != nil {
-		return
-	}
-
-	stored = genesis != nil
-	return
-}
-
 func getGenesis(native *native.NativeService, chainID uint64) (genesisHeader *types.Header, err error) {
-
-	genesisBytes, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.GENESIS_HEADER), utils.GetUint64Bytes(chainID)))
-	if err != nil {
-		err = fmt.Errorf("getGenesis, GetCacheDB err:%v", err)
-		return
-	}
-
-	if genesisBytes == nil {
-		return
+	genesisHeader = &types.Header{}
+	exist, err := store.GetGenesis(native, chainID, genesisHeader)
+	if err != nil || !exist {
+		return nil, err
 	}
-
-	genesisBytes, err = cstates.GetValueFromRawStorageItem(genesisBytes)
-	if err != nil {
-		err = fmt.Errorf("getGenesis, GetValueFromRawStorageItem err:%v", err)
-		return
-	}
-
-	{
-		genesisHeader = &types.Header{}
-		err = json.Unmarshal(genesisBytes, &genesisHeader)
-		if err != nil {
-			err = fmt.Errorf("getGenesis, json.Unmarshal err:%v", err)
-			return
-		}
-	}
-
-	return
-}
-
-func storeGenesis(native *native.NativeService, params *scom.SyncGenesisHeaderParam, genesisHeader *types.Header) (err error) {
-
-	genesisBytes, err := json.Marshal(genesisHeader)
-	if err != nil {
-		return
-	}
-
-	native.GetCacheDB().Put(
-		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.GENESIS_HEADER), utils.GetUint64Bytes(params.ChainID)),
-		cstates.GenRawStorageItem(genesisBytes))
-
-	headerWithSum := &HeaderWithDifficultySum{Header: genesisHeader, DifficultySum: genesisHeader.Difficulty}
-
-	err = putHeaderWithSum(native, params.ChainID, headerWithSum)
-	if err != nil {
-		return
-	}
-
-	putCanonicalHeight(native, params.ChainID, genesisHeader.Number.Uint64())
-	putCanonicalHash(native, params.ChainID, genesisHeader.Number.Uint64(), genesisHeader.Hash())
-
-	scom.NotifyPutHeader(native, params.ChainID, genesisHeader.Number.Uint64(), genesisHeader.Hash().Hex())
 	return
 }
 
@@ -199,6 +148,31 @@ type HeaderWithDifficultySum struct {
 	LastVoteParentOrEpoch *ecommon.Hash `json:"lastVoteParentOrEpoch"`
 }
 
+// HeaderHash ...
+func (h *HeaderWithDifficultySum) HeaderHash() ecommon.Hash {
+	return h.Header.Hash()
+}
+
+// HeaderParentHash ...
+func (h *HeaderWithDifficultySum) HeaderParentHash() ecommon.Hash {
+	return h.Header.ParentHash
+}
+
+// HeaderNumber ...
+func (h *HeaderWithDifficultySum) HeaderNumber() uint64 {
+	return h.Header.Number.Uint64()
+}
+
+// TotalDifficulty ...
+func (h *HeaderWithDifficultySum) TotalDifficulty() *big.Int {
+	return h.DifficultySum
+}
+
+var store = &posa.Store{
+	Name:      "msc",
+	NewHeader: func() posa.StoredHeader { return &HeaderWithDifficultySum{} },
+}
+
 // SyncBlockHeader ...
 func (h *Handler) SyncBlockHeader(native *native.NativeService) error {
 	headerParams := new(scom.SyncBlockHeaderParam)
@@ -226,7 +200,7 @@ func (h *Handler) SyncBlockHeader(native *native.NativeService) error {
 		}
 		headerHash := header.Hash()
 
-		exist, err := isHeaderExist(native, headerHash, ctx)
+		exist, err := store.IsHeaderExist(native, ctx.ChainID, headerHash)
 		if err != nil {
 			return fmt.Errorf("msc Handler SyncBlockHeader, isHeaderExist headerHash err: %v", err)
 		}
@@ -235,7 +209,7 @@ func (h *Handler) SyncBlockHeader(native *native.NativeService) error {
 			continue
 		}
 
-		parentExist, err := isHeaderExist(native, header.ParentHash, ctx)
+		parentExist, err := store.IsHeaderExist(native, ctx.ChainID, header.ParentHash)
 		if err != nil {
 			return fmt.Errorf("msc Handler SyncBlockHeader, isHeaderExist ParentHash err: %v", err)
 		}
@@ -259,98 +233,21 @@ func (h *Handler) SyncBlockHeader(native *native.NativeService) error {
 	return nil
 }
 
-func isHeaderExist(native *native.NativeService, headerHash ecommon.Hash, ctx *Context) (bool, error) {
-	headerStore, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress,
-		[]byte(scom.HEADER_INDEX), utils.GetUint64Bytes(ctx.ChainID), headerHash.Bytes()))
-	if err != nil {
-		return false, fmt.Errorf("msc Handler isHeaderExist error: %v", err)
-	}
-
-	return headerStore != nil, nil
-}
-
 // GetCanonicalHeight ...
 func GetCanonicalHeight(native *native.NativeService, chainID uint64) (height uint64, err error) {
-	heightStore, err := native.GetCacheDB().Get(
-		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.CURRENT_HEADER_HEIGHT), utils.GetUint64Bytes(chainID)))
-	if err != nil {
-		err = fmt.Errorf("msc Handler GetCanonicalHeight err:%v", err)
-		return
-	}
-
-	storeBytes, err := cstates.GetValueFromRawStorageItem(heightStore)
-	if err != nil {
-		err = fmt.Errorf("msc Handler GetCanonicalHeight, GetValueFromRawStorageItem err:%v", err)
-		return
-	}
-
-	height = utils.GetBytesUint64(storeBytes)
-	return
+	return store.GetCanonicalHeight(native, chainID)
 }
 
 // GetCanonicalHeader ...
 func GetCanonicalHeader(native *native.NativeService, chainID uint64, height uint64) (headerWithSum *HeaderWithDifficultySum, err error) {
-	hash, err := getCanonicalHash(native, chainID, height)
-	if err != nil {
-		return
-	}
-
-	if hash == (ecommon.Hash{}) {
-		return
-	}
-
-	headerWithSum, err = getHeader(native, hash, chainID)
-	return
-}
-
-func deleteCanonicalHash(native *native.NativeService, chainID uint64, height uint64) {
-	native.GetCacheDB().Delete(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.MAIN_CHAIN), utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(height)))
-}
-
-func getCanonicalHash(native *native.NativeService, chainID uint64, height uint64) (hash ecommon.Hash, err error) {
-	hashBytesStore, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.MAIN_CHAIN), utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(height)))
-	if err != nil {
-		return
-	}
-
-	if hashBytesStore == nil {
-		return
-	}
-
-	hashBytes, err := cstates.GetValueFromRawStorageItem(hashBytesStore)
-	if err != nil {
-		err = fmt.Errorf("msc Handler getCanonicalHash, GetValueFromRawStorageItem err:%v", err)
-		return
-	}
-
-	hash = ecommon.BytesToHash(hashBytes)
-	return
-}
-
-func putCanonicalHash(native *native.NativeService, chainID uint64, height uint64, hash ecommon.Hash) {
-	native.GetCacheDB().Put(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.MAIN_CHAIN), utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(height)),
-		cstates.GenRawStorageItem(hash.Bytes()))
-}
-
-func putHeaderWithSum(native *native.NativeService, chainID uint64, headerWithSum *HeaderWithDifficultySum) (err error) {
-
-	headerBytes, err := json.Marshal(headerWithSum)
-	if err != nil {
+	header, err := store.GetCanonicalHeader(native, chainID, height)
+	if err != nil || header == nil {
 		return
 	}
-
-	native.GetCacheDB().Put(
-		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.HEADER_INDEX), utils.GetUint64Bytes(chainID), headerWithSum.Header.Hash().Bytes()),
-		cstates.GenRawStorageItem(headerBytes))
+	headerWithSum = header.(*HeaderWithDifficultySum)
 	return
 }
 
-func putCanonicalHeight(native *native.NativeService, chainID uint64, height uint64) {
-	native.GetCacheDB().Put(
-		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.CURRENT_HEADER_HEIGHT), utils.GetUint64Bytes(chainID)),
-		cstates.GenRawStorageItem(utils.GetUint64Bytes(uint64(height))))
-}
-
 func addHeader(native *native.NativeService, header *types.Header, ctx *Context) (err error) {
 
 	parentHeader, err := getHeader(native, header.ParentHash, ctx.ChainID)
@@ -358,22 +255,7 @@ func addHeader(native *native.NativeService, header *types.Header, ctx *Context)
 		return
 	}
 
-	cheight, err := GetCanonicalHeight(native, ctx.ChainID)
-	if err != nil {
-		return
-	}
-	cheader, err := GetCanonicalHeader(native, ctx.ChainID, cheight)
-	if err != nil {
-		return
-	}
-	if cheader == nil {
-		err = fmt.Errorf("getCanonicalHeader returns nil")
-		return
-	}
-
-	localTd := cheader.DifficultySum
 	externTd := new(big.Int).Add(header.Difficulty, parentHeader.DifficultySum)
-
 	headerWithSum := &HeaderWithDifficultySum{Header: header, DifficultySum: externTd}
 	if header.Number.Uint64()%ctx.ExtraInfo.Epoch != 0 {
 		if parentHeader.Header.Number.Uint64()%ctx.ExtraInfo.Epoch == 0 {
@@ -388,58 +270,7 @@ func addHeader(native *native.NativeService, header *types.Header, ctx *Context)
 			}
 		}
 	}
-	err = putHeaderWithSum(native, ctx.ChainID, headerWithSum)
-	if err != nil {
-		return
-	}
-
-	if externTd.Cmp(localTd) > 0 {
-		// Delete any canonical number assignments above the new head
-		var headerWithSum *HeaderWithDifficultySum
-		for i := header.Number.Uint64() + 1; ; i++ {
-			headerWithSum, err = GetCanonicalHeader(native, ctx.ChainID, i)
-			if err != nil {
-				return
-			}
-			if headerWithSum == nil {
-				break
-			}
-
-			deleteCanonicalHash(native, ctx.ChainID, i)
-		}
-
-		// Overwrite any stale canonical number assignments
-		var (
-			hash       ecommon.Hash
-			headHeader *HeaderWithDifficultySum
-		)
-		cheight := header.Number.Uint64() - 1
-		headHash := header.ParentHash
-
-		for {
-			hash, err = getCanonicalHash(native, ctx.ChainID, cheight)
-			if err != nil {
-				return
-			}
-			if hash == headHash {
-				break
-			}
-
-			putCanonicalHash(native, ctx.ChainID, cheight, headHash)
-			headHeader, err = getHeader(native, headHash, ctx.ChainID)
-			if err != nil {
-				return
-			}
-			headHash = headHeader.Header.ParentHash
-			cheight--
-		}
-
-		// Extend the canonical chain with the new header
-		putCanonicalHash(native, ctx.ChainID, header.Number.Uint64(), header.Hash())
-		putCanonicalHeight(native, ctx.ChainID, header.Number.Uint64())
-	}
-
-	return nil
+	return store.AddHeader(native, ctx.ChainID, headerWithSum)
 }
 
 func snapshot(native *native.NativeService, number uint64, hash ecommon.Hash, targetSigner ecommon.Address, ctx *Context) (snap *Snapshot, lastSeenHeight uint64, err error) {
@@ -547,24 +378,11 @@ func snapshot(native *native.NativeService, number uint64, hash ecommon.Hash, ta
 }
 
 func getHeader(native *native.NativeService, hash ecommon.Hash, chainID uint64) (headerWithSum *HeaderWithDifficultySum, err error) {
-
-	headerStore, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress,
-		[]byte(scom.HEADER_INDEX), utils.GetUint64Bytes(chainID), hash.Bytes()))
+	header, err := store.GetHeader(native, chainID, hash)
 	if err != nil {
-		return nil, fmt.Errorf("msc Handler getHeader error: %v", err)
-	}
-	if headerStore == nil {
-		return nil, fmt.Errorf("msc Handler getHeader, can not find any header records")
-	}
-	storeBytes, err := cstates.GetValueFromRawStorageItem(headerStore)
-	if err != nil {
-		return nil, fmt.Errorf("msc Handler getHeader, deserialize headerBytes from raw storage item err:%v", err)
-	}
-	headerWithSum = &HeaderWithDifficultySum{}
-	if err := json.Unmarshal(storeBytes, &headerWithSum); err != nil {
-		return nil, fmt.Errorf("msc Handler getHeader, deserialize header error: %v", err)
+		return
 	}
-
+	headerWithSum = header.(*HeaderWithDifficultySum)
 	return
 }
 
@@ -809,3 +627,49 @@ func ecrecover(header *types.Header) (ecommon.Address, error) {
 func (h *Handler) SyncCrossChainMsg(native *native.NativeService) error {
 	return nil
 }
+
+// GetHeaderRange implements scom.HeaderPruner
+func (h *Handler) GetHeaderRange(native *native.NativeService, chainID uint64) (genesis, current uint64, err error) {
+	genesisHeader, err := getGenesis(native, chainID)
+	if err != nil {
+		return
+	}
+	if genesisHeader == nil {
+		err = fmt.Errorf("msc Handler GetHeaderRange, genesis header is not synced")
+		return
+	}
+	current, err = GetCanonicalHeight(native, chainID)
+	return genesisHeader.Number.Uint64(), current, err
+}
+
+// PruneHeader implements scom.HeaderPruner
+func (h *Handler) PruneHeader(native *native.NativeService, chainID uint64, height uint64) error {
+	return store.PruneHeader(native, chainID, height)
+}
+
+// GetHeaderSyncStatus implements scom.HeaderSyncQuerier, the signers are left out
+// as they change by votes between epoch headers
+func (h *Handler) GetHeaderSyncStatus(native *native.NativeService, chainID uint64) (*scom.HeaderSyncStatus, error) {
+	genesisHeader, err := getGenesis(native, chainID)
+	if err != nil || genesisHeader == nil {
+		return nil, err
+	}
+	height, err := GetCanonicalHeight(native, chainID)
+	if err != nil {
+		return nil, err
+	}
+	head, err := GetCanonicalHeader(native, chainID, height)
+	if err != nil {
+		return nil, err
+	}
+	if head == nil {
+		return nil, fmt.Errorf("msc Handler GetHeaderSyncStatus, canonical header %d not found", height)
+	}
+	genesisHash, hash := genesisHeader.Hash(), head.Header.Hash()
+	return &scom.HeaderSyncStatus{
+		GenesisHeight: genesisHeader.Number.Uint64(),
+		GenesisHash:   genesisHash.Bytes(),
+		Height:        height,
+		Hash:          hash.Bytes(),
+	}, nil
+}
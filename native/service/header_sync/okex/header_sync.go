@@ -30,7 +30,6 @@ import (
 	"github.com/polynetwork/poly/common/log"
 	cstates "github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/native"
-	"github.com/polynetwork/poly/native/event"
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
 	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
 	"github.com/polynetwork/poly/native/service/header_sync/okex/ethsecp256k1"
@@ -47,6 +46,10 @@ func NewHandler() *Handler {
 	return &Handler{}
 }
 
+func init() {
+	hscommon.RegisterHeaderSyncHandler(utils.OKEX_ROUTER, func() hscommon.HeaderSyncHandler { return NewHandler() })
+}
+
 // NewCDC ...
 func NewCDC() *codec.Codec {
 	cdc := codec.New()
@@ -173,12 +176,8 @@ func notifyEpochSwitchInfo(native *native.NativeService, chainID uint64, info *C
 	if !config.DefConfig.Common.EnableEventLog {
 		return
 	}
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.HeaderSyncContractAddress,
-			States: []interface{}{chainID, info.BlockHash.String(), info.Height,
-				info.NextValidatorsHash.String(), info.ChainID, native.GetHeight()},
-		})
+	native.AddNotify(hscommon.EpochSwitchEvent.NewNotify(chainID, info.BlockHash.String(), info.Height,
+		info.NextValidatorsHash.String(), info.ChainID, native.GetHeight()))
 }
 
 type CosmosEpochSwitchInfo struct {
@@ -18,7 +18,10 @@
 package ont
 
 import (
+	"encoding/hex"
 	"fmt"
+	"sort"
+
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
 
 	"github.com/ontio/ontology-crypto/keypair"
@@ -37,6 +40,10 @@ func NewONTHandler() *ONTHandler {
 	return &ONTHandler{}
 }
 
+func init() {
+	hscommon.RegisterHeaderSyncHandler(utils.ONT_ROUTER, func() hscommon.HeaderSyncHandler { return NewONTHandler() })
+}
+
 func (this *ONTHandler) SyncGenesisHeader(native *native.NativeService) error {
 	params := new(hscommon.SyncGenesisHeaderParam)
 	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
@@ -146,3 +153,56 @@ func (this *ONTHandler) SyncCrossChainMsg(native *native.NativeService) error {
 	}
 	return nil
 }
+
+// GetHeaderSyncStatus implements hscommon.HeaderSyncQuerier, the genesis header is the first key height
+// as it must carry the consensus peers verifying the next headers
+func (this *ONTHandler) GetHeaderSyncStatus(native *native.NativeService, chainID uint64) (*hscommon.HeaderSyncStatus, error) {
+	keyHeights, err := GetKeyHeights(native, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("ONTHandler GetHeaderSyncStatus, %v", err)
+	}
+	if len(keyHeights.HeightList) == 0 {
+		return nil, nil
+	}
+	// key heights are kept from the latest to the genesis
+	epochHeight := keyHeights.HeightList[0]
+	genesisHeight := keyHeights.HeightList[len(keyHeights.HeightList)-1]
+	genesis, err := GetHeaderByHeight(native, chainID, genesisHeight)
+	if err != nil {
+		return nil, fmt.Errorf("ONTHandler GetHeaderSyncStatus, %v", err)
+	}
+	height, err := GetCurrentHeaderHeight(native, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("ONTHandler GetHeaderSyncStatus, %v", err)
+	}
+	header, err := GetHeaderByHeight(native, chainID, height)
+	if err != nil {
+		return nil, fmt.Errorf("ONTHandler GetHeaderSyncStatus, %v", err)
+	}
+	consensusPeers, err := getConsensusPeersByHeight(native, chainID, epochHeight)
+	if err != nil {
+		return nil, fmt.Errorf("ONTHandler GetHeaderSyncStatus, %v", err)
+	}
+	peers := make([]*Peer, 0, len(consensusPeers.PeerMap))
+	for _, peer := range consensusPeers.PeerMap {
+		peers = append(peers, peer)
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].Index < peers[j].Index })
+
+	genesisHash, hash := genesis.Hash(), header.Hash()
+	status := &hscommon.HeaderSyncStatus{
+		GenesisHeight: uint64(genesisHeight),
+		GenesisHash:   genesisHash.ToArray(),
+		Height:        uint64(height),
+		Hash:          hash.ToArray(),
+		EpochHeight:   uint64(epochHeight),
+	}
+	for _, peer := range peers {
+		pubkey, err := hex.DecodeString(peer.PeerPubkey)
+		if err != nil {
+			return nil, fmt.Errorf("ONTHandler GetHeaderSyncStatus, decode peer pubkey error: %v", err)
+		}
+		status.Validators = append(status.Validators, pubkey)
+	}
+	return status, nil
+}
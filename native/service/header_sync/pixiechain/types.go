@@ -1,43 +1,23 @@
 package pixiechain
 
 import (
-	"math/big"
-
-	ecommon "github.com/ethereum/go-ethereum/common"
-	"github.com/polynetwork/poly/native/service/header_sync/eth"
+	"github.com/polynetwork/poly/native/service/header_sync/posa"
 )
 
 // Handler ...
 type Handler struct {
+	*posa.Engine
 }
 
 // GenesisHeader ...
-type GenesisHeader struct {
-	Header         eth.Header
-	PrevValidators []HeightAndValidators
-}
+type GenesisHeader = posa.GenesisHeader
 
 // ExtraInfo ...
-type ExtraInfo struct {
-	ChainID *big.Int // chainId of pixie chain. mainnet: 6626, testnet: 666
-	Period  uint64
-}
+// ChainID is the chainId of pixie chain. mainnet: 6626, testnet: 666
+type ExtraInfo = posa.ExtraInfo
 
-// Context ...
-type Context struct {
-	ExtraInfo ExtraInfo
-	ChainID   uint64
-}
-
-// HeaderWithChainID ...
-type HeaderWithChainID struct {
-	Header  *HeaderWithDifficultySum
-	ChainID uint64
-}
+// HeightAndValidators ...
+type HeightAndValidators = posa.HeightAndValidators
 
 // HeaderWithDifficultySum ...
-type HeaderWithDifficultySum struct {
-	Header          *eth.Header   `json:"header"`
-	DifficultySum   *big.Int      `json:"difficultySum"`
-	EpochParentHash *ecommon.Hash `json:"epochParentHash"`
-}
+type HeaderWithDifficultySum = posa.HeaderWithDifficultySum
@@ -37,11 +37,13 @@ import (
 	"github.com/polynetwork/poly/common/log"
 	cstates "github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/fork_manager"
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	scom "github.com/polynetwork/poly/native/service/header_sync/common"
 	"github.com/polynetwork/poly/native/service/header_sync/eth"
 	polygonTypes "github.com/polynetwork/poly/native/service/header_sync/polygon/types"
+	"github.com/polynetwork/poly/native/service/header_sync/posa"
 	"github.com/polynetwork/poly/native/service/utils"
 	"github.com/tendermint/tendermint/crypto/merkle"
 	"golang.org/x/crypto/sha3"
@@ -56,6 +58,10 @@ func NewBorHandler() *BorHandler {
 	return &BorHandler{}
 }
 
+func init() {
+	scom.RegisterHeaderSyncHandler(utils.POLYGON_BOR_ROUTER, func() scom.HeaderSyncHandler { return NewBorHandler() })
+}
+
 // HeaderWithOptionalSnap ...
 type HeaderWithOptionalSnap struct {
 	Header   eth.Header
@@ -82,7 +88,7 @@ func (h *BorHandler) SyncGenesisHeader(native *native.NativeService) (err error)
 	}
 
 	// can only store once
-	stored, err := isGenesisStored(native, params)
+	stored, err := store.IsGenesisStored(native, params.ChainID)
 	if err != nil {
 		return fmt.Errorf("bor Handler SyncGenesisHeader, isGenesisStored error: %v", err)
 	}
@@ -109,7 +115,8 @@ func (h *BorHandler) SyncGenesisHeader(native *native.NativeService) (err error)
 		return fmt.Errorf("bor Handler SyncGenesisHeader, ExtraInfo Unmarshal error: %v", err)
 	}
 
-	err = storeGenesis(native, params, &genesis)
+	headerWithSum := &HeaderWithDifficultySum{HeaderWithOptionalSnap: &genesis, DifficultySum: genesis.Header.Difficulty}
+	err = store.StoreGenesis(native, params.ChainID, &genesis, headerWithSum)
 	if err != nil {
 		return fmt.Errorf("bor Handler SyncGenesisHeader, storeGenesis error: %v", err)
 	}
@@ -117,71 +124,6 @@ func (h *BorHandler) SyncGenesisHeader(native *native.NativeService) (err error)
 	return
 }
 
-func isGenesisStored(native *native.NativeService, params *scom.SyncGenesisHeaderParam) (stored bool, err error) {
-	genesis, err := getGenesis(native, params.ChainID)
-	if err != nil {
-		return
-	}
-
-	stored = genesis != nil
-	return
-}
-
-func getGenesis(native *native.NativeService, chainID uint64) (genesisHeader *HeaderWithOptionalSnap, err error) {
-
-	genesisBytes, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.GENESIS_HEADER), utils.GetUint64Bytes(chainID)))
-	if err != nil {
-		err = fmt.Errorf("getGenesis, GetCacheDB err:%v", err)
-		return
-	}
-
-	if genesisBytes == nil {
-		return
-	}
-
-	genesisBytes, err = cstates.GetValueFromRawStorageItem(genesisBytes)
-	if err != nil {
-		err = fmt.Errorf("getGenesis, GetValueFromRawStorageItem err:%v", err)
-		return
-	}
-
-	{
-		genesisHeader = &HeaderWithOptionalSnap{}
-		err = json.Unmarshal(genesisBytes, &genesisHeader)
-		if err != nil {
-			err = fmt.Errorf("getGenesis, json.Unmarshal err:%v", err)
-			return
-		}
-	}
-
-	return
-}
-
-func storeGenesis(native *native.NativeService, params *scom.SyncGenesisHeaderParam, genesisHeader *HeaderWithOptionalSnap) (err error) {
-
-	genesisBytes, err := json.Marshal(genesisHeader)
-	if err != nil {
-		return
-	}
-
-	native.GetCacheDB().Put(
-		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.GENESIS_HEADER), utils.GetUint64Bytes(params.ChainID)),
-		cstates.GenRawStorageItem(genesisBytes))
-
-	headerWithSum := &HeaderWithDifficultySum{HeaderWithOptionalSnap: genesisHeader, DifficultySum: genesisHeader.Header.Difficulty}
-
-	err = putHeaderWithSum(native, params.ChainID, headerWithSum)
-	if err != nil {
-		return
-	}
-
-	putCanonicalHeight(native, params.ChainID, genesisHeader.Header.Number.Uint64())
-	putCanonicalHash(native, params.ChainID, genesisHeader.Header.Number.Uint64(), genesisHeader.Header.Hash())
-
-	scom.NotifyPutHeader(native, params.ChainID, genesisHeader.Header.Number.Uint64(), genesisHeader.Header.Hash().Hex())
-	return
-}
-
 type ExtraInfo struct {
 	Sprint              uint64
 	Period              uint64
@@ -202,6 +144,31 @@ type HeaderWithDifficultySum struct {
 	SnapParentHash         *ecommon.Hash           `json:"snapParentHash"`
 }
 
+// HeaderHash ...
+func (h *HeaderWithDifficultySum) HeaderHash() ecommon.Hash {
+	return h.HeaderWithOptionalSnap.Header.Hash()
+}
+
+// HeaderParentHash ...
+func (h *HeaderWithDifficultySum) HeaderParentHash() ecommon.Hash {
+	return h.HeaderWithOptionalSnap.Header.ParentHash
+}
+
+// HeaderNumber ...
+func (h *HeaderWithDifficultySum) HeaderNumber() uint64 {
+	return h.HeaderWithOptionalSnap.Header.Number.Uint64()
+}
+
+// TotalDifficulty ...
+func (h *HeaderWithDifficultySum) TotalDifficulty() *big.Int {
+	return h.DifficultySum
+}
+
+var store = &posa.Store{
+	Name:      "bor",
+	NewHeader: func() posa.StoredHeader { return &HeaderWithDifficultySum{} },
+}
+
 type HeaderWithOptionalProof struct {
 	Header eth.Header
 	Proof  []byte
@@ -238,7 +205,7 @@ func (h *BorHandler) SyncBlockHeader(native *native.NativeService) error {
 		}
 		headerHash := headerWOP.Header.Hash()
 
-		exist, err := isHeaderExist(native, headerHash, ctx)
+		exist, err := store.IsHeaderExist(native, ctx.ChainID, headerHash)
 		if err != nil {
 			return fmt.Errorf("bor Handler SyncBlockHeader, isHeaderExist headerHash err: %v", err)
 		}
@@ -247,7 +214,7 @@ func (h *BorHandler) SyncBlockHeader(native *native.NativeService) error {
 			continue
 		}
 
-		parentExist, err := isHeaderExist(native, headerWOP.Header.ParentHash, ctx)
+		parentExist, err := store.IsHeaderExist(native, ctx.ChainID, headerWOP.Header.ParentHash)
 		if err != nil {
 			return fmt.Errorf("bor Handler SyncBlockHeader, isHeaderExist ParentHash err: %v", err)
 		}
@@ -272,98 +239,21 @@ func (h *BorHandler) SyncBlockHeader(native *native.NativeService) error {
 	return nil
 }
 
-func isHeaderExist(native *native.NativeService, headerHash ecommon.Hash, ctx *Context) (bool, error) {
-	headerStore, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress,
-		[]byte(scom.HEADER_INDEX), utils.GetUint64Bytes(ctx.ChainID), headerHash.Bytes()))
-	if err != nil {
-		return false, fmt.Errorf("bor Handler isHeaderExist error: %v", err)
-	}
-
-	return headerStore != nil, nil
-}
-
 // GetCanonicalHeight ...
 func GetCanonicalHeight(native *native.NativeService, chainID uint64) (height uint64, err error) {
-	heightStore, err := native.GetCacheDB().Get(
-		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.CURRENT_HEADER_HEIGHT), utils.GetUint64Bytes(chainID)))
-	if err != nil {
-		err = fmt.Errorf("bor Handler GetCanonicalHeight err:%v", err)
-		return
-	}
-
-	storeBytes, err := cstates.GetValueFromRawStorageItem(heightStore)
-	if err != nil {
-		err = fmt.Errorf("bor Handler GetCanonicalHeight, GetValueFromRawStorageItem err:%v", err)
-		return
-	}
-
-	height = utils.GetBytesUint64(storeBytes)
-	return
+	return store.GetCanonicalHeight(native, chainID)
 }
 
 // GetCanonicalHeader ...
 func GetCanonicalHeader(native *native.NativeService, chainID uint64, height uint64) (headerWithSum *HeaderWithDifficultySum, err error) {
-	hash, err := getCanonicalHash(native, chainID, height)
-	if err != nil {
-		return
-	}
-
-	if hash == (ecommon.Hash{}) {
-		return
-	}
-
-	headerWithSum, err = getHeader(native, hash, chainID)
-	return
-}
-
-func deleteCanonicalHash(native *native.NativeService, chainID uint64, height uint64) {
-	native.GetCacheDB().Delete(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.MAIN_CHAIN), utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(height)))
-}
-
-func getCanonicalHash(native *native.NativeService, chainID uint64, height uint64) (hash ecommon.Hash, err error) {
-	hashBytesStore, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.MAIN_CHAIN), utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(height)))
-	if err != nil {
-		return
-	}
-
-	if hashBytesStore == nil {
-		return
-	}
-
-	hashBytes, err := cstates.GetValueFromRawStorageItem(hashBytesStore)
-	if err != nil {
-		err = fmt.Errorf("bor Handler getCanonicalHash, GetValueFromRawStorageItem err:%v", err)
-		return
-	}
-
-	hash = ecommon.BytesToHash(hashBytes)
-	return
-}
-
-func putCanonicalHash(native *native.NativeService, chainID uint64, height uint64, hash ecommon.Hash) {
-	native.GetCacheDB().Put(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.MAIN_CHAIN), utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(height)),
-		cstates.GenRawStorageItem(hash.Bytes()))
-}
-
-func putHeaderWithSum(native *native.NativeService, chainID uint64, headerWithSum *HeaderWithDifficultySum) (err error) {
-
-	headerBytes, err := json.Marshal(headerWithSum)
-	if err != nil {
+	header, err := store.GetCanonicalHeader(native, chainID, height)
+	if err != nil || header == nil {
 		return
 	}
-
-	native.GetCacheDB().Put(
-		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.HEADER_INDEX), utils.GetUint64Bytes(chainID), headerWithSum.HeaderWithOptionalSnap.Header.Hash().Bytes()),
-		cstates.GenRawStorageItem(headerBytes))
+	headerWithSum = header.(*HeaderWithDifficultySum)
 	return
 }
 
-func putCanonicalHeight(native *native.NativeService, chainID uint64, height uint64) {
-	native.GetCacheDB().Put(
-		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.CURRENT_HEADER_HEIGHT), utils.GetUint64Bytes(chainID)),
-		cstates.GenRawStorageItem(utils.GetUint64Bytes(uint64(height))))
-}
-
 func addHeader(native *native.NativeService, header *eth.Header, snap *Snapshot, ctx *Context) (err error) {
 
 	parentHeader, err := getHeader(native, header.ParentHash, ctx.ChainID)
@@ -371,101 +261,22 @@ func addHeader(native *native.NativeService, header *eth.Header, snap *Snapshot,
 		return
 	}
 
-	cheight, err := GetCanonicalHeight(native, ctx.ChainID)
-	if err != nil {
-		return
-	}
-	cheader, err := GetCanonicalHeader(native, ctx.ChainID, cheight)
-	if err != nil {
-		return
-	}
-	if cheader == nil {
-		err = fmt.Errorf("getCanonicalHeader returns nil")
-		return
-	}
-
-	localTd := cheader.DifficultySum
 	externTd := new(big.Int).Add(header.Difficulty, parentHeader.DifficultySum)
-
 	headerWithSum := &HeaderWithDifficultySum{HeaderWithOptionalSnap: &HeaderWithOptionalSnap{Header: *header}, DifficultySum: externTd}
 	if snap.Hash == header.Hash() {
 		headerWithSum.HeaderWithOptionalSnap.Snapshot = snap
 	} else {
 		headerWithSum.SnapParentHash = &snap.Hash
 	}
-	err = putHeaderWithSum(native, ctx.ChainID, headerWithSum)
-	if err != nil {
-		return
-	}
-
-	if externTd.Cmp(localTd) > 0 {
-		// Delete any canonical number assignments above the new head
-		var headerWithSum *HeaderWithDifficultySum
-		for i := header.Number.Uint64() + 1; ; i++ {
-			headerWithSum, err = GetCanonicalHeader(native, ctx.ChainID, i)
-			if err != nil {
-				return
-			}
-			if headerWithSum == nil {
-				break
-			}
-
-			deleteCanonicalHash(native, ctx.ChainID, i)
-		}
-
-		// Overwrite any stale canonical number assignments
-		var (
-			hash       ecommon.Hash
-			headHeader *HeaderWithDifficultySum
-		)
-		cheight := header.Number.Uint64() - 1
-		headHash := header.ParentHash
-
-		for {
-			hash, err = getCanonicalHash(native, ctx.ChainID, cheight)
-			if err != nil {
-				return
-			}
-			if hash == headHash {
-				break
-			}
-
-			putCanonicalHash(native, ctx.ChainID, cheight, headHash)
-			headHeader, err = getHeader(native, headHash, ctx.ChainID)
-			if err != nil {
-				return
-			}
-			headHash = headHeader.HeaderWithOptionalSnap.Header.ParentHash
-			cheight--
-		}
-
-		// Extend the canonical chain with the new header
-		putCanonicalHash(native, ctx.ChainID, header.Number.Uint64(), header.Hash())
-		putCanonicalHeight(native, ctx.ChainID, header.Number.Uint64())
-	}
-
-	return nil
+	return store.AddHeader(native, ctx.ChainID, headerWithSum)
 }
 
 func getHeader(native *native.NativeService, hash ecommon.Hash, chainID uint64) (headerWithSum *HeaderWithDifficultySum, err error) {
-
-	headerStore, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress,
-		[]byte(scom.HEADER_INDEX), utils.GetUint64Bytes(chainID), hash.Bytes()))
-	if err != nil {
-		return nil, fmt.Errorf("bor Handler getHeader error: %v", err)
-	}
-	if headerStore == nil {
-		return nil, fmt.Errorf("bor Handler getHeader, can not find any header records")
-	}
-	storeBytes, err := cstates.GetValueFromRawStorageItem(headerStore)
+	header, err := store.GetHeader(native, chainID, hash)
 	if err != nil {
-		return nil, fmt.Errorf("bor Handler getHeader, deserialize headerBytes from raw storage item err:%v", err)
-	}
-	headerWithSum = &HeaderWithDifficultySum{}
-	if err := json.Unmarshal(storeBytes, &headerWithSum); err != nil {
-		return nil, fmt.Errorf("bor Handler getHeader, deserialize header error: %v", err)
+		return
 	}
-
+	headerWithSum = header.(*HeaderWithDifficultySum)
 	return
 }
 
@@ -727,15 +538,20 @@ func validatorContains(a []*Validator, x *Validator) (*Validator, bool) {
 	return nil, false
 }
 
-func shouldApplyFix(currentChainID uint64) bool {
-	chainID := config.GetPolygonSnapChainID(config.DefConfig.P2PNode.NetworkId)
-	return uint32(currentChainID) != chainID
+func shouldApplyFix(native *native.NativeService, currentChainID uint64) (bool, error) {
+	chainID, err := fork_manager.GetForkHeight(native, fork_manager.POLYGON_SNAP_CHAINID)
+	if err != nil {
+		return false, err
+	}
+	return uint32(currentChainID) != uint32(chainID), nil
 }
 
 func getSnapshot(native *native.NativeService, parent *HeaderWithDifficultySum, ctx *Context) (s *Snapshot, err error) {
 	if parent.HeaderWithOptionalSnap.Snapshot != nil {
 		s = parent.HeaderWithOptionalSnap.Snapshot
-		if shouldApplyFix(ctx.ChainID) {
+		var fix bool
+		fix, err = shouldApplyFix(native, ctx.ChainID)
+		if err == nil && fix {
 			err = s.ValidatorSet.updateTotalVotingPower()
 		}
 		return
@@ -756,7 +572,8 @@ func getSnapshot(native *native.NativeService, parent *HeaderWithDifficultySum,
 	}
 
 	s = snapHeader.HeaderWithOptionalSnap.Snapshot
-	if shouldApplyFix(ctx.ChainID) {
+	fix, err := shouldApplyFix(native, ctx.ChainID)
+	if err == nil && fix {
 		err = s.ValidatorSet.updateTotalVotingPower()
 	}
 	return
@@ -883,3 +700,64 @@ func encodeSigHeader(native *native.NativeService, w io.Writer, header *eth.Head
 func (h *BorHandler) SyncCrossChainMsg(native *native.NativeService) error {
 	return nil
 }
+
+// GetHeaderRange implements scom.HeaderPruner
+func (h *BorHandler) GetHeaderRange(native *native.NativeService, chainID uint64) (genesis, current uint64, err error) {
+	var genesisHeader HeaderWithOptionalSnap
+	exist, err := store.GetGenesis(native, chainID, &genesisHeader)
+	if err != nil {
+		return
+	}
+	if !exist {
+		err = fmt.Errorf("bor Handler GetHeaderRange, genesis header is not synced")
+		return
+	}
+	current, err = GetCanonicalHeight(native, chainID)
+	return genesisHeader.Header.Number.Uint64(), current, err
+}
+
+// PruneHeader implements scom.HeaderPruner
+func (h *BorHandler) PruneHeader(native *native.NativeService, chainID uint64, height uint64) error {
+	return store.PruneHeader(native, chainID, height)
+}
+
+// GetHeaderSyncStatus implements scom.HeaderSyncQuerier, the validators are the snapshot verifying the children of the head
+func (h *BorHandler) GetHeaderSyncStatus(native *native.NativeService, chainID uint64) (*scom.HeaderSyncStatus, error) {
+	var genesis HeaderWithOptionalSnap
+	exist, err := store.GetGenesis(native, chainID, &genesis)
+	if err != nil || !exist {
+		return nil, err
+	}
+	height, err := GetCanonicalHeight(native, chainID)
+	if err != nil {
+		return nil, err
+	}
+	head, err := GetCanonicalHeader(native, chainID, height)
+	if err != nil {
+		return nil, err
+	}
+	if head == nil {
+		return nil, fmt.Errorf("bor Handler GetHeaderSyncStatus, canonical header %d not found", height)
+	}
+	snapHeader := head
+	if head.HeaderWithOptionalSnap.Snapshot == nil && head.SnapParentHash != nil {
+		snapHeader, err = getHeader(native, *head.SnapParentHash, chainID)
+		if err != nil {
+			return nil, fmt.Errorf("bor Handler GetHeaderSyncStatus, getHeader error: %v", err)
+		}
+	}
+	genesisHash, hash := genesis.Header.Hash(), head.HeaderWithOptionalSnap.Header.Hash()
+	status := &scom.HeaderSyncStatus{
+		GenesisHeight: genesis.Header.Number.Uint64(),
+		GenesisHash:   genesisHash.Bytes(),
+		Height:        height,
+		Hash:          hash.Bytes(),
+	}
+	if snap := snapHeader.HeaderWithOptionalSnap.Snapshot; snap != nil && snap.ValidatorSet != nil {
+		status.EpochHeight = snapHeader.HeaderNumber()
+		for _, v := range snap.ValidatorSet.Validators {
+			status.Validators = append(status.Validators, v.Address.Bytes())
+		}
+	}
+	return status, nil
+}
@@ -28,7 +28,6 @@ import (
 	"github.com/polynetwork/poly/common/log"
 	cstates "github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/native"
-	"github.com/polynetwork/poly/native/event"
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
 	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
 	polygonTypes "github.com/polynetwork/poly/native/service/header_sync/polygon/types"
@@ -44,6 +43,10 @@ func NewHeimdallHandler() *HeimdallHandler {
 	return &HeimdallHandler{}
 }
 
+func init() {
+	hscommon.RegisterHeaderSyncHandler(utils.POLYGON_HEIMDALL_ROUTER, func() hscommon.HeaderSyncHandler { return NewHeimdallHandler() })
+}
+
 type CosmosHeader struct {
 	Header  polygonTypes.Header
 	Commit  *polygonTypes.Commit
@@ -132,6 +135,29 @@ func (h *HeimdallHandler) SyncCrossChainMsg(native *native.NativeService) error
 	return nil
 }
 
+// GetHeaderSyncStatus implements hscommon.HeaderSyncQuerier, only the latest epoch switch header is kept,
+// so it is reported as the synced header, the genesis anchor is unknown and the validators are given by their hash
+func (h *HeimdallHandler) GetHeaderSyncStatus(native *native.NativeService, chainID uint64) (*hscommon.HeaderSyncStatus, error) {
+	val, err := native.GetCacheDB().Get(
+		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(hscommon.EPOCH_SWITCH), utils.GetUint64Bytes(chainID)))
+	if err != nil {
+		return nil, fmt.Errorf("HeimdallHandler GetHeaderSyncStatus, get epoch switch info error: %v", err)
+	}
+	if val == nil {
+		return nil, nil
+	}
+	info, err := GetEpochSwitchInfo(native, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("HeimdallHandler GetHeaderSyncStatus, %v", err)
+	}
+	return &hscommon.HeaderSyncStatus{
+		Height:         uint64(info.Height),
+		Hash:           info.BlockHash,
+		EpochHeight:    uint64(info.Height),
+		ValidatorsHash: info.NextValidatorsHash,
+	}, nil
+}
+
 func GetEpochSwitchInfo(service *native.NativeService, chainId uint64) (*CosmosEpochSwitchInfo, error) {
 	val, err := service.GetCacheDB().Get(
 		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(hscommon.EPOCH_SWITCH), utils.GetUint64Bytes(chainId)))
@@ -162,12 +188,8 @@ func notifyEpochSwitchInfo(native *native.NativeService, chainID uint64, info *C
 	if !config.DefConfig.Common.EnableEventLog {
 		return
 	}
-	native.AddNotify(
-		&event.NotifyEventInfo{
-			ContractAddress: utils.HeaderSyncContractAddress,
-			States: []interface{}{chainID, info.BlockHash.String(), info.Height,
-				info.NextValidatorsHash.String(), info.ChainID, native.GetHeight()},
-		})
+	native.AddNotify(hscommon.EpochSwitchEvent.NewNotify(chainID, info.BlockHash.String(), info.Height,
+		info.NextValidatorsHash.String(), info.ChainID, native.GetHeight()))
 }
 
 type CosmosEpochSwitchInfo struct {
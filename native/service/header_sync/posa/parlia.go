@@ -0,0 +1,836 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package posa
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	ecommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/fork_manager"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	scom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/header_sync/eth"
+	"github.com/polynetwork/poly/native/service/header_sync/eth/rlp"
+	"github.com/polynetwork/poly/native/service/utils"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	extraVanity = 32                         // Fixed number of extra-data prefix bytes reserved for signer vanity
+	extraSeal   = crypto.SignatureLength     // Fixed number of extra-data suffix bytes reserved for signer seal
+	GasLimitMax = uint64(0x7fffffffffffffff) // GasLimit maximum ( GasLimit <= 2^63-1)
+)
+
+var (
+	uncleHash  = types.CalcUncleHash(nil) // Always Keccak256(RLP([])) as uncles are meaningless outside of PoW.
+	diffInTurn = big.NewInt(2)            // Block difficulty for in-turn signatures
+	diffNoTurn = big.NewInt(1)            // Block difficulty for out-of-turn signatures
+)
+
+// Config describes how a parlia or congress side chain differs from the others.
+// Adding a chain of this family only takes a new Config.
+type Config struct {
+	// Name of the chain in errors and logs
+	Name string
+	// SealWithChainID mixes ExtraInfo.ChainID into the seal hash, as parlia does
+	SealWithChainID bool
+	// DelayedRotation keeps the previous validator set in turn for half of its
+	// size after an epoch header, as parlia does. congress rotates at once.
+	DelayedRotation bool
+	// AllowContinuousEpoch skips the check that an epoch header does not follow
+	// the previous one within half of the validator set size
+	AllowContinuousEpoch bool
+	// CheckPeriod rejects headers produced within ExtraInfo.Period of the parent
+	CheckPeriod bool
+	// PrepareHeader, if set, adjusts every decoded header before it is hashed
+	PrepareHeader func(native *native.NativeService, header *eth.Header)
+	// VerifyGas, if set, checks the gas limit and base fee of header against parent
+	VerifyGas func(native *native.NativeService, parent, header *eth.Header) error
+	// TestSigner, for test only, returns a signer to trust instead of recovering the seal
+	TestSigner func(header *eth.Header) (ecommon.Address, bool)
+}
+
+// Engine syncs headers of a parlia or congress side chain
+type Engine struct {
+	config *Config
+	store  *Store
+}
+
+// NewEngine ...
+func NewEngine(config *Config) *Engine {
+	return &Engine{
+		config: config,
+		store: &Store{
+			Name:      config.Name,
+			NewHeader: func() StoredHeader { return &HeaderWithDifficultySum{} },
+		},
+	}
+}
+
+// GenesisHeader ...
+type GenesisHeader struct {
+	Header         eth.Header
+	PrevValidators []HeightAndValidators
+}
+
+// ExtraInfo ...
+type ExtraInfo struct {
+	ChainID *big.Int // chainId of the side chain
+	Period  uint64   // block period, only checked with Config.CheckPeriod
+}
+
+// HeightAndValidators ...
+type HeightAndValidators struct {
+	Height     *big.Int
+	Validators []ecommon.Address
+	Hash       *ecommon.Hash
+}
+
+// HeaderWithDifficultySum ...
+type HeaderWithDifficultySum struct {
+	Header          *eth.Header   `json:"header"`
+	DifficultySum   *big.Int      `json:"difficultySum"`
+	EpochParentHash *ecommon.Hash `json:"epochParentHash"`
+}
+
+// HeaderHash ...
+func (h *HeaderWithDifficultySum) HeaderHash() ecommon.Hash {
+	return h.Header.Hash()
+}
+
+// HeaderParentHash ...
+func (h *HeaderWithDifficultySum) HeaderParentHash() ecommon.Hash {
+	return h.Header.ParentHash
+}
+
+// HeaderNumber ...
+func (h *HeaderWithDifficultySum) HeaderNumber() uint64 {
+	return h.Header.Number.Uint64()
+}
+
+// TotalDifficulty ...
+func (h *HeaderWithDifficultySum) TotalDifficulty() *big.Int {
+	return h.DifficultySum
+}
+
+type context struct {
+	ExtraInfo ExtraInfo
+	ChainID   uint64
+}
+
+// SyncGenesisHeader synchronize the initial block header of the side chain to poly relay chain
+func (e *Engine) SyncGenesisHeader(native *native.NativeService) (err error) {
+	params := new(scom.SyncGenesisHeaderParam)
+	//fetch the initial block header binary code and chain ID from the poly native service data
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return fmt.Errorf("%s Handler SyncGenesisHeader, contract params deserialize error: %v", e.config.Name, err)
+	}
+	// Get current epoch operator of poly chain
+	operatorAddress, err := node_manager.GetCurConOperator(native)
+	if err != nil {
+		return fmt.Errorf("%s Handler SyncGenesisHeader, get current consensus operator address error: %v", e.config.Name, err)
+	}
+
+	//check witness
+	err = utils.ValidateOwner(native, operatorAddress)
+	if err != nil {
+		return fmt.Errorf("%s Handler SyncGenesisHeader, checkWitness error: %v", e.config.Name, err)
+	}
+
+	// can only store once
+	stored, err := e.store.IsGenesisStored(native, params.ChainID)
+	if err != nil {
+		return fmt.Errorf("%s Handler SyncGenesisHeader, isGenesisStored error: %v", e.config.Name, err)
+	}
+	if stored {
+		return fmt.Errorf("%s Handler SyncGenesisHeader, genesis had been initialized", e.config.Name)
+	}
+	//genesis header that contains PrevValidators
+	var genesis GenesisHeader
+	err = json.Unmarshal(params.GenesisHeader, &genesis)
+	if err != nil {
+		return fmt.Errorf("%s Handler SyncGenesisHeader, deserialize GenesisHeader err: %v", e.config.Name, err)
+	}
+	if e.config.PrepareHeader != nil {
+		e.config.PrepareHeader(native, &genesis.Header)
+	}
+	//check the format validity of extra field
+	signersBytes := len(genesis.Header.Extra) - extraVanity - extraSeal
+	if signersBytes == 0 || signersBytes%ecommon.AddressLength != 0 {
+		return fmt.Errorf("invalid signer list, signersBytes:%d", signersBytes)
+	}
+	if len(genesis.PrevValidators) != 1 {
+		return fmt.Errorf("invalid PrevValidators")
+	}
+	//the block height of PrevValidators should be smaller than genesis header
+	if genesis.Header.Number.Cmp(genesis.PrevValidators[0].Height) <= 0 {
+		return fmt.Errorf("invalid height orders")
+	}
+	//parse the address of validators from the extra field
+	validators, err := ParseValidators(genesis.Header.Extra[extraVanity : extraVanity+signersBytes])
+	if err != nil {
+		return
+	}
+	genesis.PrevValidators = append([]HeightAndValidators{
+		{Height: genesis.Header.Number, Validators: validators},
+	}, genesis.PrevValidators...)
+
+	headerWithSum := &HeaderWithDifficultySum{Header: &genesis.Header, DifficultySum: genesis.Header.Difficulty}
+	err = e.store.StoreGenesis(native, params.ChainID, &genesis, headerWithSum)
+	if err != nil {
+		return fmt.Errorf("%s Handler SyncGenesisHeader, storeGenesis error: %v", e.config.Name, err)
+	}
+
+	return
+}
+
+// SyncBlockHeader synchronize the consequent block headers of the side chain to poly relay chain
+func (e *Engine) SyncBlockHeader(native *native.NativeService) error {
+	headerParams := new(scom.SyncBlockHeaderParam)
+	//fetch the block header binary code and chain ID from the native transaction parameter
+	if err := headerParams.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return fmt.Errorf("%s Handler SyncBlockHeader, contract params deserialize error: %v", e.config.Name, err)
+	}
+	//get the registered side chain information
+	side, err := side_chain_manager.GetSideChain(native, headerParams.ChainID)
+	if err != nil {
+		return fmt.Errorf("%s Handler SyncBlockHeader, GetSideChain error: %v", e.config.Name, err)
+	}
+	if side == nil {
+		return fmt.Errorf("%s Handler SyncBlockHeader, GetSideChain info nil", e.config.Name)
+	}
+	var extraInfo ExtraInfo
+	err = json.Unmarshal(side.ExtraInfo, &extraInfo)
+	if err != nil {
+		return fmt.Errorf("%s Handler SyncBlockHeader, ExtraInfo Unmarshal error: %v", e.config.Name, err)
+	}
+
+	ctx := &context{ExtraInfo: extraInfo, ChainID: headerParams.ChainID}
+
+	for _, v := range headerParams.Headers {
+		var header eth.Header
+		err := json.Unmarshal(v, &header)
+		if err != nil {
+			return fmt.Errorf("%s Handler SyncBlockHeader, deserialize header err: %v", e.config.Name, err)
+		}
+		if e.config.PrepareHeader != nil {
+			e.config.PrepareHeader(native, &header)
+		}
+		headerHash := header.Hash()
+		//look for header hash from poly chain to make sure this header hasn't been synced
+		exist, err := e.store.IsHeaderExist(native, ctx.ChainID, headerHash)
+		if err != nil {
+			return fmt.Errorf("%s Handler SyncBlockHeader, isHeaderExist headerHash err: %v", e.config.Name, err)
+		}
+		if exist {
+			log.Warnf("%s Handler SyncBlockHeader, header has exist. Header: %s", e.config.Name, string(v))
+			continue
+		}
+		//make sure the parent block has already been synced
+		parentExist, err := e.store.IsHeaderExist(native, ctx.ChainID, header.ParentHash)
+		if err != nil {
+			return fmt.Errorf("%s Handler SyncBlockHeader, isHeaderExist ParentHash err: %v", e.config.Name, err)
+		}
+		if !parentExist {
+			log.Warnf("%s Handler SyncBlockHeader, parent header not exist. Header: %s", e.config.Name, string(v))
+			continue
+		}
+
+		//Verify the legitimacy of the block header
+		//This function refers to https://github.com/binance-chain/bsc/blob/master/consensus/parlia/parlia.go#L324-L374
+		signer, err := e.verifyHeader(native, &header, ctx)
+		if err != nil {
+			return fmt.Errorf("%s Handler SyncBlockHeader, verifySignature err: %v", e.config.Name, err)
+		}
+
+		// get prev epochs, also checking recent limit
+		phv, pphv, lastSeenHeight, err := e.getPrevHeightAndValidators(native, &header, ctx)
+		if err != nil {
+			return fmt.Errorf("%s Handler SyncBlockHeader, getPrevHeightAndValidators err: %v", e.config.Name, err)
+		}
+
+		inTurnHV := phv
+		if e.config.DelayedRotation {
+			diffWithLastEpoch := big.NewInt(0).Sub(header.Number, phv.Height).Int64()
+			if diffWithLastEpoch <= int64(len(pphv.Validators)/2) {
+				// pphv is in effect
+				inTurnHV = pphv
+
+				if len(header.Extra) > extraVanity+extraSeal {
+					return fmt.Errorf("%s Handler SyncBlockHeader: can not change epoch continuously", e.config.Name)
+				}
+			}
+		} else if !e.config.AllowContinuousEpoch && len(header.Extra) > extraVanity+extraSeal {
+			diffWithLastEpoch := big.NewInt(0).Sub(header.Number, phv.Height).Int64()
+			if diffWithLastEpoch <= int64(len(phv.Validators)/2) {
+				return fmt.Errorf("%s Handler SyncBlockHeader: can not change epoch continuously", e.config.Name)
+			}
+		}
+
+		if lastSeenHeight > 0 {
+			limit := int64(len(inTurnHV.Validators) / 2)
+			if header.Number.Int64() <= lastSeenHeight+limit {
+				return fmt.Errorf("%s Handler SyncBlockHeader, RecentlySigned, lastSeenHeight:%d currentHeight:%d #V:%d", e.config.Name, lastSeenHeight, header.Number.Int64(), len(inTurnHV.Validators))
+			}
+		}
+
+		indexInTurn := int(header.Number.Uint64()) % len(inTurnHV.Validators)
+		if indexInTurn < 0 {
+			return fmt.Errorf("indexInTurn is negative:%d inTurnHV.Height:%d header.Number:%d", indexInTurn, inTurnHV.Height.Int64(), header.Number.Int64())
+		}
+		valid := false
+		for idx, v := range inTurnHV.Validators {
+			if v == signer {
+				valid = true
+				if indexInTurn == idx {
+					if header.Difficulty.Cmp(diffInTurn) != 0 {
+						return fmt.Errorf("invalid difficulty, got %v expect %v index:%v", header.Difficulty.Int64(), diffInTurn.Int64(), indexInTurn)
+					}
+				} else {
+					if header.Difficulty.Cmp(diffNoTurn) != 0 {
+						return fmt.Errorf("invalid difficulty, got %v expect %v index:%v", header.Difficulty.Int64(), diffNoTurn.Int64(), indexInTurn)
+					}
+				}
+			}
+		}
+		if !valid {
+			return native.Misbehave(fmt.Errorf("%s Handler SyncBlockHeader, invalid signer", e.config.Name))
+		}
+		if err := e.checkConflict(native, &header, side.BlocksToWait, ctx); err != nil {
+			return err
+		}
+		//put verified header into relay chain
+		err = e.addHeader(native, &header, phv, ctx)
+		if err != nil {
+			return fmt.Errorf("%s Handler SyncBlockHeader, addHeader err: %v", e.config.Name, err)
+		}
+
+		scom.NotifyPutHeader(native, headerParams.ChainID, header.Number.Uint64(), header.Hash().Hex())
+	}
+	return nil
+}
+
+// checkConflict returns the misbehavior if header forks off the canonical header at its height, which has been
+// confirmed by blocksToWait blocks and may have been used to verify cross chain txs. Such headers are accepted
+// before the relayer slashing fork
+func (e *Engine) checkConflict(native *native.NativeService, header *eth.Header, blocksToWait uint64, ctx *context) error {
+	forkHeight, err := fork_manager.GetForkHeight(native, fork_manager.RELAYER_SLASHING)
+	if err != nil {
+		return fmt.Errorf("%s Handler SyncBlockHeader, GetForkHeight err: %v", e.config.Name, err)
+	}
+	if uint64(native.GetHeight()) < forkHeight {
+		return nil
+	}
+	number := header.Number.Uint64()
+	height, err := e.store.GetCanonicalHeight(native, ctx.ChainID)
+	if err != nil {
+		return fmt.Errorf("%s Handler SyncBlockHeader, GetCanonicalHeight err: %v", e.config.Name, err)
+	}
+	if blocksToWait == 0 || number > height || height-number+1 < blocksToWait {
+		return nil
+	}
+	hash, err := e.store.GetCanonicalHash(native, ctx.ChainID, number)
+	if err != nil {
+		return fmt.Errorf("%s Handler SyncBlockHeader, GetCanonicalHash err: %v", e.config.Name, err)
+	}
+	if hash == (ecommon.Hash{}) || hash == header.Hash() {
+		return nil
+	}
+	return native.Misbehave(fmt.Errorf("%s Handler SyncBlockHeader, header %s conflicts with confirmed header %s at height %d",
+		e.config.Name, header.Hash().Hex(), hash.Hex(), number))
+}
+
+// SyncCrossChainMsg ...
+func (e *Engine) SyncCrossChainMsg(native *native.NativeService) error {
+	return nil
+}
+
+// GetCanonicalHeight ...
+func (e *Engine) GetCanonicalHeight(native *native.NativeService, chainID uint64) (uint64, error) {
+	return e.store.GetCanonicalHeight(native, chainID)
+}
+
+// GetCanonicalHeader ...
+func (e *Engine) GetCanonicalHeader(native *native.NativeService, chainID uint64, height uint64) (*HeaderWithDifficultySum, error) {
+	header, err := e.store.GetCanonicalHeader(native, chainID, height)
+	if err != nil || header == nil {
+		return nil, err
+	}
+	return header.(*HeaderWithDifficultySum), nil
+}
+
+// GetHeader ...
+func (e *Engine) GetHeader(native *native.NativeService, hash ecommon.Hash, chainID uint64) (*HeaderWithDifficultySum, error) {
+	header, err := e.store.GetHeader(native, chainID, hash)
+	if err != nil {
+		return nil, err
+	}
+	return header.(*HeaderWithDifficultySum), nil
+}
+
+// GetHeaderRange implements scom.HeaderPruner
+func (e *Engine) GetHeaderRange(native *native.NativeService, chainID uint64) (genesis, current uint64, err error) {
+	genesisHeader, err := e.getGenesis(native, chainID)
+	if err != nil {
+		return
+	}
+	if genesisHeader == nil {
+		err = fmt.Errorf("%s Handler GetHeaderRange, genesis header is not synced", e.config.Name)
+		return
+	}
+	current, err = e.store.GetCanonicalHeight(native, chainID)
+	return genesisHeader.Header.Number.Uint64(), current, err
+}
+
+// PruneHeader implements scom.HeaderPruner
+func (e *Engine) PruneHeader(native *native.NativeService, chainID uint64, height uint64) error {
+	return e.store.PruneHeader(native, chainID, height)
+}
+
+// GetHeaderSyncStatus implements scom.HeaderSyncQuerier, the validators are the latest set
+// carried by an epoch header of the canonical chain
+func (e *Engine) GetHeaderSyncStatus(native *native.NativeService, chainID uint64) (*scom.HeaderSyncStatus, error) {
+	genesis, err := e.getGenesis(native, chainID)
+	if err != nil || genesis == nil {
+		return nil, err
+	}
+	height, err := e.store.GetCanonicalHeight(native, chainID)
+	if err != nil {
+		return nil, err
+	}
+	head, err := e.GetCanonicalHeader(native, chainID, height)
+	if err != nil {
+		return nil, err
+	}
+	if head == nil {
+		return nil, fmt.Errorf("%s Handler GetHeaderSyncStatus, canonical header %d not found", e.config.Name, height)
+	}
+	hv, err := e.getLatestValidators(native, chainID, genesis, head)
+	if err != nil {
+		return nil, fmt.Errorf("%s Handler GetHeaderSyncStatus, %v", e.config.Name, err)
+	}
+	genesisHash, hash := genesis.Header.Hash(), head.Header.Hash()
+	status := &scom.HeaderSyncStatus{
+		GenesisHeight: genesis.Header.Number.Uint64(),
+		GenesisHash:   genesisHash.Bytes(),
+		Height:        height,
+		Hash:          hash.Bytes(),
+	}
+	if hv.Height != nil {
+		status.EpochHeight = hv.Height.Uint64()
+	}
+	for _, v := range hv.Validators {
+		status.Validators = append(status.Validators, v.Bytes())
+	}
+	return status, nil
+}
+
+// getLatestValidators walks back from header through the epoch parents to the latest epoch header
+func (e *Engine) getLatestValidators(native *native.NativeService, chainID uint64, genesis *GenesisHeader, header *HeaderWithDifficultySum) (*HeightAndValidators, error) {
+	genesisHeaderHash := genesis.Header.Hash()
+	for {
+		if header.Header.Hash() == genesisHeaderHash {
+			return &genesis.PrevValidators[0], nil
+		}
+		if len(header.Header.Extra) > extraVanity+extraSeal {
+			validators, err := ParseValidators(header.Header.Extra[extraVanity : len(header.Header.Extra)-extraSeal])
+			if err != nil {
+				return nil, fmt.Errorf("ParseValidators error: %v", err)
+			}
+			return &HeightAndValidators{Height: header.Header.Number, Validators: validators}, nil
+		}
+		parentHash := header.Header.ParentHash
+		if header.EpochParentHash != nil {
+			parentHash = *header.EpochParentHash
+		}
+		if parentHash == genesisHeaderHash {
+			return &genesis.PrevValidators[0], nil
+		}
+		var err error
+		header, err = e.GetHeader(native, parentHash, chainID)
+		if err != nil {
+			return nil, fmt.Errorf("getHeader error: %v", err)
+		}
+	}
+}
+
+func (e *Engine) getGenesis(native *native.NativeService, chainID uint64) (*GenesisHeader, error) {
+	genesis := &GenesisHeader{}
+	exist, err := e.store.GetGenesis(native, chainID, genesis)
+	if err != nil || !exist {
+		return nil, err
+	}
+	return genesis, nil
+}
+
+func (e *Engine) addHeader(native *native.NativeService, header *eth.Header, phv *HeightAndValidators, ctx *context) (err error) {
+	parentHeader, err := e.GetHeader(native, header.ParentHash, ctx.ChainID)
+	if err != nil {
+		return
+	}
+
+	externTd := new(big.Int).Add(header.Difficulty, parentHeader.DifficultySum)
+	headerWithSum := &HeaderWithDifficultySum{Header: header, DifficultySum: externTd, EpochParentHash: phv.Hash}
+	return e.store.AddHeader(native, ctx.ChainID, headerWithSum)
+}
+
+func (e *Engine) getPrevHeightAndValidators(native *native.NativeService, header *eth.Header, ctx *context) (phv, pphv *HeightAndValidators, lastSeenHeight int64, err error) {
+
+	genesis, err := e.getGenesis(native, ctx.ChainID)
+	if err != nil {
+		err = fmt.Errorf("%s Handler getGenesis error: %v", e.config.Name, err)
+		return
+	}
+
+	if genesis == nil {
+		err = fmt.Errorf("%s Handler genesis not set", e.config.Name)
+		return
+	}
+
+	genesisHeaderHash := genesis.Header.Hash()
+	if header.Hash() == genesisHeaderHash {
+		err = fmt.Errorf("genesis header should not be synced again")
+		return
+	}
+
+	lastSeenHeight = -1
+	targetCoinbase := header.Coinbase
+	if header.ParentHash == genesisHeaderHash {
+		if genesis.Header.Coinbase == targetCoinbase {
+			lastSeenHeight = genesis.Header.Number.Int64()
+		}
+
+		phv = &genesis.PrevValidators[0]
+		phv.Hash = &genesisHeaderHash
+		pphv = &genesis.PrevValidators[1]
+		return
+	}
+
+	prevHeaderWithSum, err := e.GetHeader(native, header.ParentHash, ctx.ChainID)
+	if err != nil {
+		err = fmt.Errorf("%s Handler getHeader error: %v", e.config.Name, err)
+		return
+	}
+
+	if prevHeaderWithSum.Header.Coinbase == targetCoinbase {
+		lastSeenHeight = prevHeaderWithSum.Header.Number.Int64()
+	} else {
+		nextRecentParentHash := prevHeaderWithSum.Header.ParentHash
+		defer func() {
+			if err == nil {
+				maxV := len(phv.Validators)
+				if maxV < len(pphv.Validators) {
+					maxV = len(pphv.Validators)
+				}
+				maxLimit := maxV / 2
+				for i := 0; i < maxLimit-1; i++ {
+					prevHeaderWithSum, err := e.GetHeader(native, nextRecentParentHash, ctx.ChainID)
+					if err != nil {
+						err = fmt.Errorf("%s Handler getHeader error: %v", e.config.Name, err)
+						return
+					}
+					if prevHeaderWithSum.Header.Coinbase == targetCoinbase {
+						lastSeenHeight = prevHeaderWithSum.Header.Number.Int64()
+						return
+					}
+
+					if nextRecentParentHash == genesisHeaderHash {
+						return
+					}
+					nextRecentParentHash = prevHeaderWithSum.Header.ParentHash
+				}
+			}
+		}()
+	}
+
+	var (
+		validators     []ecommon.Address
+		nextParentHash ecommon.Hash
+	)
+
+	currentPV := &phv
+
+	for {
+
+		if len(prevHeaderWithSum.Header.Extra) > extraVanity+extraSeal {
+			validators, err = ParseValidators(prevHeaderWithSum.Header.Extra[extraVanity : len(prevHeaderWithSum.Header.Extra)-extraSeal])
+			if err != nil {
+				err = fmt.Errorf("%s Handler ParseValidators error: %v", e.config.Name, err)
+				return
+			}
+			*currentPV = &HeightAndValidators{
+				Height:     prevHeaderWithSum.Header.Number,
+				Validators: validators,
+			}
+			switch *currentPV {
+			case phv:
+				hash := prevHeaderWithSum.Header.Hash()
+				phv.Hash = &hash
+				currentPV = &pphv
+			case pphv:
+				return
+			default:
+				err = fmt.Errorf("bug in %s Handler", e.config.Name)
+				return
+			}
+		}
+
+		nextParentHash = prevHeaderWithSum.Header.ParentHash
+		if prevHeaderWithSum.EpochParentHash != nil {
+			nextParentHash = *prevHeaderWithSum.EpochParentHash
+		}
+
+		if nextParentHash == genesisHeaderHash {
+			switch *currentPV {
+			case phv:
+				phv = &genesis.PrevValidators[0]
+				phv.Hash = &genesisHeaderHash
+				pphv = &genesis.PrevValidators[1]
+			case pphv:
+				pphv = &genesis.PrevValidators[0]
+			default:
+				err = fmt.Errorf("bug in %s Handler", e.config.Name)
+				return
+			}
+			return
+		}
+
+		prevHeaderWithSum, err = e.GetHeader(native, nextParentHash, ctx.ChainID)
+		if err != nil {
+			err = fmt.Errorf("%s Handler getHeader error: %v", e.config.Name, err)
+			return
+		}
+
+	}
+}
+
+// https://github.com/binance-chain/bsc/blob/master/consensus/parlia/parlia.go#L324-L374
+func (e *Engine) verifyHeader(native *native.NativeService, header *eth.Header, ctx *context) (signer ecommon.Address, err error) {
+
+	// Don't waste time checking blocks from the future
+	if header.Time > uint64(time.Now().Unix()) {
+		err = errors.New("block in the future")
+		return
+	}
+
+	// Check that the extra-data contains both the vanity and signature
+	if len(header.Extra) < extraVanity {
+		err = errors.New("extra-data 32 byte vanity prefix missing")
+		return
+	}
+	if len(header.Extra) < extraVanity+extraSeal {
+		err = errors.New("extra-data 65 byte signature suffix missing")
+		return
+	}
+
+	// Ensure that the extra-data contains a signer list on checkpoint, but none otherwise
+	signersBytes := len(header.Extra) - extraVanity - extraSeal
+
+	if signersBytes%ecommon.AddressLength != 0 {
+		err = errors.New("invalid signer list")
+		return
+	}
+
+	// Ensure that the mix digest is zero as we don't have fork protection currently
+	if header.MixDigest != (ecommon.Hash{}) {
+		err = errors.New("non-zero mix digest")
+		return
+	}
+
+	// Ensure that the block doesn't contain any uncles which are meaningless in PoA
+	if header.UncleHash != uncleHash {
+		err = errors.New("non empty uncle hash")
+		return
+	}
+
+	// Ensure that the block's difficulty is meaningful (may not be correct at this point)
+	if header.Difficulty == nil || (header.Difficulty.Cmp(diffInTurn) != 0 && header.Difficulty.Cmp(diffNoTurn) != 0) {
+		err = errors.New("invalid difficulty")
+		return
+	}
+
+	// Verify that the gas limit is <= 2^63-1
+	if header.GasLimit > GasLimitMax {
+		err = fmt.Errorf("invalid gasLimit: have %v, max %v", header.GasLimit, GasLimitMax)
+		return
+	}
+
+	// All basic checks passed, verify cascading fields
+	return e.verifyCascadingFields(native, header, ctx)
+}
+
+func (e *Engine) verifyCascadingFields(native *native.NativeService, header *eth.Header, ctx *context) (signer ecommon.Address, err error) {
+
+	number := header.Number.Uint64()
+
+	parent, err := e.GetHeader(native, header.ParentHash, ctx.ChainID)
+	if err != nil {
+		return
+	}
+
+	if parent.Header.Number.Uint64() != number-1 {
+		err = errors.New("unknown ancestor")
+		return
+	}
+
+	if e.config.CheckPeriod && parent.Header.Time+ctx.ExtraInfo.Period > header.Time {
+		err = errors.New("invalid timestamp")
+		return
+	}
+
+	// Verify that the gasUsed is <= gasLimit
+	if header.GasUsed > header.GasLimit {
+		err = fmt.Errorf("invalid gasUsed: have %d, gasLimit %d", header.GasUsed, header.GasLimit)
+		return
+	}
+
+	if e.config.VerifyGas != nil {
+		if err = e.config.VerifyGas(native, parent.Header, header); err != nil {
+			return
+		}
+	}
+
+	return e.verifySeal(native, header, ctx)
+}
+
+func (e *Engine) verifySeal(native *native.NativeService, header *eth.Header, ctx *context) (signer ecommon.Address, err error) {
+	// Verifying the genesis block is not supported
+	number := header.Number.Uint64()
+	if number == 0 {
+		err = errors.New("unknown block")
+		return
+	}
+
+	if e.config.TestSigner != nil {
+		if testSigner, ok := e.config.TestSigner(header); ok {
+			return testSigner, nil
+		}
+	}
+	// Resolve the authorization key and check against validators
+	signer, err = e.Ecrecover(header, ctx.ExtraInfo.ChainID)
+	if err != nil {
+		err = native.Misbehave(err)
+		return
+	}
+
+	if signer != header.Coinbase {
+		err = native.Misbehave(errors.New("coinbase do not match with signature"))
+		return
+	}
+
+	return
+}
+
+// Ecrecover extracts the Ethereum account address from a signed header.
+func (e *Engine) Ecrecover(header *eth.Header, chainID *big.Int) (ecommon.Address, error) {
+	// Retrieve the signature from the header extra-data
+	if len(header.Extra) < extraSeal {
+		return ecommon.Address{}, errors.New("extra-data 65 byte signature suffix missing")
+	}
+	signature := header.Extra[len(header.Extra)-extraSeal:]
+
+	// Recover the public key and the Ethereum address
+	pubkey, err := crypto.Ecrecover(e.SealHash(header, chainID).Bytes(), signature)
+	if err != nil {
+		return ecommon.Address{}, err
+	}
+	var signer ecommon.Address
+	copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
+
+	return signer, nil
+}
+
+// SealHash returns the hash of a block prior to it being sealed.
+func (e *Engine) SealHash(header *eth.Header, chainID *big.Int) (hash ecommon.Hash) {
+	hasher := sha3.NewLegacyKeccak256()
+	e.encodeSigHeader(hasher, header, chainID)
+	hasher.Sum(hash[:0])
+	return hash
+}
+
+func (e *Engine) encodeSigHeader(w io.Writer, header *eth.Header, chainID *big.Int) {
+	fields := []interface{}{
+		header.ParentHash,
+		header.UncleHash,
+		header.Coinbase,
+		header.Root,
+		header.TxHash,
+		header.ReceiptHash,
+		header.Bloom,
+		header.Difficulty,
+		header.Number,
+		header.GasLimit,
+		header.GasUsed,
+		header.Time,
+		header.Extra[:len(header.Extra)-65], // this will panic if extra is too short, should check before calling encodeSigHeader
+		header.MixDigest,
+		header.Nonce,
+	}
+	if e.config.SealWithChainID {
+		fields = append([]interface{}{chainID}, fields...)
+	}
+	err := rlp.Encode(w, fields)
+	if err != nil {
+		panic("can't encode: " + err.Error())
+	}
+}
+
+// ParseValidators ...
+func ParseValidators(validatorsBytes []byte) ([]ecommon.Address, error) {
+	if len(validatorsBytes)%ecommon.AddressLength != 0 {
+		return nil, errors.New("invalid validators bytes")
+	}
+	n := len(validatorsBytes) / ecommon.AddressLength
+	result := make([]ecommon.Address, n)
+	for i := 0; i < n; i++ {
+		address := make([]byte, ecommon.AddressLength)
+		copy(address, validatorsBytes[i*ecommon.AddressLength:(i+1)*ecommon.AddressLength])
+		result[i] = ecommon.BytesToAddress(address)
+	}
+	return result, nil
+}
+
+// VerifyGaslimit verifies the header gas limit according increase/decrease
+// in relation to the parent gas limit.
+func VerifyGaslimit(parentGasLimit, headerGasLimit uint64) error {
+	// Verify that the gas limit remains within allowed bounds
+	diff := int64(parentGasLimit) - int64(headerGasLimit)
+	if diff < 0 {
+		diff *= -1
+	}
+	limit := parentGasLimit / params.GasLimitBoundDivisor
+	if uint64(diff) >= limit {
+		return fmt.Errorf("invalid gas limit: have %d, want %d +-= %d", headerGasLimit, parentGasLimit, limit-1)
+	}
+	if headerGasLimit < params.MinGasLimit {
+		return errors.New("invalid gas limit below 5000")
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package posa
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	ecommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/polynetwork/poly/common/config"
+	cstates "github.com/polynetwork/poly/core/states"
+	scom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/header_sync/eth"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckConflict(t *testing.T) {
+	native := newTestNative(t)
+	engine := &Engine{config: &Config{Name: "test"}, store: testStore}
+	ctx := &context{ChainID: testChainID}
+
+	headers := make([]*eth.Header, 0)
+	var parent *testHeader
+	for i := int64(100); i < 105; i++ {
+		header := &eth.Header{Number: big.NewInt(i), Difficulty: big.NewInt(2)}
+		stored := &testHeader{Hash: header.Hash(), Number: uint64(i), Sum: big.NewInt(2 * (i - 99))}
+		if parent == nil {
+			assert.NoError(t, testStore.StoreGenesis(native, testChainID, stored, stored))
+		} else {
+			stored.ParentHash = parent.Hash
+			assert.NoError(t, testStore.AddHeader(native, testChainID, stored))
+		}
+		headers = append(headers, header)
+		parent = stored
+	}
+
+	conflict := func(number int64) *eth.Header {
+		return &eth.Header{Number: big.NewInt(number), Difficulty: big.NewInt(1), Extra: []byte{1}}
+	}
+	// the canonical header itself and forks within the confirmations are not misbehaviors
+	assert.NoError(t, engine.checkConflict(native, headers[2], 3, ctx))
+	assert.NoError(t, engine.checkConflict(native, conflict(103), 3, ctx))
+	assert.NoError(t, engine.checkConflict(native, conflict(105), 3, ctx))
+	assert.NoError(t, engine.checkConflict(native, conflict(102), 0, ctx))
+	assert.Nil(t, native.GetMisbehavior())
+
+	// accepted before the relayer slashing fork, which is not scheduled on main net
+	networkID := config.DefConfig.P2PNode.NetworkId
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_MAIN_NET
+	defer func() { config.DefConfig.P2PNode.NetworkId = networkID }()
+	assert.NoError(t, engine.checkConflict(native, conflict(102), 3, ctx))
+	assert.Nil(t, native.GetMisbehavior())
+
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+	err := engine.checkConflict(native, conflict(102), 3, ctx)
+	assert.Error(t, err)
+	assert.Equal(t, err, native.GetMisbehavior())
+}
+
+// legacyHeaderWithDifficultySum is the header stored by the bsc handler before posa, with the go-ethereum header
+type legacyHeaderWithDifficultySum struct {
+	Header          *types.Header `json:"header"`
+	DifficultySum   *big.Int      `json:"difficultySum"`
+	EpochParentHash *ecommon.Hash `json:"epochParentHash"`
+}
+
+func TestLegacyStoredHeader(t *testing.T) {
+	native := newTestNative(t)
+	engine := NewEngine(&Config{Name: "bsc"})
+
+	// a parlia header of an epoch, with the validators between vanity and seal
+	extra := make([]byte, extraVanity+2*ecommon.AddressLength+extraSeal)
+	for i := range extra {
+		extra[i] = byte(i)
+	}
+	epochParentHash := ecommon.HexToHash("0x0a")
+	legacy := &legacyHeaderWithDifficultySum{
+		Header: &types.Header{
+			ParentHash:  ecommon.HexToHash("0x01"),
+			UncleHash:   types.EmptyUncleHash,
+			Coinbase:    ecommon.HexToAddress("0x02"),
+			Root:        ecommon.HexToHash("0x03"),
+			TxHash:      ecommon.HexToHash("0x04"),
+			ReceiptHash: ecommon.HexToHash("0x05"),
+			Bloom:       types.BytesToBloom([]byte{6}),
+			Difficulty:  big.NewInt(2),
+			Number:      big.NewInt(6000000),
+			GasLimit:    30000000,
+			GasUsed:     12345678,
+			Time:        1616000000,
+			Extra:       extra,
+		},
+		DifficultySum:   big.NewInt(11000001),
+		EpochParentHash: &epochParentHash,
+	}
+	stored, err := json.Marshal(legacy)
+	assert.NoError(t, err)
+	native.GetCacheDB().Put(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.HEADER_INDEX),
+		utils.GetUint64Bytes(testChainID), legacy.Header.Hash().Bytes()), cstates.GenRawStorageItem(stored))
+
+	header, err := engine.store.GetHeader(native, testChainID, legacy.Header.Hash())
+	assert.NoError(t, err)
+	assert.Equal(t, legacy.Header.Hash(), header.HeaderHash())
+	assert.Equal(t, legacy.Header.ParentHash, header.HeaderParentHash())
+	assert.Equal(t, uint64(6000000), header.HeaderNumber())
+	assert.Equal(t, legacy.DifficultySum, header.TotalDifficulty())
+	assert.Equal(t, epochParentHash, *header.(*HeaderWithDifficultySum).EpochParentHash)
+
+	restored, err := json.Marshal(header)
+	assert.NoError(t, err)
+	assert.Equal(t, string(stored), string(restored))
+}
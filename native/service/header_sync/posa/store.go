@@ -0,0 +1,308 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package posa holds the header sync logic shared by the evm proof of staked
+// authority side chains: the header store with its total difficulty fork choice,
+// and a parlia/congress engine that bsc like chains are configured from.
+package posa
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	ecommon "github.com/ethereum/go-ethereum/common"
+	cstates "github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+	scom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+// StoredHeader is a side chain header as it is kept in HEADER_INDEX,
+// together with the total difficulty of the branch it ends.
+type StoredHeader interface {
+	HeaderHash() ecommon.Hash
+	HeaderParentHash() ecommon.Hash
+	HeaderNumber() uint64
+	TotalDifficulty() *big.Int
+}
+
+// Store keeps the genesis, the header index and the canonical chain of a side chain.
+// GENESIS_HEADER => the json encoded genesis
+// HEADER_INDEX => the mapping of header hash and json encoded StoredHeader
+// CURRENT_HEADER_HEIGHT => current block height of side chain in poly relay chain
+// MAIN_CHAIN => the mapping of block height and block header hash
+type Store struct {
+	// Name prefixes errors, e.g. "bsc Handler getHeader error"
+	Name string
+	// NewHeader returns an empty StoredHeader for json decoding
+	NewHeader func() StoredHeader
+}
+
+// GetGenesis decodes the stored genesis into genesis, exist is false if none is stored
+func (s *Store) GetGenesis(native *native.NativeService, chainID uint64, genesis interface{}) (exist bool, err error) {
+	genesisBytes, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.GENESIS_HEADER), utils.GetUint64Bytes(chainID)))
+	if err != nil {
+		err = fmt.Errorf("getGenesis, GetCacheDB err:%v", err)
+		return
+	}
+	if genesisBytes == nil {
+		return
+	}
+
+	genesisBytes, err = cstates.GetValueFromRawStorageItem(genesisBytes)
+	if err != nil {
+		err = fmt.Errorf("getGenesis, GetValueFromRawStorageItem err:%v", err)
+		return
+	}
+	err = json.Unmarshal(genesisBytes, genesis)
+	if err != nil {
+		err = fmt.Errorf("getGenesis, json.Unmarshal err:%v", err)
+		return
+	}
+	exist = true
+	return
+}
+
+// IsGenesisStored ...
+func (s *Store) IsGenesisStored(native *native.NativeService, chainID uint64) (bool, error) {
+	genesisBytes, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.GENESIS_HEADER), utils.GetUint64Bytes(chainID)))
+	if err != nil {
+		return false, fmt.Errorf("getGenesis, GetCacheDB err:%v", err)
+	}
+	return genesisBytes != nil, nil
+}
+
+// StoreGenesis stores genesis and makes header the head of the canonical chain
+func (s *Store) StoreGenesis(native *native.NativeService, chainID uint64, genesis interface{}, header StoredHeader) (err error) {
+	genesisBytes, err := json.Marshal(genesis)
+	if err != nil {
+		return
+	}
+	native.GetCacheDB().Put(
+		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.GENESIS_HEADER), utils.GetUint64Bytes(chainID)),
+		cstates.GenRawStorageItem(genesisBytes))
+
+	err = s.PutHeader(native, chainID, header)
+	if err != nil {
+		return
+	}
+	s.PutCanonicalHeight(native, chainID, header.HeaderNumber())
+	s.PutCanonicalHash(native, chainID, header.HeaderNumber(), header.HeaderHash())
+
+	scom.NotifyPutHeader(native, chainID, header.HeaderNumber(), header.HeaderHash().Hex())
+	return
+}
+
+// IsHeaderExist ...
+func (s *Store) IsHeaderExist(native *native.NativeService, chainID uint64, hash ecommon.Hash) (bool, error) {
+	headerStore, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress,
+		[]byte(scom.HEADER_INDEX), utils.GetUint64Bytes(chainID), hash.Bytes()))
+	if err != nil {
+		return false, fmt.Errorf("%s Handler isHeaderExist error: %v", s.Name, err)
+	}
+
+	return headerStore != nil, nil
+}
+
+// GetHeader ...
+func (s *Store) GetHeader(native *native.NativeService, chainID uint64, hash ecommon.Hash) (StoredHeader, error) {
+	headerStore, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress,
+		[]byte(scom.HEADER_INDEX), utils.GetUint64Bytes(chainID), hash.Bytes()))
+	if err != nil {
+		return nil, fmt.Errorf("%s Handler getHeader error: %v", s.Name, err)
+	}
+	if headerStore == nil {
+		return nil, fmt.Errorf("%s Handler getHeader, can not find any header records", s.Name)
+	}
+	storeBytes, err := cstates.GetValueFromRawStorageItem(headerStore)
+	if err != nil {
+		return nil, fmt.Errorf("%s Handler getHeader, deserialize headerBytes from raw storage item err:%v", s.Name, err)
+	}
+	header := s.NewHeader()
+	if err := json.Unmarshal(storeBytes, header); err != nil {
+		return nil, fmt.Errorf("%s Handler getHeader, deserialize header error: %v", s.Name, err)
+	}
+	return header, nil
+}
+
+// PutHeader ...
+func (s *Store) PutHeader(native *native.NativeService, chainID uint64, header StoredHeader) (err error) {
+	headerBytes, err := json.Marshal(header)
+	if err != nil {
+		return
+	}
+
+	native.GetCacheDB().Put(
+		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.HEADER_INDEX), utils.GetUint64Bytes(chainID), header.HeaderHash().Bytes()),
+		cstates.GenRawStorageItem(headerBytes))
+	return scom.IndexHeader(native, chainID, header.HeaderNumber(), header.HeaderHash().Bytes())
+}
+
+// GetCanonicalHeight ...
+func (s *Store) GetCanonicalHeight(native *native.NativeService, chainID uint64) (height uint64, err error) {
+	heightStore, err := native.GetCacheDB().Get(
+		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.CURRENT_HEADER_HEIGHT), utils.GetUint64Bytes(chainID)))
+	if err != nil {
+		err = fmt.Errorf("%s Handler GetCanonicalHeight err:%v", s.Name, err)
+		return
+	}
+
+	storeBytes, err := cstates.GetValueFromRawStorageItem(heightStore)
+	if err != nil {
+		err = fmt.Errorf("%s Handler GetCanonicalHeight, GetValueFromRawStorageItem err:%v", s.Name, err)
+		return
+	}
+
+	height = utils.GetBytesUint64(storeBytes)
+	return
+}
+
+// PutCanonicalHeight ...
+func (s *Store) PutCanonicalHeight(native *native.NativeService, chainID uint64, height uint64) {
+	native.GetCacheDB().Put(
+		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.CURRENT_HEADER_HEIGHT), utils.GetUint64Bytes(chainID)),
+		cstates.GenRawStorageItem(utils.GetUint64Bytes(height)))
+}
+
+// GetCanonicalHash returns an empty hash if there is no canonical header at height
+func (s *Store) GetCanonicalHash(native *native.NativeService, chainID uint64, height uint64) (hash ecommon.Hash, err error) {
+	hashBytesStore, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.MAIN_CHAIN), utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(height)))
+	if err != nil {
+		return
+	}
+	if hashBytesStore == nil {
+		return
+	}
+
+	hashBytes, err := cstates.GetValueFromRawStorageItem(hashBytesStore)
+	if err != nil {
+		err = fmt.Errorf("%s Handler getCanonicalHash, GetValueFromRawStorageItem err:%v", s.Name, err)
+		return
+	}
+
+	hash = ecommon.BytesToHash(hashBytes)
+	return
+}
+
+// PutCanonicalHash ...
+func (s *Store) PutCanonicalHash(native *native.NativeService, chainID uint64, height uint64, hash ecommon.Hash) {
+	native.GetCacheDB().Put(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.MAIN_CHAIN), utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(height)),
+		cstates.GenRawStorageItem(hash.Bytes()))
+}
+
+// DeleteCanonicalHash ...
+func (s *Store) DeleteCanonicalHash(native *native.NativeService, chainID uint64, height uint64) {
+	native.GetCacheDB().Delete(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.MAIN_CHAIN), utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(height)))
+}
+
+// PruneHeader deletes the canonical header at height from the header index and the canonical chain,
+// and the indexed headers of side forks at height
+func (s *Store) PruneHeader(native *native.NativeService, chainID uint64, height uint64) error {
+	hash, err := s.GetCanonicalHash(native, chainID, height)
+	if err != nil {
+		return err
+	}
+	if hash != (ecommon.Hash{}) {
+		native.GetCacheDB().Delete(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.HEADER_INDEX),
+			utils.GetUint64Bytes(chainID), hash.Bytes()))
+		s.DeleteCanonicalHash(native, chainID, height)
+	}
+	return scom.PruneIndexedHeaders(native, chainID, height)
+}
+
+// GetCanonicalHeader returns nil if there is no canonical header at height
+func (s *Store) GetCanonicalHeader(native *native.NativeService, chainID uint64, height uint64) (StoredHeader, error) {
+	hash, err := s.GetCanonicalHash(native, chainID, height)
+	if err != nil {
+		return nil, err
+	}
+	if hash == (ecommon.Hash{}) {
+		return nil, nil
+	}
+
+	return s.GetHeader(native, chainID, hash)
+}
+
+// AddHeader stores a verified header whose total difficulty is already summed up
+// from its parent, and reorganizes the canonical chain if its branch is heavier.
+func (s *Store) AddHeader(native *native.NativeService, chainID uint64, header StoredHeader) (err error) {
+	cheight, err := s.GetCanonicalHeight(native, chainID)
+	if err != nil {
+		return
+	}
+	cheader, err := s.GetCanonicalHeader(native, chainID, cheight)
+	if err != nil {
+		return
+	}
+	if cheader == nil {
+		err = fmt.Errorf("getCanonicalHeader returns nil")
+		return
+	}
+
+	err = s.PutHeader(native, chainID, header)
+	if err != nil {
+		return
+	}
+	if header.TotalDifficulty().Cmp(cheader.TotalDifficulty()) <= 0 {
+		return
+	}
+
+	// Delete any canonical number assignments above the new head
+	number := header.HeaderNumber()
+	for i := number + 1; ; i++ {
+		var hash ecommon.Hash
+		hash, err = s.GetCanonicalHash(native, chainID, i)
+		if err != nil {
+			return
+		}
+		if hash == (ecommon.Hash{}) {
+			break
+		}
+		s.DeleteCanonicalHash(native, chainID, i)
+	}
+
+	// Overwrite any stale canonical number assignments
+	height := number - 1
+	headHash := header.HeaderParentHash()
+	for {
+		var (
+			hash       ecommon.Hash
+			headHeader StoredHeader
+		)
+		hash, err = s.GetCanonicalHash(native, chainID, height)
+		if err != nil {
+			return
+		}
+		if hash == headHash {
+			break
+		}
+
+		s.PutCanonicalHash(native, chainID, height, headHash)
+		headHeader, err = s.GetHeader(native, chainID, headHash)
+		if err != nil {
+			return
+		}
+		headHash = headHeader.HeaderParentHash()
+		height--
+	}
+
+	// Extend the canonical chain with the new header
+	s.PutCanonicalHash(native, chainID, number, header.HeaderHash())
+	s.PutCanonicalHeight(native, chainID, number)
+	return
+}
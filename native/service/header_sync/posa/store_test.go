@@ -0,0 +1,191 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package posa
+
+import (
+	"math/big"
+	"testing"
+
+	ecommon "github.com/ethereum/go-ethereum/common"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	scom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+type testHeader struct {
+	Hash       ecommon.Hash
+	ParentHash ecommon.Hash
+	Number     uint64
+	Sum        *big.Int
+}
+
+func (h *testHeader) HeaderHash() ecommon.Hash       { return h.Hash }
+func (h *testHeader) HeaderParentHash() ecommon.Hash { return h.ParentHash }
+func (h *testHeader) HeaderNumber() uint64           { return h.Number }
+func (h *testHeader) TotalDifficulty() *big.Int      { return h.Sum }
+
+var testStore = &Store{
+	Name:      "test",
+	NewHeader: func() StoredHeader { return &testHeader{} },
+}
+
+const testChainID = uint64(6)
+
+func newTestNative(t *testing.T) *native.NativeService {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	service, err := native.NewNativeService(db, &types.Transaction{}, 0, 0, common.Uint256{0}, 0, nil, false)
+	assert.NoError(t, err)
+	return service
+}
+
+func child(parent *testHeader, tag byte, difficulty int64) *testHeader {
+	hash := parent.Hash
+	hash[0]++
+	hash[1] = tag
+	return &testHeader{
+		Hash:       hash,
+		ParentHash: parent.Hash,
+		Number:     parent.Number + 1,
+		Sum:        new(big.Int).Add(parent.Sum, big.NewInt(difficulty)),
+	}
+}
+
+func assertCanonical(t *testing.T, native *native.NativeService, headers ...*testHeader) {
+	height, err := testStore.GetCanonicalHeight(native, testChainID)
+	assert.NoError(t, err)
+	assert.Equal(t, headers[len(headers)-1].Number, height)
+	for _, h := range headers {
+		hash, err := testStore.GetCanonicalHash(native, testChainID, h.Number)
+		assert.NoError(t, err)
+		assert.Equal(t, h.Hash, hash)
+	}
+	hash, err := testStore.GetCanonicalHash(native, testChainID, height+1)
+	assert.NoError(t, err)
+	assert.Equal(t, ecommon.Hash{}, hash)
+}
+
+func TestStoreGenesis(t *testing.T) {
+	native := newTestNative(t)
+	genesis := &testHeader{Hash: ecommon.Hash{1}, Number: 100, Sum: big.NewInt(2)}
+
+	stored, err := testStore.IsGenesisStored(native, testChainID)
+	assert.NoError(t, err)
+	assert.False(t, stored)
+
+	assert.NoError(t, testStore.StoreGenesis(native, testChainID, genesis, genesis))
+
+	stored, err = testStore.IsGenesisStored(native, testChainID)
+	assert.NoError(t, err)
+	assert.True(t, stored)
+
+	got := &testHeader{}
+	exist, err := testStore.GetGenesis(native, testChainID, got)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, genesis, got)
+
+	exist, err = testStore.IsHeaderExist(native, testChainID, genesis.Hash)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assertCanonical(t, native, genesis)
+}
+
+func TestStoreAddHeaderForkChoice(t *testing.T) {
+	native := newTestNative(t)
+	genesis := &testHeader{Hash: ecommon.Hash{1}, Number: 100, Sum: big.NewInt(2)}
+	assert.NoError(t, testStore.StoreGenesis(native, testChainID, genesis, genesis))
+
+	// a1 <- a2 <- a3 with in turn difficulty
+	a1 := child(genesis, 'a', 2)
+	a2 := child(a1, 'a', 2)
+	a3 := child(a2, 'a', 2)
+	for _, h := range []*testHeader{a1, a2, a3} {
+		assert.NoError(t, testStore.AddHeader(native, testChainID, h))
+	}
+	assertCanonical(t, native, genesis, a1, a2, a3)
+
+	// a lighter branch is stored but does not become canonical
+	b1 := child(genesis, 'b', 1)
+	b2 := child(b1, 'b', 1)
+	for _, h := range []*testHeader{b1, b2} {
+		assert.NoError(t, testStore.AddHeader(native, testChainID, h))
+	}
+	assertCanonical(t, native, genesis, a1, a2, a3)
+	exist, err := testStore.IsHeaderExist(native, testChainID, b2.Hash)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+
+	// a heavier but shorter branch reorganizes the canonical chain
+	c1 := child(a1, 'c', 10)
+	assert.NoError(t, testStore.AddHeader(native, testChainID, c1))
+	assertCanonical(t, native, genesis, a1, c1)
+
+	// extending the lighter branch past the head switches back to it
+	b3 := child(b2, 'b', 20)
+	assert.NoError(t, testStore.AddHeader(native, testChainID, b3))
+	assertCanonical(t, native, genesis, b1, b2, b3)
+
+	head, err := testStore.GetCanonicalHeader(native, testChainID, b3.Number)
+	assert.NoError(t, err)
+	assert.Equal(t, b3, head)
+	head, err = testStore.GetCanonicalHeader(native, testChainID, b3.Number+1)
+	assert.NoError(t, err)
+	assert.Nil(t, head)
+}
+
+func TestStorePruneHeader(t *testing.T) {
+	native := newTestNative(t)
+	genesis := &testHeader{Hash: ecommon.Hash{1}, Number: 100, Sum: big.NewInt(2)}
+	assert.NoError(t, testStore.StoreGenesis(native, testChainID, genesis, genesis))
+	// a side fork stored before the retention policy is set is not indexed
+	old := child(genesis, 'o', 1)
+	assert.NoError(t, testStore.AddHeader(native, testChainID, old))
+	scom.PutHeaderRetention(native, &scom.HeaderRetention{ChainID: testChainID, KeepHeaders: scom.MIN_KEEP_HEADERS})
+	a1 := child(genesis, 'a', 2)
+	a2 := child(a1, 'a', 2)
+	b1 := child(genesis, 'b', 1)
+	for _, h := range []*testHeader{a1, a2, b1} {
+		assert.NoError(t, testStore.AddHeader(native, testChainID, h))
+	}
+
+	assert.NoError(t, testStore.PruneHeader(native, testChainID, a1.Number))
+	for _, h := range []*testHeader{a1, b1} {
+		exist, err := testStore.IsHeaderExist(native, testChainID, h.Hash)
+		assert.NoError(t, err)
+		assert.False(t, exist)
+	}
+	exist, err := testStore.IsHeaderExist(native, testChainID, old.Hash)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	head, err := testStore.GetCanonicalHeader(native, testChainID, a1.Number)
+	assert.NoError(t, err)
+	assert.Nil(t, head)
+	// pruning a height twice does nothing
+	assert.NoError(t, testStore.PruneHeader(native, testChainID, a1.Number))
+
+	// the chain still extends from the head
+	a3 := child(a2, 'a', 2)
+	assert.NoError(t, testStore.AddHeader(native, testChainID, a3))
+	assertCanonical(t, native, genesis, a2, a3)
+}
@@ -34,6 +34,10 @@ func NewQuorumHandler() *QuorumHandler {
 	return &QuorumHandler{}
 }
 
+func init() {
+	common.RegisterHeaderSyncHandler(utils.QUORUM_ROUTER, func() common.HeaderSyncHandler { return NewQuorumHandler() })
+}
+
 func (h *QuorumHandler) SyncGenesisHeader(ns *native.NativeService) error {
 	params := new(common.SyncGenesisHeaderParam)
 	if err := params.Deserialization(pcom.NewZeroCopySource(ns.GetInput())); err != nil {
@@ -0,0 +1,127 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package header_sync
+
+import (
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+// SetHeaderRetention sets the header retention policy of a side chain, only the consensus operator can call it
+func SetHeaderRetention(native *native.NativeService) ([]byte, error) {
+	params := new(hscommon.HeaderRetention)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetHeaderRetention, contract params deserialize error: %v", err)
+	}
+
+	// Get current epoch operator
+	operatorAddress, err := node_manager.GetCurConOperator(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetHeaderRetention, get current consensus operator address error: %v", err)
+	}
+	//check witness
+	err = utils.ValidateOwner(native, operatorAddress)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetHeaderRetention, checkWitness error: %v", err)
+	}
+
+	sideChain, err := side_chain_manager.GetSideChain(native, params.ChainID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetHeaderRetention, side_chain_manager.GetSideChain error: %v", err)
+	}
+	if sideChain == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetHeaderRetention, side chain is not registered")
+	}
+	if params.KeepHeaders != 0 && params.KeepHeaders < hscommon.MIN_KEEP_HEADERS {
+		return utils.BYTE_FALSE, fmt.Errorf("SetHeaderRetention, keep headers %d is less than %d",
+			params.KeepHeaders, hscommon.MIN_KEEP_HEADERS)
+	}
+	handler, err := GetChainHandler(sideChain.Router)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	if _, ok := handler.(hscommon.HeaderPruner); !ok {
+		return utils.BYTE_FALSE, fmt.Errorf("SetHeaderRetention, headers of router %d can not be pruned", sideChain.Router)
+	}
+
+	hscommon.PutHeaderRetention(native, params)
+	native.AddNotify(hscommon.SetHeaderRetentionEvent.NewNotify(params.ChainID, params.KeepHeaders))
+	return utils.BYTE_TRUE, nil
+}
+
+// pruneHeaders deletes at most HEADER_PRUNE_BATCH canonical headers of the side chain which are deeper than
+// its finality depth plus KeepHeaders, it stops at the lowest header pinned by a queued cross chain tx
+func pruneHeaders(native *native.NativeService, handler hscommon.HeaderSyncHandler, sideChain *side_chain_manager.SideChain) error {
+	pruner, ok := handler.(hscommon.HeaderPruner)
+	if !ok {
+		return nil
+	}
+	chainID := sideChain.ChainId
+	retention, err := hscommon.GetHeaderRetention(native, chainID)
+	if err != nil {
+		return err
+	}
+	if retention == nil {
+		return nil
+	}
+
+	genesis, current, err := pruner.GetHeaderRange(native, chainID)
+	if err != nil {
+		return err
+	}
+	keep := sideChain.BlocksToWait + retention.KeepHeaders
+	if current < genesis+keep {
+		return nil
+	}
+	end := current - keep
+	pinned, err := hscommon.GetPinnedHeights(native, chainID)
+	if err != nil {
+		return err
+	}
+	if height, ok := pinned.Lowest(); ok && height < end {
+		end = height
+	}
+	start, ok, err := hscommon.GetHeaderPrunedHeight(native, chainID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		start = genesis
+	}
+	if end <= start {
+		return nil
+	}
+	if end-start > hscommon.HEADER_PRUNE_BATCH {
+		end = start + hscommon.HEADER_PRUNE_BATCH
+	}
+
+	for height := start; height < end; height++ {
+		if err := pruner.PruneHeader(native, chainID, height); err != nil {
+			return err
+		}
+	}
+	hscommon.PutHeaderPrunedHeight(native, chainID, end)
+	hscommon.NotifyHeaderPruned(native, chainID, start, end)
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package header_sync
+
+import (
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+type testPruner struct {
+	genesis, current uint64
+	pruned           []uint64
+}
+
+func (p *testPruner) SyncGenesisHeader(service *native.NativeService) error { return nil }
+func (p *testPruner) SyncBlockHeader(service *native.NativeService) error   { return nil }
+func (p *testPruner) SyncCrossChainMsg(service *native.NativeService) error { return nil }
+
+func (p *testPruner) GetHeaderRange(service *native.NativeService, chainID uint64) (uint64, uint64, error) {
+	return p.genesis, p.current, nil
+}
+
+func (p *testPruner) PruneHeader(service *native.NativeService, chainID uint64, height uint64) error {
+	p.pruned = append(p.pruned, height)
+	return nil
+}
+
+func TestPruneHeaders(t *testing.T) {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	ns, _ := native.NewNativeService(db, &types.Transaction{}, 0, 0, common.Uint256{0}, 0, nil, false)
+	sideChain := &side_chain_manager.SideChain{ChainId: 2, BlocksToWait: 10}
+	pruner := &testPruner{genesis: 1000, current: 1000 + 10 + hscommon.MIN_KEEP_HEADERS + 300}
+
+	// all headers are kept without retention policy
+	assert.NoError(t, pruneHeaders(ns, pruner, sideChain))
+	assert.Equal(t, 0, len(pruner.pruned))
+
+	hscommon.PutHeaderRetention(ns, &hscommon.HeaderRetention{ChainID: 2, KeepHeaders: hscommon.MIN_KEEP_HEADERS})
+	assert.NoError(t, pruneHeaders(ns, pruner, sideChain))
+	assert.Equal(t, hscommon.HEADER_PRUNE_BATCH, len(pruner.pruned))
+	assert.Equal(t, uint64(1000), pruner.pruned[0])
+	height, _, err := hscommon.GetHeaderPrunedHeight(ns, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1000+hscommon.HEADER_PRUNE_BATCH), height)
+
+	// stops at the lowest pinned header
+	assert.NoError(t, hscommon.PinHeader(ns, 2, []byte{1}, 1280))
+	pruner.pruned = nil
+	assert.NoError(t, pruneHeaders(ns, pruner, sideChain))
+	assert.Equal(t, []uint64{1256, 1257, 1258, 1259, 1260, 1261, 1262, 1263, 1264, 1265, 1266, 1267, 1268, 1269,
+		1270, 1271, 1272, 1273, 1274, 1275, 1276, 1277, 1278, 1279}, pruner.pruned)
+	pruner.pruned = nil
+	assert.NoError(t, pruneHeaders(ns, pruner, sideChain))
+	assert.Equal(t, 0, len(pruner.pruned))
+
+	// prunes up to the finality depth plus the retention once unpinned
+	assert.NoError(t, hscommon.UnpinHeader(ns, 2, []byte{1}))
+	assert.NoError(t, pruneHeaders(ns, pruner, sideChain))
+	assert.Equal(t, 20, len(pruner.pruned))
+	assert.Equal(t, uint64(1299), pruner.pruned[19])
+	height, _, err = hscommon.GetHeaderPrunedHeight(ns, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, pruner.current-10-hscommon.MIN_KEEP_HEADERS, height)
+}
@@ -53,6 +53,7 @@ var MAXU256 = &big.Int{}
 
 func init() {
 	MAXU256.SetString("115792089237316195423570985008687907853269984665640564039457584007913129639935", 10)
+	scom.RegisterHeaderSyncHandler(utils.STARCOIN_ROUTER, func() scom.HeaderSyncHandler { return NewSTCHandler() })
 }
 
 // Handler ...
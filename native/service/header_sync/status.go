@@ -0,0 +1,77 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package header_sync
+
+import (
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+// GetSideChainStatus returns the serialized hscommon.SideChainStatusList of all registered side chains,
+// it walks the synced headers of every chain so it is only served by pre-execution. A chain whose status
+// fails to be queried is listed with the Error set so that it does not hide the status of the others
+func GetSideChainStatus(native *native.NativeService) ([]byte, error) {
+	if !native.IsPreExec() {
+		return utils.BYTE_FALSE, fmt.Errorf("GetSideChainStatus, only callable by pre-execution")
+	}
+	sideChains, err := side_chain_manager.GetSideChainList(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("GetSideChainStatus, %v", err)
+	}
+	list := &hscommon.SideChainStatusList{List: make([]*hscommon.SideChainStatus, 0, len(sideChains))}
+	for _, sideChain := range sideChains {
+		// the router of a chain may be dropped by later releases
+		handler, _ := GetChainHandler(sideChain.Router)
+		status, err := getSideChainStatus(native, handler, sideChain)
+		if err != nil {
+			status.Error = err.Error()
+		}
+		list.List = append(list.List, status)
+	}
+	sink := common.NewZeroCopySink(nil)
+	list.Serialization(sink)
+	return sink.Bytes(), nil
+}
+
+func getSideChainStatus(native *native.NativeService, handler hscommon.HeaderSyncHandler, sideChain *side_chain_manager.SideChain) (*hscommon.SideChainStatus, error) {
+	status := &hscommon.SideChainStatus{
+		ChainID:      sideChain.ChainId,
+		Router:       sideChain.Router,
+		BlocksToWait: sideChain.BlocksToWait,
+	}
+	querier, ok := handler.(hscommon.HeaderSyncQuerier)
+	if !ok {
+		return status, nil
+	}
+	sync, err := querier.GetHeaderSyncStatus(native, sideChain.ChainId)
+	if err != nil || sync == nil {
+		return status, err
+	}
+	status.Sync = sync
+	// the genesis header is trusted without confirmations
+	status.FinalizedHeight = status.Sync.GenesisHeight
+	if status.Sync.Height >= status.Sync.GenesisHeight+sideChain.BlocksToWait {
+		status.FinalizedHeight = status.Sync.Height - sideChain.BlocksToWait
+	}
+	return status, nil
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package header_sync
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+type testQuerier struct {
+	testPruner
+	status *hscommon.HeaderSyncStatus
+	err    error
+}
+
+func (q *testQuerier) GetHeaderSyncStatus(service *native.NativeService, chainID uint64) (*hscommon.HeaderSyncStatus, error) {
+	return q.status, q.err
+}
+
+func TestGetSideChainStatusFinalizedHeight(t *testing.T) {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	ns, _ := native.NewNativeService(db, &types.Transaction{}, 0, 0, common.Uint256{0}, 0, nil, true)
+	sideChain := &side_chain_manager.SideChain{ChainId: 2, Router: utils.ETH_ROUTER, BlocksToWait: 12}
+
+	// handlers without status
+	status, err := getSideChainStatus(ns, &testPruner{}, sideChain)
+	assert.NoError(t, err)
+	assert.Nil(t, status.Sync)
+	assert.Equal(t, uint64(12), status.BlocksToWait)
+
+	querier := &testQuerier{}
+	status, err = getSideChainStatus(ns, querier, sideChain)
+	assert.NoError(t, err)
+	assert.Nil(t, status.Sync)
+
+	querier.status = &hscommon.HeaderSyncStatus{GenesisHeight: 100, Height: 105}
+	status, err = getSideChainStatus(ns, querier, sideChain)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), status.FinalizedHeight)
+
+	querier.status.Height = 200
+	status, err = getSideChainStatus(ns, querier, sideChain)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(188), status.FinalizedHeight)
+	assert.Equal(t, querier.status, status.Sync)
+
+	querier.err = fmt.Errorf("no header")
+	status, err = getSideChainStatus(ns, querier, sideChain)
+	assert.Error(t, err)
+	assert.Nil(t, status.Sync)
+	assert.Equal(t, uint64(0), status.FinalizedHeight)
+}
+
+func TestGetSideChainStatus(t *testing.T) {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	ns, _ := native.NewNativeService(db, &types.Transaction{}, 0, 0, common.Uint256{0}, 0, nil, true)
+	for _, sideChain := range []*side_chain_manager.SideChain{
+		{ChainId: 2, Router: utils.ETH_ROUTER, Name: "eth", BlocksToWait: 12},
+		{ChainId: 6, Router: utils.BSC_ROUTER, Name: "bsc", BlocksToWait: 21},
+	} {
+		assert.NoError(t, side_chain_manager.PutSideChain(ns, sideChain))
+	}
+
+	result, err := GetSideChainStatus(ns)
+	assert.NoError(t, err)
+	list := new(hscommon.SideChainStatusList)
+	assert.NoError(t, list.Deserialization(common.NewZeroCopySource(result)))
+	assert.Equal(t, 2, len(list.List))
+	assert.Equal(t, uint64(2), list.List[0].ChainID)
+	assert.Equal(t, uint64(6), list.List[1].ChainID)
+	assert.Equal(t, uint64(utils.BSC_ROUTER), list.List[1].Router)
+	// no genesis header is synced
+	assert.Nil(t, list.List[0].Sync)
+	assert.Nil(t, list.List[1].Sync)
+
+	ns, _ = native.NewNativeService(db, &types.Transaction{}, 0, 0, common.Uint256{0}, 0, nil, false)
+	_, err = GetSideChainStatus(ns)
+	assert.Error(t, err)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/native"
 	"github.com/polynetwork/poly/native/service/cross_chain_manager"
+	"github.com/polynetwork/poly/native/service/governance/fork_manager"
 	"github.com/polynetwork/poly/native/service/governance/neo3_state_manager"
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
 	"github.com/polynetwork/poly/native/service/governance/relayer_manager"
@@ -40,6 +41,10 @@ func init() {
 	native.Contracts[utils.Neo3StateManagerContractAddress] = neo3_state_manager.RegisterStateValidatorManagerContract
 	native.Contracts[utils.SignatureManagerContractAddress] = signature_manager.RegisterSignatureManagerContract
 	native.Contracts[utils.ReplenishContractAddress] = replenish.RegisterReplenishContract
+	native.Contracts[utils.ForkManagerContractAddress] = fork_manager.RegisterForkManagerContract
+
+	native.FailedTxHandlers[utils.HeaderSyncContractAddress] = relayer_manager.RecordFailedTx
+	native.FailedTxHandlers[utils.CrossChainManagerContractAddress] = relayer_manager.RecordFailedTx
 
 	config.EXTRA_INFO_HEIGHT_FORK_CHECK = true
 }
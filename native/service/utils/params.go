@@ -18,9 +18,7 @@
 package utils
 
 import (
-	"fmt"
 	"github.com/polynetwork/poly/common"
-	"github.com/polynetwork/poly/common/config"
 )
 
 type BtcNetType int
@@ -44,6 +42,7 @@ var (
 	Neo3StateManagerContractAddress, _  = common.AddressParseFromBytes([]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x07})
 	SignatureManagerContractAddress, _  = common.AddressParseFromBytes([]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08})
 	ReplenishContractAddress, _         = common.AddressParseFromBytes([]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x09})
+	ForkManagerContractAddress, _       = common.AddressParseFromBytes([]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a})
 
 	VOTE_ROUTER             = uint64(0)
 	BTC_ROUTER              = uint64(1)
@@ -68,19 +67,5 @@ var (
 	HARMONY_ROUTER          = uint64(21)
 	BYTOM_ROUTER            = uint64(22)
 	RIPPLE_ROUTER           = uint64(23)
+	BEACON_ROUTER           = uint64(24)
 )
-
-//Check router StartBlock to prevent hard forks
-func CheckRouterStartBlock(router uint64, block uint32) (err error) {
-	var startBLock uint32
-	switch router {
-	case HARMONY_ROUTER, HSC_ROUTER, BYTOM_ROUTER:
-		if config.DefConfig.P2PNode.NetworkId == config.NETWORK_ID_MAIN_NET {
-			startBLock = 18823000
-		}
-	}
-	if startBLock > 0 && block < startBLock {
-		return fmt.Errorf("not a supported router:%d", router)
-	}
-	return
-}
\ No newline at end of file
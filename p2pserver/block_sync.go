@@ -288,6 +288,7 @@ func (this *BlockSyncMgr) checkTimeout() {
 
 	for height, flightInfo := range headerTimeoutFlights {
 		this.addTimeoutCnt(flightInfo.GetNodeId())
+		syncTimeoutCounter.WithLabelValues("header").Inc()
 		if height <= curHeaderHeight {
 			this.delFlightHeader(height)
 			continue
@@ -313,6 +314,7 @@ func (this *BlockSyncMgr) checkTimeout() {
 	for blockHash, flightInfos := range blockTimeoutFlights {
 		for _, flightInfo := range flightInfos {
 			this.addTimeoutCnt(flightInfo.GetNodeId())
+			syncTimeoutCounter.WithLabelValues("block").Inc()
 			if flightInfo.Height <= curBlockHeight {
 				this.delFlightBlock(blockHash)
 				continue
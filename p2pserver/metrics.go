@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2pserver
+
+import (
+	"github.com/polynetwork/poly/common/metrics"
+)
+
+var syncTimeoutCounter = metrics.NewCounterVec("poly_p2p_sync_timeouts_total",
+	"Timed out block sync requests by flight type", "type")
+
+//registerMetrics exports the connection and block sync state of the server
+func (this *P2PServer) registerMetrics() {
+	metrics.NewGaugeFunc("poly_p2p_connections", "Connections of the p2p network by direction",
+		metrics.Labels{"direction": "inbound"}, func() float64 {
+			return float64(this.network.GetInConnRecordLen())
+		})
+	metrics.NewGaugeFunc("poly_p2p_connections", "Connections of the p2p network by direction",
+		metrics.Labels{"direction": "outbound"}, func() float64 {
+			return float64(this.network.GetOutConnRecordLen())
+		})
+	metrics.NewGaugeFunc("poly_p2p_established_peers", "Established neighbor peers",
+		nil, func() float64 {
+			return float64(this.network.GetConnectionCnt())
+		})
+	metrics.NewGaugeFunc("poly_p2p_sync_flights", "Block sync requests on flight by flight type",
+		metrics.Labels{"type": "header"}, func() float64 {
+			return float64(this.blockSync.getFlightHeaderCount())
+		})
+	metrics.NewGaugeFunc("poly_p2p_sync_flights", "Block sync requests on flight by flight type",
+		metrics.Labels{"type": "block"}, func() float64 {
+			return float64(this.blockSync.getFlightBlockCount())
+		})
+	metrics.NewGaugeFunc("poly_p2p_sync_cached_blocks", "Synced blocks waiting to be saved",
+		nil, func() float64 {
+			return float64(this.blockSync.getBlockCacheSize())
+		})
+}
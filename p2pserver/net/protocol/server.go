@@ -50,6 +50,7 @@ type P2P interface {
 	GetMsgChan(isConsensus bool) chan *types.MsgPayload
 	GetPeerFromAddr(addr string) *peer.Peer
 	AddOutConnectingList(addr string) (added bool)
+	GetInConnRecordLen() int
 	GetOutConnRecordLen() int
 	RemoveFromConnectingList(addr string)
 	RemoveFromOutConnRecord(addr string)
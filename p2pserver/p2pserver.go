@@ -81,6 +81,7 @@ func NewServer() *P2PServer {
 	p.quitSyncRecent = make(chan bool)
 	p.quitOnline = make(chan bool)
 	p.quitHeartBeat = make(chan bool)
+	p.registerMetrics()
 	return p
 }
 
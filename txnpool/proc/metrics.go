@@ -0,0 +1,52 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package proc
+
+import (
+	"github.com/polynetwork/poly/common/metrics"
+	tc "github.com/polynetwork/poly/txnpool/common"
+)
+
+var statsNames = map[tc.TxnStatsType]string{
+	tc.RcvStats:       "received",
+	tc.SuccessStats:   "success",
+	tc.FailureStats:   "failure",
+	tc.DuplicateStats: "duplicate",
+	tc.SigErrStats:    "sig_error",
+	tc.StateErrStats:  "state_error",
+}
+
+// registerMetrics exports the tx statistics and the pool size of the server
+func (s *TXPoolServer) registerMetrics() {
+	for statsType, name := range statsNames {
+		index := int(statsType) - 1
+		metrics.NewCounterFunc("poly_txpool_txs_total", "Transactions handled by the tx pool by result",
+			metrics.Labels{"result": name}, func() float64 {
+				return float64(s.getStats()[index])
+			})
+	}
+	metrics.NewGaugeFunc("poly_txpool_verified_txs", "Verified transactions waiting in the tx pool",
+		nil, func() float64 {
+			return float64(s.getTransactionCount())
+		})
+	metrics.NewGaugeFunc("poly_txpool_pending_txs", "Transactions being verified by the tx pool",
+		nil, func() float64 {
+			return float64(s.getPendingListSize())
+		})
+}
@@ -101,9 +101,15 @@ func (ta *TxActor) isValidSender(txn *tx.Transaction) (err error) {
 		}
 		// Check if address is registreed relayer
 		if len(value) > 0 {
-			// Here means address is registered relayer
-			flag = false
-			break
+			// Here means address is registered relayer, which is permitted unless deactivated by slashing
+			deactivated, err := isDeactivatedRelayer(address)
+			if err != nil {
+				return err
+			}
+			if !deactivated {
+				flag = false
+				break
+			}
 		}
 		// Check if address is included in permittedAddrMap, if so, the txn is permitted
 		if val, ok := permittedAddrMap[address]; val && ok {
@@ -118,6 +124,23 @@ func (ta *TxActor) isValidSender(txn *tx.Transaction) (err error) {
 	return nil
 }
 
+// isDeactivatedRelayer checks the status recorded by relayer_manager for the registered relayer
+func isDeactivatedRelayer(address common.Address) (bool, error) {
+	key := append([]byte(relayer_manager.RELAYER_STATUS), address[:]...)
+	value, err := bactor.GetStorageItem(utils.RelayerManagerContractAddress, key)
+	if err != nil {
+		if err == scommon.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	status := new(relayer_manager.RelayerStatus)
+	if err := status.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return false, err
+	}
+	return status.Deactivated, nil
+}
+
 var permittedAddrMap = make(map[common.Address]bool)
 var lastTime int64
 var lock sync.RWMutex
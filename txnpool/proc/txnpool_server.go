@@ -108,6 +108,7 @@ func (s *TXPoolServer) init(num uint8, disablePreExec, disableBroadcastNetTx boo
 	}
 
 	s.stats = txStats{count: make([]uint64, tc.MaxStats-1)}
+	s.registerMetrics()
 
 	s.slots = make(chan struct{}, tc.MAX_LIMITATION)
 	for i := 0; i < tc.MAX_LIMITATION; i++ {